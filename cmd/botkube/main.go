@@ -20,29 +20,25 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/infracloudio/botkube/pkg/bot"
 	"github.com/infracloudio/botkube/pkg/config"
 	"github.com/infracloudio/botkube/pkg/controller"
+	"github.com/infracloudio/botkube/pkg/execute"
+	"github.com/infracloudio/botkube/pkg/filterengine"
+	"github.com/infracloudio/botkube/pkg/health"
 	"github.com/infracloudio/botkube/pkg/log"
 	"github.com/infracloudio/botkube/pkg/metrics"
 	"github.com/infracloudio/botkube/pkg/notify"
 	"github.com/infracloudio/botkube/pkg/utils"
 )
 
-const (
-	defaultMetricsPort = "2112"
-)
-
 func main() {
-	// Prometheus metrics
-	metricsPort, exists := os.LookupEnv("METRICS_PORT")
-	if !exists {
-		metricsPort = defaultMetricsPort
-	}
-	go metrics.ServeMetrics(metricsPort)
 	if err := startController(); err != nil {
 		log.Fatal(err)
 	}
@@ -55,9 +51,69 @@ func startController() error {
 		return fmt.Errorf("Error in loading configuration. Error:%s", err.Error())
 	}
 
-	// List notifiers
-	notifiers := notify.ListNotifiers(conf.Communications)
+	// Report configuration problems, only fatal ones stop BotKube
+	issues := conf.Validate()
+	for _, issue := range issues {
+		if issue.Fatal {
+			log.Errorf("Invalid configuration. %s", issue.Message)
+			continue
+		}
+		log.Warnf("Configuration warning. %s", issue.Message)
+	}
+	if config.HasFatal(issues) {
+		return fmt.Errorf("Error in validating configuration. Please fix the errors reported above")
+	}
+
+	// Validate notification templates
+	if err := notify.InitTemplates(conf.Settings.Templates); err != nil {
+		return fmt.Errorf("Error in loading templates. Error:%s", err.Error())
+	}
+	notify.InitClusterScopedKinds(conf.Settings.ClusterScopedKinds)
+	notify.InitEventVerbs(conf.Settings.EventVerbs)
+	execute.InitConfig(conf)
+	execute.InitCommandPrefix(conf.Settings.CommandPrefix)
+	execute.InitInstance(conf.Settings.InstanceName, conf.Settings.UnaddressedCommands)
+	execute.InitImpersonation(conf.Settings.AllowImpersonation)
+	execute.InitNodeOps(conf.Settings.AllowNodeOps, conf.Settings.DrainDeleteEmptyDirData)
+	execute.InitApproval(conf.Settings.RequireApproval)
+	execute.InitConfirmation(conf.Settings.RequireConfirmation)
+	execute.InitExec(conf.Settings.AllowExec, conf.Settings.ExecAllowlist)
+	if err := utils.InitProxy(conf.Settings.Proxy); err != nil {
+		log.Errorf("%s. Proxy from HTTPS_PROXY env is used", err.Error())
+	}
+	filterengine.InitDeduplication(conf.Settings.Deduplication)
+
+	// Set kubectl binaries
+	if err := execute.InitKubectl(conf.Settings.Kubectl); err != nil {
+		log.Errorf("%s. kubectl commands will fail until the path in settings.kubectl is fixed", err.Error())
+	}
+
+	// Open dead letter sink for undeliverable events
+	if err := notify.InitDeadLetter(conf.Settings.Notifiers.DeadLetter); err != nil {
+		log.Errorf("Failed to open dead letter file. Undeliverable events will be dropped. Error:%s", err.Error())
+	}
+
+	// Apply filter settings
+	for name, setting := range conf.Settings.Filters {
+		if err := filterengine.DefaultFilterEngine.SetFilterScope(name, setting.Namespaces); err != nil {
+			log.Errorf("Failed to configure filter %s. %s", name, err.Error())
+		}
+	}
+	filterengine.DefaultFilterEngine.ResetFilters(conf.Settings.Filters)
+
+	// Prometheus metrics, settings.metrics.port takes precedence over METRICS_PORT env
+	metricsPort := conf.Settings.Metrics.Port
+	if len(metricsPort) == 0 {
+		metricsPort = os.Getenv("METRICS_PORT")
+	}
+	if len(metricsPort) != 0 {
+		log.Infof("Serving metrics on port %s", metricsPort)
+		go func() {
+			log.Errorf("Error in metrics server. %v", metrics.ServeMetrics(metricsPort))
+		}()
+	}
 
+	var teamsBot *bot.Teams
 	if conf.Communications.Slack.Enabled {
 		log.Info("Starting slack bot")
 		sb := bot.NewSlackBot(conf)
@@ -72,9 +128,8 @@ func startController() error {
 
 	if conf.Communications.Teams.Enabled {
 		log.Info("Starting MS Teams bot")
-		tb := bot.NewTeamsBot(conf)
-		notifiers = append(notifiers, tb)
-		go tb.Start()
+		teamsBot = bot.NewTeamsBot(conf)
+		go teamsBot.Start()
 	}
 
 	if conf.Communications.Discord.Enabled {
@@ -83,6 +138,30 @@ func startController() error {
 		go db.Start()
 	}
 
+	// List notifiers, built again on config reload with the same MS Teams bot
+	controller.NotifierBuilder = func(c *config.Config) []notify.Notifier {
+		notifiers := notify.ListNotifiers(c.Communications)
+		if teamsBot != nil {
+			notifiers = append(notifiers, teamsBot)
+		}
+		notifiers = notify.WithDryRun(notifiers, c.Settings.DryRun)
+		// Stop sending to the notifiers which fail persistently
+		return notify.WithCircuitBreaker(notifiers, c.Settings.Notifiers.CircuitBreaker, c.Settings.ClusterName)
+	}
+	if conf.Settings.DryRun {
+		log.Warn("Dry run is enabled. Notifications are logged instead of sent")
+	}
+	notifiers := controller.NotifierBuilder(conf)
+
+	// Serve liveness and readiness endpoints
+	if len(conf.Settings.Health.Port) != 0 {
+		log.Infof("Serving health endpoints on port %s", conf.Settings.Health.Port)
+		hs := health.NewServer(conf.Settings.Health.Port, controller.Notifiers)
+		go func() {
+			log.Errorf("Error in health server. %v", hs.Start())
+		}()
+	}
+
 	// Start upgrade notifier
 	if conf.Settings.UpgradeNotifier {
 		log.Info("Starting upgrade notifier")
@@ -90,9 +169,12 @@ func startController() error {
 	}
 
 	// Init KubeClient, InformerMap and start controller
-	utils.InitKubeClient()
+	utils.InitKubeClient(conf.Settings.Kubernetes)
 	utils.InitInformerMap(conf)
 	utils.InitResourceMap(conf)
-	controller.RegisterInformers(conf, notifiers)
+	// Run until SIGTERM, the notifications in progress are sent before exiting
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
+	defer stop()
+	controller.RegisterInformers(ctx, conf, notifiers)
 	return nil
 }
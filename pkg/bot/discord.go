@@ -46,6 +46,7 @@ type discordMessage struct {
 	BotID         string
 	Request       string
 	Response      string
+	ContentType   execute.ContentType
 	IsAuthChannel bool
 	Session       *discordgo.Session
 }
@@ -118,12 +119,36 @@ func (dm *discordMessage) HandleMessage(b *DiscordBot) {
 	}
 
 	e := execute.NewDefaultExecutor(dm.Request, b.AllowKubectl, b.RestrictAccess, b.DefaultNamespace,
-		b.ClusterName, config.DiscordBot, b.ChannelID, dm.IsAuthChannel)
+		b.ClusterName, config.DiscordBot, b.ChannelID, "", dm.Event.Author.ID, dm.IsAuthChannel, dm.channelSender())
 
-	dm.Response = e.Execute()
+	res := e.ExecuteResult()
+	dm.Response, dm.ContentType = res.Output, res.ContentType
 	dm.Send()
 }
 
+// channelSender returns sender to post messages in the channel where the command was received
+func (dm *discordMessage) channelSender() *execute.ChannelSender {
+	return &execute.ChannelSender{
+		Channel: dm.Event.ChannelID,
+		Send: func(msg string) error {
+			reply := *dm
+			reply.Response, reply.ContentType = msg, execute.ContentTypeText
+			reply.Send()
+			return nil
+		},
+		SendFile: dm.uploadFile,
+	}
+}
+
+// uploadFile sends the content as a file to the channel where the command was received
+func (dm *discordMessage) uploadFile(name, content string) error {
+	params := &discordgo.MessageSend{
+		Files: []*discordgo.File{{Name: name, Reader: strings.NewReader(content)}},
+	}
+	_, err := dm.Session.ChannelMessageSendComplex(dm.Event.ChannelID, params)
+	return err
+}
+
 func (dm discordMessage) Send() {
 	log.Debugf("Discord incoming Request: %s", dm.Request)
 	log.Debugf("Discord Response: %s", dm.Response)
@@ -150,7 +175,7 @@ func (dm discordMessage) Send() {
 		return
 	}
 
-	if _, err := dm.Session.ChannelMessageSend(dm.Event.ChannelID, formatCodeBlock(dm.Response)); err != nil {
+	if _, err := dm.Session.ChannelMessageSend(dm.Event.ChannelID, formatResultBlock(dm.Response, dm.ContentType)); err != nil {
 		log.Error("Error in sending message:", err)
 	}
 }
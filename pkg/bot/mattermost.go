@@ -65,6 +65,7 @@ type MMBot struct {
 type mattermostMessage struct {
 	Event         *model.WebSocketEvent
 	Response      string
+	ContentType   execute.ContentType
 	Request       string
 	IsAuthChannel bool
 	APIClient     *model.Client4
@@ -150,11 +151,39 @@ func (mm *mattermostMessage) handleMessage(b MMBot) {
 	mm.Request = strings.TrimPrefix(post.Message, "@"+b.BotName+" ")
 
 	e := execute.NewDefaultExecutor(mm.Request, b.AllowKubectl, b.RestrictAccess, b.DefaultNamespace,
-		b.ClusterName, config.MattermostBot, b.ChannelName, mm.IsAuthChannel)
-	mm.Response = e.Execute()
+		b.ClusterName, config.MattermostBot, b.ChannelName, "", post.UserId, mm.IsAuthChannel, mm.channelSender())
+	res := e.ExecuteResult()
+	mm.Response, mm.ContentType = res.Output, res.ContentType
 	mm.sendMessage()
 }
 
+// channelSender returns sender to post messages in the channel where the command was received
+func (mm *mattermostMessage) channelSender() *execute.ChannelSender {
+	return &execute.ChannelSender{
+		Channel: mm.Event.Broadcast.ChannelId,
+		Send: func(msg string) error {
+			reply := *mm
+			reply.Response, reply.ContentType = msg, execute.ContentTypeText
+			reply.sendMessage()
+			return nil
+		},
+		SendFile: mm.uploadFile,
+	}
+}
+
+// uploadFile posts the content as a file to the channel where the command was received
+func (mm *mattermostMessage) uploadFile(name, content string) error {
+	res, resp := mm.APIClient.UploadFileAsRequestBody([]byte(content), mm.Event.Broadcast.ChannelId, name)
+	if resp.Error != nil {
+		return resp.Error
+	}
+	post := &model.Post{ChannelId: mm.Event.Broadcast.ChannelId, FileIds: []string{res.FileInfos[0].Id}}
+	if _, resp := mm.APIClient.CreatePost(post); resp.Error != nil {
+		return resp.Error
+	}
+	return nil
+}
+
 // Send messages to Mattermost
 func (mm mattermostMessage) sendMessage() {
 	log.Debugf("Mattermost incoming Request: %s", mm.Request)
@@ -174,7 +203,7 @@ func (mm mattermostMessage) sendMessage() {
 		}
 		post.FileIds = []string{string(res.FileInfos[0].Id)}
 	} else {
-		post.Message = formatCodeBlock(mm.Response)
+		post.Message = formatResultBlock(mm.Response, mm.ContentType)
 	}
 
 	// Create a post in the Channel
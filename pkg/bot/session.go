@@ -0,0 +1,120 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// sessionIdleTimeout ends the sessions without commands for the duration
+	sessionIdleTimeout = time.Hour
+
+	sessionStartedMsg        = "Session started on cluster '%s'. Commands in this thread run without mentioning BotKube until `session end`."
+	sessionAlreadyStartedMsg = "Session is already started in this thread on cluster '%s'."
+	sessionEndedMsg          = "Session ended on cluster '%s'."
+	noSessionMsg             = "No session is started in this thread on cluster '%s'."
+	sessionUsageMsg          = "Usage: session start|end"
+)
+
+// slackSessions are the Slack threads where commands are served without mentioning BotKube
+var slackSessions = &sessionStore{}
+
+// sessionStore tracks the last command time of the session threads
+type sessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]time.Time
+}
+
+// sessionKey identifies the thread of the channel
+func sessionKey(channel, threadTS string) string {
+	return fmt.Sprintf("%s/%s", channel, threadTS)
+}
+
+// start opens the session, returns false if it is already started
+func (s *sessionStore) start(key string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sessions == nil {
+		s.sessions = map[string]time.Time{}
+	}
+	for k, last := range s.sessions {
+		if now.Sub(last) > sessionIdleTimeout {
+			delete(s.sessions, k)
+		}
+	}
+	if _, ok := s.sessions[key]; ok {
+		return false
+	}
+	s.sessions[key] = now
+	return true
+}
+
+// end closes the session, returns false if there is no session
+func (s *sessionStore) end(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.sessions[key]
+	delete(s.sessions, key)
+	return ok
+}
+
+// active returns true if the session is started and not idle, the idle time starts again
+func (s *sessionStore) active(key string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last, ok := s.sessions[key]
+	if !ok {
+		return false
+	}
+	if now.Sub(last) > sessionIdleTimeout {
+		delete(s.sessions, key)
+		return false
+	}
+	s.sessions[key] = now
+	return true
+}
+
+// runSessionCommand starts or ends the session of the thread, false is returned if the request isn't a session command
+func (s *sessionStore) runSessionCommand(request, key, clusterName string) (string, bool) {
+	args := strings.Fields(request)
+	if len(args) == 0 || args[0] != "session" {
+		return "", false
+	}
+	if len(args) != 2 {
+		return sessionUsageMsg, true
+	}
+	switch args[1] {
+	case "start":
+		if !s.start(key, time.Now()) {
+			return fmt.Sprintf(sessionAlreadyStartedMsg, clusterName), true
+		}
+		return fmt.Sprintf(sessionStartedMsg, clusterName), true
+	case "end":
+		if !s.end(key) {
+			return fmt.Sprintf(noSessionMsg, clusterName), true
+		}
+		return fmt.Sprintf(sessionEndedMsg, clusterName), true
+	}
+	return sessionUsageMsg, true
+}
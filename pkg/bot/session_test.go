@@ -0,0 +1,112 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package bot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nlopes/slack"
+)
+
+func TestSlackSessionLifecycle(t *testing.T) {
+	defer func(s *sessionStore) { slackSessions = s }(slackSessions)
+	slackSessions = &sessionStore{}
+
+	var mu sync.Mutex
+	var replies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/conversations.info" {
+			_, _ = w.Write([]byte(`{"ok":true,"channel":{"id":"C1","name":"general","is_channel":true}}`))
+			return
+		}
+		mu.Lock()
+		replies = append(replies, r.FormValue("thread_ts")+" "+r.FormValue("text"))
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"ok":true,"channel":"C1","ts":"9.9"}`))
+	}))
+	defer ts.Close()
+
+	b := &SlackBot{ClusterName: "test", ChannelName: "general"}
+	api := slack.New("token", slack.OptionAPIURL(ts.URL+"/"))
+	handle := func(text, timestamp, threadTS string) {
+		sm := slackMessage{
+			Event:       &slack.MessageEvent{Msg: slack.Msg{Channel: "C1", User: "U1", Text: text, Timestamp: timestamp, ThreadTimestamp: threadTS}},
+			BotID:       "B1",
+			SlackClient: api,
+		}
+		sm.HandleMessage(b)
+	}
+
+	handle("<@B1> session start", "1.1", "")
+	handle("ping", "1.2", "1.1")
+	// Threads without session and the channel need the mention
+	handle("ping", "2.2", "2.1")
+	handle("ping", "3.1", "")
+	handle("<@B1> session end", "1.3", "1.1")
+	handle("ping", "1.4", "1.1")
+
+	// Replies are posted in the session thread
+	expected := []string{
+		"1.1 " + formatCodeBlock("Session started on cluster 'test'. Commands in this thread run without mentioning BotKube until `session end`."),
+		"1.1 ```\npong from cluster 'test'",
+		"1.1 " + formatCodeBlock("Session ended on cluster 'test'."),
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(replies) != len(expected) {
+		t.Fatalf("expected: %+v != actual: %+v\n", expected, replies)
+	}
+	for i := range expected {
+		if !strings.HasPrefix(replies[i], expected[i]) {
+			t.Errorf("expected: %+v != actual: %+v\n", expected[i], replies[i])
+		}
+	}
+}
+
+func TestSessionStore(t *testing.T) {
+	s := &sessionStore{}
+	now := time.Now()
+	if s.active("C1/1.1", now) {
+		t.Error("expected no session before start")
+	}
+	if !s.start("C1/1.1", now) || s.start("C1/1.1", now) {
+		t.Error("expected session to start once")
+	}
+	if !s.active("C1/1.1", now.Add(30*time.Minute)) {
+		t.Error("expected session to be active")
+	}
+	// Idle time starts again with every command
+	if !s.active("C1/1.1", now.Add(80*time.Minute)) {
+		t.Error("expected session to be active after a command")
+	}
+	if s.active("C1/1.1", now.Add(3*time.Hour)) {
+		t.Error("expected idle session to end")
+	}
+	s.start("C1/2.1", now)
+	if !s.end("C1/2.1") || s.end("C1/2.1") {
+		t.Error("expected session to end once")
+	}
+}
@@ -21,6 +21,7 @@ package bot
 
 import (
 	"strings"
+	"time"
 
 	"github.com/infracloudio/botkube/pkg/config"
 	"github.com/infracloudio/botkube/pkg/execute"
@@ -38,6 +39,11 @@ type SlackBot struct {
 	SlackURL         string
 	BotID            string
 	DefaultNamespace string
+	Mode             config.SlackMode
+	AppToken         string
+	SigningSecret    string
+	EventsPort       string
+	EventsPath       string
 }
 
 // slackMessage contains message details to execute command and send back the result
@@ -46,6 +52,7 @@ type slackMessage struct {
 	BotID         string
 	Request       string
 	Response      string
+	ContentType   execute.ContentType
 	IsAuthChannel bool
 	RTM           *slack.RTM
 	SlackClient   *slack.Client
@@ -60,10 +67,15 @@ func NewSlackBot(c *config.Config) Bot {
 		ClusterName:      c.Settings.ClusterName,
 		ChannelName:      c.Communications.Slack.Channel,
 		DefaultNamespace: c.Settings.Kubectl.DefaultNamespace,
+		Mode:             c.Communications.Slack.Mode,
+		AppToken:         c.Communications.Slack.AppToken,
+		SigningSecret:    c.Communications.Slack.SigningSecret,
+		EventsPort:       c.Communications.Slack.EventsPort,
+		EventsPath:       c.Communications.Slack.EventsPath,
 	}
 }
 
-// Start starts the slacknot RTM connection and listens for messages
+// Start starts the slacknot RTM or Socket Mode connection and listens for messages, or serves Events API requests
 func (b *SlackBot) Start() {
 	var botID string
 	api := slack.New(b.Token)
@@ -77,6 +89,17 @@ func (b *SlackBot) Start() {
 		}
 		botID = authResp.UserID
 	}
+	// Sends fail with missing_scope error, tell which scopes to add before any event is sent
+	b.checkScopes(api)
+
+	switch b.Mode {
+	case config.SlackSocketMode:
+		b.startSocketMode(api, botID)
+		return
+	case config.SlackEventsMode:
+		b.startEventsMode(api, botID)
+		return
+	}
 
 	RTM := api.NewRTM()
 	go RTM.ManageConnection()
@@ -127,13 +150,17 @@ func (b *SlackBot) Start() {
 }
 
 func (sm *slackMessage) HandleMessage(b *SlackBot) {
+	// Commands in session threads are served without the mention
+	threadTS := sm.Event.ThreadTimestamp
+	inSession := len(threadTS) != 0 && slackSessions.active(sessionKey(sm.Event.Channel, threadTS), time.Now())
+
 	// Check if message posted in authenticated channel
 	info, err := sm.SlackClient.GetConversationInfo(sm.Event.Channel, true)
 	if err == nil {
 		if info.IsChannel || info.IsPrivate {
 			// Message posted in a channel
 			// Serve only if starts with mention
-			if !strings.HasPrefix(sm.Event.Text, "<@"+sm.BotID+">") {
+			if !inSession && !strings.HasPrefix(sm.Event.Text, "<@"+sm.BotID+">") {
 				return
 			}
 			// Serve only if current channel is in config
@@ -150,12 +177,57 @@ func (sm *slackMessage) HandleMessage(b *SlackBot) {
 	// Trim the @BotKube prefix
 	sm.Request = strings.TrimPrefix(sm.Event.Text, "<@"+sm.BotID+">")
 
+	// session start in the channel opens a thread on the message
+	if len(threadTS) == 0 {
+		threadTS = sm.Event.Timestamp
+	}
+	if sm.IsAuthChannel {
+		if res, ok := slackSessions.runSessionCommand(sm.Request, sessionKey(sm.Event.Channel, threadTS), b.ClusterName); ok {
+			sm.Event.ThreadTimestamp = threadTS
+			sm.Response = res
+			sm.Send()
+			return
+		}
+	}
+
+	// Outputs and confirmations of session commands are kept apart from the rest of the channel
+	thread := ""
+	if inSession {
+		thread = threadTS
+	}
 	e := execute.NewDefaultExecutor(sm.Request, b.AllowKubectl, b.RestrictAccess, b.DefaultNamespace,
-		b.ClusterName, config.SlackBot, b.ChannelName, sm.IsAuthChannel)
-	sm.Response = e.Execute()
+		b.ClusterName, config.SlackBot, b.ChannelName, thread, sm.Event.User, sm.IsAuthChannel, sm.channelSender())
+	res := e.ExecuteResult()
+	sm.Response, sm.ContentType = res.Output, res.ContentType
 	sm.Send()
 }
 
+// channelSender returns sender to post messages in the channel where the command was received
+func (sm *slackMessage) channelSender() *execute.ChannelSender {
+	return &execute.ChannelSender{
+		Channel: sm.Event.Channel,
+		Send: func(msg string) error {
+			reply := *sm
+			reply.Response, reply.ContentType = msg, execute.ContentTypeText
+			reply.Send()
+			return nil
+		},
+		SendFile: sm.uploadFile,
+	}
+}
+
+// uploadFile uploads the content as a file to the channel where the command was received
+func (sm *slackMessage) uploadFile(name, content string) error {
+	params := slack.FileUploadParameters{
+		Filename: name,
+		Title:    name,
+		Content:  content,
+		Channels: []string{sm.Event.Channel},
+	}
+	_, err := sm.SlackClient.UploadFile(params)
+	return err
+}
+
 func (sm *slackMessage) Send() {
 	log.Debugf("Slack incoming Request: %s", sm.Request)
 	log.Debugf("Slack Response: %s", sm.Response)
@@ -165,27 +237,20 @@ func (sm *slackMessage) Send() {
 	}
 	// Upload message as a file if too long
 	if len(sm.Response) >= 3990 {
-		params := slack.FileUploadParameters{
-			Filename: sm.Request,
-			Title:    sm.Request,
-			Content:  sm.Response,
-			Channels: []string{sm.Event.Channel},
-		}
-		_, err := sm.RTM.UploadFile(params)
-		if err != nil {
+		if err := sm.uploadFile(sm.Request, sm.Response); err != nil {
 			log.Error("Error in uploading file:", err)
 		}
 		return
 	}
 
-	var options = []slack.MsgOption{slack.MsgOptionText(formatCodeBlock(sm.Response), false), slack.MsgOptionAsUser(true)}
+	var options = []slack.MsgOption{slack.MsgOptionText(formatResultBlock(sm.Response, sm.ContentType), false), slack.MsgOptionAsUser(true)}
 
 	//if the message is from thread then add an option to return the response to the thread
 	if sm.Event.ThreadTimestamp != "" {
 		options = append(options, slack.MsgOptionTS(sm.Event.ThreadTimestamp))
 	}
 
-	if _, _, err := sm.RTM.PostMessage(sm.Event.Channel, options...); err != nil {
+	if _, _, err := sm.SlackClient.PostMessage(sm.Event.Channel, options...); err != nil {
 		log.Error("Error in sending message:", err)
 	}
 }
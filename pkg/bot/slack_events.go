@@ -0,0 +1,187 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/nlopes/slack"
+)
+
+const (
+	defaultSlackEventsPort = "3000"
+	defaultSlackEventsPath = "/slack/events"
+	// maxSlackEventSize limits the request body read before the signature is verified
+	maxSlackEventSize = 1 << 20
+	// deliveredEventTTL is how long a message is remembered to skip its app_mention and message duplicates
+	deliveredEventTTL = 5 * time.Minute
+)
+
+// slackEventsRequest is the body of the Events API requests
+type slackEventsRequest struct {
+	Type      string          `json:"type"`
+	Challenge string          `json:"challenge"`
+	Event     json.RawMessage `json:"event"`
+}
+
+// slackEventsHandler verifies the Events API requests and passes the message events to handle
+type slackEventsHandler struct {
+	signingSecret string
+	botID         string
+	handle        func(ev *slack.MessageEvent)
+	delivered     deliveredEvents
+}
+
+// startEventsMode serves the Events API requests on eventsPort and eventsPath
+func (b *SlackBot) startEventsMode(api *slack.Client, botID string) {
+	if len(b.SigningSecret) == 0 {
+		log.Error("Slack signingSecret is required for events mode. BotKube won't receive Slack commands")
+		return
+	}
+	port, path := b.EventsPort, b.EventsPath
+	if len(port) == 0 {
+		port = defaultSlackEventsPort
+	}
+	if len(path) == 0 {
+		path = defaultSlackEventsPath
+	}
+	h := &slackEventsHandler{
+		signingSecret: b.SigningSecret,
+		botID:         botID,
+		handle: func(ev *slack.MessageEvent) {
+			sm := slackMessage{
+				Event:       ev,
+				BotID:       botID,
+				SlackClient: api,
+			}
+			sm.HandleMessage(b)
+		},
+	}
+	mux := http.NewServeMux()
+	mux.Handle(path, h)
+	log.Infof("Started Slack events API server on port %s", port)
+	log.Errorf("Error in Slack events API server. %v", http.ListenAndServe(fmt.Sprintf(":%s", port), mux))
+}
+
+// ServeHTTP answers url_verification challenges and runs the commands of message and app_mention events
+// Slack expects a response within 3 seconds, commands run after the request is acknowledged
+func (h *slackEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxSlackEventSize))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := verifySlackRequest(r.Header, body, h.signingSecret); err != nil {
+		log.Errorf("Rejected Slack events API request. %s", err.Error())
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	// Retries are sent if the acknowledgement was late, the command already runs
+	if len(r.Header.Get("X-Slack-Retry-Num")) != 0 {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var req slackEventsRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		log.Errorf("Slack events API unmarshalling error: %s", err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	switch req.Type {
+	case "url_verification":
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(req.Challenge))
+		return
+	case "event_callback":
+		ev, err := parseSlackEvent(req.Event)
+		if err != nil {
+			log.Errorf("Slack events API unmarshalling error: %s", err.Error())
+			break
+		}
+		// Skip if message posted by BotKube
+		if ev == nil || ev.User == h.botID || !h.delivered.first(ev.Channel+"/"+ev.Timestamp, time.Now()) {
+			break
+		}
+		go h.handle(ev)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// verifySlackRequest checks the request signature computed with the signing secret
+func verifySlackRequest(header http.Header, body []byte, signingSecret string) error {
+	verifier, err := slack.NewSecretsVerifier(header, signingSecret)
+	if err != nil {
+		return err
+	}
+	if _, err := verifier.Write(body); err != nil {
+		return err
+	}
+	return verifier.Ensure()
+}
+
+// parseSlackEvent returns the message of message and app_mention events, nil for other events
+func parseSlackEvent(data json.RawMessage) (*slack.MessageEvent, error) {
+	var ev slack.MessageEvent
+	if err := json.Unmarshal(data, &ev); err != nil {
+		return nil, err
+	}
+	// Ignore edits, deletes and other message subtypes
+	if ev.Type != "message" && ev.Type != "app_mention" || len(ev.SubType) != 0 || strings.TrimSpace(ev.Text) == "" {
+		return nil, nil
+	}
+	return &ev, nil
+}
+
+// deliveredEvents remembers the delivered messages, a mention in a channel is delivered as both app_mention and message
+type deliveredEvents struct {
+	mu       sync.Mutex
+	messages map[string]time.Time
+}
+
+// first checks if the message wasn't delivered before now and records it
+func (d *deliveredEvents) first(key string, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.messages == nil {
+		d.messages = make(map[string]time.Time)
+	}
+	for k, at := range d.messages {
+		if now.Sub(at) >= deliveredEventTTL {
+			delete(d.messages, k)
+		}
+	}
+	if _, ok := d.messages[key]; ok {
+		return false
+	}
+	d.messages[key] = now
+	return true
+}
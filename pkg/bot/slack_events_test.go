@@ -0,0 +1,195 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package bot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nlopes/slack"
+)
+
+const testSigningSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+// signedSlackRequest returns an Events API request signed with the secret at the timestamp
+func signedSlackRequest(body, secret string, at time.Time) *http.Request {
+	ts := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", ts, body)
+	req := httptest.NewRequest(http.MethodPost, defaultSlackEventsPath, strings.NewReader(body))
+	req.Header.Set("X-Slack-Request-Timestamp", ts)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestVerifySlackRequest(t *testing.T) {
+	body := `{"type":"url_verification","challenge":"abc"}`
+	tests := map[string]struct {
+		req   *http.Request
+		body  string
+		valid bool
+	}{
+		`valid signature`:   {signedSlackRequest(body, testSigningSecret, time.Now()), body, true},
+		`wrong secret`:      {signedSlackRequest(body, "other", time.Now()), body, false},
+		`modified body`:     {signedSlackRequest(body, testSigningSecret, time.Now()), `{"type":"url_verification","challenge":"xyz"}`, false},
+		`expired timestamp`: {signedSlackRequest(body, testSigningSecret, time.Now().Add(-10*time.Minute)), body, false},
+		`missing headers`:   {httptest.NewRequest(http.MethodPost, defaultSlackEventsPath, strings.NewReader(body)), body, false},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			err := verifySlackRequest(test.req.Header, []byte(test.body), testSigningSecret)
+			if valid := err == nil; valid != test.valid {
+				t.Errorf("expected: %+v != actual: %+v (%v)\n", test.valid, valid, err)
+			}
+		})
+	}
+}
+
+func TestSlackEventsHandler(t *testing.T) {
+	handled := make(chan string, 10)
+	h := &slackEventsHandler{
+		signingSecret: testSigningSecret,
+		botID:         "B1",
+		handle: func(ev *slack.MessageEvent) {
+			handled <- ev.Channel + " " + ev.User + " " + ev.Text
+		},
+	}
+	callback := func(event string) string {
+		return `{"type":"event_callback","event":` + event + `}`
+	}
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+		body   string
+	}{
+		{
+			name:   `url verification`,
+			req:    signedSlackRequest(`{"type":"url_verification","challenge":"3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P"}`, testSigningSecret, time.Now()),
+			status: http.StatusOK,
+			body:   "3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P",
+		},
+		{
+			name:   `invalid signature`,
+			req:    signedSlackRequest(callback(`{"type":"message","channel":"C1","user":"U1","text":"get pods","ts":"1.1"}`), "other", time.Now()),
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   `method not allowed`,
+			req:    httptest.NewRequest(http.MethodGet, defaultSlackEventsPath, nil),
+			status: http.StatusMethodNotAllowed,
+		},
+		{
+			name:   `app mention`,
+			req:    signedSlackRequest(callback(`{"type":"app_mention","channel":"C1","user":"U1","text":"<@B1> ping","ts":"2.1"}`), testSigningSecret, time.Now()),
+			status: http.StatusOK,
+		},
+		{
+			name:   `message duplicate of app mention`,
+			req:    signedSlackRequest(callback(`{"type":"message","channel":"C1","user":"U1","text":"<@B1> ping","ts":"2.1"}`), testSigningSecret, time.Now()),
+			status: http.StatusOK,
+		},
+		{
+			name:   `direct message`,
+			req:    signedSlackRequest(callback(`{"type":"message","channel":"D1","user":"U2","text":"get pods","ts":"3.1"}`), testSigningSecret, time.Now()),
+			status: http.StatusOK,
+		},
+		{
+			name:   `message of BotKube`,
+			req:    signedSlackRequest(callback(`{"type":"message","channel":"C1","user":"B1","text":"pong","ts":"4.1"}`), testSigningSecret, time.Now()),
+			status: http.StatusOK,
+		},
+		{
+			name:   `message subtype`,
+			req:    signedSlackRequest(callback(`{"type":"message","subtype":"message_changed","channel":"C1","user":"U1","text":"<@B1> ping","ts":"5.1"}`), testSigningSecret, time.Now()),
+			status: http.StatusOK,
+		},
+		{
+			name:   `invalid json`,
+			req:    signedSlackRequest(`{"type":`, testSigningSecret, time.Now()),
+			status: http.StatusBadRequest,
+		},
+	}
+	retry := signedSlackRequest(callback(`{"type":"message","channel":"C1","user":"U1","text":"<@B1> version","ts":"6.1"}`), testSigningSecret, time.Now())
+	retry.Header.Set("X-Slack-Retry-Num", "1")
+	tests = append(tests, struct {
+		name   string
+		req    *http.Request
+		status int
+		body   string
+	}{name: `retry`, req: retry, status: http.StatusOK})
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.req)
+		if w.Code != test.status || w.Body.String() != test.body {
+			t.Errorf("%s: expected: %d %q != actual: %d %q\n", test.name, test.status, test.body, w.Code, w.Body.String())
+		}
+	}
+
+	expected := map[string]bool{"C1 U1 <@B1> ping": true, "D1 U2 get pods": true}
+	actual := map[string]bool{}
+	for len(actual) < len(expected) {
+		select {
+		case msg := <-handled:
+			actual[msg] = true
+		case <-time.After(time.Second):
+			t.Fatalf("expected: %+v != actual: %+v\n", expected, actual)
+		}
+	}
+	select {
+	case msg := <-handled:
+		t.Errorf("unexpected message handled: %s", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
+
+func TestDeliveredEventsFirst(t *testing.T) {
+	var d deliveredEvents
+	now := time.Now()
+	tests := []struct {
+		key      string
+		at       time.Time
+		expected bool
+	}{
+		{"C1/1.1", now, true},
+		{"C1/1.1", now.Add(time.Minute), false},
+		{"C2/1.1", now.Add(time.Minute), true},
+		{"C1/1.1", now.Add(deliveredEventTTL), true},
+	}
+	for i, test := range tests {
+		if actual := d.first(test.key, test.at); actual != test.expected {
+			t.Errorf("%d: expected: %+v != actual: %+v\n", i, test.expected, actual)
+		}
+	}
+}
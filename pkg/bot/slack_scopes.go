@@ -0,0 +1,129 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/nlopes/slack"
+)
+
+const (
+	// slackClassicBotScope is granted to the classic Slack app bot tokens, it includes all the bot permissions
+	slackClassicBotScope  = "bot"
+	slackMissingScopesMsg = "Slack bot token is missing the scopes: %s. Please add them in OAuth & Permissions of the Slack app and reinstall it, otherwise BotKube fails to %s."
+)
+
+// slackScopes are the granular bot scopes BotKube needs
+var slackScopes = []struct {
+	scope string
+	usage string
+	// socketMode is true for the scopes of the events received in socket mode
+	socketMode bool
+}{
+	{scope: "chat:write", usage: "send notifications"},
+	{scope: "files:write", usage: "upload long command outputs"},
+	{scope: "channels:read", usage: "check the channels of commands"},
+	{scope: "app_mentions:read", usage: "receive commands", socketMode: true},
+	{scope: "channels:history", usage: "receive session commands", socketMode: true},
+}
+
+// checkScopes warns about the scopes missing in the bot token, the message is also posted to the channel if possible
+func (b *SlackBot) checkScopes(api *slack.Client) {
+	apiURL := slackAPIURL
+	if len(b.SlackURL) != 0 {
+		apiURL = b.SlackURL
+	}
+	granted, err := slackTokenScopes(apiURL, b.Token)
+	if err != nil {
+		log.Errorf("Failed to check the Slack bot token scopes. %s", err.Error())
+		return
+	}
+	missing, usages := missingSlackScopes(granted, b.Mode == config.SlackSocketMode)
+	if len(missing) == 0 {
+		return
+	}
+	msg := fmt.Sprintf(slackMissingScopesMsg, strings.Join(missing, ", "), strings.Join(usages, ", "))
+	log.Warn(msg)
+	if !granted["chat:write"] {
+		return
+	}
+	if _, _, err := api.PostMessage(b.ChannelName, slack.MsgOptionText(msg, false), slack.MsgOptionAsUser(true)); err != nil {
+		log.Errorf("Failed to send missing Slack scopes message. %s", err.Error())
+	}
+}
+
+// slackTokenScopes returns the scopes of the token from X-OAuth-Scopes header of auth.test response
+// nil is returned if the header is not set, e.g for legacy tokens
+func slackTokenScopes(apiURL, token string) (map[string]bool, error) {
+	req, err := http.NewRequest(http.MethodPost, apiURL+"auth.test", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res struct {
+		Ok    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	if !res.Ok {
+		return nil, fmt.Errorf("auth.test failed: %s", res.Error)
+	}
+	header := resp.Header.Get("X-OAuth-Scopes")
+	if len(header) == 0 {
+		return nil, nil
+	}
+	scopes := map[string]bool{}
+	for _, s := range strings.Split(header, ",") {
+		scopes[strings.TrimSpace(s)] = true
+	}
+	return scopes, nil
+}
+
+// missingSlackScopes returns the required scopes not granted with what fails without them
+// Nothing is missing if the scopes are unknown or the token is of a classic bot
+func missingSlackScopes(granted map[string]bool, socketMode bool) (missing []string, usages []string) {
+	if granted == nil || granted[slackClassicBotScope] {
+		return nil, nil
+	}
+	for _, s := range slackScopes {
+		if s.socketMode && !socketMode || granted[s.scope] {
+			continue
+		}
+		missing = append(missing, s.scope)
+		usages = append(usages, s.usage)
+	}
+	return missing, usages
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package bot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/nlopes/slack"
+)
+
+func TestSlackCheckScopes(t *testing.T) {
+	tests := map[string]struct {
+		scopes   string
+		authTest string
+		mode     config.SlackMode
+		expected []string
+	}{
+		`all scopes granted`: {
+			scopes:   "chat:write,files:write,channels:read",
+			authTest: `{"ok":true}`,
+		},
+		`missing scopes`: {
+			scopes:   "chat:write,channels:read",
+			authTest: `{"ok":true}`,
+			expected: []string{"Slack bot token is missing the scopes: files:write. Please add them in OAuth & Permissions of the Slack app and reinstall it, otherwise BotKube fails to upload long command outputs."},
+		},
+		`missing socket mode scopes`: {
+			scopes:   "chat:write, files:write, channels:read, app_mentions:read",
+			authTest: `{"ok":true}`,
+			mode:     config.SlackSocketMode,
+			expected: []string{"Slack bot token is missing the scopes: channels:history. Please add them in OAuth & Permissions of the Slack app and reinstall it, otherwise BotKube fails to receive session commands."},
+		},
+		// The message can't be posted without chat:write, it is only logged
+		`missing chat:write`: {
+			scopes:   "files:write",
+			authTest: `{"ok":true}`,
+		},
+		`classic bot token`: {
+			scopes:   "identify,bot",
+			authTest: `{"ok":true}`,
+		},
+		`unknown scopes`: {
+			authTest: `{"ok":true}`,
+		},
+		`invalid token`: {
+			scopes:   "chat:write",
+			authTest: `{"ok":false,"error":"invalid_auth"}`,
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			var posted []string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/auth.test":
+					if r.Header.Get("Authorization") != "Bearer xoxb-token" {
+						t.Errorf("unexpected authorization header %q", r.Header.Get("Authorization"))
+					}
+					if len(test.scopes) != 0 {
+						w.Header().Set("X-OAuth-Scopes", test.scopes)
+					}
+					_, _ = w.Write([]byte(test.authTest))
+				case "/chat.postMessage":
+					mu.Lock()
+					posted = append(posted, r.FormValue("text"))
+					mu.Unlock()
+					_, _ = w.Write([]byte(`{"ok":true,"channel":"C1","ts":"1.1"}`))
+				default:
+					t.Errorf("unexpected request %s", r.URL.Path)
+				}
+			}))
+			defer ts.Close()
+
+			b := &SlackBot{Token: "xoxb-token", ChannelName: "general", SlackURL: ts.URL + "/", Mode: test.mode}
+			b.checkScopes(slack.New(b.Token, slack.OptionAPIURL(b.SlackURL)))
+			mu.Lock()
+			defer mu.Unlock()
+			if !reflect.DeepEqual(test.expected, posted) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, posted)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/notify"
+	"github.com/infracloudio/botkube/pkg/utils"
+	"github.com/nlopes/slack"
+)
+
+const (
+	slackAPIURL = "https://slack.com/api/"
+	// socketModeRetryInterval is the time to wait before reconnecting to Slack Socket Mode
+	socketModeRetryInterval = 5 * time.Second
+)
+
+// socketModeEnvelope is the message received over Socket Mode WebSocket
+type socketModeEnvelope struct {
+	EnvelopeID string `json:"envelope_id"`
+	Type       string `json:"type"`
+	Payload    struct {
+		Event json.RawMessage `json:"event"`
+	} `json:"payload"`
+}
+
+// socketModeInteraction is the envelope of interactive component actions, e.g button clicks
+type socketModeInteraction struct {
+	Type    string                    `json:"type"`
+	Payload slack.InteractionCallback `json:"payload"`
+}
+
+// socketModeAck acknowledges the envelope, Slack retries the delivery otherwise
+type socketModeAck struct {
+	EnvelopeID string `json:"envelope_id"`
+}
+
+// startSocketMode receives messages over Socket Mode and reconnects when the connection is closed
+func (b *SlackBot) startSocketMode(api *slack.Client, botID string) {
+	if len(b.AppToken) == 0 {
+		log.Error("Slack appToken is required for socket mode. BotKube won't receive Slack commands")
+		return
+	}
+	for {
+		url, err := b.openSocketModeConnection()
+		if err != nil {
+			log.Errorf("Failed to open Slack socket mode connection. Retrying in %s. Error: %s", socketModeRetryInterval, err.Error())
+			time.Sleep(socketModeRetryInterval)
+			continue
+		}
+		if err := b.listenSocketMode(url, api, botID); err != nil {
+			log.Errorf("Slack socket mode connection closed. Reconnecting. Error: %s", err.Error())
+		}
+	}
+}
+
+// openSocketModeConnection returns the WebSocket URL to connect using the app-level token
+func (b *SlackBot) openSocketModeConnection() (string, error) {
+	apiURL := slackAPIURL
+	if len(b.SlackURL) != 0 {
+		apiURL = b.SlackURL
+	}
+	req, err := http.NewRequest(http.MethodPost, apiURL+"apps.connections.open", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+b.AppToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var res struct {
+		Ok    bool   `json:"ok"`
+		Error string `json:"error"`
+		URL   string `json:"url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return "", err
+	}
+	if !res.Ok {
+		return "", fmt.Errorf("apps.connections.open failed: %s", res.Error)
+	}
+	return res.URL, nil
+}
+
+// listenSocketMode handles envelopes until Slack asks to reconnect or the connection fails
+func (b *SlackBot) listenSocketMode(url string, api *slack.Client, botID string) error {
+	dialer := *websocket.DefaultDialer
+	dialer.Proxy = utils.Proxy
+	conn, _, err := dialer.Dial(url, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		ack, ev, reconnect, err := parseSocketModeEnvelope(data)
+		if err != nil {
+			log.Errorf("Slack socket mode unmarshalling error: %s", err.Error())
+			continue
+		}
+		if ack != nil {
+			if err := conn.WriteJSON(ack); err != nil {
+				return err
+			}
+		}
+		if reconnect {
+			log.Info("Slack requested socket mode reconnect")
+			return nil
+		}
+		if interaction := parseSocketModeInteraction(data); interaction != nil {
+			handleAckInteraction(api, interaction)
+			continue
+		}
+		// Skip if message posted by BotKube
+		if ev == nil || ev.User == botID {
+			continue
+		}
+		sm := slackMessage{
+			Event:       ev,
+			BotID:       botID,
+			SlackClient: api,
+		}
+		sm.HandleMessage(b)
+	}
+}
+
+// parseSocketModeEnvelope returns the acknowledgement to send and the message event, if any
+func parseSocketModeEnvelope(data []byte) (*socketModeAck, *slack.MessageEvent, bool, error) {
+	var envelope socketModeEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, nil, false, err
+	}
+
+	var ack *socketModeAck
+	if len(envelope.EnvelopeID) != 0 {
+		ack = &socketModeAck{EnvelopeID: envelope.EnvelopeID}
+	}
+
+	switch envelope.Type {
+	case "hello":
+		log.Info("BotKube connected to Slack using socket mode!")
+	case "disconnect":
+		return ack, nil, true, nil
+	case "events_api":
+		var ev slack.MessageEvent
+		if err := json.Unmarshal(envelope.Payload.Event, &ev); err != nil {
+			return ack, nil, false, err
+		}
+		// Ignore edits, deletes and other message subtypes
+		if ev.Type != "message" || len(ev.SubType) != 0 || strings.TrimSpace(ev.Text) == "" {
+			return ack, nil, false, nil
+		}
+		return ack, &ev, false, nil
+	}
+	return ack, nil, false, nil
+}
+
+// parseSocketModeInteraction returns the interaction callback if the envelope contains one
+func parseSocketModeInteraction(data []byte) *slack.InteractionCallback {
+	var envelope socketModeInteraction
+	if err := json.Unmarshal(data, &envelope); err != nil || envelope.Type != "interactive" {
+		return nil
+	}
+	return &envelope.Payload
+}
+
+// handleAckInteraction posts the acknowledgement in the thread of the notification when Acknowledge button is clicked
+func handleAckInteraction(api *slack.Client, callback *slack.InteractionCallback) {
+	msg, ok := ackMessage(callback)
+	if !ok {
+		return
+	}
+	if _, _, err := api.PostMessage(callback.Channel.ID, slack.MsgOptionText(msg, false),
+		slack.MsgOptionTS(callback.MessageTs), slack.MsgOptionAsUser(true)); err != nil {
+		log.Errorf("Failed to post acknowledgement. Error: %s", err.Error())
+	}
+}
+
+// ackMessage returns the acknowledgement message for Acknowledge button clicks
+func ackMessage(callback *slack.InteractionCallback) (string, bool) {
+	if callback.CallbackID != notify.SlackAckCallbackID {
+		return "", false
+	}
+	for _, action := range callback.ActionCallback.AttachmentActions {
+		if action.Name == notify.SlackAckActionName {
+			return fmt.Sprintf("Alert for %s acknowledged by <@%s>", action.Value, callback.User.ID), true
+		}
+	}
+	return "", false
+}
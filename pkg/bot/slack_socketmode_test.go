@@ -0,0 +1,125 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package bot
+
+import (
+	"testing"
+)
+
+func TestParseSocketModeEnvelope(t *testing.T) {
+	tests := map[string]struct {
+		data      string
+		ack       string
+		text      string
+		reconnect bool
+		err       bool
+	}{
+		`hello`: {
+			data: `{"type":"hello","num_connections":1}`,
+		},
+		`message event`: {
+			data: `{"envelope_id":"1","type":"events_api","payload":{"event":{"type":"message","channel":"C1","user":"U1","text":"<@B1> ping","ts":"1.1"}}}`,
+			ack:  "1",
+			text: "<@B1> ping",
+		},
+		`message subtype is ignored`: {
+			data: `{"envelope_id":"2","type":"events_api","payload":{"event":{"type":"message","subtype":"message_changed","channel":"C1"}}}`,
+			ack:  "2",
+		},
+		`other event is ignored`: {
+			data: `{"envelope_id":"3","type":"events_api","payload":{"event":{"type":"reaction_added","user":"U1"}}}`,
+			ack:  "3",
+		},
+		`disconnect`: {
+			data:      `{"type":"disconnect","reason":"refresh_requested"}`,
+			reconnect: true,
+		},
+		`invalid json`: {
+			data: `{"type":`,
+			err:  true,
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			ack, ev, reconnect, err := parseSocketModeEnvelope([]byte(test.data))
+			if test.err {
+				if err == nil {
+					t.Errorf("expected error for %s", test.data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			actualAck := ""
+			if ack != nil {
+				actualAck = ack.EnvelopeID
+			}
+			actualText := ""
+			if ev != nil {
+				actualText = ev.Text
+			}
+			if actualAck != test.ack || actualText != test.text || reconnect != test.reconnect {
+				t.Errorf("expected: %+v, %+v, %+v != actual: %+v, %+v, %+v\n", test.ack, test.text, test.reconnect, actualAck, actualText, reconnect)
+			}
+		})
+	}
+}
+
+func TestAckInteraction(t *testing.T) {
+	tests := map[string]struct {
+		data     string
+		expected string
+		ok       bool
+	}{
+		`acknowledge button`: {
+			data:     `{"envelope_id":"1","type":"interactive","payload":{"type":"interactive_message","callback_id":"botkube_ack","channel":{"id":"C1"},"user":{"id":"U1","name":"alice"},"message_ts":"1.1","actions":[{"name":"ack","type":"button","value":"Pod default/nginx"}]}}`,
+			expected: "Alert for Pod default/nginx acknowledged by <@U1>",
+			ok:       true,
+		},
+		`other callback`: {
+			data: `{"envelope_id":"2","type":"interactive","payload":{"type":"interactive_message","callback_id":"other","channel":{"id":"C1"},"user":{"id":"U1"},"actions":[{"name":"ack","type":"button","value":"Pod default/nginx"}]}}`,
+		},
+		`other action`: {
+			data: `{"envelope_id":"3","type":"interactive","payload":{"type":"interactive_message","callback_id":"botkube_ack","channel":{"id":"C1"},"user":{"id":"U1"},"actions":[{"name":"other","type":"button"}]}}`,
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			interaction := parseSocketModeInteraction([]byte(test.data))
+			if interaction == nil {
+				t.Fatalf("expected interaction in %s", test.data)
+			}
+			actual, ok := ackMessage(interaction)
+			if actual != test.expected || ok != test.ok {
+				t.Errorf("expected: %+v, %+v != actual: %+v, %+v\n", test.expected, test.ok, actual, ok)
+			}
+			if interaction.Channel.ID != "C1" {
+				t.Errorf("expected: %+v != actual: %+v\n", "C1", interaction.Channel.ID)
+			}
+		})
+	}
+
+	if interaction := parseSocketModeInteraction([]byte(`{"envelope_id":"4","type":"events_api","payload":{"event":{"type":"message"}}}`)); interaction != nil {
+		t.Errorf("expected no interaction for events_api envelope")
+	}
+}
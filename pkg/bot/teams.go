@@ -26,7 +26,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
+	"sync"
 
 	"github.com/infracloudio/botkube/pkg/config"
 	"github.com/infracloudio/botkube/pkg/events"
@@ -69,6 +71,11 @@ type Teams struct {
 	DefaultNamespace string
 
 	ConversationRef *schema.ConversationReference
+
+	minSeverity config.Level
+	// conversationRefPath is the file ConversationRef is saved in to send notifications after restart
+	conversationRefPath string
+	refMu               sync.Mutex
 }
 
 type consentContext struct {
@@ -87,6 +94,18 @@ func NewTeamsBot(c *config.Config) *Teams {
 	if msgPath == "" {
 		msgPath = "/"
 	}
+	// Notifications are sent to the saved conversation without waiting for notifier start
+	var ref *schema.ConversationReference
+	if path := c.Communications.Teams.ConversationRefPath; len(path) != 0 {
+		var err error
+		if ref, err = loadConversationRef(path); err != nil {
+			log.Errorf("Failed to load MS Teams conversation reference from %s. Send notifier start to enable notifications. %s", path, err.Error())
+		}
+		if ref != nil {
+			log.Infof("Loaded MS Teams conversation reference from %s", path)
+			config.Notify = true
+		}
+	}
 	return &Teams{
 		AppID:            c.Communications.Teams.AppID,
 		AppPassword:      c.Communications.Teams.AppPassword,
@@ -97,9 +116,19 @@ func NewTeamsBot(c *config.Config) *Teams {
 		RestrictAccess:   c.Settings.Kubectl.RestrictAccess,
 		DefaultNamespace: c.Settings.Kubectl.DefaultNamespace,
 		ClusterName:      c.Settings.ClusterName,
+
+		ConversationRef: ref,
+
+		minSeverity:         c.Communications.Teams.MinSeverity,
+		conversationRefPath: c.Communications.Teams.ConversationRefPath,
 	}
 }
 
+// MinSeverity returns minimum level of events sent to MS Teams
+func (t *Teams) MinSeverity() config.Level {
+	return t.minSeverity
+}
+
 // Start MS Teams server to serve messages from Teams client
 func (t *Teams) Start() {
 	var err error
@@ -200,7 +229,7 @@ func (t *Teams) processActivity(w http.ResponseWriter, req *http.Request) {
 
 			msg := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(consentCtx.Command), "<at>BotKube</at>"))
 			e := execute.NewDefaultExecutor(msg, t.AllowKubectl, t.RestrictAccess, t.DefaultNamespace,
-				t.ClusterName, config.TeamsBot, "", true)
+				t.ClusterName, config.TeamsBot, "", "", turn.Activity.From.ID, true, nil)
 			out := e.Execute()
 
 			actJSON, _ := json.MarshalIndent(turn.Activity, "", "  ")
@@ -241,24 +270,30 @@ func (t *Teams) processMessage(activity schema.Activity) string {
 	args := strings.Fields(msg)
 	if activity.Conversation.ConversationType != convTypePersonal && len(args) > 0 && execute.ValidNotifierCommand[args[0]] {
 		if len(args) < 2 {
-			return execute.IncompleteCmdMsg
+			return execute.IncompleteCmdMsg(config.TeamsBot)
 		}
 		if execute.Start.String() == args[1] {
 			config.Notify = true
 			ref := coreActivity.GetCoversationReference(activity)
-			t.ConversationRef = &ref
 			// Remove messageID from the ChannelID
 			if ID, ok := activity.ChannelData["teamsChannelId"]; ok {
-				t.ConversationRef.ChannelID = ID.(string)
-				t.ConversationRef.Conversation.ID = ID.(string)
+				ref.ChannelID = ID.(string)
+				ref.Conversation.ID = ID.(string)
 			}
+			t.setConversationRef(&ref)
 			return fmt.Sprintf(execute.NotifierStartMsg, t.ClusterName)
 		}
+		// Stopped notifications stay off after restart
+		if execute.Stop.String() == args[1] && len(t.conversationRefPath) != 0 {
+			if err := os.Remove(t.conversationRefPath); err != nil && !os.IsNotExist(err) {
+				log.Errorf("Failed to remove MS Teams conversation reference file. %s", err.Error())
+			}
+		}
 	}
 
 	// Multicluster is not supported for Teams
 	e := execute.NewDefaultExecutor(msg, t.AllowKubectl, t.RestrictAccess, t.DefaultNamespace,
-		t.ClusterName, config.TeamsBot, "", true)
+		t.ClusterName, config.TeamsBot, "", "", activity.From.ID, true, nil)
 	return formatCodeBlock(e.Execute())
 }
 
@@ -290,7 +325,16 @@ func (t *Teams) putRequest(u string, data []byte) error {
 func (t *Teams) SendEvent(event events.Event) error {
 	card := formatTeamsMessage(event, t.NotifType)
 	if err := t.sendProactiveMessage(card); err != nil {
-		log.Errorf("Failed to send notification. %s", err.Error())
+		if isInvalidConversationRef(err) {
+			t.checkConversationRef(err)
+			return err
+		}
+		// Card may be rejected, e.g if it is too large. Send the event as plain text instead
+		log.Warnf("Failed to send notification card, sending plain text. %s", err.Error())
+		if err := t.SendMessage(teamsPlainText(event)); err != nil {
+			log.Errorf("Failed to send notification. %s", err.Error())
+			return err
+		}
 	}
 	log.Debugf("Event successfully sent to MS Teams >> %+v", event)
 	return nil
@@ -298,28 +342,36 @@ func (t *Teams) SendEvent(event events.Event) error {
 
 // SendMessage sends message to MsTeams
 func (t *Teams) SendMessage(msg string) error {
-	if t.ConversationRef == nil {
+	ref := t.conversationRef()
+	if ref == nil {
 		log.Infof("Skipping SendMessage since conversation ref not set")
 		return nil
 	}
-	err := t.Adapter.ProactiveMessage(context.TODO(), *t.ConversationRef, coreActivity.HandlerFuncs{
+	err := t.Adapter.ProactiveMessage(context.TODO(), *ref, coreActivity.HandlerFuncs{
 		OnMessageFunc: func(turn *coreActivity.TurnContext) (schema.Activity, error) {
 			return turn.SendActivity(coreActivity.MsgOptionText(msg))
 		},
 	})
 	if err != nil {
+		t.checkConversationRef(err)
 		return err
 	}
 	log.Debug("Message successfully sent to MS Teams")
 	return nil
 }
 
+// Ready returns nil since MS Teams connects to BotKube when a message is sent to the bot
+func (t *Teams) Ready() error {
+	return nil
+}
+
 func (t *Teams) sendProactiveMessage(card map[string]interface{}) error {
-	if t.ConversationRef == nil {
+	ref := t.conversationRef()
+	if ref == nil {
 		log.Infof("Skipping SendMessage since conversation ref not set")
 		return nil
 	}
-	err := t.Adapter.ProactiveMessage(context.TODO(), *t.ConversationRef, coreActivity.HandlerFuncs{
+	err := t.Adapter.ProactiveMessage(context.TODO(), *ref, coreActivity.HandlerFuncs{
 		OnMessageFunc: func(turn *coreActivity.TurnContext) (schema.Activity, error) {
 			attachments := []schema.Attachment{
 				{
@@ -52,7 +52,7 @@ func teamsShortNotification(event events.Event) map[string]interface{} {
 			},
 			{
 				"type": "TextBlock",
-				"text": strings.ReplaceAll(notify.FormatShortMessage(event), "```", ""),
+				"text": teamsPlainText(event),
 				"wrap": true,
 			},
 		},
@@ -75,12 +75,17 @@ func teamsLongNotification(event events.Event) map[string]interface{} {
 		})
 	}
 
+	sectionFacts = append(sectionFacts, fact{
+		"title": "Kind",
+		"value": event.Kind,
+	})
+
 	sectionFacts = append(sectionFacts, fact{
 		"title": "Name",
 		"value": event.Name,
 	})
 
-	if event.Namespace != "" {
+	if event.Namespace != "" && !notify.IsClusterScoped(event) {
 		sectionFacts = append(sectionFacts, fact{
 			"title": "Namespace",
 			"value": event.Namespace,
@@ -105,36 +110,21 @@ func teamsLongNotification(event events.Event) map[string]interface{} {
 		})
 	}
 
-	if event.Action != "" {
+	if len(event.Diff) > 0 {
 		sectionFacts = append(sectionFacts, fact{
-			"title": "Action",
-			"value": event.Action,
+			"title": "Diff",
+			"value": event.Diff,
 		})
 	}
 
-	if len(event.Recommendations) > 0 {
-		rec := ""
-		for _, r := range event.Recommendations {
-			rec = rec + r + "\n"
-		}
-		sectionFacts = append(sectionFacts, fact{
-			"title": "Recommendations",
-			"value": rec,
-		})
-	}
-
-	if len(event.Warnings) > 0 {
-		warn := ""
-		for _, w := range event.Warnings {
-			warn = warn + w + "\n"
-		}
+	if event.Action != "" {
 		sectionFacts = append(sectionFacts, fact{
-			"title": "Warnings",
-			"value": warn,
+			"title": "Action",
+			"value": event.Action,
 		})
 	}
 
-	card["body"] = []map[string]interface{}{
+	body := []map[string]interface{}{
 		{
 			"type":  "TextBlock",
 			"text":  event.Title,
@@ -146,5 +136,37 @@ func teamsLongNotification(event events.Event) map[string]interface{} {
 			"facts": sectionFacts,
 		},
 	}
+	body = append(body, teamsListSection("Recommendations", event.Recommendations)...)
+	body = append(body, teamsListSection("Warnings", event.Warnings)...)
+	card["body"] = body
 	return card
 }
+
+// teamsListSection returns the card elements showing items as a list under the title, nil if there are no items
+func teamsListSection(title string, items []string) []map[string]interface{} {
+	if len(items) == 0 {
+		return nil
+	}
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		list = append(list, "- "+item)
+	}
+	return []map[string]interface{}{
+		{
+			"type":      "TextBlock",
+			"text":      title,
+			"weight":    "Bolder",
+			"separator": true,
+		},
+		{
+			"type": "TextBlock",
+			"text": strings.Join(list, "\n"),
+			"wrap": true,
+		},
+	}
+}
+
+// teamsPlainText returns the event as plain text, it is sent if the card can't be sent
+func teamsPlainText(event events.Event) string {
+	return strings.ReplaceAll(notify.FormatShortMessage(event), "```", "")
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package bot
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+func TestTeamsLongNotification(t *testing.T) {
+	event := events.Event{
+		Title:           "Pod nginx is in BackOff",
+		Kind:            "Pod",
+		Name:            "nginx",
+		Namespace:       "default",
+		Reason:          "BackOff",
+		Level:           config.Critical,
+		Recommendations: []string{"Check the logs", "Set resource limits"},
+	}
+	card := teamsLongNotification(event)
+	body := card["body"].([]map[string]interface{})
+
+	if color := body[0]["color"]; color != "attention" {
+		t.Errorf("expected: attention != actual: %+v\n", color)
+	}
+	expectedFacts := []fact{
+		{"title": "Kind", "value": "Pod"},
+		{"title": "Name", "value": "nginx"},
+		{"title": "Namespace", "value": "default"},
+		{"title": "Reason", "value": "BackOff"},
+	}
+	if facts := body[1]["facts"]; !reflect.DeepEqual(facts, expectedFacts) {
+		t.Errorf("expected: %+v != actual: %+v\n", expectedFacts, facts)
+	}
+	if len(body) != 4 || body[2]["text"] != "Recommendations" || body[3]["text"] != "- Check the logs\n- Set resource limits" {
+		t.Errorf("expected recommendations section != actual: %+v\n", body[2:])
+	}
+
+	// No section without recommendations
+	event.Recommendations = nil
+	event.Level = config.Warn
+	body = teamsLongNotification(event)["body"].([]map[string]interface{})
+	if len(body) != 2 || body[0]["color"] != "warning" {
+		t.Errorf("expected title and facts only != actual: %+v\n", body)
+	}
+}
+
+func TestTeamsListSection(t *testing.T) {
+	if section := teamsListSection("Warnings", nil); section != nil {
+		t.Errorf("expected: nil != actual: %+v\n", section)
+	}
+}
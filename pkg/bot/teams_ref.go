@@ -0,0 +1,119 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/msbotbuilder-go/schema"
+	"github.com/infracloudio/msbotbuilder-go/schema/customerror"
+)
+
+const conversationRefInvalidMsg = "MS Teams conversation reference is no longer valid. Send `notifier start` to BotKube in the channel to capture it again"
+
+// loadConversationRef reads the conversation reference saved in the file, nil is returned if the file doesn't exist
+func loadConversationRef(path string) (*schema.ConversationReference, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ref := &schema.ConversationReference{}
+	if err := json.Unmarshal(data, ref); err != nil {
+		return nil, err
+	}
+	return ref, nil
+}
+
+// saveConversationRef writes the conversation reference to the file
+// The file is replaced atomically so that a crash doesn't leave a partial reference behind
+func saveConversationRef(path string, ref schema.ConversationReference) error {
+	data, err := json.Marshal(ref)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// isInvalidConversationRef checks if MS Teams rejected the proactive message because the conversation is not accessible
+// e.g the bot is removed from the team or the channel is deleted
+func isInvalidConversationRef(err error) bool {
+	var httpErr customerror.HTTPError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	return httpErr.StatusCode == http.StatusForbidden || httpErr.StatusCode == http.StatusNotFound
+}
+
+// conversationRef returns the reference of the conversation notifications are sent to, nil if not set
+func (t *Teams) conversationRef() *schema.ConversationReference {
+	t.refMu.Lock()
+	defer t.refMu.Unlock()
+	return t.ConversationRef
+}
+
+// setConversationRef sets the conversation to send notifications to and saves it if conversationRefPath is set
+// nil ref removes the saved reference
+func (t *Teams) setConversationRef(ref *schema.ConversationReference) {
+	t.refMu.Lock()
+	t.ConversationRef = ref
+	t.refMu.Unlock()
+	if len(t.conversationRefPath) == 0 {
+		return
+	}
+	if ref == nil {
+		if err := os.Remove(t.conversationRefPath); err != nil && !os.IsNotExist(err) {
+			log.Errorf("Failed to remove MS Teams conversation reference file. %s", err.Error())
+		}
+		return
+	}
+	if err := saveConversationRef(t.conversationRefPath, *ref); err != nil {
+		log.Errorf("Failed to save MS Teams conversation reference. Notifications stop on restart until notifier start is sent again. %s", err.Error())
+	}
+}
+
+// checkConversationRef clears the conversation reference if err shows it is no longer valid
+func (t *Teams) checkConversationRef(err error) {
+	if !isInvalidConversationRef(err) {
+		return
+	}
+	log.Warn(conversationRefInvalidMsg)
+	t.setConversationRef(nil)
+}
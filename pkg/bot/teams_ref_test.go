@@ -0,0 +1,103 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/infracloudio/botkube/pkg/config"
+
+	"github.com/infracloudio/msbotbuilder-go/schema"
+	"github.com/infracloudio/msbotbuilder-go/schema/customerror"
+)
+
+func TestConversationRefPersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "teams")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "conversation-ref.json")
+
+	defer func(notify bool) { config.Notify = notify }(config.Notify)
+
+	// Nothing is loaded before notifier start
+	c := &config.Config{}
+	c.Communications.Teams.ConversationRefPath = path
+	if bot := NewTeamsBot(c); bot.conversationRef() != nil {
+		t.Fatalf("expected no conversation ref != actual: %+v", bot.conversationRef())
+	}
+
+	ref := &schema.ConversationReference{
+		ChannelID:  "19:channel@thread.skype",
+		ServiceURL: "https://smba.trafficmanager.net/emea/",
+		Conversation: schema.ConversationAccount{
+			ID: "19:channel@thread.skype",
+		},
+	}
+	bot := NewTeamsBot(c)
+	bot.setConversationRef(ref)
+
+	// Reference is available after restart
+	config.Notify = false
+	restarted := NewTeamsBot(c)
+	if !reflect.DeepEqual(restarted.conversationRef(), ref) {
+		t.Errorf("expected: %+v != actual: %+v\n", ref, restarted.conversationRef())
+	}
+	if !config.Notify {
+		t.Error("expected notifications to be enabled with saved conversation ref")
+	}
+
+	// Invalid reference is removed to capture it again
+	restarted.checkConversationRef(fmt.Errorf("Failed to send response.: %w", customerror.HTTPError{StatusCode: 403, HtErr: errors.New("invalid response")}))
+	if restarted.conversationRef() != nil {
+		t.Errorf("expected invalid conversation ref to be cleared")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected conversation ref file to be removed, error: %v", err)
+	}
+}
+
+func TestIsInvalidConversationRef(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected bool
+	}{
+		`forbidden`:       {customerror.HTTPError{StatusCode: 403}, true},
+		`not found`:       {fmt.Errorf("Failed to send response.: %w", customerror.HTTPError{StatusCode: 404}), true},
+		`server error`:    {customerror.HTTPError{StatusCode: 500}, false},
+		`other error`:     {errors.New("connection reset"), false},
+		`no error at all`: {nil, false},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := isInvalidConversationRef(test.err); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
@@ -22,8 +22,19 @@ package bot
 import (
 	"fmt"
 	"strings"
+
+	"github.com/infracloudio/botkube/pkg/execute"
 )
 
 func formatCodeBlock(msg string) string {
 	return fmt.Sprintf("```\n%s\n```", strings.TrimSpace(msg))
 }
+
+// formatResultBlock formats the command output as code block with the language hint of its content type
+func formatResultBlock(msg string, contentType execute.ContentType) string {
+	switch contentType {
+	case execute.ContentTypeYAML, execute.ContentTypeJSON:
+		return fmt.Sprintf("```%s\n%s\n```", contentType, strings.TrimSpace(msg))
+	}
+	return formatCodeBlock(msg)
+}
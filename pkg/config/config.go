@@ -20,9 +20,14 @@
 package config
 
 import (
+	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
@@ -138,16 +143,72 @@ type CommunicationsConfig struct {
 	Webhook       Webhook
 	Teams         Teams
 	ElasticSearch ElasticSearch
+	Jira          Jira
 }
 
 // Slack configuration to authentication and send notifications
 type Slack struct {
-	Enabled   bool
-	Channel   string
-	NotifType NotifType `yaml:",omitempty"`
-	Token     string    `yaml:",omitempty"`
+	Enabled     bool
+	Channel     string
+	NotifType   NotifType `yaml:",omitempty"`
+	Token       string    `yaml:",omitempty"`
+	MinSeverity Level     `yaml:"minSeverity,omitempty"`
+	// ChannelSeverity is a map of channel name to the minimum level of events sent to the channel, MinSeverity is used for the other channels
+	ChannelSeverity map[string]Level `yaml:"channelSeverity,omitempty"`
+	// Mode selects how BotKube receives commands, SlackRTMMode by default
+	Mode SlackMode `yaml:",omitempty"`
+	// AppToken is the app-level token required for SlackSocketMode
+	AppToken string `yaml:"appToken,omitempty"`
+	// SigningSecret verifies the requests received in SlackEventsMode
+	SigningSecret string `yaml:"signingSecret,omitempty"`
+	// EventsPort and EventsPath are where the Events API requests are served in SlackEventsMode
+	EventsPort string `yaml:"eventsPort,omitempty"`
+	EventsPath string `yaml:"eventsPath,omitempty"`
+	// AckButton adds Acknowledge button to error and critical event notifications, it requires SlackSocketMode
+	AckButton bool `yaml:"ackButton,omitempty"`
+	// Mentions is a map of channel name to the mention added to notifications sent to the channel
+	Mentions map[string]SlackMention `yaml:"mentions,omitempty"`
+	// Colors overrides the attachment color of the levels with hex values, e.g #2eb886
+	Colors map[Level]string `yaml:"colors,omitempty"`
+	// UpdateInPlace edits the last notification of a resource with its create and update events instead of posting new ones
+	UpdateInPlace bool `yaml:"updateInPlace,omitempty"`
+	// ThreadEvents posts events sharing a correlation ID as replies to the first notification of the ID
+	ThreadEvents bool `yaml:"threadEvents,omitempty"`
+	// MaxSendsPerMinute limits the messages posted to each channel, the limit is disabled if 0
+	MaxSendsPerMinute int `yaml:"maxSendsPerMinute,omitempty"`
+	// ThrottleMode selects what happens to the messages over MaxSendsPerMinute, SlackThrottleQueue by default
+	ThrottleMode SlackThrottleMode `yaml:"throttleMode,omitempty"`
+}
+
+// SlackMention pings users in the channel for events at or above the severity
+type SlackMention struct {
+	// Text is the mention, e.g <!here>, <!channel> or <!subteam^ID> for a user group
+	Text        string
+	MinSeverity Level `yaml:"minSeverity,omitempty"`
 }
 
+// SlackMode is the transport used to receive Slack messages
+type SlackMode string
+
+const (
+	// SlackRTMMode receives messages using Real Time Messaging API
+	SlackRTMMode SlackMode = "rtm"
+	// SlackSocketMode receives messages over an outbound WebSocket using Socket Mode
+	SlackSocketMode SlackMode = "socket"
+	// SlackEventsMode receives messages as Events API requests, Slack needs to reach the BotKube endpoint
+	SlackEventsMode SlackMode = "events"
+)
+
+// SlackThrottleMode is how the messages over communications.slack.maxSendsPerMinute are handled
+type SlackThrottleMode string
+
+const (
+	// SlackThrottleQueue delays the messages in order until the channel is under the limit
+	SlackThrottleQueue SlackThrottleMode = "queue"
+	// SlackThrottleDrop drops the messages and posts how many were dropped once the channel is under the limit
+	SlackThrottleDrop SlackThrottleMode = "drop"
+)
+
 // ElasticSearch config auth settings
 type ElasticSearch struct {
 	Enabled       bool
@@ -157,6 +218,21 @@ type ElasticSearch struct {
 	SkipTLSVerify bool       `yaml:"skipTLSVerify"`
 	AWSSigning    AWSSigning `yaml:"awsSigning"`
 	Index         Index
+	MinSeverity   Level `yaml:"minSeverity,omitempty"`
+	// Bulk buffers the events and indexes them together with the _bulk API
+	Bulk ElasticSearchBulk `yaml:"bulk,omitempty"`
+}
+
+// ElasticSearchBulk contains the thresholds at which the buffered events are sent
+// Requests are compressed with gzip unless AWS signing is enabled
+type ElasticSearchBulk struct {
+	Enabled bool
+	// Actions is the number of buffered events which triggers a request, defaults to 100
+	Actions int `yaml:"actions,omitempty"`
+	// Size is the size in bytes of the buffered events which triggers a request, defaults to 5MB
+	Size int `yaml:"size,omitempty"`
+	// FlushInterval is the maximum time an event is buffered, defaults to 5s
+	FlushInterval time.Duration `yaml:"flushInterval,omitempty"`
 }
 
 // AWSSigning contains AWS configurations
@@ -183,6 +259,11 @@ type Mattermost struct {
 	Team      string
 	Channel   string
 	NotifType NotifType `yaml:",omitempty"`
+	// ThreadEvents posts events of the same resource as replies in a thread
+	ThreadEvents bool  `yaml:"threadEvents,omitempty"`
+	MinSeverity  Level `yaml:"minSeverity,omitempty"`
+	// ChannelSeverity is a map of channel name to the minimum level of events sent to the channel, MinSeverity is used for the other channels
+	ChannelSeverity map[string]Level `yaml:"channelSeverity,omitempty"`
 }
 
 // Teams creds for authentication with MS Teams
@@ -194,21 +275,46 @@ type Teams struct {
 	Port        string
 	MessagePath string
 	NotifType   NotifType `yaml:",omitempty"`
+	MinSeverity Level     `yaml:"minSeverity,omitempty"`
+	// ConversationRefPath is the file the conversation captured with notifier start is saved in
+	// Notifications are sent to it after restart without sending notifier start again
+	ConversationRefPath string `yaml:"conversationRefPath,omitempty"`
 }
 
 // Discord configuration for authentication and send notifications
 type Discord struct {
-	Enabled   bool
-	Token     string
-	BotID     string
-	Channel   string
-	NotifType NotifType `yaml:",omitempty"`
+	Enabled     bool
+	Token       string
+	BotID       string
+	Channel     string
+	NotifType   NotifType `yaml:",omitempty"`
+	MinSeverity Level     `yaml:"minSeverity,omitempty"`
 }
 
 // Webhook configuration to send notifications
 type Webhook struct {
+	Enabled     bool
+	URL         string
+	MinSeverity Level `yaml:"minSeverity,omitempty"`
+}
+
+// Jira configuration to create issues for events
+type Jira struct {
 	Enabled bool
-	URL     string
+	// URL is the base URL of Jira, e.g https://example.atlassian.net
+	URL string
+	// Username is used with Token as API token for basic auth, Token is sent as bearer token if Username is empty
+	Username string `yaml:",omitempty"`
+	Token    string
+	// Project is the key of the project issues are created in
+	Project string
+	// IssueType is the type of created issues, defaults to Bug
+	IssueType string `yaml:"issueType,omitempty"`
+	// MinSeverity defaults to critical for Jira
+	MinSeverity Level `yaml:"minSeverity,omitempty"`
+	// CommentIssue is the key of the issue messages like BotKube start and stop are added to as comments, e.g OPS-1
+	// Messages are not sent to Jira if empty
+	CommentIssue string `yaml:"commentIssue,omitempty"`
 }
 
 // Kubectl configuration for executing commands inside cluster
@@ -217,6 +323,54 @@ type Kubectl struct {
 	Commands         Commands
 	DefaultNamespace string `yaml:"defaultNamespace"`
 	RestrictAccess   bool   `yaml:"restrictAccess"`
+	// BinaryPath is the path of default kubectl binary
+	BinaryPath string `yaml:"binaryPath,omitempty"`
+	// Binaries is a map of kubectl client version and binary path, selected with --kubectl-version flag
+	Binaries map[string]string `yaml:",omitempty"`
+	// DefaultLogsTail is the number of lines passed with --tail to logs command if not set by user. Set -1 to disable
+	DefaultLogsTail int `yaml:"defaultLogsTail,omitempty"`
+	// DefaultChunkSize is the number of objects passed with --chunk-size to get command if not set by user. Set -1 to disable
+	DefaultChunkSize int `yaml:"defaultChunkSize,omitempty"`
+	// CacheDir is the directory passed with --cache-dir to kubectl to cache API discovery, defaults to botkube-kubectl-cache in the temp directory
+	CacheDir string `yaml:"cacheDir,omitempty"`
+	// Watch allows get command with --watch flag to post snapshots for a limited duration
+	Watch KubectlWatch `yaml:",omitempty"`
+	// AllowedOutputFormats restricts the values of -o flag, e.g wide. All formats are allowed if empty
+	AllowedOutputFormats []string `yaml:"allowedOutputFormats,omitempty"`
+	// Clusters are queried with kubectl commands passing their name with --cluster-name flag
+	// Commands without the flag run in the cluster BotKube is deployed in
+	Clusters []KubectlCluster `yaml:",omitempty"`
+	// DescribeMaxLines truncates describe output to the number of lines, the full output is returned with get-full command
+	// Output is not truncated if 0
+	DescribeMaxLines int `yaml:"describeMaxLines,omitempty"`
+	// ExplainCacheTTL is the duration explain output is cached for, output is not cached if 0
+	ExplainCacheTTL time.Duration `yaml:"explainCacheTTL,omitempty"`
+	// GuardGetAll rejects get all command across all namespaces and truncates its output like describe
+	GuardGetAll bool `yaml:"guardGetAll,omitempty"`
+	// RedactFields are JSONPath expressions of the fields replaced with <redacted> in YAML and JSON output
+	// e.g {.spec.containers[*].env[?(@.name=="*_PASSWORD")].value}
+	// Output formats selecting fields, e.g jsonpath and go-template, are rejected if it is set
+	RedactFields []string `yaml:"redactFields,omitempty"`
+}
+
+// KubectlCluster is a kubeconfig context to run kubectl commands in
+type KubectlCluster struct {
+	// Name is matched with --cluster-name flag value
+	Name    string
+	Context string
+	// Kubeconfig is the path of the kubeconfig file with the context, KUBECONFIG env or ~/.kube/config is used if empty
+	Kubeconfig string `yaml:",omitempty"`
+}
+
+// KubectlWatch contains settings for get command with --watch flag, the flag is removed if watch is not enabled
+type KubectlWatch struct {
+	Enabled bool
+	// MaxDuration after which the watch is stopped, defaults to 1m
+	MaxDuration time.Duration `yaml:"maxDuration,omitempty"`
+	// Debounce is the minimum interval between the snapshots, defaults to 5s
+	Debounce time.Duration `yaml:",omitempty"`
+	// MaxPerChannel is the number of concurrent watches allowed in a channel, defaults to 1
+	MaxPerChannel int `yaml:"maxPerChannel,omitempty"`
 }
 
 // Commands allowed in bot
@@ -230,7 +384,345 @@ type Settings struct {
 	ClusterName     string
 	Kubectl         Kubectl
 	ConfigWatcher   bool
-	UpgradeNotifier bool `yaml:"upgradeNotifier"`
+	UpgradeNotifier bool                     `yaml:"upgradeNotifier"`
+	Filters         map[string]FilterSetting `yaml:",omitempty"`
+	RequiredLabels  []string                 `yaml:"requiredLabels,omitempty"`
+	// NonProdNamespaces can contain a * that would expand to zero or more arbitrary characters
+	NonProdNamespaces []string      `yaml:"nonProdNamespaces,omitempty"`
+	ResourceNames     ResourceNames `yaml:"resourceNames,omitempty"`
+	Metrics           Metrics       `yaml:",omitempty"`
+	Health            Health        `yaml:",omitempty"`
+	// RequireConfirmation asks to confirm the notifier start and stop commands
+	RequireConfirmation bool `yaml:"requireConfirmation,omitempty"`
+	// RequireApproval runs mutating kubectl commands only after another user replies approve with the request id
+	RequireApproval bool `yaml:"requireApproval,omitempty"`
+	// AllowNodeOps enables cordon, uncordon and drain commands, they run after the user confirms them
+	AllowNodeOps bool `yaml:"allowNodeOps,omitempty"`
+	// DrainDeleteEmptyDirData allows --delete-emptydir-data flag with drain command, pods with emptyDir volumes lose their data
+	DrainDeleteEmptyDirData bool      `yaml:"drainDeleteEmptyDirData,omitempty"`
+	Templates               Templates `yaml:",omitempty"`
+	Notifiers               Notifiers `yaml:",omitempty"`
+	// AllowExec enables kubectl exec command for the commands in ExecAllowlist only
+	AllowExec bool `yaml:"allowExec,omitempty"`
+	// ExecAllowlist contains commands allowed to run with kubectl exec, e.g "cat /etc/config"
+	ExecAllowlist []string `yaml:"execAllowlist,omitempty"`
+	// AllowImpersonation passes --as, --as-group and --as-uid flags to kubectl, they are removed otherwise
+	AllowImpersonation bool `yaml:"allowImpersonation,omitempty"`
+	// ClusterScopedKinds contains the kinds of cluster scoped custom resources, e.g ClusterIssuer
+	// Events of these kinds are shown without the namespace
+	ClusterScopedKinds []string `yaml:"clusterScopedKinds,omitempty"`
+	// EventVerbs is a map of event type, i.e create, update or delete, to the wording used in notifications
+	// e.g "was deployed" for create. Defaults to "has been created", "has been updated" and "has been deleted"
+	EventVerbs map[string]string `yaml:"eventVerbs,omitempty"`
+	// RateLimits is a map of resource kind, e.g Pod, to the limit of update events sent for each resource of the kind
+	RateLimits map[string]RateLimit `yaml:"rateLimits,omitempty"`
+	// CommandPrefix is required at the start of every command if set, e.g !bk. Messages without it are ignored
+	CommandPrefix string `yaml:"commandPrefix,omitempty"`
+	// InstanceName identifies BotKube when more instances share a channel, commands can be addressed to it, e.g ping@readonly
+	InstanceName string `yaml:"instanceName,omitempty"`
+	// UnaddressedCommands sets how commands without instance name are handled, UnaddressedRespond by default
+	UnaddressedCommands UnaddressedPolicy `yaml:"unaddressedCommands,omitempty"`
+	// Runbooks is a map of event reason, e.g BackOff, or kind and reason, e.g Pod/BackOff, to a runbook URL
+	Runbooks map[string]string `yaml:"runbooks,omitempty"`
+	// MaintenanceWindows are periods during which event notifications are not sent
+	MaintenanceWindows []MaintenanceWindow `yaml:"maintenanceWindows,omitempty"`
+	EventReasons       EventReasons        `yaml:"eventReasons,omitempty"`
+	// Reminders re-notifies the resources which are still in error
+	Reminders Reminders `yaml:",omitempty"`
+	// Kubernetes configures the client used for the informers and the commands served by BotKube
+	Kubernetes Kubernetes `yaml:",omitempty"`
+	// RestartSpike configures the container restarts warned about by RestartSpikeChecker filter
+	RestartSpike RestartSpike `yaml:"restartSpike,omitempty"`
+	// Deduplication removes the recommendations and warnings repeated by the filters, DeduplicateExact by default
+	Deduplication Deduplication `yaml:"deduplication,omitempty"`
+	// EnrichCrashLogs adds the last log lines of the crashing container to the Pod event messages
+	EnrichCrashLogs bool `yaml:"enrichCrashLogs,omitempty"`
+	// CrashLogLines is the number of log lines added with enrichCrashLogs, 10 by default and at most MaxCrashLogLines
+	CrashLogLines int `yaml:"crashLogLines,omitempty"`
+	// AllowedRegistries are the registry hosts ImageRegistryChecker filter allows the Pod images from, e.g docker.io
+	AllowedRegistries []string `yaml:"allowedRegistries,omitempty"`
+	// StrictRegistries makes ImageRegistryChecker also recommend blocking the images from other registries
+	StrictRegistries bool `yaml:"strictRegistries,omitempty"`
+	// Proxy is the http, https or socks5 proxy URL used to connect to Slack, Teams, webhook, Elasticsearch and Jira
+	// HTTPS_PROXY and HTTP_PROXY env are used if empty
+	Proxy string `yaml:"proxy,omitempty"`
+	// DryRun logs the notifications with their target instead of sending them to the notifiers
+	DryRun bool `yaml:"dryRun,omitempty"`
+	// Routing sends the events to the channel of the first matching rule, the notifier channel is used if none matches
+	// Channel set with botkube.io/channel annotation takes precedence
+	Routing []RoutingRule `yaml:"routing,omitempty"`
+}
+
+// RoutingRule matches events by namespace and kind patterns which can contain * wildcard, e.g kube-*
+// Empty pattern matches all events, kind is case insensitive
+type RoutingRule struct {
+	Namespace string `yaml:",omitempty"`
+	Kind      string `yaml:",omitempty"`
+	Channel   string
+}
+
+// MaxCrashLogLines is the maximum of settings.crashLogLines
+const MaxCrashLogLines = 50
+
+// EventReasons filters k8s Warning and Normal events by reason, e.g BackOff. Reasons are matched case-insensitively
+// Include contains the reasons notified, empty list matches all. Exclude contains the reasons never notified
+type EventReasons struct {
+	Include []string `yaml:",omitempty"`
+	Exclude []string `yaml:",omitempty"`
+}
+
+// Reminders configures the notifications repeated for errors which are not resolved
+type Reminders struct {
+	// ErrorInterval is the time between reminders, reminders are disabled if not set
+	ErrorInterval time.Duration `yaml:"errorInterval,omitempty"`
+}
+
+// RestartSpike sets how many restarts of a container within the window are warned about
+// Threshold defaults to 3 and Window to 10 minutes
+type RestartSpike struct {
+	Threshold int32         `yaml:",omitempty"`
+	Window    time.Duration `yaml:",omitempty"`
+}
+
+// Kubernetes contains the rate limits of the API server client, kubectl commands are not affected
+type Kubernetes struct {
+	// QPS is the queries per second sent to the API server, client-go default of 5 is used if not set
+	QPS float32 `yaml:"qps,omitempty"`
+	// Burst is the number of queries allowed above QPS for a short time, client-go default of 10 is used if not set
+	Burst int `yaml:",omitempty"`
+}
+
+// Deduplication is how the recommendations and warnings of an event are compared to remove the repeated ones
+type Deduplication string
+
+const (
+	// DeduplicateExact removes the identical recommendations and warnings
+	DeduplicateExact Deduplication = "exact"
+	// DeduplicateNormalized removes also the ones differing only in case, whitespace or a trailing period
+	DeduplicateNormalized Deduplication = "normalized"
+	// DeduplicateOff keeps all the recommendations and warnings
+	DeduplicateOff Deduplication = "off"
+)
+
+// UnaddressedPolicy is the handling of commands not addressed to any instance
+type UnaddressedPolicy string
+
+const (
+	// UnaddressedRespond executes commands without instance name
+	UnaddressedRespond UnaddressedPolicy = "respond"
+	// UnaddressedIgnore executes only the commands addressed to the instance
+	UnaddressedIgnore UnaddressedPolicy = "ignore"
+)
+
+// RateLimit caps the update events sent for a resource, the updates over the limit are suppressed
+type RateLimit struct {
+	// PerMinute is the number of update events allowed per minute
+	PerMinute int `yaml:"perMinute"`
+}
+
+// Notifiers contains settings applied to all the notifiers
+type Notifiers struct {
+	CircuitBreaker CircuitBreaker `yaml:"circuitBreaker,omitempty"`
+	DeadLetter     DeadLetter     `yaml:"deadLetter,omitempty"`
+	// ShutdownTimeout is the time to wait for notifications in progress on SIGTERM, defaults to 10s
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout,omitempty"`
+}
+
+// DeadLetter contains configuration to capture events which couldn't be delivered
+// Path is a file where records are appended as JSON lines, dead letter sink is disabled if empty
+type DeadLetter struct {
+	Path string `yaml:",omitempty"`
+}
+
+// CircuitBreaker stops sending to a notifier after FailureThreshold consecutive failures for Cooldown duration
+// Fallback is the name of communication backend to post a diagnostic message when a circuit opens
+type CircuitBreaker struct {
+	FailureThreshold int           `yaml:"failureThreshold,omitempty"`
+	Cooldown         time.Duration `yaml:",omitempty"`
+	Fallback         string        `yaml:",omitempty"`
+}
+
+// Templates contains Go text/template strings to format notifications, events.Event is the template context
+// Short is used for short notifications and summary of the events
+type Templates struct {
+	Short string `yaml:",omitempty"`
+}
+
+// Health contains configuration for liveness and readiness endpoints
+// /healthz and /readyz are served only if Port is set
+type Health struct {
+	Port string `yaml:",omitempty"`
+}
+
+// Metrics contains configuration for Prometheus metrics endpoint
+// Metrics are served only if Port is set
+type Metrics struct {
+	Port string `yaml:",omitempty"`
+}
+
+// ResourceNames contains regular expressions to filter events by resource name
+// Include contains a list of regular expressions, only matching resources are notified. Empty list matches all
+// Ignore contains a list of regular expressions, matching resources are never notified
+// Expressions must match the whole resource name
+// example : ignore [.*-canary-.*]
+type ResourceNames struct {
+	Include []string `yaml:",omitempty"`
+	Ignore  []string `yaml:",omitempty"`
+
+	includeRegex []*regexp.Regexp
+	ignoreRegex  []*regexp.Regexp
+}
+
+// Compile compiles the regular expressions, returns error if any of the expression is invalid
+func (r *ResourceNames) Compile() (err error) {
+	if r.includeRegex, err = compileRegexList(r.Include); err != nil {
+		return err
+	}
+	r.ignoreRegex, err = compileRegexList(r.Ignore)
+	return err
+}
+
+// IsAllowed checks if resource name matches include list and does not match ignore list
+// Compile must be called before IsAllowed
+func (r ResourceNames) IsAllowed(name string) bool {
+	if len(r.includeRegex) > 0 {
+		included := false
+		for _, re := range r.includeRegex {
+			if re.MatchString(name) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	for _, re := range r.ignoreRegex {
+		if re.MatchString(name) {
+			return false
+		}
+	}
+	return true
+}
+
+func compileRegexList(exprs []string) ([]*regexp.Regexp, error) {
+	var list []*regexp.Regexp
+	for _, expr := range exprs {
+		if len(expr) == 0 {
+			continue
+		}
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("Invalid resource name expression %q. %s", expr, err.Error())
+		}
+		list = append(list, re)
+	}
+	return list, nil
+}
+
+// FilterSetting contains configuration for a registered filter
+// Enabled sets if the filter runs on startup and after filters reset command, filters are enabled if not set
+// Namespaces restricts the filter to run only on events from the matching namespaces
+// Include and Ignore can contain a * that would expand to zero or more arbitrary characters
+// example : include [dev-*], ignore [dev-secure]
+type FilterSetting struct {
+	Enabled    *bool `yaml:",omitempty"`
+	Namespaces Namespaces
+}
+
+// IsEnabled returns the enabled state set for the filter, true if not set
+func (s FilterSetting) IsEnabled() bool {
+	return s.Enabled == nil || *s.Enabled
+}
+
+// Redacted returns copy of the config with credentials of all the communication backends and the proxy removed
+// Secrets of new backends must be added here
+func (c Config) Redacted() Config {
+	c.Settings.Proxy = redactURL(c.Settings.Proxy)
+	c.Communications.Slack.Token = ""
+	c.Communications.Slack.AppToken = ""
+	c.Communications.Slack.SigningSecret = ""
+	c.Communications.Mattermost.Token = ""
+	c.Communications.Discord.Token = ""
+	c.Communications.Webhook.URL = ""
+	c.Communications.Teams.AppPassword = ""
+	c.Communications.ElasticSearch.Username = ""
+	c.Communications.ElasticSearch.Password = ""
+	c.Communications.Jira.URL = ""
+	c.Communications.Jira.Username = ""
+	c.Communications.Jira.Token = ""
+	return c
+}
+
+// redactURL returns the URL without user and password, empty if it can't be parsed
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	return u.String()
+}
+
+// levelSeverity defines order of event levels
+var levelSeverity = map[Level]int{
+	Debug:    0,
+	Info:     1,
+	Warn:     2,
+	Error:    3,
+	Critical: 4,
+}
+
+// IsValid checks if the level is one of the known levels
+func (l Level) IsValid() bool {
+	_, ok := levelSeverity[l]
+	return ok
+}
+
+// IsAtLeast checks if the level is same or more severe than min level
+// Empty min level allows all levels, unknown levels are considered as info
+func (l Level) IsAtLeast(min Level) bool {
+	if len(min) == 0 {
+		return true
+	}
+	severity, ok := levelSeverity[l]
+	if !ok {
+		severity = levelSeverity[Info]
+	}
+	return severity >= levelSeverity[min]
+}
+
+// validateMinSeverity returns error if minSeverity of any communication backend is invalid
+func (c CommunicationsConfig) validateMinSeverity() error {
+	for name, level := range map[string]Level{
+		"slack":         c.Slack.MinSeverity,
+		"mattermost":    c.Mattermost.MinSeverity,
+		"discord":       c.Discord.MinSeverity,
+		"webhook":       c.Webhook.MinSeverity,
+		"teams":         c.Teams.MinSeverity,
+		"elasticsearch": c.ElasticSearch.MinSeverity,
+		"jira":          c.Jira.MinSeverity,
+	} {
+		if len(level) != 0 && !level.IsValid() {
+			return fmt.Errorf("Invalid minSeverity '%s' for %s. Valid levels are debug, info, warn, error and critical", level, name)
+		}
+	}
+	for name, levels := range map[string]map[string]Level{
+		"slack":      c.Slack.ChannelSeverity,
+		"mattermost": c.Mattermost.ChannelSeverity,
+	} {
+		for channel, level := range levels {
+			if !level.IsValid() {
+				return fmt.Errorf("Invalid minSeverity '%s' for %s channel %s. Valid levels are debug, info, warn, error and critical", level, name, channel)
+			}
+		}
+	}
+	for channel, mention := range c.Slack.Mentions {
+		if len(mention.MinSeverity) != 0 && !mention.MinSeverity.IsValid() {
+			return fmt.Errorf("Invalid minSeverity '%s' for slack mention in channel %s. Valid levels are debug, info, warn, error and critical", mention.MinSeverity, channel)
+		}
+	}
+	return nil
 }
 
 func (eventType EventType) String() string {
@@ -252,13 +744,48 @@ func NewCommunicationsConfig() (*Communications, error) {
 	if err != nil {
 		return c, err
 	}
+	if b, err = expandEnv(b); err != nil {
+		return c, err
+	}
 
 	if len(b) != 0 {
-		yaml.Unmarshal(b, c)
+		if err := yaml.Unmarshal(b, c); err != nil {
+			return c, err
+		}
 	}
 	return c, nil
 }
 
+// envVarRegex matches ${VAR} and ${VAR:-default} references in the config files
+var envVarRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// expandEnv replaces environment variable references in the config with their values
+// Variables which are not set fail the load unless a default is given. Comment lines are left as is
+func expandEnv(b []byte) ([]byte, error) {
+	var missing []string
+	lines := strings.Split(string(b), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		lines[i] = envVarRegex.ReplaceAllStringFunc(line, func(ref string) string {
+			m := envVarRegex.FindStringSubmatch(ref)
+			if value, ok := os.LookupEnv(m[1]); ok {
+				return value
+			}
+			if len(m[2]) > 0 {
+				return m[3]
+			}
+			missing = append(missing, m[1])
+			return ref
+		})
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("Environment variables %s referenced in the config are not set. Set them or provide a default with ${VAR:-default}", strings.Join(missing, ", "))
+	}
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
 // New returns new Config
 func New() (*Config, error) {
 	c := &Config{}
@@ -274,9 +801,18 @@ func New() (*Config, error) {
 	if err != nil {
 		return c, err
 	}
+	if b, err = expandEnv(b); err != nil {
+		return nil, err
+	}
 
 	if len(b) != 0 {
-		yaml.Unmarshal(b, c)
+		if err := yaml.Unmarshal(b, c); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.Settings.ResourceNames.Compile(); err != nil {
+		return nil, err
 	}
 
 	comm, err := NewCommunicationsConfig()
@@ -284,6 +820,9 @@ func New() (*Config, error) {
 		return nil, err
 	}
 	c.Communications = comm.Communications
+	if err := c.Communications.validateMinSeverity(); err != nil {
+		return nil, err
+	}
 
 	return c, nil
 }
@@ -0,0 +1,146 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// clockFormat is the format of the start and end of windows repeated every day
+const clockFormat = "15:04"
+
+// MaintenanceWindow is a period during which event notifications are suppressed
+// Start and End are RFC3339 timestamps for a one-off window, or times of the day in HH:MM format for a window
+// repeated every day. A daily window ending before its start ends on the next day, e.g 22:00 to 02:00
+type MaintenanceWindow struct {
+	Name  string `yaml:"name,omitempty"`
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+	// Days restricts a daily window to the listed days of the week, e.g Sat, Sun. Empty list matches every day
+	Days []string `yaml:"days,omitempty"`
+	// Timezone of a daily window, e.g Europe/Berlin, defaults to UTC
+	Timezone string `yaml:"timezone,omitempty"`
+	// RecordEvents keeps the suppressed events in the history listed by events command
+	RecordEvents bool `yaml:"recordEvents,omitempty"`
+}
+
+// Validate checks the format of the window
+func (w MaintenanceWindow) Validate() error {
+	_, err := w.next(time.Now())
+	return err
+}
+
+// ActiveUntil returns the end of the window if the time is within it
+// The start is inclusive and the end exclusive. Invalid windows are never active
+func (w MaintenanceWindow) ActiveUntil(now time.Time) (time.Time, bool) {
+	end, err := w.next(now)
+	if err != nil || end.IsZero() {
+		return time.Time{}, false
+	}
+	return end, true
+}
+
+// next returns the end of the window if it's active at the time, zero time otherwise
+func (w MaintenanceWindow) next(now time.Time) (time.Time, error) {
+	if start, err := time.Parse(time.RFC3339, w.Start); err == nil {
+		end, err := time.Parse(time.RFC3339, w.End)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("End %q must be RFC3339 timestamp like the start", w.End)
+		}
+		if !end.After(start) {
+			return time.Time{}, fmt.Errorf("End %q must be after the start %q", w.End, w.Start)
+		}
+		if len(w.Days) != 0 {
+			return time.Time{}, fmt.Errorf("Days can be set only for daily windows")
+		}
+		if !now.Before(start) && now.Before(end) {
+			return end, nil
+		}
+		return time.Time{}, nil
+	}
+
+	start, err := time.Parse(clockFormat, w.Start)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Start %q must be RFC3339 timestamp or time of the day in HH:MM format", w.Start)
+	}
+	end, err := time.Parse(clockFormat, w.End)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("End %q must be time of the day in HH:MM format like the start", w.End)
+	}
+	loc := time.UTC
+	if len(w.Timezone) != 0 {
+		if loc, err = time.LoadLocation(w.Timezone); err != nil {
+			return time.Time{}, fmt.Errorf("Invalid timezone %q. %s", w.Timezone, err.Error())
+		}
+	}
+	days := map[time.Weekday]bool{}
+	for _, d := range w.Days {
+		day, ok := parseWeekday(d)
+		if !ok {
+			return time.Time{}, fmt.Errorf("Invalid day %q. Use Mon, Tue, Wed, Thu, Fri, Sat or Sun", d)
+		}
+		days[day] = true
+	}
+
+	now = now.In(loc)
+	// Window started yesterday can still be active, e.g 22:00 to 02:00
+	for _, offset := range []int{0, -1} {
+		y, m, d := now.AddDate(0, 0, offset).Date()
+		from := time.Date(y, m, d, start.Hour(), start.Minute(), 0, 0, loc)
+		if len(days) != 0 && !days[from.Weekday()] {
+			continue
+		}
+		to := time.Date(y, m, d, end.Hour(), end.Minute(), 0, 0, loc)
+		if !to.After(from) {
+			to = to.AddDate(0, 0, 1)
+		}
+		if !now.Before(from) && now.Before(to) {
+			return to, nil
+		}
+	}
+	return time.Time{}, nil
+}
+
+// parseWeekday accepts English day names and their three letter abbreviations, case-insensitively
+func parseWeekday(day string) (time.Weekday, bool) {
+	day = strings.ToLower(strings.TrimSpace(day))
+	if len(day) < 3 {
+		return 0, false
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if day == name || day == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// ActiveMaintenanceWindow returns the first window active at the time and its end
+func ActiveMaintenanceWindow(windows []MaintenanceWindow, now time.Time) (MaintenanceWindow, time.Time, bool) {
+	for _, w := range windows {
+		if end, ok := w.ActiveUntil(now); ok {
+			return w, end, true
+		}
+	}
+	return MaintenanceWindow{}, time.Time{}, false
+}
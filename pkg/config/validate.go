@@ -0,0 +1,304 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	// resourceNameRegex matches resource names in <group>/<version>/<resource> or <version>/<resource> format
+	resourceNameRegex = regexp.MustCompile(`^([a-z0-9]([-a-z0-9.]*[a-z0-9])?/)?v[0-9]+((alpha|beta)[0-9]+)?/[a-z0-9]+$`)
+	// namespacePatternRegex matches namespace names which can contain * wildcard
+	namespacePatternRegex = regexp.MustCompile(`^[a-z0-9*]([-a-z0-9*]*[a-z0-9*])?$`)
+	// hexColorRegex matches colors in #RRGGBB format
+	hexColorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+	validEventTypes = map[EventType]bool{
+		CreateEvent:  true,
+		UpdateEvent:  true,
+		DeleteEvent:  true,
+		ErrorEvent:   true,
+		WarningEvent: true,
+		NormalEvent:  true,
+		InfoEvent:    true,
+		AllEvent:     true,
+	}
+)
+
+// ValidationIssue is a problem found in the configuration
+type ValidationIssue struct {
+	// Fatal issues prevent BotKube from starting, the rest are reported as warnings
+	Fatal   bool
+	Message string
+}
+
+func (i ValidationIssue) String() string {
+	if i.Fatal {
+		return "ERROR: " + i.Message
+	}
+	return "WARNING: " + i.Message
+}
+
+// HasFatal checks if any of the issues is fatal
+func HasFatal(issues []ValidationIssue) bool {
+	for _, i := range issues {
+		if i.Fatal {
+			return true
+		}
+	}
+	return false
+}
+
+// validator collects the validation issues
+type validator struct {
+	issues []ValidationIssue
+}
+
+func (v *validator) fatalf(format string, args ...interface{}) {
+	v.issues = append(v.issues, ValidationIssue{Fatal: true, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) warnf(format string, args ...interface{}) {
+	v.issues = append(v.issues, ValidationIssue{Message: fmt.Sprintf(format, args...)})
+}
+
+// required reports fatal issue if the field of an enabled notifier is empty
+func (v *validator) required(field, value string) {
+	if len(strings.TrimSpace(value)) == 0 {
+		v.fatalf("%s is required", field)
+	}
+}
+
+// Validate returns the problems found in the configuration
+// Enabled notifiers missing credentials are fatal, unknown resources and malformed lists are warnings
+func (c *Config) Validate() []ValidationIssue {
+	v := &validator{}
+	v.communications(c.Communications)
+	v.resources(c.Resources)
+
+	if len(c.Settings.ClusterName) == 0 {
+		v.warnf("settings.clustername is empty, commands with --cluster-name flag won't work")
+	}
+	names := c.Settings.ResourceNames
+	if err := names.Compile(); err != nil {
+		v.fatalf("settings.resourceNames: %s", err.Error())
+	}
+	for name, setting := range c.Settings.Filters {
+		v.namespaces(fmt.Sprintf("settings.filters.%s.namespaces", name), setting.Namespaces)
+	}
+	for i, cluster := range c.Settings.Kubectl.Clusters {
+		if len(cluster.Name) == 0 || len(cluster.Context) == 0 {
+			v.warnf("settings.kubectl.clusters[%d] must have name and context, the cluster is ignored", i)
+		}
+	}
+	for kind, limit := range c.Settings.RateLimits {
+		if limit.PerMinute <= 0 {
+			v.warnf("settings.rateLimits.%s.perMinute must be greater than 0, the limit is ignored", kind)
+		}
+	}
+	switch c.Settings.UnaddressedCommands {
+	case "", UnaddressedRespond:
+	case UnaddressedIgnore:
+		if len(c.Settings.InstanceName) == 0 {
+			v.warnf("settings.unaddressedCommands is %s but settings.instanceName is empty, all commands are ignored", UnaddressedIgnore)
+		}
+	default:
+		v.warnf("settings.unaddressedCommands '%s' is invalid, use %s or %s. Commands are handled as with %s", c.Settings.UnaddressedCommands, UnaddressedRespond, UnaddressedIgnore, UnaddressedRespond)
+	}
+	switch c.Settings.Deduplication {
+	case "", DeduplicateExact, DeduplicateNormalized, DeduplicateOff:
+	default:
+		v.warnf("settings.deduplication '%s' is invalid, use %s, %s or %s. Recommendations are deduplicated with %s", c.Settings.Deduplication, DeduplicateExact, DeduplicateNormalized, DeduplicateOff, DeduplicateExact)
+	}
+	if c.Settings.CrashLogLines < 0 || c.Settings.CrashLogLines > MaxCrashLogLines {
+		v.warnf("settings.crashLogLines must be between 1 and %d, the closest limit is used", MaxCrashLogLines)
+	}
+	for _, r := range c.Settings.AllowedRegistries {
+		if len(r) == 0 || strings.ContainsAny(r, "/@") {
+			v.warnf("settings.allowedRegistries '%s' is invalid, use the registry host, e.g docker.io or localhost:5000", r)
+		}
+	}
+	if len(c.Settings.Proxy) != 0 {
+		if u, err := url.Parse(c.Settings.Proxy); err != nil || u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5" {
+			v.warnf("settings.proxy must be a http, https or socks5 URL, e.g http://proxy:3128. Proxy from HTTPS_PROXY env is used")
+		}
+	}
+	for i, r := range c.Settings.Routing {
+		if len(strings.TrimSpace(r.Channel)) == 0 {
+			v.warnf("settings.routing[%d] must have channel, the rule is ignored", i)
+		}
+		if len(r.Namespace) != 0 {
+			v.namespacePattern(fmt.Sprintf("settings.routing[%d].namespace", i), r.Namespace)
+		}
+	}
+	if c.Settings.RestartSpike.Threshold < 0 || c.Settings.RestartSpike.Window < 0 {
+		v.warnf("settings.restartSpike threshold and window must be greater than 0, defaults are used for negative values")
+	}
+	if c.Settings.Kubernetes.QPS < 0 || c.Settings.Kubernetes.Burst < 0 {
+		v.warnf("settings.kubernetes qps and burst must be greater than 0, client-go defaults are used for negative values")
+	}
+	if c.Settings.Reminders.ErrorInterval < 0 {
+		v.warnf("settings.reminders.errorInterval must be greater than 0, reminders are disabled")
+	}
+	for i, w := range c.Settings.MaintenanceWindows {
+		if err := w.Validate(); err != nil {
+			v.warnf("settings.maintenanceWindows[%d]: %s, the window is ignored", i, err.Error())
+		}
+	}
+	return v.issues
+}
+
+func (v *validator) communications(c CommunicationsConfig) {
+	if c.Slack.Enabled {
+		v.required("communications.slack.token", c.Slack.Token)
+		if len(c.Slack.Channel) == 0 {
+			v.warnf("communications.slack.channel is empty, notifications won't be sent to Slack")
+		}
+		switch c.Slack.Mode {
+		case "", SlackRTMMode:
+		case SlackSocketMode:
+			v.required("communications.slack.appToken", c.Slack.AppToken)
+		case SlackEventsMode:
+			v.required("communications.slack.signingSecret", c.Slack.SigningSecret)
+		default:
+			v.fatalf("communications.slack.mode '%s' is invalid, use %s, %s or %s", c.Slack.Mode, SlackRTMMode, SlackSocketMode, SlackEventsMode)
+		}
+		if c.Slack.AckButton && c.Slack.Mode != SlackSocketMode {
+			v.warnf("communications.slack.ackButton requires %s mode, the button is not added", SlackSocketMode)
+		}
+		if c.Slack.MaxSendsPerMinute < 0 {
+			v.warnf("communications.slack.maxSendsPerMinute must not be negative, messages are not throttled")
+		}
+		switch c.Slack.ThrottleMode {
+		case "", SlackThrottleQueue, SlackThrottleDrop:
+		default:
+			v.fatalf("communications.slack.throttleMode '%s' is invalid, use %s or %s", c.Slack.ThrottleMode, SlackThrottleQueue, SlackThrottleDrop)
+		}
+		for level, color := range c.Slack.Colors {
+			if !level.IsValid() {
+				v.warnf("communications.slack.colors.%s is not a valid level, the color is ignored", level)
+			} else if !IsHexColor(color) {
+				v.warnf("communications.slack.colors.%s '%s' is not a hex color, the default color is used", level, color)
+			}
+		}
+	}
+	if c.Mattermost.Enabled {
+		v.required("communications.mattermost.url", c.Mattermost.URL)
+		v.required("communications.mattermost.token", c.Mattermost.Token)
+		v.required("communications.mattermost.team", c.Mattermost.Team)
+		if len(c.Mattermost.Channel) == 0 {
+			v.warnf("communications.mattermost.channel is empty, notifications won't be sent to Mattermost")
+		}
+	}
+	if c.Discord.Enabled {
+		v.required("communications.discord.token", c.Discord.Token)
+		v.required("communications.discord.botid", c.Discord.BotID)
+		if len(c.Discord.Channel) == 0 {
+			v.warnf("communications.discord.channel is empty, notifications won't be sent to Discord")
+		}
+	}
+	if c.Teams.Enabled {
+		v.required("communications.teams.appID", c.Teams.AppID)
+		v.required("communications.teams.appPassword", c.Teams.AppPassword)
+	}
+	if c.ElasticSearch.Enabled {
+		v.required("communications.elasticsearch.server", c.ElasticSearch.Server)
+		if c.ElasticSearch.AWSSigning.Enabled {
+			v.required("communications.elasticsearch.awsSigning.awsRegion", c.ElasticSearch.AWSSigning.AWSRegion)
+		}
+	}
+	if c.Webhook.Enabled {
+		v.required("communications.webhook.url", c.Webhook.URL)
+	}
+	if c.Jira.Enabled {
+		v.required("communications.jira.url", c.Jira.URL)
+		v.required("communications.jira.token", c.Jira.Token)
+		v.required("communications.jira.project", c.Jira.Project)
+	}
+	if err := c.validateMinSeverity(); err != nil {
+		v.fatalf("%s", err.Error())
+	}
+}
+
+func (v *validator) resources(resources []Resource) {
+	seen := map[string]bool{}
+	for i, r := range resources {
+		field := fmt.Sprintf("resources[%d]", i)
+		if len(r.Name) == 0 {
+			v.warnf("%s.name is empty", field)
+			continue
+		}
+		field = fmt.Sprintf("resources.%s", r.Name)
+		if !resourceNameRegex.MatchString(r.Name) {
+			v.warnf("%s is not a known resource kind, use <group>/<version>/<resource> format e.g apps/v1/deployments", field)
+		}
+		if seen[r.Name] {
+			v.warnf("%s is configured more than once", field)
+		}
+		seen[r.Name] = true
+		if len(r.Events) == 0 {
+			v.warnf("%s.events is empty, no events will be notified", field)
+		}
+		for _, e := range r.Events {
+			if !validEventTypes[e] {
+				v.warnf("%s.events contains unknown event type '%s'", field, e)
+			}
+		}
+		v.namespaces(field+".namespaces", r.Namespaces)
+	}
+}
+
+func (v *validator) namespaces(field string, ns Namespaces) {
+	for _, n := range ns.Include {
+		if n == "all" {
+			if len(ns.Include) > 1 {
+				v.warnf("%s.include contains 'all' with other namespaces, the other namespaces are redundant", field)
+			}
+			continue
+		}
+		v.namespacePattern(field+".include", n)
+	}
+	for _, n := range ns.Ignore {
+		// Empty ignore entry is used in the default config to ignore nothing
+		if len(n) == 0 {
+			continue
+		}
+		if n == "all" {
+			v.warnf("%s.ignore contains 'all', use include list to select namespaces", field)
+			continue
+		}
+		v.namespacePattern(field+".ignore", n)
+	}
+}
+
+func (v *validator) namespacePattern(field, pattern string) {
+	if len(pattern) > 63 || !namespacePatternRegex.MatchString(pattern) {
+		v.warnf("%s contains invalid namespace '%s'", field, pattern)
+	}
+}
+
+// IsHexColor checks if the color is in #RRGGBB format
+func IsHexColor(color string) bool {
+	return hexColorRegex.MatchString(color)
+}
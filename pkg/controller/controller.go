@@ -20,13 +20,10 @@
 package controller
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"os/signal"
-	"path/filepath"
 	"reflect"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/infracloudio/botkube/pkg/config"
@@ -36,10 +33,10 @@ import (
 	// Register filters
 	_ "github.com/infracloudio/botkube/pkg/filterengine/filters"
 	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/metrics"
 	"github.com/infracloudio/botkube/pkg/notify"
 	"github.com/infracloudio/botkube/pkg/utils"
 
-	"github.com/fsnotify/fsnotify"
 	coreV1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -49,7 +46,6 @@ import (
 const (
 	controllerStartMsg = "...and now my watch begins for cluster '%s'! :crossed_swords:"
 	controllerStopMsg  = "My watch has ended for cluster '%s'!\nPlease send `@BotKube notifier start` to enable notification once BotKube comes online."
-	configUpdateMsg    = "Looks like the configuration is updated for cluster '%s'. I shall halt my watch till I read it."
 )
 
 var eventGVR = schema.GroupVersionResource{
@@ -57,18 +53,34 @@ var eventGVR = schema.GroupVersionResource{
 	Resource: "events",
 }
 
-var startTime time.Time
-
 // RegisterInformers creates new informer controllers to watch k8s resources
-func RegisterInformers(c *config.Config, notifiers []notify.Notifier) {
+// It blocks until ctx is canceled, then stops accepting events, waits for the notifications in progress and sends the buffered events
+func RegisterInformers(ctx context.Context, c *config.Config, notifiers []notify.Notifier) {
 	sendMessage(c, notifiers, fmt.Sprintf(controllerStartMsg, c.Settings.ClusterName))
-	startTime = time.Now()
+	current.Store(newPipeline(c, notifiers, time.Now()))
+	informersStopCh = startInformers(c)
+	go remind(loadPipeline(), informersStopCh)
 
 	// Start config file watcher if enabled
 	if c.Settings.ConfigWatcher {
-		go configWatcher(c, notifiers)
+		go configWatcher()
 	}
 
+	<-ctx.Done()
+	log.Info("Shutting down, new events are not sent")
+	sends.stop()
+	p := loadPipeline()
+	sendMessage(p.conf, p.notifiers, fmt.Sprintf(controllerStopMsg, p.conf.Settings.ClusterName))
+	timeout := shutdownTimeout(p.conf.Settings.Notifiers.ShutdownTimeout)
+	if !sends.wait(timeout) {
+		log.Warnf("Timed out after %s waiting for notifications in progress, they may be lost", timeout)
+	}
+	notify.Close(p.notifiers)
+}
+
+// startInformers registers the event handlers on utils.DynamicKubeInformerFactory and starts it
+// The returned channel stops the informers when closed
+func startInformers(c *config.Config) chan struct{} {
 	// Register informers for resource lifecycle events
 	if len(c.Resources) > 0 {
 		log.Info("Registering resource lifecycle informer")
@@ -77,7 +89,7 @@ func RegisterInformers(c *config.Config, notifiers []notify.Notifier) {
 				continue
 			}
 			log.Infof("Adding informer for resource:%s", r.Name)
-			utils.ResourceInformerMap[r.Name].AddEventHandler(registerEventHandlers(c, notifiers, r.Name, r.Events))
+			utils.ResourceInformerMap[r.Name].AddEventHandler(registerEventHandlers(r.Name, r.Events))
 		}
 	}
 
@@ -106,71 +118,71 @@ func RegisterInformers(c *config.Config, notifiers []notify.Notifier) {
 			switch strings.ToLower(eventObj.Type) {
 			case config.WarningEvent.String():
 				// Send WarningEvent as ErrorEvents
-				sendEvent(obj, nil, c, notifiers, utils.GVRToString(gvr), config.ErrorEvent)
+				sendEvent(obj, nil, utils.GVRToString(gvr), config.ErrorEvent)
 			case config.NormalEvent.String():
 				// Send NormalEvent as Insignificant InfoEvent
-				sendEvent(obj, nil, c, notifiers, utils.GVRToString(gvr), config.InfoEvent)
+				sendEvent(obj, nil, utils.GVRToString(gvr), config.InfoEvent)
 			}
 		},
 	})
 	stopCh := make(chan struct{})
-	defer close(stopCh)
-
 	utils.DynamicKubeInformerFactory.Start(stopCh)
-
-	sigterm := make(chan os.Signal, 1)
-	signal.Notify(sigterm, syscall.SIGTERM, syscall.SIGINT, syscall.SIGKILL, syscall.SIGQUIT, syscall.SIGSTOP)
-
-	<-sigterm
-	sendMessage(c, notifiers, fmt.Sprintf(controllerStopMsg, c.Settings.ClusterName))
-	// Sleep for some time to send termination notification
-	time.Sleep(5 * time.Second)
+	return stopCh
 }
 
-func registerEventHandlers(c *config.Config, notifiers []notify.Notifier, resourceType string, events []config.EventType) (handlerFns cache.ResourceEventHandlerFuncs) {
+func registerEventHandlers(resourceType string, events []config.EventType) (handlerFns cache.ResourceEventHandlerFuncs) {
 	for _, event := range events {
 		if event == config.AllEvent || event == config.CreateEvent {
 			handlerFns.AddFunc = func(obj interface{}) {
 				log.Debugf("Processing add to %v", resourceType)
-				sendEvent(obj, nil, c, notifiers, resourceType, config.CreateEvent)
+				sendEvent(obj, nil, resourceType, config.CreateEvent)
 			}
 		}
 
 		if event == config.AllEvent || event == config.UpdateEvent {
 			handlerFns.UpdateFunc = func(old, new interface{}) {
 				log.Debugf("Processing update to %v\n Object: %+v\n", resourceType, new)
-				sendEvent(new, old, c, notifiers, resourceType, config.UpdateEvent)
+				sendEvent(new, old, resourceType, config.UpdateEvent)
 			}
 		}
 
 		if event == config.AllEvent || event == config.DeleteEvent {
 			handlerFns.DeleteFunc = func(obj interface{}) {
 				log.Debugf("Processing delete to %v", resourceType)
-				sendEvent(obj, nil, c, notifiers, resourceType, config.DeleteEvent)
+				sendEvent(obj, nil, resourceType, config.DeleteEvent)
 			}
 		}
 	}
 	return handlerFns
 }
 
-func sendEvent(obj, oldObj interface{}, c *config.Config, notifiers []notify.Notifier, resource string, eventType config.EventType) {
+func sendEvent(obj, oldObj interface{}, resource string, eventType config.EventType) {
+	p := loadPipeline()
+	c := p.conf
+
 	// Filter namespaces
 	objectMeta := utils.GetObjectMetaData(obj)
 
 	switch eventType {
 	case config.InfoEvent:
 		// Skip if ErrorEvent is not configured for the resource
-		if !utils.CheckOperationAllowed(utils.AllowedEventKindsMap, objectMeta.Namespace, resource, config.ErrorEvent) {
+		if !utils.CheckOperationAllowed(p.allowedEvents, objectMeta.Namespace, resource, config.ErrorEvent) {
 			log.Debugf("Ignoring %s to %s/%v in %s namespaces", eventType, resource, objectMeta.Name, objectMeta.Namespace)
 			return
 		}
 	default:
-		if !utils.CheckOperationAllowed(utils.AllowedEventKindsMap, objectMeta.Namespace, resource, eventType) {
+		if !utils.CheckOperationAllowed(p.allowedEvents, objectMeta.Namespace, resource, eventType) {
 			log.Debugf("Ignoring %s to %s/%v in %s namespaces", eventType, resource, objectMeta.Name, objectMeta.Namespace)
 			return
 		}
 	}
 
+	// Skip resources muted with resources mute command
+	if utils.MutedResources.Contains(resource) {
+		log.Debugf("Ignoring %s to %s/%v as the resource is muted", eventType, resource, objectMeta.Name)
+		return
+	}
+
 	log.Debugf("Processing %s to %s/%v in %s namespaces", eventType, resource, objectMeta.Name, objectMeta.Namespace)
 
 	// Check if Notify disabled
@@ -181,9 +193,19 @@ func sendEvent(obj, oldObj interface{}, c *config.Config, notifiers []notify.Not
 
 	// Create new event object
 	event := events.New(obj, eventType, resource, c.Settings.ClusterName)
+	// Skip events from ignored resource names
+	if !c.Settings.ResourceNames.IsAllowed(event.Name) {
+		log.Debugf("Ignoring %s to %s/%v as the name is filtered by settings.resourceNames", eventType, resource, event.Name)
+		return
+	}
+	// Skip k8s events with reasons filtered by settings.eventReasons
+	if utils.GetObjectTypeMetaData(obj).Kind == "Event" && !p.reasons.allow(event.Reason) {
+		log.Debugf("Ignoring %s event with reason %s as it is filtered by settings.eventReasons", resource, event.Reason)
+		return
+	}
 	// Skip older events
 	if !event.TimeStamp.IsZero() {
-		if event.TimeStamp.Before(startTime) {
+		if event.TimeStamp.Before(p.startTime) {
 			log.Debug("Skipping older events")
 			return
 		}
@@ -193,10 +215,10 @@ func sendEvent(obj, oldObj interface{}, c *config.Config, notifiers []notify.Not
 	if eventType == config.UpdateEvent {
 		var updateMsg string
 		// Check if all namespaces allowed
-		updateSetting, exist := utils.AllowedUpdateEventsMap[utils.KindNS{Resource: resource, Namespace: "all"}]
+		updateSetting, exist := p.allowedUpdates[utils.KindNS{Resource: resource, Namespace: "all"}]
 		if !exist {
 			// Check if specified namespace is allowed
-			updateSetting, exist = utils.AllowedUpdateEventsMap[utils.KindNS{Resource: resource, Namespace: objectMeta.Namespace}]
+			updateSetting, exist = p.allowedUpdates[utils.KindNS{Resource: resource, Namespace: objectMeta.Namespace}]
 		}
 		if exist {
 			// Calculate object diff as per the updateSettings
@@ -214,7 +236,7 @@ func sendEvent(obj, oldObj interface{}, c *config.Config, notifiers []notify.Not
 		// Send update notification only if fields in updateSetting are changed
 		if len(updateMsg) > 0 {
 			if updateSetting.IncludeDiff {
-				event.Messages = append(event.Messages, updateMsg)
+				event.Diff = updateMsg
 			}
 		} else {
 			// skipping least significant update
@@ -225,6 +247,10 @@ func sendEvent(obj, oldObj interface{}, c *config.Config, notifiers []notify.Not
 
 	// Filter events
 	event = filterengine.DefaultFilterEngine.Run(obj, event)
+	// Stop reminding about the resource once it recovers
+	if event.Type == config.InfoEvent || event.Type == config.DeleteEvent {
+		p.reminders.resolve(event)
+	}
 	if event.Skip {
 		log.Debugf("Skipping event: %#v", event)
 		return
@@ -247,9 +273,49 @@ func sendEvent(obj, oldObj interface{}, c *config.Config, notifiers []notify.Not
 		log.Debug("Skipping Recommendations in Event Notifications")
 	}
 
-	// Send event over notifiers
-	for _, n := range notifiers {
-		go n.SendEvent(event)
+	// Suppress events during planned maintenance
+	if w, _, ok := config.ActiveMaintenanceWindow(c.Settings.MaintenanceWindows, time.Now()); ok {
+		if w.RecordEvents {
+			events.Record(event)
+		}
+		log.Debugf("Suppressing %s to %s/%v during maintenance window %q", eventType, resource, event.Name, w.Name)
+		return
+	}
+
+	// Suppress events over the rate limit for the resource kind
+	if !p.limiter.allow(&event) {
+		log.Debugf("Suppressing %s to %s/%v as it exceeds settings.rateLimits", eventType, resource, event.Name)
+		return
+	}
+
+	// Logs are fetched only for the events which are sent
+	p.crashLogs.enrich(obj, &event)
+	p.reminders.track(event)
+	send(p, event)
+}
+
+// send sends the event over the notifiers of the pipeline
+func send(p *pipeline, event events.Event) {
+	p.router.route(&event)
+	events.IncSentCount()
+	events.Record(event)
+	metrics.IncEvents(event.Kind, event.Type.String(), string(event.Level))
+	for _, n := range p.notifiers {
+		// Skip notifiers configured for more severe events in the channel the event is routed to
+		if !notify.IsSevereEnough(n, event.Level, event.Channel) {
+			log.Debugf("Skipping %s event for %s as the level is below minSeverity", event.Level, notify.Name(n))
+			continue
+		}
+		n := n
+		sent := sends.goEvent(func() {
+			err := n.SendEvent(event)
+			metrics.IncNotifications(notify.Name(n), err)
+			notify.SendToDeadLetter(n, event, err)
+		})
+		if !sent {
+			log.Debugf("Dropping event %#v as BotKube is shutting down", event)
+			return
+		}
 	}
 }
 
@@ -261,49 +327,7 @@ func sendMessage(c *config.Config, notifiers []notify.Notifier, msg string) {
 
 	// Send message over notifiers
 	for _, n := range notifiers {
-		go n.SendMessage(msg)
-	}
-}
-
-func configWatcher(c *config.Config, notifiers []notify.Notifier) {
-	configPath := os.Getenv("CONFIG_PATH")
-	configFile := filepath.Join(configPath, config.ResourceConfigFileName)
-
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		log.Fatal("Failed to create file watcher ", err)
-	}
-	defer watcher.Close()
-
-	done := make(chan bool)
-	go func() {
-		for {
-			select {
-			case _, ok := <-watcher.Events:
-				if !ok {
-					log.Errorf("Error in getting events for config file:%s. Error: %s", configFile, err.Error())
-					return
-				}
-				log.Infof("Config file %s is updated. Hence restarting the Pod", configFile)
-				done <- true
-
-			case err, ok := <-watcher.Errors:
-				if !ok {
-					log.Errorf("Error in getting events for config file:%s. Error: %s", configFile, err.Error())
-					return
-				}
-			}
-		}
-	}()
-	log.Infof("Registering watcher on configfile %s", configFile)
-	err = watcher.Add(configFile)
-	if err != nil {
-		log.Errorf("Unable to register watch on config file:%s. Error: %s", configFile, err.Error())
-		return
+		n := n
+		sends.goMessage(func() { _ = n.SendMessage(msg) })
 	}
-	<-done
-	sendMessage(c, notifiers, fmt.Sprintf(configUpdateMsg, c.Settings.ClusterName))
-	// Wait for Notifier to send message
-	time.Sleep(5 * time.Second)
-	os.Exit(0)
 }
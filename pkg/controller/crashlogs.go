@@ -0,0 +1,132 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package controller
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/execute"
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+const (
+	defaultCrashLogLines = 10
+	crashLogsMsg         = "Last logs of container '%s':\n%s"
+)
+
+// containerFieldPathRegex matches the container in the field path of k8s events, e.g spec.containers{nginx}
+var containerFieldPathRegex = regexp.MustCompile(`^spec\.(?:init)?[cC]ontainers\{(.+)\}$`)
+
+// logsFetcher returns the last lines of the container logs, the logs of the previous instance if previous is true
+type logsFetcher func(namespace, pod, container string, previous bool, lines int) (string, error)
+
+// crashLogs adds the logs of the crashing containers to the Pod events
+type crashLogs struct {
+	lines int
+	fetch logsFetcher
+}
+
+// newCrashLogs returns nil if settings.enrichCrashLogs is not enabled
+func newCrashLogs(c config.Settings) *crashLogs {
+	if !c.EnrichCrashLogs {
+		return nil
+	}
+	lines := c.CrashLogLines
+	switch {
+	case lines <= 0:
+		lines = defaultCrashLogLines
+	case lines > config.MaxCrashLogLines:
+		lines = config.MaxCrashLogLines
+	}
+	return &crashLogs{lines: lines, fetch: execute.ContainerLogs}
+}
+
+// enrich appends the logs of the containers crashing in the Pod event to the messages
+// Logs of the crashed instance are used if available, the logs of the running instance otherwise
+func (c *crashLogs) enrich(obj interface{}, event *events.Event) {
+	if c == nil || event.Kind != "Pod" {
+		return
+	}
+	for _, container := range crashedContainers(obj) {
+		out, err := c.fetch(event.Namespace, event.Name, container, true, c.lines)
+		if err != nil || len(strings.TrimSpace(out)) == 0 {
+			out, err = c.fetch(event.Namespace, event.Name, container, false, c.lines)
+		}
+		if err != nil {
+			log.Debugf("Unable to get logs of pod %s/%s container %s. %s", event.Namespace, event.Name, container, err.Error())
+			continue
+		}
+		if out = strings.TrimRight(out, "\n"); len(out) == 0 {
+			continue
+		}
+		name := container
+		if len(name) == 0 {
+			name = event.Name
+		}
+		event.Messages = append(event.Messages, fmt.Sprintf(crashLogsMsg, name, out))
+	}
+}
+
+// crashedContainers returns the containers of the Pod in CrashLoopBackOff or terminated with Error
+// For BackOff k8s events the container is taken from the involved object, empty if the event doesn't name it
+func crashedContainers(obj interface{}) []string {
+	unstructuredObj, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+	if utils.GetObjectTypeMetaData(obj).Kind == "Event" {
+		var eventObj coreV1.Event
+		if err := utils.TransformIntoTypedObject(unstructuredObj, &eventObj); err != nil {
+			log.Errorf("Unable to transform object type: %v, into type: %v", reflect.TypeOf(obj), reflect.TypeOf(eventObj))
+			return nil
+		}
+		// BackOff is also the reason of image pull retries
+		if eventObj.Reason != "BackOff" || !strings.Contains(eventObj.Message, "restarting failed container") {
+			return nil
+		}
+		if m := containerFieldPathRegex.FindStringSubmatch(eventObj.InvolvedObject.FieldPath); m != nil {
+			return []string{m[1]}
+		}
+		return []string{""}
+	}
+
+	var pod coreV1.Pod
+	if err := utils.TransformIntoTypedObject(unstructuredObj, &pod); err != nil {
+		log.Errorf("Unable to transform object type: %v, into type: %v", reflect.TypeOf(obj), reflect.TypeOf(pod))
+		return nil
+	}
+	var containers []string
+	for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		waiting, terminated := status.State.Waiting, status.State.Terminated
+		if waiting != nil && waiting.Reason == "CrashLoopBackOff" || terminated != nil && terminated.Reason == "Error" {
+			containers = append(containers, status.Name)
+		}
+	}
+	return containers
+}
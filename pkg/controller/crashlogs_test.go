@@ -0,0 +1,164 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package controller
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+func toUnstructured(t *testing.T, obj interface{}) *unstructured.Unstructured {
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &unstructured.Unstructured{Object: m}
+}
+
+func TestNewCrashLogs(t *testing.T) {
+	tests := map[string]struct {
+		settings config.Settings
+		expected *int
+	}{
+		`disabled`:      {settings: config.Settings{CrashLogLines: 5}},
+		`default lines`: {settings: config.Settings{EnrichCrashLogs: true}, expected: intPtr(defaultCrashLogLines)},
+		`custom lines`:  {settings: config.Settings{EnrichCrashLogs: true, CrashLogLines: 5}, expected: intPtr(5)},
+		`bounded lines`: {settings: config.Settings{EnrichCrashLogs: true, CrashLogLines: 500}, expected: intPtr(config.MaxCrashLogLines)},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			c := newCrashLogs(test.settings)
+			if test.expected == nil {
+				if c != nil {
+					t.Errorf("expected: nil != actual: %+v\n", c)
+				}
+				return
+			}
+			if c == nil || c.lines != *test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", *test.expected, c)
+			}
+		})
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestCrashLogsEnrich(t *testing.T) {
+	crashingPod := coreV1.Pod{
+		TypeMeta:   metaV1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+		ObjectMeta: metaV1.ObjectMeta{Name: "nginx", Namespace: "default"},
+		Status: coreV1.PodStatus{ContainerStatuses: []coreV1.ContainerStatus{
+			{Name: "sidecar", State: coreV1.ContainerState{Running: &coreV1.ContainerStateRunning{}}},
+			{Name: "nginx", State: coreV1.ContainerState{Waiting: &coreV1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+		}},
+	}
+	backOffEvent := coreV1.Event{
+		TypeMeta:       metaV1.TypeMeta{Kind: "Event", APIVersion: "v1"},
+		ObjectMeta:     metaV1.ObjectMeta{Name: "nginx.16", Namespace: "default"},
+		InvolvedObject: coreV1.ObjectReference{Kind: "Pod", Name: "nginx", Namespace: "default", FieldPath: "spec.containers{nginx}"},
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+	}
+	imagePullEvent := backOffEvent
+	imagePullEvent.Message = `Back-off pulling image "nginx:foo"`
+
+	type call struct {
+		container string
+		previous  bool
+	}
+	tests := map[string]struct {
+		obj      interface{}
+		logs     map[call]string
+		expected []string
+		calls    []call
+	}{
+		`crash looping pod`: {
+			obj:      toUnstructured(t, &crashingPod),
+			logs:     map[call]string{{"nginx", true}: "starting\npanic: boom\n"},
+			expected: []string{"Back-off", "Last logs of container 'nginx':\nstarting\npanic: boom"},
+			calls:    []call{{"nginx", true}},
+		},
+		`back-off k8s event`: {
+			obj:      toUnstructured(t, &backOffEvent),
+			logs:     map[call]string{{"nginx", true}: "panic: boom"},
+			expected: []string{"Back-off", "Last logs of container 'nginx':\npanic: boom"},
+			calls:    []call{{"nginx", true}},
+		},
+		`current logs if previous are not available`: {
+			obj:      toUnstructured(t, &crashingPod),
+			logs:     map[call]string{{"nginx", false}: "panic: boom"},
+			expected: []string{"Back-off", "Last logs of container 'nginx':\npanic: boom"},
+			calls:    []call{{"nginx", true}, {"nginx", false}},
+		},
+		`no logs`: {
+			obj:      toUnstructured(t, &crashingPod),
+			expected: []string{"Back-off"},
+			calls:    []call{{"nginx", true}, {"nginx", false}},
+		},
+		`image pull back-off`: {
+			obj:      toUnstructured(t, &imagePullEvent),
+			expected: []string{"Back-off"},
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			var calls []call
+			c := &crashLogs{lines: 3, fetch: func(namespace, pod, container string, previous bool, lines int) (string, error) {
+				if namespace != "default" || pod != "nginx" || lines != 3 {
+					t.Errorf("unexpected logs request %s/%s %d lines", namespace, pod, lines)
+				}
+				calls = append(calls, call{container, previous})
+				if out, ok := test.logs[call{container, previous}]; ok {
+					return out, nil
+				}
+				return "", fmt.Errorf("previous terminated container %q not found", container)
+			}}
+			event := events.Event{Kind: "Pod", Name: "nginx", Namespace: "default", Messages: []string{"Back-off"}}
+			c.enrich(test.obj, &event)
+			if !reflect.DeepEqual(test.expected, event.Messages) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, event.Messages)
+			}
+			if !reflect.DeepEqual(test.calls, calls) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.calls, calls)
+			}
+		})
+	}
+
+	// disabled enrichment and other kinds are untouched
+	event := events.Event{Kind: "Pod", Name: "nginx", Namespace: "default"}
+	var disabled *crashLogs
+	disabled.enrich(toUnstructured(t, &crashingPod), &event)
+	if len(event.Messages) != 0 {
+		t.Errorf("expected: no messages != actual: %+v\n", event.Messages)
+	}
+}
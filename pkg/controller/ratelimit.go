@@ -0,0 +1,139 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+const suppressedEventsMsg = "%d more update(s) for this resource were suppressed by settings.rateLimits"
+
+// rateLimiter is a token bucket of update events per resource for the kinds configured in settings.rateLimits
+type rateLimiter struct {
+	mu sync.Mutex
+	// limits is a map of lowercase kind to events allowed per minute
+	limits    map[string]int
+	buckets   map[string]*bucket
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens     float64
+	last       time.Time
+	suppressed int
+}
+
+// newRateLimiter returns nil if no valid limits are configured
+func newRateLimiter(c map[string]config.RateLimit) *rateLimiter {
+	limits := map[string]int{}
+	for kind, limit := range c {
+		if limit.PerMinute > 0 {
+			limits[strings.ToLower(kind)] = limit.PerMinute
+		}
+	}
+	if len(limits) == 0 {
+		return nil
+	}
+	return &rateLimiter{
+		limits:  limits,
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+// allow takes a token for the update event resource and returns false if the event has to be suppressed
+// Other events are always allowed, the count of updates suppressed since the last allowed event is added to the event messages
+func (r *rateLimiter) allow(event *events.Event) bool {
+	if r == nil {
+		return true
+	}
+	limit, ok := r.limits[strings.ToLower(event.Kind)]
+	if !ok {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	r.prune(now)
+
+	key := fmt.Sprintf("%s/%s/%s", strings.ToLower(event.Kind), event.Namespace, event.Name)
+	b, ok := r.buckets[key]
+	if event.Type != config.UpdateEvent {
+		if ok {
+			b.report(event)
+		}
+		// No more updates are reported for a deleted resource
+		if event.Type == config.DeleteEvent {
+			delete(r.buckets, key)
+		}
+		return true
+	}
+	if !ok {
+		b = &bucket{tokens: float64(limit), last: now}
+		r.buckets[key] = b
+	}
+	b.refill(now, limit)
+	if b.tokens < 1 {
+		b.suppressed++
+		return false
+	}
+	b.tokens--
+	b.report(event)
+	return true
+}
+
+// report adds the count of suppressed events to the event messages and resets it
+func (b *bucket) report(event *events.Event) {
+	if b.suppressed > 0 {
+		event.Messages = append(event.Messages, fmt.Sprintf(suppressedEventsMsg, b.suppressed))
+		b.suppressed = 0
+	}
+}
+
+func (b *bucket) refill(now time.Time, limit int) {
+	b.tokens += now.Sub(b.last).Minutes() * float64(limit)
+	if b.tokens > float64(limit) {
+		b.tokens = float64(limit)
+	}
+	b.last = now
+}
+
+// prune removes the buckets which are full again, once a minute
+// Buckets with suppressed events are kept for an hour to report the count with the next event
+func (r *rateLimiter) prune(now time.Time) {
+	if now.Sub(r.lastPrune) < time.Minute {
+		return
+	}
+	r.lastPrune = now
+	for key, b := range r.buckets {
+		idle := now.Sub(b.last)
+		if (b.suppressed == 0 && idle >= time.Minute) || idle >= time.Hour {
+			delete(r.buckets, key)
+		}
+	}
+}
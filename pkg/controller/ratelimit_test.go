@@ -0,0 +1,115 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := newRateLimiter(map[string]config.RateLimit{"Pod": {PerMinute: 2}, "Service": {PerMinute: 0}})
+	r.now = func() time.Time { return now }
+
+	pod := events.Event{Kind: "Pod", Namespace: "default", Name: "nginx", Type: config.UpdateEvent}
+	steps := []struct {
+		after    time.Duration
+		event    events.Event
+		allowed  bool
+		messages []string
+	}{
+		{0, pod, true, nil},
+		{0, pod, true, nil},
+		{0, pod, false, nil},
+		{time.Second, pod, false, nil},
+		// Other resources and kinds have their own buckets
+		{0, events.Event{Kind: "Pod", Namespace: "default", Name: "redis", Type: config.UpdateEvent}, true, nil},
+		{0, events.Event{Kind: "Service", Namespace: "default", Name: "nginx", Type: config.UpdateEvent}, true, nil},
+		{0, events.Event{Kind: "Service", Namespace: "default", Name: "nginx", Type: config.UpdateEvent}, true, nil},
+		{0, events.Event{Kind: "Service", Namespace: "default", Name: "nginx", Type: config.UpdateEvent}, true, nil},
+		// One token is refilled in 30s
+		{30 * time.Second, pod, true, []string{"2 more update(s) for this resource were suppressed by settings.rateLimits"}},
+		{0, pod, false, nil},
+		{2 * time.Minute, pod, true, []string{"1 more update(s) for this resource were suppressed by settings.rateLimits"}},
+		{0, pod, true, nil},
+	}
+	for i, step := range steps {
+		now = now.Add(step.after)
+		event := step.event
+		if actual := r.allow(&event); actual != step.allowed {
+			t.Errorf("step %d: expected: %+v != actual: %+v\n", i, step.allowed, actual)
+		}
+		if !reflect.DeepEqual(event.Messages, step.messages) {
+			t.Errorf("step %d: expected: %+v != actual: %+v\n", i, step.messages, event.Messages)
+		}
+	}
+}
+
+func TestRateLimiterOtherEvents(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := newRateLimiter(map[string]config.RateLimit{"Pod": {PerMinute: 1}})
+	r.now = func() time.Time { return now }
+
+	event := func(eventType config.EventType) events.Event {
+		return events.Event{Kind: "Pod", Namespace: "default", Name: "nginx", Type: eventType}
+	}
+	steps := []struct {
+		event    events.Event
+		allowed  bool
+		messages []string
+	}{
+		{event(config.CreateEvent), true, nil},
+		{event(config.UpdateEvent), true, nil},
+		{event(config.UpdateEvent), false, nil},
+		{event(config.UpdateEvent), false, nil},
+		// Error and delete events are not limited and report the suppressed updates
+		{event(config.ErrorEvent), true, []string{"2 more update(s) for this resource were suppressed by settings.rateLimits"}},
+		{event(config.UpdateEvent), false, nil},
+		{event(config.DeleteEvent), true, []string{"1 more update(s) for this resource were suppressed by settings.rateLimits"}},
+		{event(config.DeleteEvent), true, nil},
+		// The bucket of the deleted resource is removed
+		{event(config.UpdateEvent), true, nil},
+	}
+	for i, step := range steps {
+		event := step.event
+		if actual := r.allow(&event); actual != step.allowed {
+			t.Errorf("step %d: expected: %+v != actual: %+v\n", i, step.allowed, actual)
+		}
+		if !reflect.DeepEqual(event.Messages, step.messages) {
+			t.Errorf("step %d: expected: %+v != actual: %+v\n", i, step.messages, event.Messages)
+		}
+	}
+}
+
+func TestRateLimiterNotConfigured(t *testing.T) {
+	r := newRateLimiter(map[string]config.RateLimit{"Pod": {PerMinute: -1}})
+	if r != nil {
+		t.Errorf("expected: nil != actual: %+v\n", r)
+	}
+	event := events.Event{Kind: "Pod"}
+	if !r.allow(&event) {
+		t.Errorf("expected nil limiter to allow events")
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package controller
+
+import (
+	"strings"
+
+	"github.com/infracloudio/botkube/pkg/config"
+)
+
+// reasonFilter contains the lowercase reasons of settings.eventReasons
+type reasonFilter struct {
+	include map[string]bool
+	exclude map[string]bool
+}
+
+// newReasonFilter returns nil if no reasons are configured
+func newReasonFilter(c config.EventReasons) *reasonFilter {
+	f := &reasonFilter{include: reasonSet(c.Include), exclude: reasonSet(c.Exclude)}
+	if len(f.include) == 0 && len(f.exclude) == 0 {
+		return nil
+	}
+	return f
+}
+
+func reasonSet(reasons []string) map[string]bool {
+	set := map[string]bool{}
+	for _, r := range reasons {
+		if r = strings.ToLower(strings.TrimSpace(r)); len(r) != 0 {
+			set[r] = true
+		}
+	}
+	return set
+}
+
+// allow returns true if the reason is included and not excluded
+func (f *reasonFilter) allow(reason string) bool {
+	if f == nil {
+		return true
+	}
+	reason = strings.ToLower(reason)
+	if len(f.include) != 0 && !f.include[reason] {
+		return false
+	}
+	return !f.exclude[reason]
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/infracloudio/botkube/pkg/config"
+)
+
+func TestReasonFilterAllow(t *testing.T) {
+	tests := map[string]struct {
+		reasons  config.EventReasons
+		reason   string
+		expected bool
+	}{
+		`nothing configured`:           {config.EventReasons{}, "Scheduled", true},
+		`empty reasons ignored`:        {config.EventReasons{Include: []string{""}}, "Scheduled", true},
+		`included`:                     {config.EventReasons{Include: []string{"BackOff", "Unhealthy"}}, "BackOff", true},
+		`included case-insensitive`:    {config.EventReasons{Include: []string{"backoff"}}, "BackOff", true},
+		`not included`:                 {config.EventReasons{Include: []string{"BackOff", "Unhealthy"}}, "Pulled", false},
+		`excluded`:                     {config.EventReasons{Exclude: []string{"Pulled", "Scheduled"}}, "Scheduled", false},
+		`not excluded`:                 {config.EventReasons{Exclude: []string{"Pulled", "Scheduled"}}, "BackOff", true},
+		`exclude takes precedence`:     {config.EventReasons{Include: []string{"Failed"}, Exclude: []string{"failed"}}, "Failed", false},
+		`included and other excluded`:  {config.EventReasons{Include: []string{"Failed"}, Exclude: []string{"Pulled"}}, "Failed", true},
+		`empty reason not in includes`: {config.EventReasons{Include: []string{"Failed"}}, "", false},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := newReasonFilter(test.reasons).allow(test.reason); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package controller
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/execute"
+	"github.com/infracloudio/botkube/pkg/filterengine"
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/notify"
+	"github.com/infracloudio/botkube/pkg/utils"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	configUpdateMsg = "Looks like the configuration is updated for cluster '%s'. I shall halt my watch till I read it."
+	configReloadMsg = "Configuration reloaded for cluster '%s'. My watch continues with the updated settings."
+
+	// reloadDelay is the time to wait for the config file events to settle before reloading
+	reloadDelay = 2 * time.Second
+	// configMapDataDir is the symlink swapped by Kubernetes on ConfigMap volume updates
+	configMapDataDir = "..data"
+)
+
+// NotifierBuilder creates the notifiers for the reloaded config
+// It is replaced by main to carry over the bots which are notifiers too, like MS Teams
+var NotifierBuilder = func(c *config.Config) []notify.Notifier {
+	notifiers := notify.WithDryRun(notify.ListNotifiers(c.Communications), c.Settings.DryRun)
+	return notify.WithCircuitBreaker(notifiers, c.Settings.Notifiers.CircuitBreaker, c.Settings.ClusterName)
+}
+
+// pipeline is the config and everything built from it to process the events
+// It is swapped as a whole on config reload so that an event never sees a partial config
+type pipeline struct {
+	conf           *config.Config
+	notifiers      []notify.Notifier
+	allowedEvents  map[utils.EventKind]bool
+	allowedUpdates map[utils.KindNS]config.UpdateSetting
+	limiter        *rateLimiter
+	reasons        *reasonFilter
+	reminders      *reminders
+	crashLogs      *crashLogs
+	router         *router
+	// startTime is used to skip the events which happened before the informers were started
+	startTime time.Time
+}
+
+var (
+	current atomic.Value
+	// informersStopCh stops the running informers, accessed only by RegisterInformers and configWatcher
+	informersStopCh chan struct{}
+)
+
+func newPipeline(c *config.Config, notifiers []notify.Notifier, startTime time.Time) *pipeline {
+	return &pipeline{
+		conf:           c,
+		notifiers:      notifiers,
+		allowedEvents:  utils.AllowedEventKindsMap,
+		allowedUpdates: utils.AllowedUpdateEventsMap,
+		limiter:        newRateLimiter(c.Settings.RateLimits),
+		reasons:        newReasonFilter(c.Settings.EventReasons),
+		reminders:      newReminders(c.Settings.Reminders),
+		crashLogs:      newCrashLogs(c.Settings),
+		router:         newRouter(c.Settings.Routing),
+		startTime:      startTime,
+	}
+}
+
+func loadPipeline() *pipeline {
+	return current.Load().(*pipeline)
+}
+
+// Notifiers returns the notifiers of the config in use, nil until RegisterInformers starts the controller
+func Notifiers() []notify.Notifier {
+	p, ok := current.Load().(*pipeline)
+	if !ok {
+		return nil
+	}
+	return p.notifiers
+}
+
+// configWatcher reloads the config when the config files are updated
+// BotKube restarts if the update touches the settings read only at startup
+func configWatcher() {
+	configPath := os.Getenv("CONFIG_PATH")
+	if len(configPath) == 0 {
+		configPath = "."
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Fatal("Failed to create file watcher ", err)
+	}
+	defer watcher.Close()
+
+	// Watch the directory since ConfigMap updates replace the files instead of writing to them
+	log.Infof("Registering watcher on config directory %s", configPath)
+	if err := watcher.Add(configPath); err != nil {
+		log.Errorf("Unable to register watch on config directory:%s. Error: %s", configPath, err.Error())
+		return
+	}
+
+	var reloadCh <-chan time.Time
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				log.Errorf("Error in getting events for config directory:%s", configPath)
+				return
+			}
+			if !isConfigFile(event.Name) {
+				continue
+			}
+			// An update generates a burst of events, reload once it is over
+			reloadCh = time.After(reloadDelay)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				log.Errorf("Error in getting events for config directory:%s", configPath)
+				return
+			}
+			log.Errorf("Error in watching config directory:%s. Error: %s", configPath, err.Error())
+
+		case <-reloadCh:
+			reloadCh = nil
+			restart, err := reload()
+			if err != nil {
+				log.Errorf("Failed to reload configuration, continuing with the current one. %s", err.Error())
+				continue
+			}
+			if restart {
+				p := loadPipeline()
+				log.Info("Config files are updated with settings applied only at startup. Hence restarting the Pod")
+				sendMessage(p.conf, p.notifiers, fmt.Sprintf(configUpdateMsg, p.conf.Settings.ClusterName))
+				// Wait for Notifier to send message
+				time.Sleep(5 * time.Second)
+				notify.Close(p.notifiers)
+				os.Exit(0)
+			}
+		}
+	}
+}
+
+func isConfigFile(path string) bool {
+	switch filepath.Base(path) {
+	case config.ResourceConfigFileName, config.CommunicationConfigFileName, configMapDataDir:
+		return true
+	}
+	return false
+}
+
+// reload reads and validates the config files, and swaps the config in use only if they are valid
+// Returns true without applying the config if BotKube has to be restarted for the changes
+func reload() (bool, error) {
+	old := loadPipeline()
+	c, err := config.New()
+	if err != nil {
+		return false, fmt.Errorf("Error in loading configuration. %s", err.Error())
+	}
+	for _, issue := range c.Validate() {
+		if issue.Fatal {
+			return false, fmt.Errorf("Invalid configuration. %s", issue.Message)
+		}
+	}
+	if restartRequired(old.conf, c) {
+		return true, nil
+	}
+
+	// Templates are the last thing that can be rejected, nothing is changed before they are loaded
+	if err := notify.InitTemplates(c.Settings.Templates); err != nil {
+		return false, fmt.Errorf("Error in loading templates. %s", err.Error())
+	}
+	notify.InitClusterScopedKinds(c.Settings.ClusterScopedKinds)
+	notify.InitEventVerbs(c.Settings.EventVerbs)
+	execute.InitConfig(c)
+	execute.InitCommandPrefix(c.Settings.CommandPrefix)
+	execute.InitInstance(c.Settings.InstanceName, c.Settings.UnaddressedCommands)
+	execute.InitImpersonation(c.Settings.AllowImpersonation)
+	execute.InitNodeOps(c.Settings.AllowNodeOps, c.Settings.DrainDeleteEmptyDirData)
+	execute.InitApproval(c.Settings.RequireApproval)
+	execute.InitConfirmation(c.Settings.RequireConfirmation)
+	execute.InitExec(c.Settings.AllowExec, c.Settings.ExecAllowlist)
+	if err := utils.InitProxy(c.Settings.Proxy); err != nil {
+		log.Errorf("%s. Proxy from HTTPS_PROXY env is used", err.Error())
+	}
+	filterengine.InitDeduplication(c.Settings.Deduplication)
+	if err := execute.InitKubectl(c.Settings.Kubectl); err != nil {
+		log.Errorf("%s. kubectl commands will fail until the path in settings.kubectl is fixed", err.Error())
+	}
+	applyFilterSettings(old.conf, c)
+
+	// Restart the informers for the updated resources
+	startTime := time.Now()
+	close(informersStopCh)
+	utils.InitInformerMap(c)
+	utils.InitResourceMap(c)
+	p := newPipeline(c, NotifierBuilder(c), startTime)
+	current.Store(p)
+	informersStopCh = startInformers(c)
+	go remind(p, informersStopCh)
+	notify.Close(old.notifiers)
+
+	log.Info("Configuration reloaded")
+	sendMessage(c, p.notifiers, fmt.Sprintf(configReloadMsg, c.Settings.ClusterName))
+	return false, nil
+}
+
+// applyFilterSettings sets the filter scopes from the new config
+// Filters removed from settings.filters run in all the namespaces again
+// Enabled state is applied only for the filters where it changed, to keep the state set with filters commands
+func applyFilterSettings(old, updated *config.Config) {
+	for name, setting := range old.Settings.Filters {
+		if _, ok := updated.Settings.Filters[name]; !ok {
+			filterengine.DefaultFilterEngine.SetFilterScope(name, config.Namespaces{})
+			if !setting.IsEnabled() {
+				filterengine.DefaultFilterEngine.SetFilter(name, true)
+			}
+		}
+	}
+	for name, setting := range updated.Settings.Filters {
+		if err := filterengine.DefaultFilterEngine.SetFilterScope(name, setting.Namespaces); err != nil {
+			log.Errorf("Failed to configure filter %s. %s", name, err.Error())
+			continue
+		}
+		if setting.IsEnabled() != old.Settings.Filters[name].IsEnabled() {
+			filterengine.DefaultFilterEngine.SetFilter(name, setting.IsEnabled())
+		}
+	}
+}
+
+// restartRequired reports whether the settings read only at startup are changed
+// These are used by the bots, metrics and health servers and the upgrade notifier
+func restartRequired(old, updated *config.Config) bool {
+	o, u := old.Communications, updated.Communications
+	if !reflect.DeepEqual(o.Slack, u.Slack) || !reflect.DeepEqual(o.Mattermost, u.Mattermost) ||
+		!reflect.DeepEqual(o.Discord, u.Discord) || !reflect.DeepEqual(o.Teams, u.Teams) {
+		return true
+	}
+	before, after := old.Settings, updated.Settings
+	return before.ClusterName != after.ClusterName ||
+		before.ConfigWatcher != after.ConfigWatcher ||
+		before.UpgradeNotifier != after.UpgradeNotifier ||
+		before.Kubectl.Enabled != after.Kubectl.Enabled ||
+		before.Kubectl.RestrictAccess != after.Kubectl.RestrictAccess ||
+		before.Kubectl.DefaultNamespace != after.Kubectl.DefaultNamespace ||
+		before.Metrics.Port != after.Metrics.Port ||
+		before.Health.Port != after.Health.Port ||
+		before.Kubernetes != after.Kubernetes ||
+		before.Notifiers.DeadLetter.Path != after.Notifiers.DeadLetter.Path
+}
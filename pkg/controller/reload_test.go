@@ -0,0 +1,117 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/notify"
+)
+
+func TestRestartRequired(t *testing.T) {
+	base := func() *config.Config {
+		c := &config.Config{}
+		c.Settings.ClusterName = "test"
+		c.Communications.Slack.Enabled = true
+		c.Communications.Slack.Channel = "general"
+		return c
+	}
+	tests := map[string]struct {
+		update   func(c *config.Config)
+		expected bool
+	}{
+		`no changes`: {
+			update:   func(c *config.Config) {},
+			expected: false,
+		},
+		`resources changed`: {
+			update: func(c *config.Config) {
+				c.Resources = []config.Resource{{Name: "v1/pods", Events: []config.EventType{config.CreateEvent}}}
+			},
+			expected: false,
+		},
+		`webhook notifier enabled`: {
+			update: func(c *config.Config) {
+				c.Communications.Webhook.Enabled = true
+				c.Communications.Webhook.URL = "http://localhost"
+			},
+			expected: false,
+		},
+		`slack channel changed`: {
+			update:   func(c *config.Config) { c.Communications.Slack.Channel = "random" },
+			expected: true,
+		},
+		`cluster name changed`: {
+			update:   func(c *config.Config) { c.Settings.ClusterName = "prod" },
+			expected: true,
+		},
+		`kubectl enabled`: {
+			update:   func(c *config.Config) { c.Settings.Kubectl.Enabled = true },
+			expected: true,
+		},
+		`kubernetes client qps changed`: {
+			update:   func(c *config.Config) { c.Settings.Kubernetes.QPS = 50 },
+			expected: true,
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			updated := base()
+			test.update(updated)
+			if actual := restartRequired(base(), updated); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestIsConfigFile(t *testing.T) {
+	tests := map[string]bool{
+		"/config/resource_config.yaml": true,
+		"/config/comm_config.yaml":     true,
+		"/config/..data":               true,
+		"/config/..2021_01_01.123":     false,
+		"/config/other.yaml":           false,
+	}
+	for path, expected := range tests {
+		if actual := isConfigFile(path); actual != expected {
+			t.Errorf("%s: expected: %+v != actual: %+v\n", path, expected, actual)
+		}
+	}
+}
+
+func TestNotifiers(t *testing.T) {
+	c := &config.Config{}
+	old, updated := []notify.Notifier{&notify.Webhook{URL: "old"}}, []notify.Notifier{&notify.Webhook{URL: "updated"}}
+
+	current.Store(newPipeline(c, old, time.Now()))
+	if actual := Notifiers(); !reflect.DeepEqual(actual, old) {
+		t.Errorf("expected: %+v != actual: %+v\n", old, actual)
+	}
+	// Reload stores the pipeline with the new notifiers
+	current.Store(newPipeline(c, updated, time.Now()))
+	if actual := Notifiers(); !reflect.DeepEqual(actual, updated) {
+		t.Errorf("expected: %+v != actual: %+v\n", updated, actual)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/log"
+)
+
+// reminderTick is the maximum time between checks for due reminders
+const reminderTick = time.Minute
+
+// reminders tracks the resources in error and repeats their last error notification every interval
+// A resource is tracked until a Normal event or a delete event of the resource arrives
+type reminders struct {
+	mu       sync.Mutex
+	interval time.Duration
+	ongoing  map[string]*ongoingError
+	now      func() time.Time
+}
+
+type ongoingError struct {
+	event events.Event
+	since time.Time
+	next  time.Time
+	sent  int
+}
+
+// newReminders returns nil if settings.reminders.errorInterval is not set
+func newReminders(c config.Reminders) *reminders {
+	if c.ErrorInterval <= 0 {
+		return nil
+	}
+	return &reminders{
+		interval: c.ErrorInterval,
+		ongoing:  map[string]*ongoingError{},
+		now:      time.Now,
+	}
+}
+
+func reminderKey(event events.Event) string {
+	return fmt.Sprintf("%s/%s/%s", strings.ToLower(event.Kind), event.Namespace, event.Name)
+}
+
+// track records the error events sent, the next reminder is due an interval after the last error notification
+func (r *reminders) track(event events.Event) {
+	if r == nil || !event.Level.IsAtLeast(config.Error) || event.Type == config.DeleteEvent {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	key := reminderKey(event)
+	e, ok := r.ongoing[key]
+	if !ok {
+		e = &ongoingError{since: now}
+		r.ongoing[key] = e
+	}
+	e.event = event
+	e.next = now.Add(r.interval)
+}
+
+// resolve stops the reminders about the event resource
+func (r *reminders) resolve(event events.Event) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := reminderKey(event)
+	if _, ok := r.ongoing[key]; ok {
+		log.Debugf("Stopping reminders about %s as it recovered", key)
+		delete(r.ongoing, key)
+	}
+}
+
+// due returns the reminders to be sent at now and schedules the next ones
+func (r *reminders) due(now time.Time) []events.Event {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []events.Event
+	for _, e := range r.ongoing {
+		if now.Before(e.next) {
+			continue
+		}
+		e.sent++
+		e.next = now.Add(r.interval)
+		due = append(due, reminderEvent(e.event, e.sent, now.Sub(e.since).Round(time.Minute), now))
+	}
+	return due
+}
+
+// reminderEvent returns the copy of the error event for the nth reminder, the tone escalates with n
+func reminderEvent(event events.Event, n int, failingFor time.Duration, now time.Time) events.Event {
+	var prefix, msg string
+	resource := fmt.Sprintf("%s %s", event.Kind, event.Name)
+	if len(event.Namespace) != 0 {
+		resource = fmt.Sprintf("%s %s/%s", event.Kind, event.Namespace, event.Name)
+	}
+	switch {
+	case n == 1:
+		prefix = "Reminder"
+		msg = fmt.Sprintf("%s is still failing after %s", resource, failingFor)
+	case n == 2:
+		prefix = "Still failing"
+		msg = fmt.Sprintf("%s has been failing for %s, reminder #%d", resource, failingFor, n)
+	default:
+		prefix = "Needs attention"
+		msg = fmt.Sprintf(":rotating_light: %s has been failing for %s and is still not resolved, reminder #%d", resource, failingFor, n)
+	}
+	event.Title = fmt.Sprintf("%s: %s", prefix, event.Title)
+	event.Messages = append([]string{msg}, event.Messages...)
+	event.TimeStamp = now
+	return event
+}
+
+// remind sends the due reminders of the pipeline until stopCh is closed
+// Reminders are not sent during maintenance windows, they are sent once the window ends
+func remind(p *pipeline, stopCh <-chan struct{}) {
+	r := p.reminders
+	if r == nil {
+		return
+	}
+	tick := reminderTick
+	if r.interval < tick {
+		tick = r.interval
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			now := r.now()
+			if !config.Notify {
+				continue
+			}
+			if _, _, ok := config.ActiveMaintenanceWindow(p.conf.Settings.MaintenanceWindows, now); ok {
+				continue
+			}
+			for _, event := range r.due(now) {
+				log.Debugf("Sending reminder about %s", reminderKey(event))
+				send(p, event)
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+func TestReminders(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := newReminders(config.Reminders{ErrorInterval: time.Hour})
+	r.now = func() time.Time { return now }
+
+	crash := events.Event{Kind: "Pod", Namespace: "default", Name: "nginx", Title: "v1/pods error", Type: config.ErrorEvent, Level: config.Error, Messages: []string{"Back-off restarting failed container"}}
+	recovered := events.Event{Kind: "Pod", Namespace: "default", Name: "nginx", Type: config.InfoEvent, Level: config.Info}
+	steps := []struct {
+		after    time.Duration
+		track    *events.Event
+		resolve  *events.Event
+		expected []events.Event
+	}{
+		{after: 0, track: &crash},
+		{after: 30 * time.Minute},
+		// Events which are not errors are not tracked
+		{after: 0, track: &events.Event{Kind: "Pod", Namespace: "default", Name: "redis", Type: config.CreateEvent, Level: config.Info}},
+		{after: 30 * time.Minute, expected: []events.Event{{
+			Kind: "Pod", Namespace: "default", Name: "nginx", Title: "Reminder: v1/pods error", Type: config.ErrorEvent, Level: config.Error,
+			Messages:  []string{"Pod default/nginx is still failing after 1h0m0s", "Back-off restarting failed container"},
+			TimeStamp: now.Add(time.Hour),
+		}}},
+		{after: 30 * time.Minute},
+		{after: 30 * time.Minute, expected: []events.Event{{
+			Kind: "Pod", Namespace: "default", Name: "nginx", Title: "Still failing: v1/pods error", Type: config.ErrorEvent, Level: config.Error,
+			Messages:  []string{"Pod default/nginx has been failing for 2h0m0s, reminder #2", "Back-off restarting failed container"},
+			TimeStamp: now.Add(2 * time.Hour),
+		}}},
+		// A new error notification postpones the next reminder
+		{after: 30 * time.Minute, track: &crash},
+		{after: 30 * time.Minute},
+		{after: 30 * time.Minute, expected: []events.Event{{
+			Kind: "Pod", Namespace: "default", Name: "nginx", Title: "Needs attention: v1/pods error", Type: config.ErrorEvent, Level: config.Error,
+			Messages:  []string{":rotating_light: Pod default/nginx has been failing for 3h30m0s and is still not resolved, reminder #3", "Back-off restarting failed container"},
+			TimeStamp: now.Add(3*time.Hour + 30*time.Minute),
+		}}},
+		// Reminders stop once the resource recovers
+		{after: 0, resolve: &recovered},
+		{after: 2 * time.Hour},
+	}
+	for i, step := range steps {
+		now = now.Add(step.after)
+		if step.track != nil {
+			r.track(*step.track)
+		}
+		if step.resolve != nil {
+			r.resolve(*step.resolve)
+		}
+		actual := r.due(now)
+		if !reflect.DeepEqual(actual, step.expected) {
+			t.Errorf("step %d: expected: %+v != actual: %+v\n", i, step.expected, actual)
+		}
+	}
+}
+
+func TestRemindersNotConfigured(t *testing.T) {
+	r := newReminders(config.Reminders{})
+	if r != nil {
+		t.Errorf("expected: nil != actual: %+v\n", r)
+	}
+	r.track(events.Event{Kind: "Pod", Level: config.Error})
+	r.resolve(events.Event{Kind: "Pod"})
+	if actual := r.due(time.Now()); actual != nil {
+		t.Errorf("expected: nil != actual: %+v\n", actual)
+	}
+}
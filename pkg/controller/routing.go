@@ -0,0 +1,65 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package controller
+
+import (
+	"strings"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+// router sets the channel of the events from settings.routing
+type router struct {
+	rules []config.RoutingRule
+}
+
+// newRouter returns nil if no routing rules are configured, rules without channel are ignored
+func newRouter(rules []config.RoutingRule) *router {
+	r := &router{}
+	for _, rule := range rules {
+		if rule.Channel = strings.TrimSpace(rule.Channel); len(rule.Channel) != 0 {
+			r.rules = append(r.rules, rule)
+		}
+	}
+	if len(r.rules) == 0 {
+		return nil
+	}
+	return r
+}
+
+// route sets the channel of the first matching rule, the channel from botkube.io/channel annotation is kept
+func (r *router) route(event *events.Event) {
+	if r == nil || len(event.Channel) != 0 {
+		return
+	}
+	for _, rule := range r.rules {
+		if matchRoutingPattern(rule.Namespace, event.Namespace) && matchRoutingPattern(strings.ToLower(rule.Kind), strings.ToLower(event.Kind)) {
+			event.Channel = rule.Channel
+			return
+		}
+	}
+}
+
+// matchRoutingPattern returns true for empty pattern, otherwise the value must match the pattern with * wildcard
+func matchRoutingPattern(pattern, value string) bool {
+	return len(pattern) == 0 || utils.MatchNamespace(pattern, value)
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package controller
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/notify"
+)
+
+func TestRouterRoute(t *testing.T) {
+	rules := []config.RoutingRule{
+		{Namespace: "kube-system", Channel: "platform"},
+		{Namespace: "apps", Kind: "Deployment", Channel: "deployments"},
+		{Namespace: "apps", Channel: "dev"},
+		{Namespace: "team-*", Channel: "teams"},
+		{Kind: "node", Channel: "infra"},
+		{Namespace: "ignored"},
+	}
+	tests := map[string]struct {
+		event    events.Event
+		expected string
+	}{
+		`namespace rule`:                 {events.Event{Namespace: "kube-system", Kind: "Pod"}, "platform"},
+		`first match wins`:               {events.Event{Namespace: "apps", Kind: "Deployment"}, "deployments"},
+		`namespace rule after kind rule`: {events.Event{Namespace: "apps", Kind: "Pod"}, "dev"},
+		`wildcard namespace`:             {events.Event{Namespace: "team-x", Kind: "Pod"}, "teams"},
+		`kind is case insensitive`:       {events.Event{Kind: "Node"}, "infra"},
+		`no match uses default channel`:  {events.Event{Namespace: "prod", Kind: "Pod"}, ""},
+		`rule without channel ignored`:   {events.Event{Namespace: "ignored", Kind: "Pod"}, ""},
+		`annotation channel is kept`:     {events.Event{Namespace: "kube-system", Kind: "Pod", Channel: "#team-x"}, "#team-x"},
+	}
+	r := newRouter(rules)
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			event := test.event
+			r.route(&event)
+			if event.Channel != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, event.Channel)
+			}
+		})
+	}
+}
+
+func TestNewRouterWithoutRules(t *testing.T) {
+	r := newRouter([]config.RoutingRule{{Namespace: "apps", Channel: " "}})
+	if r != nil {
+		t.Errorf("expected nil router without valid rules, got %+v", r)
+	}
+	event := events.Event{Namespace: "apps"}
+	r.route(&event)
+	if event.Channel != "" {
+		t.Errorf("expected: %+v != actual: %+v\n", "", event.Channel)
+	}
+}
+
+// channelNotifier records the channels of the sent events, the notifier channel is "k8s-activity"
+type channelNotifier struct {
+	mu       sync.Mutex
+	sent     []string
+	severity map[string]config.Level
+}
+
+func (n *channelNotifier) SendEvent(event events.Event) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	channel := event.Channel
+	if len(channel) == 0 {
+		channel = "k8s-activity"
+	}
+	n.sent = append(n.sent, channel+"/"+event.Name)
+	return nil
+}
+
+func (n *channelNotifier) SendMessage(string) error { return nil }
+
+func (n *channelNotifier) Ready() error { return nil }
+
+func (n *channelNotifier) ChannelMinSeverity(channel string) config.Level {
+	if len(channel) == 0 {
+		channel = "k8s-activity"
+	}
+	return n.severity[channel]
+}
+
+func TestSendChannelSeverity(t *testing.T) {
+	n := &channelNotifier{severity: map[string]config.Level{"alerts": config.Warn}}
+	p := &pipeline{
+		notifiers: []notify.Notifier{n},
+		router:    newRouter([]config.RoutingRule{{Namespace: "prod", Channel: "alerts"}}),
+	}
+	for _, event := range []events.Event{
+		{Name: "prod-info", Namespace: "prod", Level: config.Info},
+		{Name: "prod-warn", Namespace: "prod", Level: config.Warn},
+		{Name: "prod-error", Namespace: "prod", Level: config.Error},
+		{Name: "dev-info", Namespace: "dev", Level: config.Info},
+		{Name: "dev-debug", Namespace: "dev", Level: config.Debug},
+	} {
+		send(p, event)
+	}
+	if !sends.wait(time.Second) {
+		t.Fatal("expected events to be sent")
+	}
+	expected := []string{"alerts/prod-error", "alerts/prod-warn", "k8s-activity/dev-debug", "k8s-activity/dev-info"}
+	sort.Strings(n.sent)
+	if !reflect.DeepEqual(n.sent, expected) {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, n.sent)
+	}
+}
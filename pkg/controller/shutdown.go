@@ -0,0 +1,95 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package controller
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultShutdownTimeout is used if settings.notifiers.shutdownTimeout is not configured
+const defaultShutdownTimeout = 10 * time.Second
+
+// sends tracks the notifications sent in background
+var sends sendTracker
+
+// sendTracker counts the notifications in progress so that shutdown can wait for them
+// Events are rejected once it is stopped, messages like the stop notification are still sent
+type sendTracker struct {
+	mu      sync.Mutex
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// goEvent runs send in a new goroutine, it returns false without running it if the tracker is stopped
+func (t *sendTracker) goEvent(send func()) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return false
+	}
+	t.run(send)
+	return true
+}
+
+// goMessage runs send in a new goroutine
+func (t *sendTracker) goMessage(send func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.run(send)
+}
+
+func (t *sendTracker) run(send func()) {
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		send()
+	}()
+}
+
+// stop rejects the events sent after it
+func (t *sendTracker) stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+}
+
+// wait returns true if the notifications in progress are sent before the timeout
+func (t *sendTracker) wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// shutdownTimeout returns the configured time to wait for notifications in progress
+func shutdownTimeout(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return defaultShutdownTimeout
+	}
+	return timeout
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package controller
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSendTracker(t *testing.T) {
+	var tracker sendTracker
+	release := make(chan struct{})
+	sent := make(chan string, 3)
+
+	if !tracker.goEvent(func() {
+		<-release
+		sent <- "event"
+	}) {
+		t.Fatal("expected event to be accepted before stop")
+	}
+	tracker.stop()
+	if tracker.goEvent(func() { sent <- "late event" }) {
+		t.Error("expected event to be rejected after stop")
+	}
+	tracker.goMessage(func() { sent <- "message" })
+
+	// Blocked event is not sent before the timeout
+	if tracker.wait(10 * time.Millisecond) {
+		t.Error("expected wait to time out while the event is in progress")
+	}
+	close(release)
+	if !tracker.wait(time.Second) {
+		t.Fatal("expected wait to return after the notifications are sent")
+	}
+	close(sent)
+
+	actual := map[string]bool{}
+	for s := range sent {
+		actual[s] = true
+	}
+	if !actual["event"] || !actual["message"] || actual["late event"] {
+		t.Errorf("expected: event and message != actual: %+v\n", actual)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	tests := map[string]struct {
+		timeout  time.Duration
+		expected time.Duration
+	}{
+		`not configured`: {0, defaultShutdownTimeout},
+		`configured`:     {30 * time.Second, 30 * time.Second},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := shutdownTimeout(test.timeout); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
@@ -68,7 +68,10 @@ func UpgradeNotifier(c *config.Config, notifiers []notify.Notifier) {
 			if notified == true {
 				return
 			}
-			// Check periodically
+			// Check periodically with the notifiers in use, they are replaced on config reload
+			if n := Notifiers(); n != nil {
+				notifiers = n
+			}
 			checkRelease(c, notifiers)
 		}
 	}
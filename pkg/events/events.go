@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/infracloudio/botkube/pkg/config"
@@ -30,9 +31,13 @@ import (
 	"github.com/infracloudio/botkube/pkg/utils"
 
 	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
+// sentCount is the number of events sent to notifiers since startup
+var sentCount uint64
+
 // Event to store required information from k8s objects
 type Event struct {
 	Code      string
@@ -52,11 +57,23 @@ type Event struct {
 	Action    string
 	Skip      bool `json:",omitempty"`
 	Resource  string
+	// Diff contains the changes in settings.updateSetting.fields for update events with includeDiff enabled
+	Diff string `json:",omitempty"`
+	// ClusterScoped is true if the object, or the object involved in k8s event, has no namespace
+	ClusterScoped bool
+	// CorrelationID groups the events of one run, e.g a Job and its Pods, see correlationID
+	CorrelationID string `json:",omitempty"`
 
 	Recommendations []string
 	Warnings        []string
 }
 
+// CorrelationIDAnnotation sets the correlation ID of the events of an object
+const CorrelationIDAnnotation = "botkube.io/correlation-id"
+
+// runKinds are the kinds whose objects are a run themselves, their events share the ID with the events of the objects they own
+var runKinds = map[string]bool{"Job": true}
+
 // LevelMap is a map of event type to Level
 var LevelMap map[config.EventType]config.Level
 
@@ -123,6 +140,46 @@ func New(object interface{}, eventType config.EventType, resource, clusterName s
 		event.Count = eventObj.Count
 		event.Action = eventObj.Action
 		event.TimeStamp = eventObj.LastTimestamp.Time
+		// objectMeta includes the annotations of the involved object
+		if id := objectMeta.Annotations[CorrelationIDAnnotation]; id != "" {
+			event.CorrelationID = id
+		} else if runKinds[eventObj.InvolvedObject.Kind] {
+			event.CorrelationID = runID(eventObj.InvolvedObject.Kind, eventObj.InvolvedObject.Namespace, eventObj.InvolvedObject.Name, string(eventObj.InvolvedObject.UID))
+		}
+	} else {
+		event.CorrelationID = correlationID(objectTypeMeta.Kind, objectMeta)
 	}
+	event.ClusterScoped = len(event.Namespace) == 0
 	return event
 }
+
+// correlationID returns the botkube.io/correlation-id annotation of the object if set
+// Otherwise objects owned by a run, e.g Pods of a Job, are correlated by the run, empty if the object is not part of a run
+// Objects of long-lived controllers, e.g ReplicaSet or DaemonSet, are correlated only with the annotation
+func correlationID(kind string, objectMeta metaV1.ObjectMeta) string {
+	if id := objectMeta.Annotations[CorrelationIDAnnotation]; id != "" {
+		return id
+	}
+	if runKinds[kind] {
+		return runID(kind, objectMeta.Namespace, objectMeta.Name, string(objectMeta.UID))
+	}
+	if owner := metaV1.GetControllerOf(&objectMeta); owner != nil && runKinds[owner.Kind] {
+		return runID(owner.Kind, objectMeta.Namespace, owner.Name, string(owner.UID))
+	}
+	return ""
+}
+
+// runID identifies a run by its owner, the UID tells apart the runs of recreated owners with the same name
+func runID(kind, namespace, name, uid string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", kind, namespace, name, uid)
+}
+
+// IncSentCount increments the count of events sent to notifiers
+func IncSentCount() {
+	atomic.AddUint64(&sentCount, 1)
+}
+
+// SentCount returns the number of events sent to notifiers since startup
+func SentCount() uint64 {
+	return atomic.LoadUint64(&sentCount)
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package events
+
+import (
+	"testing"
+
+	"github.com/infracloudio/botkube/pkg/config"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestNewClusterScoped(t *testing.T) {
+	tests := map[string]struct {
+		object   map[string]interface{}
+		expected bool
+	}{
+		`cluster scoped resource`: {
+			object: map[string]interface{}{
+				"apiVersion": "storage.k8s.io/v1",
+				"kind":       "StorageClass",
+				"metadata":   map[string]interface{}{"name": "standard"},
+			},
+			expected: true,
+		},
+		`namespaced resource`: {
+			object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata":   map[string]interface{}{"name": "nginx", "namespace": "default"},
+			},
+			expected: false,
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			event := New(&unstructured.Unstructured{Object: test.object}, config.CreateEvent, "test", "test")
+			if event.ClusterScoped != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, event.ClusterScoped)
+			}
+		})
+	}
+}
+
+func TestNewCorrelationID(t *testing.T) {
+	tests := map[string]struct {
+		object   map[string]interface{}
+		expected string
+	}{
+		`annotation`: {
+			object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata": map[string]interface{}{
+					"name":        "nginx",
+					"namespace":   "default",
+					"annotations": map[string]interface{}{CorrelationIDAnnotation: "release-42"},
+					"ownerReferences": []interface{}{
+						map[string]interface{}{"apiVersion": "batch/v1", "kind": "Job", "name": "backup", "uid": "1", "controller": true},
+					},
+				},
+			},
+			expected: "release-42",
+		},
+		`pod owned by job`: {
+			object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata": map[string]interface{}{
+					"name":      "backup-x2b4k",
+					"namespace": "default",
+					"ownerReferences": []interface{}{
+						map[string]interface{}{"apiVersion": "batch/v1", "kind": "Job", "name": "backup", "uid": "1", "controller": true},
+					},
+				},
+			},
+			expected: "Job/default/backup/1",
+		},
+		`job`: {
+			object: map[string]interface{}{
+				"apiVersion": "batch/v1",
+				"kind":       "Job",
+				"metadata":   map[string]interface{}{"name": "backup", "namespace": "default", "uid": "1"},
+			},
+			expected: "Job/default/backup/1",
+		},
+		`pod owned by replicaset`: {
+			object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata": map[string]interface{}{
+					"name":      "nginx-5d8f7-x2b4k",
+					"namespace": "default",
+					"ownerReferences": []interface{}{
+						map[string]interface{}{"apiVersion": "apps/v1", "kind": "ReplicaSet", "name": "nginx-5d8f7", "uid": "3", "controller": true},
+					},
+				},
+			},
+			expected: "",
+		},
+		`pod owned by daemonset`: {
+			object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata": map[string]interface{}{
+					"name":      "fluentd-x2b4k",
+					"namespace": "kube-system",
+					"ownerReferences": []interface{}{
+						map[string]interface{}{"apiVersion": "apps/v1", "kind": "DaemonSet", "name": "fluentd", "uid": "4", "controller": true},
+					},
+				},
+			},
+			expected: "",
+		},
+		`owner is not a controller`: {
+			object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata": map[string]interface{}{
+					"name":      "nginx",
+					"namespace": "default",
+					"ownerReferences": []interface{}{
+						map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap", "name": "owner", "uid": "2"},
+					},
+				},
+			},
+			expected: "",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			event := New(&unstructured.Unstructured{Object: test.object}, config.CreateEvent, "test", "test")
+			if event.CorrelationID != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, event.CorrelationID)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package events
+
+import (
+	"strings"
+	"sync"
+)
+
+// historySize is the maximum number of events kept in memory
+const historySize = 200
+
+// subscriptionBuffer is the number of events buffered for each subscriber
+const subscriptionBuffer = 100
+
+var (
+	// history is a bounded ring buffer of the events sent to notifiers
+	history = newRingBuffer(historySize)
+
+	// subscribers receive the recorded events, e.g for events tail command
+	subscribers = &subscriberSet{subs: map[*subscriber]bool{}}
+)
+
+// Query to filter the recent events. Empty fields match any value
+type Query struct {
+	Kind      string
+	Namespace string
+	Level     string
+	Limit     int
+}
+
+// Match returns true if the event satisfies the query
+func (q Query) Match(event Event) bool {
+	if len(q.Kind) != 0 && !strings.EqualFold(q.Kind, event.Kind) {
+		return false
+	}
+	if len(q.Namespace) != 0 && q.Namespace != event.Namespace {
+		return false
+	}
+	if len(q.Level) != 0 && !strings.EqualFold(q.Level, string(event.Level)) {
+		return false
+	}
+	return true
+}
+
+type ringBuffer struct {
+	mu     sync.RWMutex
+	events []Event
+	next   int
+	full   bool
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	return &ringBuffer{events: make([]Event, size)}
+}
+
+func (r *ringBuffer) add(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events[r.next] = event
+	r.next = (r.next + 1) % len(r.events)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// query returns matching events, newest first
+func (r *ringBuffer) query(q Query) []Event {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	count := r.next
+	if r.full {
+		count = len(r.events)
+	}
+	result := []Event{}
+	for i := 1; i <= count; i++ {
+		if q.Limit > 0 && len(result) == q.Limit {
+			break
+		}
+		event := r.events[(r.next-i+len(r.events))%len(r.events)]
+		if q.Match(event) {
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+// Record stores the event in the recent events history and publishes it to the subscribers
+func Record(event Event) {
+	history.add(event)
+	subscribers.publish(event)
+}
+
+// Subscribe returns a channel receiving the recorded events matching the query and a function to unsubscribe
+// Events are dropped for the subscribers which don't read them fast enough
+func Subscribe(q Query) (<-chan Event, func()) {
+	return subscribers.subscribe(q)
+}
+
+// Recent returns the recent events matching the query, newest first
+func Recent(q Query) []Event {
+	return history.query(q)
+}
+
+type subscriber struct {
+	query  Query
+	events chan Event
+}
+
+type subscriberSet struct {
+	mu   sync.RWMutex
+	subs map[*subscriber]bool
+}
+
+func (s *subscriberSet) subscribe(q Query) (<-chan Event, func()) {
+	sub := &subscriber{query: q, events: make(chan Event, subscriptionBuffer)}
+	s.mu.Lock()
+	s.subs[sub] = true
+	s.mu.Unlock()
+	var once sync.Once
+	return sub.events, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subs, sub)
+			s.mu.Unlock()
+			close(sub.events)
+		})
+	}
+}
+
+func (s *subscriberSet) publish(event Event) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for sub := range s.subs {
+		if !sub.query.Match(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
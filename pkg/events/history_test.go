@@ -0,0 +1,116 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package events
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/infracloudio/botkube/pkg/config"
+)
+
+func TestRingBufferQuery(t *testing.T) {
+	r := newRingBuffer(3)
+	for i := 0; i < 5; i++ {
+		level := config.Info
+		if i%2 == 0 {
+			level = config.Error
+		}
+		r.add(Event{Name: fmt.Sprintf("pod-%d", i), Kind: "Pod", Namespace: "default", Level: level})
+	}
+
+	tests := map[string]struct {
+		query    Query
+		expected []string
+	}{
+		`oldest events are overwritten`: {
+			query:    Query{},
+			expected: []string{"pod-4", "pod-3", "pod-2"},
+		},
+		`filter by level`: {
+			query:    Query{Level: "error"},
+			expected: []string{"pod-4", "pod-2"},
+		},
+		`limit the result`: {
+			query:    Query{Limit: 1},
+			expected: []string{"pod-4"},
+		},
+		`kind is case insensitive`: {
+			query:    Query{Kind: "pod", Namespace: "default"},
+			expected: []string{"pod-4", "pod-3", "pod-2"},
+		},
+		`no match`: {
+			query:    Query{Namespace: "prod"},
+			expected: []string{},
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			actual := []string{}
+			for _, e := range r.query(test.query) {
+				actual = append(actual, e.Name)
+			}
+			if fmt.Sprint(actual) != fmt.Sprint(test.expected) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRingBufferNotFull(t *testing.T) {
+	r := newRingBuffer(3)
+	r.add(Event{Name: "pod-0"})
+	if actual := r.query(Query{}); len(actual) != 1 || actual[0].Name != "pod-0" {
+		t.Errorf("expected: %+v != actual: %+v\n", []string{"pod-0"}, actual)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	s := &subscriberSet{subs: map[*subscriber]bool{}}
+	ch, cancel := s.subscribe(Query{Namespace: "prod"})
+	s.publish(Event{Name: "pod-0", Namespace: "default"})
+	s.publish(Event{Name: "pod-1", Namespace: "prod"})
+	cancel()
+	s.publish(Event{Name: "pod-2", Namespace: "prod"})
+	// cancel must be safe to call more than once
+	cancel()
+
+	actual := []string{}
+	for e := range ch {
+		actual = append(actual, e.Name)
+	}
+	expected := []string{"pod-1"}
+	if fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
+
+func TestSubscribeSlowSubscriber(t *testing.T) {
+	s := &subscriberSet{subs: map[*subscriber]bool{}}
+	ch, cancel := s.subscribe(Query{})
+	defer cancel()
+	for i := 0; i < subscriptionBuffer+10; i++ {
+		s.publish(Event{Name: fmt.Sprintf("pod-%d", i)})
+	}
+	if len(ch) != subscriptionBuffer {
+		t.Errorf("expected: %+v != actual: %+v\n", subscriptionBuffer, len(ch))
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/log"
+)
+
+const (
+	// apiResourcesCacheTTL is how long the full api-resources list is reused for the filtered commands
+	apiResourcesCacheTTL = time.Minute
+	// apiResourcesMaxLines is the length of api-resources output uploaded as a file if the platform supports files
+	apiResourcesMaxLines = 40
+	apiResourcesFileName = "api-resources.txt"
+	apiResourcesSentMsg  = "Sent api-resources output as a file on cluster '%s'."
+	apiResourcesNoneMsg  = "No resources found."
+	apiGroupFlag         = "--api-group"
+	namespacedFlag       = "--namespaced"
+)
+
+// apiResourcesOutputs caches the full api-resources list, discovery of all the API groups is slow on large clusters
+var apiResourcesOutputs = newExplainCache(apiResourcesCacheTTL)
+
+// apiResourcesFilter selects the resources of api-resources output like --api-group and --namespaced flags
+type apiResourcesFilter struct {
+	group      *string
+	namespaced *bool
+}
+
+// parseAPIResourcesFilter returns the filter of api-resources command args, false if args contain other flags
+// Commands with other flags, e.g -o wide or --verbs, are passed to kubectl as is
+func parseAPIResourcesFilter(args []string) (apiResourcesFilter, bool) {
+	var f apiResourcesFilter
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "api-resources":
+			continue
+		case arg == AbbrNamespaceFlag.String() || arg == NamespaceFlag.String():
+			// api-resources is not namespaced, the default namespace added to the commands is ignored
+			i++
+		case strings.HasPrefix(arg, NamespaceFlag.String()+"="):
+			continue
+		case arg == apiGroupFlag:
+			if i+1 >= len(args) {
+				return f, false
+			}
+			group := args[i+1]
+			f.group = &group
+			i++
+		case strings.HasPrefix(arg, apiGroupFlag+"="):
+			group := strings.TrimPrefix(arg, apiGroupFlag+"=")
+			f.group = &group
+		case arg == namespacedFlag:
+			namespaced := true
+			f.namespaced = &namespaced
+		case strings.HasPrefix(arg, namespacedFlag+"="):
+			namespaced, err := strconv.ParseBool(strings.TrimPrefix(arg, namespacedFlag+"="))
+			if err != nil {
+				return f, false
+			}
+			f.namespaced = &namespaced
+		default:
+			return f, false
+		}
+	}
+	return f, true
+}
+
+// apply returns the lines of api-resources output matching the filter, the header is kept
+// Columns are found by the header to support both APIGROUP and APIVERSION columns of kubectl versions
+func (f apiResourcesFilter) apply(out string) string {
+	if f.group == nil && f.namespaced == nil {
+		return out
+	}
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], "NAME") {
+		return out
+	}
+	header := lines[0]
+	groupCol, versionCol, namespacedCol := strings.Index(header, "APIGROUP"), strings.Index(header, "APIVERSION"), strings.Index(header, "NAMESPACED")
+
+	matched := []string{header}
+	for _, line := range lines[1:] {
+		if f.group != nil {
+			group := ""
+			switch {
+			case groupCol >= 0:
+				group = column(line, groupCol)
+			case versionCol >= 0:
+				// Core resources have only the version, e.g v1
+				if gv := column(line, versionCol); strings.Contains(gv, "/") {
+					group = strings.SplitN(gv, "/", 2)[0]
+				}
+			}
+			if group != *f.group {
+				continue
+			}
+		}
+		if f.namespaced != nil && namespacedCol >= 0 && column(line, namespacedCol) != strconv.FormatBool(*f.namespaced) {
+			continue
+		}
+		matched = append(matched, line)
+	}
+	if len(matched) == 1 {
+		return apiResourcesNoneMsg
+	}
+	return strings.Join(matched, "\n")
+}
+
+// column returns the value starting at the offset of its header, empty if the line is shorter
+func column(line string, offset int) string {
+	if offset >= len(line) {
+		return ""
+	}
+	fields := strings.Fields(line[offset:])
+	if len(fields) == 0 || line[offset] == ' ' {
+		return ""
+	}
+	return fields[0]
+}
+
+// sendAPIResources uploads long api-resources output as a file, the output is returned as is otherwise
+func (e *DefaultExecutor) sendAPIResources(out string) string {
+	if strings.Count(strings.TrimRight(out, "\n"), "\n") < apiResourcesMaxLines || e.Sender == nil || e.Sender.SendFile == nil {
+		return out
+	}
+	if err := e.Sender.SendFile(apiResourcesFileName, out); err != nil {
+		log.Errorf("Failed to upload api-resources output. %s", err.Error())
+		return out
+	}
+	return fmt.Sprintf(apiResourcesSentMsg, e.ClusterName)
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	apiResourcesOutput = "NAME           SHORTNAMES   APIVERSION                     NAMESPACED   KIND\n" +
+		"bindings                    v1                             true         Binding\n" +
+		"nodes          no           v1                             false        Node\n" +
+		"pods           po           v1                             true         Pod\n" +
+		"deployments    deploy       apps/v1                        true         Deployment\n" +
+		"clusterroles                rbac.authorization.k8s.io/v1   false        ClusterRole\n"
+	apiResourcesGroupOutput = "NAME           SHORTNAMES   APIGROUP                    NAMESPACED   KIND\n" +
+		"pods           po                                       true         Pod\n" +
+		"deployments    deploy       apps                        true         Deployment\n" +
+		"clusterroles                rbac.authorization.k8s.io   false        ClusterRole\n"
+)
+
+func TestParseAPIResourcesFilter(t *testing.T) {
+	group, core, namespaced, clusterScoped := "apps", "", true, false
+	tests := map[string]struct {
+		args     []string
+		expected apiResourcesFilter
+		ok       bool
+	}{
+		`no flags`:               {[]string{"api-resources"}, apiResourcesFilter{}, true},
+		`api group`:              {[]string{"api-resources", "--api-group", "apps"}, apiResourcesFilter{group: &group}, true},
+		`core api group`:         {[]string{"api-resources", "--api-group="}, apiResourcesFilter{group: &core}, true},
+		`namespaced`:             {[]string{"api-resources", "--namespaced"}, apiResourcesFilter{namespaced: &namespaced}, true},
+		`cluster scoped`:         {[]string{"api-resources", "--namespaced=false"}, apiResourcesFilter{namespaced: &clusterScoped}, true},
+		`default namespace`:      {[]string{"-n", "default", "api-resources", "--api-group=apps"}, apiResourcesFilter{group: &group}, true},
+		`output format`:          {[]string{"api-resources", "-o", "wide"}, apiResourcesFilter{}, false},
+		`invalid namespaced`:     {[]string{"api-resources", "--namespaced=maybe"}, apiResourcesFilter{}, false},
+		`api group without name`: {[]string{"api-resources", "--api-group"}, apiResourcesFilter{}, false},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			actual, ok := parseAPIResourcesFilter(test.args)
+			if ok != test.ok || ok && !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected: %+v %+v != actual: %+v %+v\n", test.expected, test.ok, actual, ok)
+			}
+		})
+	}
+}
+
+func TestAPIResourcesFilterApply(t *testing.T) {
+	apps, core, rbac, namespaced, clusterScoped := "apps", "", "rbac.authorization.k8s.io", true, false
+	tests := map[string]struct {
+		out      string
+		filter   apiResourcesFilter
+		expected []string
+	}{
+		`api group`:             {apiResourcesOutput, apiResourcesFilter{group: &apps}, []string{"deployments"}},
+		`core api group`:        {apiResourcesOutput, apiResourcesFilter{group: &core}, []string{"bindings", "nodes", "pods"}},
+		`namespaced`:            {apiResourcesOutput, apiResourcesFilter{namespaced: &namespaced}, []string{"bindings", "pods", "deployments"}},
+		`core and cluster`:      {apiResourcesOutput, apiResourcesFilter{group: &core, namespaced: &clusterScoped}, []string{"nodes"}},
+		`APIGROUP column`:       {apiResourcesGroupOutput, apiResourcesFilter{group: &rbac}, []string{"clusterroles"}},
+		`APIGROUP core group`:   {apiResourcesGroupOutput, apiResourcesFilter{group: &core}, []string{"pods"}},
+		`APIGROUP cluster`:      {apiResourcesGroupOutput, apiResourcesFilter{namespaced: &clusterScoped}, []string{"clusterroles"}},
+		`no filter`:             {apiResourcesOutput, apiResourcesFilter{}, []string{"bindings", "nodes", "pods", "deployments", "clusterroles"}},
+		`no matching resources`: {apiResourcesGroupOutput, apiResourcesFilter{group: &apps, namespaced: &clusterScoped}, nil},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			out := test.filter.apply(test.out)
+			if test.expected == nil {
+				if out != apiResourcesNoneMsg {
+					t.Errorf("expected: %+v != actual: %+v\n", apiResourcesNoneMsg, out)
+				}
+				return
+			}
+			var actual []string
+			for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n")[1:] {
+				actual = append(actual, strings.Fields(line)[0])
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRunKubectlCommandAPIResources(t *testing.T) {
+	defer func(cache *explainCache) { apiResourcesOutputs = cache }(apiResourcesOutputs)
+	apiResourcesOutputs = newExplainCache(time.Hour)
+	KubectlResponse["api-resources"] = apiResourcesOutput
+	KubectlResponse["api-resources -o name"] = "pods\ndeployments.apps"
+	defer delete(KubectlResponse, "api-resources")
+	defer delete(KubectlResponse, "api-resources -o name")
+
+	expected := "Cluster: dev\n" +
+		"NAME           SHORTNAMES   APIVERSION                     NAMESPACED   KIND\n" +
+		"deployments    deploy       apps/v1                        true         Deployment"
+	if actual := runKubectlCommand([]string{"api-resources", "--api-group=apps"}, "dev", "default", true, nil); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+	// Other filters are applied to the cached list even if kubectl response changes
+	KubectlResponse["api-resources"] = "changed"
+	expected = "Cluster: dev\n" +
+		"NAME           SHORTNAMES   APIVERSION                     NAMESPACED   KIND\n" +
+		"nodes          no           v1                             false        Node\n" +
+		"clusterroles                rbac.authorization.k8s.io/v1   false        ClusterRole"
+	if actual := runKubectlCommand([]string{"api-resources", "--namespaced=false"}, "dev", "default", true, nil); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+	// Other flags are passed to kubectl
+	expected = "Cluster: dev\npods\ndeployments.apps"
+	if actual := runKubectlCommand([]string{"api-resources", "-o", "name"}, "dev", "", true, nil); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
+
+func TestSendAPIResources(t *testing.T) {
+	long := "Cluster: test\n" + apiResourcesOutput + strings.Repeat("pods           po           v1                             true         Pod\n", apiResourcesMaxLines)
+	tests := map[string]struct {
+		out          string
+		withUpload   bool
+		expected     string
+		expectedFile string
+	}{
+		`short output`: {out: "Cluster: test\n" + apiResourcesOutput, withUpload: true, expected: "Cluster: test\n" + apiResourcesOutput},
+		`long output uploaded`: {
+			out:          long,
+			withUpload:   true,
+			expected:     "Sent api-resources output as a file on cluster 'test'.",
+			expectedFile: "api-resources.txt:" + long,
+		},
+		`long output without upload`: {out: long, expected: long},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			uploaded := ""
+			sender := &ChannelSender{Channel: "general", Send: func(msg string) error { return nil }}
+			if test.withUpload {
+				sender.SendFile = func(name, content string) error {
+					uploaded = fmt.Sprintf("%s:%s", name, content)
+					return nil
+				}
+			}
+			e := &DefaultExecutor{ClusterName: "test", Sender: sender}
+			if actual := e.sendAPIResources(test.out); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+			if uploaded != test.expectedFile {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expectedFile, uploaded)
+			}
+		})
+	}
+}
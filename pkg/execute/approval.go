@@ -0,0 +1,178 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/log"
+)
+
+const (
+	// approvalTTL is the duration after which a pending approval request expires
+	approvalTTL = 15 * time.Minute
+
+	approvalPromptMsg   = "Command `%s` needs approval of another user on cluster '%s'. Reply `approve %s` within %d minutes to run it."
+	approvalNoUserMsg   = "Sorry, the command needs approval but I couldn't identify the requester."
+	approvalNotFoundMsg = "There is no pending request '%s' to approve."
+	approvalSelfMsg     = "Sorry, request '%s' must be approved by a user other than the requester."
+	approvalApprovedMsg = "Request '%s' approved, running `%s`."
+)
+
+var validApproveCommand = map[string]bool{
+	"approve": true,
+}
+
+// mutatingVerbs are kubectl verbs which change resources, they need approval if settings.requireApproval is set
+var mutatingVerbs = map[string]bool{
+	"annotate":  true,
+	"apply":     true,
+	"autoscale": true,
+	"cordon":    true,
+	"create":    true,
+	"delete":    true,
+	"drain":     true,
+	"edit":      true,
+	"expose":    true,
+	"label":     true,
+	"patch":     true,
+	"replace":   true,
+	"run":       true,
+	"scale":     true,
+	"set":       true,
+	"taint":     true,
+	"uncordon":  true,
+}
+
+// mutatingRolloutCommands are kubectl rollout subcommands which change resources, status and history are read-only
+var mutatingRolloutCommands = map[string]bool{
+	"pause":   true,
+	"restart": true,
+	"resume":  true,
+	"undo":    true,
+}
+
+// isMutating returns true if the kubectl command changes resources
+// Any argument of rollout matching a mutating subcommand counts, so flags before the subcommand can't skip approval
+func isMutating(args []string) bool {
+	if mutatingVerbs[args[0]] {
+		return true
+	}
+	if args[0] != "rollout" {
+		return false
+	}
+	for _, arg := range args[1:] {
+		if mutatingRolloutCommands[arg] {
+			return true
+		}
+	}
+	return false
+}
+
+// requireApproval is set from settings.requireApproval
+var requireApproval bool
+
+type approvalRequest struct {
+	user    string
+	args    []string
+	expires time.Time
+}
+
+// pendingApprovals stores the commands waiting for approval, keyed by request id
+// Ids are prefixed with the cluster name so that each BotKube instance approves its own requests only
+var pendingApprovals = struct {
+	sync.Mutex
+	lastID   int
+	requests map[string]approvalRequest
+}{requests: map[string]approvalRequest{}}
+
+// requestApproval stores the command as pending and returns the prompt with the request id
+func requestApproval(user string, args []string, clusterName string) string {
+	if len(user) == 0 {
+		return approvalNoUserMsg
+	}
+	pendingApprovals.Lock()
+	defer pendingApprovals.Unlock()
+	pendingApprovals.lastID++
+	id := fmt.Sprintf("%s-%d", clusterName, pendingApprovals.lastID)
+	pendingApprovals.requests[id] = approvalRequest{
+		user:    user,
+		args:    append([]string{}, args...),
+		expires: time.Now().Add(approvalTTL),
+	}
+	return fmt.Sprintf(approvalPromptMsg, strings.Join(args, " "), clusterName, id, int(approvalTTL.Minutes()))
+}
+
+// approveRequest returns the command args of the request if the user can approve it
+// A response is returned instead if the request is not found or the user is the requester
+func approveRequest(id, user string) ([]string, string) {
+	pendingApprovals.Lock()
+	defer pendingApprovals.Unlock()
+	req, found := pendingApprovals.requests[id]
+	if !found || time.Now().After(req.expires) {
+		delete(pendingApprovals.requests, id)
+		return nil, fmt.Sprintf(approvalNotFoundMsg, id)
+	}
+	if len(user) == 0 || user == req.user {
+		return nil, fmt.Sprintf(approvalSelfMsg, id)
+	}
+	delete(pendingApprovals.requests, id)
+	return req.args, ""
+}
+
+// InitApproval sets whether mutating kubectl commands need approval of another user
+func InitApproval(require bool) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	requireApproval = require
+}
+
+// runApproveCommand runs the pending command of the request passed as argument
+func (e *DefaultExecutor) runApproveCommand(args []string) string {
+	if !e.AllowKubectl || (e.RestrictAccess && !e.IsAuthChannel) {
+		return ""
+	}
+	if len(args) < 2 {
+		return IncompleteCmdMsg(e.Platform)
+	}
+	id := args[1]
+	// Requests of other clusters are approved by their BotKube instance
+	if !strings.HasPrefix(id, e.ClusterName+"-") {
+		return ""
+	}
+	cmdArgs, res := approveRequest(id, e.User)
+	if len(res) != 0 {
+		return res
+	}
+	log.Infof("Request %s approved by %s", id, e.User)
+	result := runKubectl(cmdArgs, e.ClusterName, e.DefaultNamespace, true, nil)
+	// Node operations run after approval are audited with the approver like the confirmed ones
+	if validNodeOpCommand[cmdArgs[0]] {
+		status := "succeeded"
+		if result.exitCode != 0 {
+			status = "failed: " + strings.TrimSpace(result.stderr)
+		}
+		auditNodeOp(e.User, e.ChannelName, e.ClusterName, strings.Join(cmdArgs, " "), fmt.Sprintf("%s after approving request %s", status, id))
+	}
+	return fmt.Sprintf(approvalApprovedMsg, id, strings.Join(cmdArgs, " ")) + "\n" + result.String()
+}
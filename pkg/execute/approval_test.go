@@ -0,0 +1,164 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+// lastApprovalID returns the id of the request stored last for the cluster
+func lastApprovalID(clusterName string) string {
+	pendingApprovals.Lock()
+	defer pendingApprovals.Unlock()
+	return fmt.Sprintf("%s-%d", clusterName, pendingApprovals.lastID)
+}
+
+func TestApproveRequest(t *testing.T) {
+	args := []string{"scale", "deployments/nginx", "--replicas=2"}
+	prompt := requestApproval("alice", args, "dev")
+	id := lastApprovalID("dev")
+	expectedPrompt := fmt.Sprintf(approvalPromptMsg, strings.Join(args, " "), "dev", id, int(approvalTTL.Minutes()))
+	if prompt != expectedPrompt {
+		t.Errorf("expected: %+v != actual: %+v\n", expectedPrompt, prompt)
+	}
+
+	tests := []struct {
+		name         string
+		user         string
+		expectedArgs []string
+		expectedRes  string
+	}{
+		{"self approval", "alice", nil, fmt.Sprintf(approvalSelfMsg, id)},
+		{"unknown approver", "", nil, fmt.Sprintf(approvalSelfMsg, id)},
+		{"second user", "bob", args, ""},
+		{"approved already", "carol", nil, fmt.Sprintf(approvalNotFoundMsg, id)},
+	}
+	for _, test := range tests {
+		actualArgs, actualRes := approveRequest(id, test.user)
+		if !reflect.DeepEqual(actualArgs, test.expectedArgs) || actualRes != test.expectedRes {
+			t.Errorf("%s: expected: %+v %q != actual: %+v %q\n", test.name, test.expectedArgs, test.expectedRes, actualArgs, actualRes)
+		}
+	}
+}
+
+func TestApproveRequestExpired(t *testing.T) {
+	requestApproval("alice", []string{"delete", "pod", "nginx"}, "dev")
+	id := lastApprovalID("dev")
+	pendingApprovals.Lock()
+	req := pendingApprovals.requests[id]
+	req.expires = time.Now().Add(-time.Second)
+	pendingApprovals.requests[id] = req
+	pendingApprovals.Unlock()
+
+	expected := fmt.Sprintf(approvalNotFoundMsg, id)
+	if _, actual := approveRequest(id, "bob"); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
+
+func TestRequestApprovalWithoutUser(t *testing.T) {
+	if actual := requestApproval("", []string{"delete", "pod", "nginx"}, "dev"); actual != approvalNoUserMsg {
+		t.Errorf("expected: %+v != actual: %+v\n", approvalNoUserMsg, actual)
+	}
+}
+
+func TestRunApproveCommand(t *testing.T) {
+	KubectlResponse["scale deployments/nginx --replicas=2"] = "deployment.apps/nginx scaled"
+	defer delete(KubectlResponse, "scale deployments/nginx --replicas=2")
+	requestApproval("alice", []string{"scale", "deployments/nginx", "--replicas=2"}, "dev")
+	id := lastApprovalID("dev")
+
+	// Requests of other clusters are ignored
+	other := &DefaultExecutor{AllowKubectl: true, ClusterName: "prod", User: "bob", IsAuthChannel: true}
+	if actual := other.runApproveCommand([]string{"approve", id}); actual != "" {
+		t.Errorf("expected empty response for other cluster != actual: %+v\n", actual)
+	}
+
+	e := &DefaultExecutor{AllowKubectl: true, ClusterName: "dev", User: "bob", IsAuthChannel: true}
+	expected := fmt.Sprintf(approvalApprovedMsg, id, "scale deployments/nginx --replicas=2") + "\nCluster: dev\ndeployment.apps/nginx scaled"
+	if actual := e.runApproveCommand([]string{"approve", id}); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
+
+func TestRunApproveCommandNodeOp(t *testing.T) {
+	KubectlResponse["cordon node-1"] = "node/node-1 cordoned"
+	defer delete(KubectlResponse, "cordon node-1")
+	requestApproval("alice", []string{"cordon", "node-1"}, "dev")
+	id := lastApprovalID("dev")
+
+	e := &DefaultExecutor{AllowKubectl: true, ClusterName: "dev", User: "bob", ChannelName: "ops", IsAuthChannel: true}
+	expected := fmt.Sprintf(approvalApprovedMsg, id, "cordon node-1") + "\nCluster: dev\nnode/node-1 cordoned"
+	if actual := e.runApproveCommand([]string{"approve", id}); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
+
+func TestExecuteRequireApproval(t *testing.T) {
+	defer utils.SetKubectlMaps(utils.GetKubectlMaps())
+	utils.SetKubectlMaps(&utils.KubectlMaps{Verbs: map[string]bool{"scale": true}, Resources: map[string]bool{"deployments": true}})
+	KubectlResponse["-n default scale deployments nginx --replicas=2"] = "deployment.apps/nginx scaled"
+	defer delete(KubectlResponse, "-n default scale deployments nginx --replicas=2")
+	defer InitApproval(false)
+
+	msg := "scale deployments nginx --replicas=2"
+	e := NewDefaultExecutor(msg, true, false, "default", "dev", config.SlackBot, "general", "", "alice", true, nil)
+	if expected, actual := "Cluster: dev\ndeployment.apps/nginx scaled", e.Execute(); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+
+	InitApproval(true)
+	actual := e.Execute()
+	expected := fmt.Sprintf(approvalPromptMsg, msg, "dev", lastApprovalID("dev"), int(approvalTTL.Minutes()))
+	if actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
+
+func TestIsMutating(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		expected bool
+	}{
+		`mutating verb`:               {[]string{"scale", "deployments/nginx", "--replicas=2"}, true},
+		`read-only verb`:              {[]string{"get", "pods"}, false},
+		`rollout restart`:             {[]string{"rollout", "restart", "deployments/nginx"}, true},
+		`rollout undo`:                {[]string{"rollout", "undo", "deployments/nginx"}, true},
+		`rollout restart after flags`: {[]string{"rollout", "-n", "prod", "restart", "deployments/nginx"}, true},
+		`rollout status`:              {[]string{"rollout", "status", "deployments/nginx"}, false},
+		`rollout history`:             {[]string{"rollout", "history", "deployments/nginx"}, false},
+		`rollout without subcommand`:  {[]string{"rollout"}, false},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := isMutating(test.args); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// confirmArg is the suffix to confirm a mutating command
+	confirmArg = "confirm"
+	// confirmationTTL is the duration after which a pending confirmation expires
+	confirmationTTL = 5 * time.Minute
+
+	confirmationPromptMsg = "Are you sure you want to run `notifier %s` on cluster '%s'? Reply `notifier %s %s` within %d minutes to proceed."
+	noPendingActionMsg    = "There is no pending `notifier %s` to confirm. Please run `notifier %s` first."
+)
+
+// pendingConfirmations stores expiry time of the actions waiting for confirmation, keyed by channel and action
+var pendingConfirmations = struct {
+	sync.Mutex
+	actions map[string]time.Time
+}{actions: map[string]time.Time{}}
+
+// checkConfirmation returns a response if the action needs to wait for confirmation
+// Empty response is returned if the action can proceed
+func checkConfirmation(channel string, action NotifierAction, args []string, clusterName string, required bool) string {
+	if !required {
+		return ""
+	}
+	key := channel + "/" + action.String()
+
+	pendingConfirmations.Lock()
+	defer pendingConfirmations.Unlock()
+	if len(args) > 0 && args[0] == confirmArg {
+		expiry, found := pendingConfirmations.actions[key]
+		delete(pendingConfirmations.actions, key)
+		if !found || time.Now().After(expiry) {
+			return fmt.Sprintf(noPendingActionMsg, action, action)
+		}
+		return ""
+	}
+	pendingConfirmations.actions[key] = time.Now().Add(confirmationTTL)
+	return fmt.Sprintf(confirmationPromptMsg, action, clusterName, action, confirmArg, int(confirmationTTL.Minutes()))
+}
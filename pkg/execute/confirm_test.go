@@ -0,0 +1,68 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCheckConfirmation(t *testing.T) {
+	prompt := fmt.Sprintf(confirmationPromptMsg, Stop, "test-cluster", Stop, confirmArg, int(confirmationTTL.Minutes()))
+	noPending := fmt.Sprintf(noPendingActionMsg, Stop, Stop)
+	tests := map[string]struct {
+		// steps are args passed to consecutive notifier stop commands
+		steps    [][]string
+		required bool
+		expected []string
+	}{
+		`confirmation not required`:    {[][]string{{}}, false, []string{""}},
+		`confirm suffix not required`:  {[][]string{{confirmArg}}, false, []string{""}},
+		`prompt and confirm`:           {[][]string{{}, {confirmArg}}, true, []string{prompt, ""}},
+		`confirm without prompt`:       {[][]string{{confirmArg}}, true, []string{noPending}},
+		`confirm only once`:            {[][]string{{}, {confirmArg}, {confirmArg}}, true, []string{prompt, "", noPending}},
+		`prompt again without confirm`: {[][]string{{}, {}}, true, []string{prompt, prompt}},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			channel := name
+			for i, args := range test.steps {
+				if actual := checkConfirmation(channel, Stop, args, "test-cluster", test.required); actual != test.expected[i] {
+					t.Errorf("expected: %+v != actual: %+v\n", test.expected[i], actual)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckConfirmationExpired(t *testing.T) {
+	channel := "expired"
+	checkConfirmation(channel, Start, nil, "test-cluster", true)
+	pendingConfirmations.Lock()
+	pendingConfirmations.actions[channel+"/"+Start.String()] = time.Now().Add(-time.Second)
+	pendingConfirmations.Unlock()
+
+	expected := fmt.Sprintf(noPendingActionMsg, Start, Start)
+	if actual := checkConfirmation(channel, Start, []string{confirmArg}, "test-cluster", true); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
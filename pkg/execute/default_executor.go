@@ -22,6 +22,10 @@
 package execute
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
 	"os/exec"
 )
 
@@ -35,7 +39,7 @@ type DefaultRunner struct {
 func NewCommandRunner(command string, args []string) CommandRunner {
 	return DefaultRunner{
 		command: command,
-		args:    args,
+		args:    withCacheDir(args),
 	}
 }
 
@@ -45,3 +49,39 @@ func (r DefaultRunner) Run() (string, error) {
 	out, err := cmd.CombinedOutput()
 	return string(out), err
 }
+
+// DefaultStreamRunner contains default implementation for Stream
+type DefaultStreamRunner struct {
+	command string
+	args    []string
+}
+
+// NewStreamRunner returns new DefaultStreamRunner
+func NewStreamRunner(command string, args []string) StreamRunner {
+	return DefaultStreamRunner{
+		command: command,
+		args:    withCacheDir(args),
+	}
+}
+
+// Stream executes bash command and calls handle for each line of the output until ctx is done
+func (r DefaultStreamRunner) Stream(ctx context.Context, handle func(line string)) error {
+	cmd := exec.CommandContext(ctx, r.command, r.args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		handle(scanner.Text())
+	}
+	if err := cmd.Wait(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("%s%s", stderr.String(), err.Error())
+	}
+	return nil
+}
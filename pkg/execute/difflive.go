@@ -0,0 +1,225 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+var validDiffCommand = map[string]bool{
+	"diff": true,
+}
+
+const (
+	diffLiveUsageMsg      = "Usage: diff live <kind>/<name> [-n <namespace>]"
+	diffNotAllowedMsg     = "Sorry, the admin hasn't allowed diff of %s on cluster '%s'."
+	diffNoLastAppliedMsg  = "'%s' in namespace '%s' has no last-applied-configuration annotation on cluster '%s'. Only resources created with 'kubectl apply' can be compared."
+	diffNoChangesMsg      = "'%s' in namespace '%s' matches its last-applied-configuration on cluster '%s'."
+	diffChangesMsg        = "Changes of '%s' in namespace '%s' since the last 'kubectl apply' on cluster '%s' (-: applied, +: live):\n"
+	diffLiveErrorMsg      = "Error in getting '%s' in namespace '%s'!"
+	lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+	diffNoneValue         = "<none>"
+)
+
+// secretDataFields are the fields of Secrets, all their values are redacted
+var secretDataFields = []string{"data.", "stringData."}
+
+// runDiffLiveCommand compares the live object to its last-applied-configuration annotation
+// Only the applied fields are compared, with the labels and annotations added to the live object since
+func (e *DefaultExecutor) runDiffLiveCommand(args []string) string {
+	var params []string
+	clusterName, namespace := "", ""
+	for i := 2; i < len(args); i++ {
+		switch {
+		case args[i] == ClusterFlag.String() || args[i] == AbbrNamespaceFlag.String() || args[i] == NamespaceFlag.String():
+			if i+1 < len(args) {
+				if args[i] == ClusterFlag.String() {
+					clusterName = trimQuotes(args[i+1])
+				} else {
+					namespace = args[i+1]
+				}
+			}
+			i++
+		case strings.HasPrefix(args[i], ClusterFlag.String()+"="):
+			clusterName = trimQuotes(strings.SplitAfterN(args[i], ClusterFlag.String()+"=", 2)[1])
+		case strings.HasPrefix(args[i], NamespaceFlag.String()+"="):
+			namespace = strings.SplitAfterN(args[i], NamespaceFlag.String()+"=", 2)[1]
+		default:
+			params = append(params, args[i])
+		}
+	}
+	if len(clusterName) != 0 && clusterName != e.ClusterName {
+		return ""
+	}
+	if len(clusterName) == 0 && !e.IsAuthChannel {
+		return ""
+	}
+	if e.RestrictAccess && !e.IsAuthChannel {
+		return ""
+	}
+	if !e.AllowKubectl {
+		return fmt.Sprintf(kubectlDisabledMsg, e.ClusterName)
+	}
+	if len(params) != 1 {
+		return diffLiveUsageMsg
+	}
+	parts := strings.SplitN(params[0], "/", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return diffLiveUsageMsg
+	}
+	if !utils.GetKubectlMaps().AllowedVerb("diff") || !isAllowedResource(parts[0]) {
+		return fmt.Sprintf(diffNotAllowedMsg, parts[0], e.ClusterName)
+	}
+	if len(namespace) == 0 {
+		namespace = e.DefaultNamespace
+	}
+	if len(namespace) == 0 {
+		namespace = "default"
+	}
+
+	runner := NewCommandRunner(kubectlBinary, []string{"get", params[0], "-n", namespace, "-o", "json"})
+	out, err := runner.Run()
+	if err != nil {
+		log.Errorf("Error in executing diff live command: %s", out+err.Error())
+		if msg := classifyKubectlError(out+err.Error(), e.ClusterName); len(msg) != 0 {
+			return msg
+		}
+		return fmt.Sprintf(diffLiveErrorMsg, params[0], namespace)
+	}
+	var live map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &live); err != nil {
+		log.Errorf("Unable to parse %s in namespace %s. %s", params[0], namespace, err.Error())
+		return fmt.Sprintf(diffLiveErrorMsg, params[0], namespace)
+	}
+
+	diff, ok := diffLastApplied(live)
+	if !ok {
+		return fmt.Sprintf(diffNoLastAppliedMsg, params[0], namespace, e.ClusterName)
+	}
+	if len(diff) == 0 {
+		return fmt.Sprintf(diffNoChangesMsg, params[0], namespace, e.ClusterName)
+	}
+	return fmt.Sprintf(diffChangesMsg, params[0], namespace, e.ClusterName) + utils.TruncateDiff(diff)
+}
+
+// isAllowedResource returns true if the resource, its kind or short name is in settings.kubectl.commands.resources
+func isAllowedResource(resource string) bool {
+	return utils.GetKubectlMaps().AllowedResource(strings.ToLower(resource))
+}
+
+// diffLastApplied returns the differences of the live object from its last-applied-configuration annotation
+// It returns false if the object doesn't have the annotation
+func diffLastApplied(live map[string]interface{}) (string, bool) {
+	metadata, _ := live["metadata"].(map[string]interface{})
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	lastApplied, ok := annotations[lastAppliedAnnotation].(string)
+	if !ok {
+		return "", false
+	}
+	var applied map[string]interface{}
+	if err := json.Unmarshal([]byte(lastApplied), &applied); err != nil {
+		log.Errorf("Unable to parse %s annotation. %s", lastAppliedAnnotation, err.Error())
+		return "", false
+	}
+
+	appliedFields, liveFields := map[string]string{}, map[string]string{}
+	flattenFields("", applied, appliedFields)
+	flattenFields("", live, liveFields)
+	delete(liveFields, "metadata.annotations."+lastAppliedAnnotation)
+	isSecret := live["kind"] == "Secret"
+
+	fields := []string{}
+	for field := range appliedFields {
+		if appliedFields[field] != liveFields[field] {
+			fields = append(fields, field)
+		}
+	}
+	for field := range liveFields {
+		_, ok := appliedFields[field]
+		if !ok && (strings.HasPrefix(field, "metadata.labels.") || strings.HasPrefix(field, "metadata.annotations.")) {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	var b strings.Builder
+	for _, field := range fields {
+		before, after := appliedFields[field], liveFields[field]
+		if len(before) == 0 {
+			before = diffNoneValue
+		}
+		if len(after) == 0 {
+			after = diffNoneValue
+		}
+		if utils.IsSecretField(field) || isSecret && hasAnyPrefix(field, secretDataFields) {
+			before, after = utils.RedactedValue, utils.RedactedValue
+		}
+		fmt.Fprintf(&b, "%s:\n\t-: %s\n\t+: %s\n", field, before, after)
+	}
+	return b.String(), true
+}
+
+// flattenFields adds the scalar values of the object to fields keyed by their path, e.g spec.containers[nginx].image
+// List items are keyed by their name if they have one, so reordered items are matched. Status is skipped since it is never applied
+func flattenFields(path string, value interface{}, fields map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if len(path) == 0 && key == "status" {
+				continue
+			}
+			childPath := key
+			if len(path) != 0 {
+				childPath = path + "." + key
+			}
+			flattenFields(childPath, child, fields)
+		}
+	case []interface{}:
+		for i, child := range v {
+			key := fmt.Sprintf("%d", i)
+			if item, ok := child.(map[string]interface{}); ok {
+				if name, ok := item["name"].(string); ok && len(name) != 0 {
+					key = name
+				}
+			}
+			flattenFields(fmt.Sprintf("%s[%s]", path, key), child, fields)
+		}
+	case nil:
+		// Same as unset, e.g creationTimestamp: null in the applied config
+	default:
+		fields[path] = fmt.Sprintf("%v", v)
+	}
+}
+
+// hasAnyPrefix returns true if s starts with any of the prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
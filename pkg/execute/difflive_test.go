@@ -0,0 +1,165 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+const (
+	liveDeployment = `{
+  "apiVersion": "apps/v1",
+  "kind": "Deployment",
+  "metadata": {
+    "name": "nginx",
+    "namespace": "default",
+    "labels": {"app": "nginx", "hotfix": "true"},
+    "annotations": {
+      "kubectl.kubernetes.io/last-applied-configuration": "{\"apiVersion\":\"apps/v1\",\"kind\":\"Deployment\",\"metadata\":{\"annotations\":{},\"labels\":{\"app\":\"nginx\"},\"name\":\"nginx\",\"namespace\":\"default\",\"creationTimestamp\":null},\"spec\":{\"replicas\":2,\"template\":{\"spec\":{\"containers\":[{\"name\":\"nginx\",\"image\":\"nginx:1.19\",\"env\":[{\"name\":\"DB_PASSWORD\",\"value\":\"applied\"}]}]}}}}\n"
+    },
+    "uid": "0e0e0e0e"
+  },
+  "spec": {
+    "replicas": 5,
+    "template": {"spec": {"containers": [{"name": "nginx", "image": "nginx:1.19", "imagePullPolicy": "IfNotPresent", "env": [{"name": "DB_PASSWORD", "value": "live"}]}]}}
+  },
+  "status": {"replicas": 5}
+}`
+	unchangedPod = `{
+  "apiVersion": "v1",
+  "kind": "Pod",
+  "metadata": {
+    "name": "nginx",
+    "namespace": "web",
+    "annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{\"apiVersion\":\"v1\",\"kind\":\"Pod\",\"metadata\":{\"name\":\"nginx\",\"namespace\":\"web\"}}"}
+  },
+  "status": {"phase": "Running"}
+}`
+	createdPod = `{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "redis", "namespace": "default"}}`
+	liveSecret = `{
+  "apiVersion": "v1",
+  "kind": "Secret",
+  "metadata": {
+    "name": "db",
+    "namespace": "default",
+    "annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{\"apiVersion\":\"v1\",\"kind\":\"Secret\",\"metadata\":{\"name\":\"db\",\"namespace\":\"default\"},\"data\":{\"url\":\"YQ==\"}}"}
+  },
+  "data": {"url": "Yg=="}
+}`
+)
+
+func TestRunDiffLiveCommand(t *testing.T) {
+	responses := map[string]string{
+		"get deployment/nginx -n default -o json": liveDeployment,
+		"get pods/nginx -n web -o json":           unchangedPod,
+		"get pod/redis -n default -o json":        createdPod,
+		"get secrets/db -n default -o json":       liveSecret,
+	}
+	for cmd, out := range responses {
+		KubectlResponse[cmd] = out
+	}
+	defer func() {
+		for cmd := range responses {
+			delete(KubectlResponse, cmd)
+		}
+	}()
+	defer utils.SetKubectlMaps(utils.GetKubectlMaps())
+	utils.SetKubectlMaps(&utils.KubectlMaps{
+		Verbs:     map[string]bool{"diff": true},
+		Resources: map[string]bool{"deployments": true, "pods": true, "secrets": true},
+		Kinds:     map[string]string{"deployment": "deployments", "pod": "pods", "secret": "secrets"},
+	})
+
+	tests := map[string]struct {
+		command  string
+		expected string
+	}{
+		`changed resource`: {
+			command: "diff live deployment/nginx",
+			expected: "Changes of 'deployment/nginx' in namespace 'default' since the last 'kubectl apply' on cluster 'test' (-: applied, +: live):\n" +
+				"metadata.labels.hotfix:\n\t-: <none>\n\t+: true\n" +
+				"spec.replicas:\n\t-: 2\n\t+: 5\n" +
+				"spec.template.spec.containers[nginx].env[DB_PASSWORD].value:\n\t-: <redacted>\n\t+: <redacted>\n",
+		},
+		`unchanged resource`: {
+			command:  "diff live pods/nginx -n web",
+			expected: "'pods/nginx' in namespace 'web' matches its last-applied-configuration on cluster 'test'.",
+		},
+		`resource without annotation`: {
+			command:  "diff live pod/redis",
+			expected: "'pod/redis' in namespace 'default' has no last-applied-configuration annotation on cluster 'test'. Only resources created with 'kubectl apply' can be compared.",
+		},
+		`secret data redacted`: {
+			command: "diff live secrets/db --namespace=default",
+			expected: "Changes of 'secrets/db' in namespace 'default' since the last 'kubectl apply' on cluster 'test' (-: applied, +: live):\n" +
+				"data.url:\n\t-: <redacted>\n\t+: <redacted>\n",
+		},
+		`resource not allowed`: {
+			command:  "diff live configmaps/app",
+			expected: "Sorry, the admin hasn't allowed diff of configmaps on cluster 'test'.",
+		},
+		`missing name`: {
+			command:  "diff live deployment",
+			expected: diffLiveUsageMsg,
+		},
+		`other cluster`: {
+			command:  "diff live deployment/nginx --cluster-name other",
+			expected: "",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			e := &DefaultExecutor{Message: test.command, AllowKubectl: true, ClusterName: "test", IsAuthChannel: true}
+			if actual := e.Execute(); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDiffLastAppliedTruncated(t *testing.T) {
+	applied, data := map[string]interface{}{}, map[string]interface{}{}
+	for _, key := range strings.Split("abcdefghijklmnopqrstuvwxyz", "") {
+		applied[key] = strings.Repeat(key, 100)
+		data[key] = strings.ToUpper(strings.Repeat(key, 100))
+	}
+	b, err := json.Marshal(map[string]interface{}{"data": applied})
+	if err != nil {
+		t.Fatal(err)
+	}
+	live := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{lastAppliedAnnotation: string(b)},
+		},
+		"data": data,
+	}
+	diff, ok := diffLastApplied(live)
+	if !ok {
+		t.Fatal("expected last-applied-configuration to be found")
+	}
+	if actual := utils.TruncateDiff(diff); len(actual) > 2000 || !strings.HasSuffix(actual, "... diff truncated\n") {
+		t.Errorf("expected truncated diff, actual: %+v\n", actual)
+	}
+}
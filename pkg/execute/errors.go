@@ -0,0 +1,57 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	kubectlNotFoundMsg  = "%s. Please check the name and the namespace."
+	kubectlForbiddenMsg = "BotKube is not allowed to do that on cluster '%s'. Please ask the admin to check the RBAC rules of BotKube's ClusterRole."
+	kubectlTimeoutMsg   = "Request to the Kubernetes API server of cluster '%s' timed out. Please try again."
+	kubectlRefusedMsg   = "Unable to connect to the Kubernetes API server of cluster '%s'."
+)
+
+// notFoundRegex matches the server error of a missing resource, e.g Error from server (NotFound): pods "nginx" not found
+var notFoundRegex = regexp.MustCompile(`\(NotFound\): (.+ not found)`)
+
+// classifyKubectlError returns a short message for the common kubectl failures in out
+// Empty string is returned if the failure is not recognized
+func classifyKubectlError(out, clusterName string) string {
+	switch {
+	case strings.Contains(out, "(Forbidden)") || strings.Contains(out, " is forbidden: "):
+		return fmt.Sprintf(kubectlForbiddenMsg, clusterName)
+	case strings.Contains(out, "(NotFound)"):
+		detail := "Resource not found"
+		if m := notFoundRegex.FindStringSubmatch(out); m != nil {
+			detail = m[1]
+		}
+		return fmt.Sprintf(kubectlNotFoundMsg, detail)
+	case strings.Contains(out, "connection refused") || strings.Contains(out, "was refused"):
+		return fmt.Sprintf(kubectlRefusedMsg, clusterName)
+	case strings.Contains(out, "(Timeout)") || strings.Contains(out, "i/o timeout") ||
+		strings.Contains(out, "Client.Timeout exceeded") || strings.Contains(out, "context deadline exceeded"):
+		return fmt.Sprintf(kubectlTimeoutMsg, clusterName)
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"testing"
+)
+
+func TestClassifyKubectlError(t *testing.T) {
+	tests := map[string]struct {
+		out      string
+		expected string
+	}{
+		`not found`: {
+			out:      `Error from server (NotFound): pods "nginx" not found` + "exit status 1",
+			expected: `pods "nginx" not found. Please check the name and the namespace.`,
+		},
+		`forbidden`: {
+			out:      `Error from server (Forbidden): secrets is forbidden: User "system:serviceaccount:botkube:botkube" cannot list resource "secrets"`,
+			expected: "BotKube is not allowed to do that on cluster 'dev'. Please ask the admin to check the RBAC rules of BotKube's ClusterRole.",
+		},
+		`timeout`: {
+			out:      "Unable to connect to the server: net/http: request canceled (Client.Timeout exceeded while awaiting headers)",
+			expected: "Request to the Kubernetes API server of cluster 'dev' timed out. Please try again.",
+		},
+		`server timeout`: {
+			out:      "Error from server (Timeout): the server was unable to return a response in the time allotted",
+			expected: "Request to the Kubernetes API server of cluster 'dev' timed out. Please try again.",
+		},
+		`connection refused`: {
+			out:      "The connection to the server 10.0.0.1:6443 was refused - did you specify the right host or port?",
+			expected: "Unable to connect to the Kubernetes API server of cluster 'dev'.",
+		},
+		`unknown error`: {
+			out:      "error: unknown flag: --foo",
+			expected: "",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := classifyKubectlError(test.out, "dev"); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/log"
+)
+
+const (
+	tailStartedMsg         = "Tailing events%s on cluster '%s' for %s. I will post new events here."
+	tailStoppedMsg         = "Stopped tailing events on cluster '%s' after %s."
+	tailInvalidDurationMsg = "Invalid duration '%s' for events tail command. Pass a duration up to %s, e.g 5m."
+	tailChannelLimitMsg    = "Sorry, events are already tailed in this channel. Please wait for the running tail to finish."
+	tailLimitMsg           = "Sorry, only %d events tail(s) can run at a time. Please wait for a running tail to finish."
+
+	defaultTailDuration = 5 * time.Minute
+	maxTailDuration     = 30 * time.Minute
+	// maxEventTails is the number of tails running at a time across all channels
+	maxEventTails = 5
+)
+
+var (
+	// tailInterval is how often the new events are posted, events are batched in between
+	tailInterval = defaultWatchDebounce
+
+	// runningTails are the channels with running events tail
+	runningTails = struct {
+		sync.Mutex
+		channels map[string]bool
+	}{channels: map[string]bool{}}
+)
+
+// runEventsTail streams the recorded events matching the options into the channel for the duration
+func (e *DefaultExecutor) runEventsTail(args []string) string {
+	args, duration, err := parseTailDuration(args)
+	if err != nil {
+		return err.Error()
+	}
+	q, clusterName, err := parseEventsQuery(args)
+	if err != nil {
+		return err.Error()
+	}
+	if len(clusterName) != 0 && clusterName != e.ClusterName {
+		return ""
+	}
+	if e.Sender == nil {
+		return fmt.Sprintf(watchUnsupportedMsg, e.Platform)
+	}
+	if msg := acquireTail(e.Sender.Channel); len(msg) != 0 {
+		return msg
+	}
+
+	sender, clusterName, interval := e.Sender, e.ClusterName, tailInterval
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	ch, unsubscribe := events.Subscribe(q)
+	go func() {
+		defer releaseTail(sender.Channel)
+		defer cancel()
+		defer unsubscribe()
+		send := func(msg string) {
+			if err := sender.Send(msg); err != nil {
+				log.Errorf("Failed to send tailed events. Error: %s", err.Error())
+			}
+		}
+		tailEvents(ctx, ch, func(list []events.Event) {
+			send(fmt.Sprintf("New events on cluster '%s'\n\n%s", clusterName, makeEventsList(list)))
+		}, interval)
+		send(fmt.Sprintf(tailStoppedMsg, clusterName, duration))
+	}()
+	return fmt.Sprintf(tailStartedMsg, describeTailQuery(q), e.ClusterName, duration)
+}
+
+// tailEvents batches the events received from the subscription and sends them once per interval until ctx is done
+func tailEvents(ctx context.Context, ch <-chan events.Event, send func([]events.Event), interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var batch []events.Event
+	flush := func() {
+		if len(batch) != 0 {
+			send(batch)
+			batch = nil
+		}
+	}
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, event)
+		case <-ticker.C:
+			flush()
+		case <-ctx.Done():
+			flush()
+			return
+		}
+	}
+}
+
+// parseTailDuration removes the positional duration from events tail options
+func parseTailDuration(args []string) ([]string, time.Duration, error) {
+	duration := defaultTailDuration
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			rest = append(rest, arg)
+			// Flag value is the next argument unless passed as --flag=value
+			if !strings.Contains(arg, "=") && i+1 < len(args) {
+				i++
+				rest = append(rest, args[i])
+			}
+			continue
+		}
+		d, err := time.ParseDuration(arg)
+		if err != nil || d <= 0 || d > maxTailDuration {
+			return nil, 0, fmt.Errorf(tailInvalidDurationMsg, arg, maxTailDuration)
+		}
+		duration = d
+	}
+	return rest, duration, nil
+}
+
+// describeTailQuery returns the query filters for the tail started message
+func describeTailQuery(q events.Query) string {
+	var filters []string
+	if len(q.Kind) != 0 {
+		filters = append(filters, "kind "+q.Kind)
+	}
+	if len(q.Namespace) != 0 {
+		filters = append(filters, "namespace "+q.Namespace)
+	}
+	if len(q.Level) != 0 {
+		filters = append(filters, "level "+q.Level)
+	}
+	if len(filters) == 0 {
+		return ""
+	}
+	return " of " + strings.Join(filters, ", ")
+}
+
+// acquireTail returns the message why the tail can't be started, empty if it can
+func acquireTail(channel string) string {
+	runningTails.Lock()
+	defer runningTails.Unlock()
+	if runningTails.channels[channel] {
+		return tailChannelLimitMsg
+	}
+	if len(runningTails.channels) >= maxEventTails {
+		return fmt.Sprintf(tailLimitMsg, maxEventTails)
+	}
+	runningTails.channels[channel] = true
+	return ""
+}
+
+func releaseTail(channel string) {
+	runningTails.Lock()
+	defer runningTails.Unlock()
+	delete(runningTails.channels, channel)
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+func TestParseTailDuration(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		rest     []string
+		duration time.Duration
+		err      bool
+	}{
+		`default duration`: {
+			args:     []string{"--namespace", "prod"},
+			rest:     []string{"--namespace", "prod"},
+			duration: defaultTailDuration,
+		},
+		`duration after flags`: {
+			args:     []string{"--namespace", "prod", "5m"},
+			rest:     []string{"--namespace", "prod"},
+			duration: 5 * time.Minute,
+		},
+		`duration before flag with value`: {
+			args:     []string{"90s", "--level=error"},
+			rest:     []string{"--level=error"},
+			duration: 90 * time.Second,
+		},
+		`invalid duration`: {
+			args: []string{"forever"},
+			err:  true,
+		},
+		`duration over the max`: {
+			args: []string{"1h"},
+			err:  true,
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			rest, duration, err := parseTailDuration(test.args)
+			if test.err {
+				if err == nil {
+					t.Errorf("expected error, got rest: %+v, duration: %s", rest, duration)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(rest, test.rest) || duration != test.duration {
+				t.Errorf("expected: %+v %s != actual: %+v %s\n", test.rest, test.duration, rest, duration)
+			}
+		})
+	}
+}
+
+func TestTailEventsDurationExpiry(t *testing.T) {
+	ch := make(chan events.Event, 2)
+	ch <- events.Event{Name: "pod-0"}
+	ch <- events.Event{Name: "pod-1"}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var sent []string
+	done := make(chan struct{})
+	go func() {
+		tailEvents(ctx, ch, func(list []events.Event) {
+			for _, e := range list {
+				sent = append(sent, e.Name)
+			}
+		}, time.Hour)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected tail to stop when the duration expires")
+	}
+	// Batched events are sent when the duration expires
+	expected := []string{"pod-0", "pod-1"}
+	if !reflect.DeepEqual(sent, expected) {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, sent)
+	}
+}
+
+func TestRunEventsTail(t *testing.T) {
+	tailInterval = 10 * time.Millisecond
+	defer func() { tailInterval = defaultWatchDebounce }()
+
+	var mu sync.Mutex
+	var sent []string
+	stopped := make(chan struct{})
+	sender := &ChannelSender{Channel: "tail-test", Send: func(msg string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, msg)
+		if strings.HasPrefix(msg, "Stopped tailing") {
+			close(stopped)
+		}
+		return nil
+	}}
+	e := &DefaultExecutor{ClusterName: "test-cluster", Sender: sender}
+
+	actual := e.runEventsTail([]string{"--namespace", "tail-test", "200ms"})
+	expected := fmt.Sprintf(tailStartedMsg, " of namespace tail-test", "test-cluster", 200*time.Millisecond)
+	if actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+	if actual := e.runEventsTail([]string{"1m"}); actual != tailChannelLimitMsg {
+		t.Errorf("expected: %+v != actual: %+v\n", tailChannelLimitMsg, actual)
+	}
+
+	events.Record(events.Event{Name: "nginx", Kind: "Pod", Namespace: "tail-test", Level: "info", Type: "create"})
+	events.Record(events.Event{Name: "redis", Kind: "Pod", Namespace: "other", Level: "info", Type: "create"})
+
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected tail to stop after the duration")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sent) != 2 || !strings.Contains(sent[0], "nginx") || strings.Contains(sent[0], "redis") {
+		t.Fatalf("expected new event and stop messages, got: %+v", sent)
+	}
+	expected = fmt.Sprintf(tailStoppedMsg, "test-cluster", 200*time.Millisecond)
+	if sent[1] != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, sent[1])
+	}
+}
+
+func TestAcquireTailLimit(t *testing.T) {
+	for i := 0; i < maxEventTails; i++ {
+		channel := fmt.Sprintf("channel-%d", i)
+		if msg := acquireTail(channel); len(msg) != 0 {
+			t.Fatalf("unexpected limit for %s: %s", channel, msg)
+		}
+		defer releaseTail(channel)
+	}
+	expected := fmt.Sprintf(tailLimitMsg, maxEventTails)
+	if actual := acquireTail("channel-over"); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	execDisabledMsg       = "Sorry, the admin hasn't allowed exec command on cluster '%s'."
+	execInteractiveMsg    = "Sorry, interactive exec is not supported. Please remove '%s' flag."
+	execMissingCommandMsg = "Please pass the command to run after '--', e.g exec mypod -- cat /etc/config"
+	execNotAllowedMsg     = "Sorry, '%s' is not in the list of commands allowed with exec. Allowed commands: %s"
+)
+
+// allowExec and execAllowlist are set from settings.allowExec and settings.execAllowlist
+var (
+	allowExec     bool
+	execAllowlist []string
+)
+
+// InitExec sets whether exec command is allowed and the commands which can be run with it
+func InitExec(allow bool, allowlist []string) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	allowExec = allow
+	execAllowlist = allowlist
+}
+
+// validateExec returns a message to reply if exec command is not allowed, empty string otherwise
+// Only commands passed after "--" matching an entry of allowlist are allowed and stdin or tty is never attached
+func validateExec(args []string, clusterName string, allowExec bool, allowlist []string) string {
+	if !allowExec {
+		return fmt.Sprintf(execDisabledMsg, clusterName)
+	}
+	separator := -1
+	for i, arg := range args {
+		if arg == "--" {
+			separator = i
+			break
+		}
+		if isInteractiveFlag(arg) {
+			return fmt.Sprintf(execInteractiveMsg, arg)
+		}
+	}
+	if separator == -1 || separator == len(args)-1 {
+		return execMissingCommandMsg
+	}
+
+	command := strings.Join(args[separator+1:], " ")
+	for _, allowed := range allowlist {
+		if command == strings.Join(strings.Fields(allowed), " ") {
+			return ""
+		}
+	}
+	allowed := "none"
+	if len(allowlist) != 0 {
+		allowed = strings.Join(allowlist, ", ")
+	}
+	return fmt.Sprintf(execNotAllowedMsg, command, allowed)
+}
+
+// isInteractiveFlag checks if the flag attaches stdin or allocates tty
+// Combined short flags like -it or -ti are also detected
+func isInteractiveFlag(arg string) bool {
+	if strings.HasPrefix(arg, "--") {
+		name := strings.SplitN(strings.TrimPrefix(arg, "--"), "=", 2)[0]
+		return name == "stdin" || name == "tty"
+	}
+	if !strings.HasPrefix(arg, "-") {
+		return false
+	}
+	for _, c := range strings.TrimPrefix(arg, "-") {
+		switch c {
+		case 'i', 't':
+			return true
+		case 'c', 'f', 'n', '=':
+			// Rest of the arg is the value of the flag, e.g -c=istio-proxy
+			return false
+		}
+	}
+	return false
+}
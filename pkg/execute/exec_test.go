@@ -0,0 +1,150 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"testing"
+)
+
+func TestValidateExec(t *testing.T) {
+	allowlist := []string{"cat /etc/config", "ls  -l /tmp"}
+	tests := map[string]struct {
+		args      []string
+		allowExec bool
+		expected  string
+	}{
+		`exec disabled`: {
+			args:     []string{"exec", "nginx", "--", "cat", "/etc/config"},
+			expected: "Sorry, the admin hasn't allowed exec command on cluster 'dev'.",
+		},
+		`allowed command`: {
+			args:      []string{"-n", "default", "exec", "nginx", "--", "cat", "/etc/config"},
+			allowExec: true,
+		},
+		`allowed command with container`: {
+			args:      []string{"exec", "nginx", "-c=istio-proxy", "--", "ls", "-l", "/tmp"},
+			allowExec: true,
+		},
+		`allowed command with container value containing i and t`: {
+			args:      []string{"exec", "nginx", "-c", "tiller", "--", "cat", "/etc/config"},
+			allowExec: true,
+		},
+		`command not in allowlist`: {
+			args:      []string{"exec", "nginx", "--", "sh"},
+			allowExec: true,
+			expected:  "Sorry, 'sh' is not in the list of commands allowed with exec. Allowed commands: cat /etc/config, ls  -l /tmp",
+		},
+		`allowed command with extra args`: {
+			args:      []string{"exec", "nginx", "--", "cat", "/etc/config", "/etc/shadow"},
+			allowExec: true,
+			expected:  "Sorry, 'cat /etc/config /etc/shadow' is not in the list of commands allowed with exec. Allowed commands: cat /etc/config, ls  -l /tmp",
+		},
+		`allowed command chained with shell`: {
+			args:      []string{"exec", "nginx", "--", "cat", "/etc/config;", "sh"},
+			allowExec: true,
+			expected:  "Sorry, 'cat /etc/config; sh' is not in the list of commands allowed with exec. Allowed commands: cat /etc/config, ls  -l /tmp",
+		},
+		`missing separator`: {
+			args:      []string{"exec", "nginx", "cat", "/etc/config"},
+			allowExec: true,
+			expected:  execMissingCommandMsg,
+		},
+		`missing command`: {
+			args:      []string{"exec", "nginx", "--"},
+			allowExec: true,
+			expected:  execMissingCommandMsg,
+		},
+		`interactive flag -it`: {
+			args:      []string{"exec", "-it", "nginx", "--", "cat", "/etc/config"},
+			allowExec: true,
+			expected:  "Sorry, interactive exec is not supported. Please remove '-it' flag.",
+		},
+		`interactive flag -ti`: {
+			args:      []string{"exec", "nginx", "-ti", "--", "cat", "/etc/config"},
+			allowExec: true,
+			expected:  "Sorry, interactive exec is not supported. Please remove '-ti' flag.",
+		},
+		`stdin flag -i`: {
+			args:      []string{"exec", "nginx", "-i", "--", "cat", "/etc/config"},
+			allowExec: true,
+			expected:  "Sorry, interactive exec is not supported. Please remove '-i' flag.",
+		},
+		`tty flag -t`: {
+			args:      []string{"exec", "-t", "nginx", "--", "cat", "/etc/config"},
+			allowExec: true,
+			expected:  "Sorry, interactive exec is not supported. Please remove '-t' flag.",
+		},
+		`combined with quiet flag`: {
+			args:      []string{"exec", "-qi", "nginx", "--", "cat", "/etc/config"},
+			allowExec: true,
+			expected:  "Sorry, interactive exec is not supported. Please remove '-qi' flag.",
+		},
+		`long stdin flag`: {
+			args:      []string{"exec", "nginx", "--stdin", "--", "cat", "/etc/config"},
+			allowExec: true,
+			expected:  "Sorry, interactive exec is not supported. Please remove '--stdin' flag.",
+		},
+		`long tty flag with value`: {
+			args:      []string{"exec", "nginx", "--tty=true", "--", "cat", "/etc/config"},
+			allowExec: true,
+			expected:  "Sorry, interactive exec is not supported. Please remove '--tty=true' flag.",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := validateExec(test.args, "dev", test.allowExec, allowlist); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestValidateExecEmptyAllowlist(t *testing.T) {
+	expected := "Sorry, 'cat /etc/config' is not in the list of commands allowed with exec. Allowed commands: none"
+	if actual := validateExec([]string{"exec", "nginx", "--", "cat", "/etc/config"}, "dev", true, nil); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
+
+func TestRunKubectlCommandExecArgs(t *testing.T) {
+	InitExec(true, []string{"tail -f /log", "tail --watch /log", "tail --cluster-name other /log", "tail -n 5 /log"})
+	defer InitExec(false, nil)
+	tests := map[string]struct {
+		args []string
+		cmd  string
+	}{
+		`follow flag of the command`:       {[]string{"exec", "nginx", "--", "tail", "-f", "/log"}, "-n default exec nginx -- tail -f /log"},
+		`watch flag of the command`:        {[]string{"exec", "nginx", "--", "tail", "--watch", "/log"}, "-n default exec nginx -- tail --watch /log"},
+		`cluster name flag of the command`: {[]string{"exec", "nginx", "--", "tail", "--cluster-name", "other", "/log"}, "-n default exec nginx -- tail --cluster-name other /log"},
+		`namespace flag of the command`:    {[]string{"exec", "nginx", "--", "tail", "-n", "5", "/log"}, "-n default exec nginx -- tail -n 5 /log"},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			KubectlResponse[test.cmd] = "log line"
+			defer delete(KubectlResponse, test.cmd)
+			expected := "Cluster: test-cluster\nlog line"
+			if actual := runKubectlCommand(test.args, "test-cluster", "default", true, nil); actual != expected {
+				t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+			}
+		})
+	}
+}
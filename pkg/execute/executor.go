@@ -21,18 +21,29 @@ package execute
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"text/tabwriter"
+	"time"
 	"unicode"
 
 	"gopkg.in/yaml.v2"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
 	filterengine "github.com/infracloudio/botkube/pkg/filterengine"
 	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/metrics"
 	"github.com/infracloudio/botkube/pkg/utils"
 )
 
@@ -53,6 +64,22 @@ var (
 	validInfoCommand = map[string]bool{
 		"commands": true,
 	}
+	validStatusCommand = map[string]bool{
+		"status": true,
+	}
+	validEventsCommand = map[string]bool{
+		"events": true,
+	}
+	validConfigCommand = map[string]bool{
+		"config": true,
+	}
+	validMaintenanceCommand = map[string]bool{
+		"maintenance": true,
+	}
+	// validDebugCommand is a map of BotKube debug commands, not to be confused with kubectl debug verbs
+	validDebugCommand = map[string]bool{
+		"debug": true,
+	}
 	validDebugCommands = map[string]bool{
 		"exec":         true,
 		"logs":         true,
@@ -66,43 +93,79 @@ var (
 	}
 
 	kubectlBinary = "/usr/local/bin/kubectl"
+
+	// commandPrefix is required at the start of commands if not empty
+	commandPrefix string
+	// requireConfirmation is set from settings.requireConfirmation
+	requireConfirmation bool
+	// conf is the config in use, reload sets it only once the new config is validated
+	conf *config.Config
+
+	// settingsMu guards the settings of the Init functions, config reload changes them while commands are executed
+	settingsMu sync.RWMutex
+
+	// startTime is used to calculate uptime of the BotKube instance
+	startTime = time.Now()
 )
 
 const (
-	notifierStopMsg    = "Sure! I won't send you notifications from cluster '%s' anymore."
-	unsupportedCmdMsg  = "Command not supported. Please run /botkubehelp to see supported commands."
-	kubectlDisabledMsg = "Sorry, the admin hasn't given me the permission to execute kubectl command on cluster '%s'."
-	filterNameMissing  = "You forgot to pass filter name. Please pass one of the following valid filters:\n\n%s"
-	filterEnabled      = "I have enabled '%s' filter on '%s' cluster."
-	filterDisabled     = "Done. I won't run '%s' filter on '%s' cluster."
+	notifierStopMsg     = "Sure! I won't send you notifications from cluster '%s' anymore."
+	unsupportedCmdMsg   = "Command not supported. Please %s to see supported commands."
+	incompleteCmdMsg    = "You missed to pass options for the command. Please %s to see command options."
+	kubectlDisabledMsg  = "Sorry, the admin hasn't given me the permission to execute kubectl command on cluster '%s'."
+	filterNameMissing   = "You forgot to pass filter name. Please pass one of the following valid filters:\n\n%s"
+	filterEnabled       = "I have enabled '%s' filter on '%s' cluster."
+	filterDisabled      = "Done. I won't run '%s' filter on '%s' cluster."
+	filterNameInvalid   = "Filter '%s' not found. Please pass one of the following valid filters:\n\n%s"
+	filterNameAmbiguous = "Filter name '%s' matches more than one filter: %s"
+	filtersResetMsg     = "Done. Filters are reset to the config on '%s' cluster.%s"
+	filtersUnchangedMsg = "Filters already match the config on '%s' cluster."
+
+	showConfigInvalidFlagMsg = "Invalid option '%s' for showconfig command. Use --summary, --yaml or one of the config sections."
+	eventsInvalidFlagMsg     = "Invalid option '%s' for events command. Use --kind, --namespace, --level or --count."
+	noEventsMsg              = "No matching events found on cluster '%s'."
+	logLevelMsg              = "Log level is '%s' on cluster '%s'."
+	logLevelChangedMsg       = "Done. Log level changed to '%s' on cluster '%s'."
+	invalidLogLevelMsg       = "Invalid log level '%s'. Please pass one of debug, info, warn or error."
+	configValidMsg           = "Configuration of cluster '%s' is valid."
+	maintenanceActiveMsg     = "Maintenance window%s is active on cluster '%s' until %s. Event notifications are not sent."
+	maintenanceInactiveMsg   = "No maintenance window is active on cluster '%s'."
+
+	// defaultEventsCount is the number of events returned if --count is not passed
+	defaultEventsCount = 10
+	// maxEventsCount limits the events returned to keep the message readable
+	maxEventsCount = 50
 
 	// NotifierStartMsg notifier enabled response message
 	NotifierStartMsg = "Brace yourselves, notifications are coming from cluster '%s'."
-	// IncompleteCmdMsg incomplete command response message
-	IncompleteCmdMsg = "You missed to pass options for the command. Please run /botkubehelp to see command options."
 	// WrongClusterCmdMsg incomplete command response message
 	WrongClusterCmdMsg = "Sorry, the admin hasn't configured me to do that for the cluster '%s'."
-
-	// Custom messages for teams platform
-	teamsUnsupportedCmdMsg = "Command not supported. Please visit botkube.io/usage to see supported commands."
-	teamsIncompleteCmdMsg  = "You missed to pass options for the command. Please run /botkubehelp to see command options."
 )
 
 // Executor is an interface for processes to execute commands
 type Executor interface {
 	Execute() string
+	ExecuteResult() Result
 }
 
 // DefaultExecutor is a default implementations of Executor
 type DefaultExecutor struct {
-	Platform         config.BotPlatform
-	Message          string
-	AllowKubectl     bool
-	RestrictAccess   bool
-	ClusterName      string
-	ChannelName      string
+	Platform       config.BotPlatform
+	Message        string
+	AllowKubectl   bool
+	RestrictAccess bool
+	ClusterName    string
+	ChannelName    string
+	// Thread is the thread of the channel the command was sent in, empty if the command is not scoped to a thread
+	Thread string
+	// User is the id of the user who sent the command, empty if the platform doesn't provide it
+	User             string
 	IsAuthChannel    bool
 	DefaultNamespace string
+	Sender           *ChannelSender
+
+	// kubectl is the result of the kubectl command run by Execute, nil for BotKube commands
+	kubectl *kubectlResult
 }
 
 // CommandRunner is an interface to run bash commands
@@ -110,6 +173,11 @@ type CommandRunner interface {
 	Run() (string, error)
 }
 
+// StreamRunner is an interface to run long running bash commands and read the output line by line
+type StreamRunner interface {
+	Stream(ctx context.Context, handle func(line string)) error
+}
+
 // NotifierAction creates custom type for notifier actions
 type NotifierAction string
 
@@ -130,11 +198,26 @@ type CommandFlags string
 
 // Defines botkube flags
 const (
-	ClusterFlag    CommandFlags = "--cluster-name"
-	FollowFlag     CommandFlags = "--follow"
-	AbbrFollowFlag CommandFlags = "-f"
-	WatchFlag      CommandFlags = "--watch"
-	AbbrWatchFlag  CommandFlags = "-w"
+	ClusterFlag           CommandFlags = "--cluster-name"
+	FollowFlag            CommandFlags = "--follow"
+	AbbrFollowFlag        CommandFlags = "-f"
+	WatchFlag             CommandFlags = "--watch"
+	AbbrWatchFlag         CommandFlags = "-w"
+	AllClustersFlag       CommandFlags = "--all-clusters"
+	KubectlVersionFlag    CommandFlags = "--kubectl-version"
+	TailFlag              CommandFlags = "--tail"
+	SummaryFlag           CommandFlags = "--summary"
+	YamlFlag              CommandFlags = "--yaml"
+	KindFlag              CommandFlags = "--kind"
+	NamespaceFlag         CommandFlags = "--namespace"
+	AbbrNamespaceFlag     CommandFlags = "-n"
+	AllNamespacesFlag     CommandFlags = "--all-namespaces"
+	AbbrAllNamespacesFlag CommandFlags = "-A"
+	LevelFlag             CommandFlags = "--level"
+	CountFlag             CommandFlags = "--count"
+	ChunkSizeFlag         CommandFlags = "--chunk-size"
+	CacheDirFlag          CommandFlags = "--cache-dir"
+	JSONResultFlag        CommandFlags = "--json-result"
 )
 
 func (flag CommandFlags) String() string {
@@ -146,9 +229,11 @@ type FiltersAction string
 
 // Filter command options
 const (
-	FilterList    FiltersAction = "list"
-	FilterEnable  FiltersAction = "enable"
-	FilterDisable FiltersAction = "disable"
+	FilterList     FiltersAction = "list"
+	FilterEnable   FiltersAction = "enable"
+	FilterDisable  FiltersAction = "disable"
+	FilterDescribe FiltersAction = "describe"
+	FilterReset    FiltersAction = "reset"
 )
 
 // infoAction for options in Info commands
@@ -159,14 +244,49 @@ const (
 	infoList infoAction = "list"
 )
 
+// eventsAction for options in events commands
+type eventsAction string
+
+// Events command options
+const (
+	eventsList eventsAction = "list"
+	eventsTail eventsAction = "tail"
+)
+
+// configAction for options in config commands
+type configAction string
+
+// Config command options
+const (
+	configValidate configAction = "validate"
+)
+
+// maintenanceAction for options in maintenance commands
+type maintenanceAction string
+
+// Maintenance command options
+const (
+	maintenanceStatus maintenanceAction = "status"
+)
+
+// debugAction for options in debug commands
+type debugAction string
+
+// Debug command options
+const (
+	debugLogLevel debugAction = "loglevel"
+)
+
 func (action FiltersAction) String() string {
 	return string(action)
 }
 
 // NewDefaultExecutor returns new Executor object
 // msg should not contain the BotId
+// user is the id of the user who sent the message, it is used to approve commands
+// sender is used by commands posting messages after the response, e.g get --watch. It can be nil if not supported
 func NewDefaultExecutor(msg string, allowkubectl, restrictAccess bool, defaultNamespace,
-	clusterName string, platform config.BotPlatform, channelName string, isAuthChannel bool) Executor {
+	clusterName string, platform config.BotPlatform, channelName, thread, user string, isAuthChannel bool, sender *ChannelSender) Executor {
 	return &DefaultExecutor{
 		Platform:         platform,
 		Message:          msg,
@@ -174,15 +294,40 @@ func NewDefaultExecutor(msg string, allowkubectl, restrictAccess bool, defaultNa
 		RestrictAccess:   restrictAccess,
 		ClusterName:      clusterName,
 		ChannelName:      channelName,
+		Thread:           thread,
+		User:             user,
 		IsAuthChannel:    isAuthChannel,
 		DefaultNamespace: defaultNamespace,
+		Sender:           sender,
 	}
 }
 
+// stateKey returns the key of the confirmations and truncated outputs of the channel, they are kept apart for each thread
+func (e *DefaultExecutor) stateKey() string {
+	if len(e.Thread) == 0 {
+		return e.ChannelName
+	}
+	return e.ChannelName + "/" + e.Thread
+}
+
 // Execute executes commands and returns output
 func (e *DefaultExecutor) Execute() string {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return e.execute()
+}
+
+// execute runs the command, settingsMu must be held
+func (e *DefaultExecutor) execute() (out string) {
 	// Remove hyperlink if it got added automatically
 	command := utils.RemoveHyperlink(e.Message)
+	command, ok := trimCommandPrefix(command, commandPrefix)
+	if !ok {
+		return ""
+	}
+	if command, ok = trimInstanceAddress(command); !ok {
+		return ""
+	}
 	args := strings.Fields(strings.TrimSpace(command))
 	if len(args) == 0 {
 		if e.IsAuthChannel {
@@ -190,11 +335,16 @@ func (e *DefaultExecutor) Execute() string {
 		}
 		return "" // this prevents all bots on all clusters to answer something
 	}
-	if len(args) >= 1 && utils.AllowedKubectlVerbMap[args[0]] {
+	defer func() {
+		metrics.IncCommands(commandName(args[0]), commandStatus(e.Platform, out))
+	}()
+	// Node operations need confirmation, they run as kubectl verbs if settings.allowNodeOps is not set
+	if validNodeOpCommand[args[0]] && allowNodeOps {
+		return e.runNodeOpCommand(args)
+	}
+	if kubectlMaps := utils.GetKubectlMaps(); kubectlMaps.AllowedVerb(args[0]) {
 		if validDebugCommands[args[0]] || // Don't check for resource if is a valid debug command
-			utils.AllowedKubectlResourceMap[args[1]] || // Check if allowed resource
-			utils.AllowedKubectlResourceMap[utils.KindResourceMap[strings.ToLower(args[1])]] || // Check if matches with kind name
-			utils.AllowedKubectlResourceMap[utils.ShortnameResourceMap[strings.ToLower(args[1])]] { // Check if matches with short name
+			len(args) > 1 && kubectlMaps.AllowedResource(args[1]) { // Check if allowed resource, kind or short name
 			isClusterNamePresent := strings.Contains(e.Message, "--cluster-name")
 			if !e.AllowKubectl {
 				if isClusterNamePresent && e.ClusterName == utils.GetClusterNameFromKubectlCmd(e.Message) {
@@ -206,13 +356,41 @@ func (e *DefaultExecutor) Execute() string {
 			if e.RestrictAccess && !e.IsAuthChannel && isClusterNamePresent {
 				return ""
 			}
-			return runKubectlCommand(args, e.ClusterName, e.DefaultNamespace, e.IsAuthChannel)
+			// Mutating commands run once another user approves them
+			if requireApproval && isMutating(args) {
+				if !isClusterNamePresent && !e.IsAuthChannel {
+					return ""
+				}
+				if isClusterNamePresent {
+					if _, ok := resolveCluster(trimQuotes(utils.GetClusterNameFromKubectlCmd(e.Message)), e.ClusterName); !ok {
+						return ""
+					}
+				}
+				return requestApproval(e.User, args, e.ClusterName)
+			}
+			res := runKubectl(args, e.ClusterName, e.DefaultNamespace, e.IsAuthChannel, e.watchFunc())
+			e.kubectl = &res
+			out := res.String()
+			if args[0] == "describe" {
+				return truncateOutput(e.stateKey(), out, describeMaxLines)
+			}
+			if guardGetAll && isGetAll(args) {
+				return truncateOutput(e.stateKey(), out, getAllMaxLines)
+			}
+			if args[0] == "api-resources" {
+				return e.sendAPIResources(out)
+			}
+			return out
 		}
 	}
 	if ValidNotifierCommand[args[0]] {
 		return e.runNotifierCommand(args, e.ClusterName, e.IsAuthChannel)
 	}
 	if validPingCommand[args[0]] {
+		if utils.Contains(args, AllClustersFlag.String()) {
+			// Short response to make it easy to spot the clusters which didn't reply
+			return fmt.Sprintf("pong from cluster '%s' (pod: %s, uptime: %s)", e.ClusterName, podName(), formatUptime(time.Since(startTime)))
+		}
 		res := runVersionCommand(args, e.ClusterName)
 		if len(res) == 0 {
 			return ""
@@ -232,17 +410,145 @@ func (e *DefaultExecutor) Execute() string {
 		return e.runInfoCommand(args, e.IsAuthChannel)
 	}
 
+	// Check if status command
+	if validStatusCommand[args[0]] {
+		return e.runStatusCommand(args, e.IsAuthChannel)
+	}
+
+	// Check if whoami command
+	if validWhoamiCommand[args[0]] {
+		return e.runWhoamiCommand(args, e.IsAuthChannel)
+	}
+
+	// Check if events command
+	if validEventsCommand[args[0]] {
+		return e.runEventsCommand(args, e.IsAuthChannel)
+	}
+
+	if validApproveCommand[args[0]] {
+		return e.runApproveCommand(args)
+	}
+	// Check if getfile command
+	if validGetFileCommand[args[0]] {
+		return e.runGetFileCommand(args)
+	}
+
+	// Check if diff live command, kubectl diff needs a local file
+	if validDiffCommand[args[0]] && len(args) > 1 && args[1] == "live" {
+		return e.runDiffLiveCommand(args)
+	}
+
+	// Check if get-full command
+	if validFullOutputCommand[args[0]] {
+		return e.runFullOutputCommand(args)
+	}
+
+	// Check if resources command
+	if validResourcesCommand[args[0]] {
+		return e.runResourcesCommand(args, e.IsAuthChannel)
+	}
+
+	// Check if maintenance command
+	if validMaintenanceCommand[args[0]] {
+		return e.runMaintenanceCommand(args, e.IsAuthChannel)
+	}
+
+	// Check if config command, other subcommands are kubectl config which is not supported
+	if validConfigCommand[args[0]] && len(args) > 1 && args[1] == string(configValidate) {
+		return e.runConfigCommand(args, e.IsAuthChannel)
+	}
+
+	// Check if debug command, other subcommands are kubectl debug
+	if validDebugCommand[args[0]] && len(args) > 1 && args[1] == string(debugLogLevel) {
+		return e.runDebugCommand(args, e.IsAuthChannel)
+	}
+
 	if e.IsAuthChannel {
 		return printDefaultMsg(e.Platform)
 	}
 	return ""
 }
 
+// InitConfig sets the config used by the commands showing and resetting BotKube state
+func InitConfig(c *config.Config) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	conf = c
+}
+
+// loadedConfig returns the config set with InitConfig, settingsMu must be held
+func loadedConfig() (*config.Config, error) {
+	if conf == nil {
+		return nil, fmt.Errorf("Configuration is not loaded")
+	}
+	return conf, nil
+}
+
+// InitCommandPrefix sets the prefix required at the start of commands, commands don't need a prefix if empty
+func InitCommandPrefix(prefix string) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	commandPrefix = strings.TrimSpace(prefix)
+}
+
+// trimCommandPrefix removes the prefix from the command, it returns false if the command doesn't start with it
+// The prefix is matched case-insensitively
+func trimCommandPrefix(command, prefix string) (string, bool) {
+	if len(prefix) == 0 {
+		return command, true
+	}
+	command = strings.TrimSpace(command)
+	if len(command) < len(prefix) || !strings.EqualFold(command[:len(prefix)], prefix) {
+		return "", false
+	}
+	rest := command[len(prefix):]
+	// Prefix must be followed by space, e.g !bkget pods is not a command
+	if len(rest) != 0 && !unicode.IsSpace(rune(rest[0])) {
+		return "", false
+	}
+	return rest, true
+}
+
+// commandName returns the command label for metrics, unknown commands are grouped to avoid high cardinality
+func commandName(cmd string) string {
+	if utils.GetKubectlMaps().AllowedVerb(cmd) || ValidNotifierCommand[cmd] || validPingCommand[cmd] || validVersionCommand[cmd] ||
+		validFilterCommand[cmd] || validInfoCommand[cmd] || validStatusCommand[cmd] || validEventsCommand[cmd] || validDebugCommand[cmd] || validConfigCommand[cmd] ||
+		validResourcesCommand[cmd] || validFullOutputCommand[cmd] || validApproveCommand[cmd] || validMaintenanceCommand[cmd] || validGetFileCommand[cmd] ||
+		validWhoamiCommand[cmd] || validDiffCommand[cmd] || validNodeOpCommand[cmd] {
+		return cmd
+	}
+	return "unknown"
+}
+
+// commandStatus returns the command status label for metrics based on the response
+func commandStatus(p config.BotPlatform, out string) string {
+	switch out {
+	case "":
+		return metrics.StatusIgnored
+	case printDefaultMsg(p):
+		return metrics.StatusUnsupported
+	}
+	return metrics.StatusSuccess
+}
+
 func printDefaultMsg(p config.BotPlatform) string {
-	if p == config.TeamsBot {
-		return teamsUnsupportedCmdMsg
+	return fmt.Sprintf(unsupportedCmdMsg, helpHint(p))
+}
+
+// IncompleteCmdMsg returns the incomplete command response message for the platform
+func IncompleteCmdMsg(p config.BotPlatform) string {
+	return fmt.Sprintf(incompleteCmdMsg, helpHint(p))
+}
+
+// helpHint tells how to get the command help on the platform, /botkubehelp is a slash command of the Slack app only
+func helpHint(p config.BotPlatform) string {
+	switch p {
+	case config.TeamsBot:
+		return "visit botkube.io/usage"
+	case config.MattermostBot, config.DiscordBot:
+		return "run @BotKube commands list or visit botkube.io/usage"
 	}
-	return unsupportedCmdMsg
+	return "run /botkubehelp"
 }
 
 // Trim single and double quotes from ends of string
@@ -255,56 +561,205 @@ func trimQuotes(clusterValue string) string {
 	})
 }
 
-func runKubectlCommand(args []string, clusterName, defaultNamespace string, isAuthChannel bool) string {
+// beforeSeparator returns the args passed before "--", the rest belong to the command run with exec
+func beforeSeparator(args []string) []string {
+	for i, arg := range args {
+		if arg == "--" {
+			return args[:i]
+		}
+	}
+	return args
+}
+
+// runKubectlCommand runs kubectl command, get command with watch flag is passed to watch if it is not nil
+func runKubectlCommand(args []string, clusterName, defaultNamespace string, isAuthChannel bool, watch func(binary string, args []string) string) string {
+	return runKubectl(args, clusterName, defaultNamespace, isAuthChannel, watch).String()
+}
+
+// runKubectl runs kubectl command and returns its output, the error and exit code are kept separately for JSON results
+func runKubectl(args []string, clusterName, defaultNamespace string, isAuthChannel bool, watch func(binary string, args []string) string) kubectlResult {
+	// Limit logs output if --tail is not passed
+	args = withDefaultTail(args)
+	// List large collections in chunks if --chunk-size is not passed
+	args = withDefaultChunkSize(args)
+	verb := args[0]
+	checkGetAll := guardGetAll && isGetAll(args)
 
 	// run commands in namespace specified under Config.Settings.DefaultNamespace field
-	if !utils.Contains(args, "-n") && !utils.Contains(args, "--namespace") && len(defaultNamespace) != 0 {
+	if flags := beforeSeparator(args); !utils.Contains(flags, "-n") && !utils.Contains(flags, "--namespace") && len(defaultNamespace) != 0 {
 		args = append([]string{"-n", defaultNamespace}, utils.DeleteDoubleWhiteSpace(args)...)
 	}
 
 	// Remove unnecessary flags
 	finalArgs := []string{}
 	isClusterNameArg := false
+	isKubectlVersionArg := false
+	isWatch := false
+	kubectlVersion := ""
+	target := clusterName
+	var contextFlags []string
 	for index, arg := range args {
 		if isClusterNameArg {
 			isClusterNameArg = false
 			continue
 		}
+		if isKubectlVersionArg {
+			isKubectlVersionArg = false
+			kubectlVersion = trimQuotes(arg)
+			continue
+		}
+		if arg == "--" {
+			// Arguments of the command run with exec are passed unchanged
+			finalArgs = append(finalArgs, args[index:]...)
+			break
+		}
+		// Check --kubectl-version flag to select kubectl binary
+		if arg == KubectlVersionFlag.String() {
+			isKubectlVersionArg = true
+			continue
+		}
+		if strings.HasPrefix(arg, KubectlVersionFlag.String()+"=") {
+			kubectlVersion = trimQuotes(strings.SplitAfterN(arg, KubectlVersionFlag.String()+"=", 2)[1])
+			continue
+		}
 		if arg == AbbrFollowFlag.String() || strings.HasPrefix(arg, FollowFlag.String()) {
 			continue
 		}
 		if arg == AbbrWatchFlag.String() || strings.HasPrefix(arg, WatchFlag.String()) {
+			isWatch = true
 			continue
 		}
 		// Check --cluster-name flag
 		if strings.HasPrefix(arg, ClusterFlag.String()) {
 			// Check if flag value in current or next argument and compare with config.settings.clustername
+			// or the clusters in settings.kubectl.clusters
+			name := ""
 			if arg == ClusterFlag.String() {
-				if index == len(args)-1 || trimQuotes(args[index+1]) != clusterName {
-					return ""
+				if index == len(args)-1 {
+					return kubectlResult{ignored: true}
 				}
+				name = trimQuotes(args[index+1])
 				isClusterNameArg = true
 			} else {
-				if trimQuotes(strings.SplitAfterN(arg, ClusterFlag.String()+"=", 2)[1]) != clusterName {
-					return ""
-				}
+				name = trimQuotes(strings.SplitAfterN(arg, ClusterFlag.String()+"=", 2)[1])
+			}
+			flags, ok := resolveCluster(name, clusterName)
+			if !ok {
+				return kubectlResult{ignored: true}
 			}
+			target, contextFlags = name, flags
 			isAuthChannel = true
 			continue
 		}
 		finalArgs = append(finalArgs, arg)
 	}
 	if isAuthChannel == false {
-		return ""
+		return kubectlResult{ignored: true}
+	}
+	// Flags are passed before the verb to keep the arguments after "--" intact
+	finalArgs = append(contextFlags, finalArgs...)
+	clusterName = target
+	binary, err := kubectlBinaryPath(kubectlVersion)
+	if err != nil {
+		return kubectlResult{cluster: clusterName, stderr: err.Error(), exitCode: 1}
+	}
+	if msg := validateOutputFormat(finalArgs, allowedOutputFormats); len(msg) != 0 {
+		return kubectlResult{cluster: clusterName, stderr: msg, exitCode: 1}
+	}
+	if msg := validateRedactableFormat(finalArgs, redactFields); len(msg) != 0 {
+		return kubectlResult{cluster: clusterName, stderr: msg, exitCode: 1}
+	}
+	// get all lists every resource type, it is allowed only in a namespace
+	if checkGetAll {
+		if msg := validateGetAll(finalArgs); len(msg) != 0 {
+			return kubectlResult{cluster: clusterName, stderr: msg, exitCode: 1}
+		}
+	}
+	if verb == "exec" {
+		if msg := validateExec(finalArgs, clusterName, allowExec, execAllowlist); len(msg) != 0 {
+			return kubectlResult{cluster: clusterName, stderr: msg, exitCode: 1}
+		}
+	}
+	sortBy := ""
+	if verb == "top" {
+		if finalArgs, sortBy, err = extractTopSortFlag(finalArgs); err != nil {
+			return kubectlResult{cluster: clusterName, stderr: err.Error(), exitCode: 1}
+		}
+	}
+	// Impersonation is a privilege, the flags are removed unless it is allowed
+	impersonationNote := ""
+	if !allowImpersonation {
+		var stripped bool
+		if finalArgs, stripped = stripImpersonationFlags(finalArgs); stripped {
+			impersonationNote = fmt.Sprintf(impersonationDisabledMsg, clusterName) + "\n"
+		}
+	}
+	if isWatch && verb == "get" && watch != nil {
+		if len(redactFields) != 0 && isRedactable(outputContentType(finalArgs[len(contextFlags):])) {
+			return kubectlResult{cluster: clusterName, stderr: redactWatchMsg, exitCode: 1}
+		}
+		return kubectlResult{cluster: clusterName, note: impersonationNote, stdout: watch(binary, finalArgs)}
+	}
+	// explain output is served from cache until settings.kubectl.explainCacheTTL expires
+	cacheKey := binary + " " + strings.Join(finalArgs, " ")
+	if verb == "explain" {
+		if out, ok := explainOutputs.get(cacheKey, time.Now()); ok {
+			return kubectlResult{cluster: clusterName, note: impersonationNote, stdout: out}
+		}
+	}
+	// Filtered api-resources commands are served from the full list cached for apiResourcesCacheTTL
+	var apiFilter *apiResourcesFilter
+	if verb == "api-resources" {
+		if f, ok := parseAPIResourcesFilter(finalArgs[len(contextFlags):]); ok {
+			apiFilter = &f
+			finalArgs = append(append([]string{}, contextFlags...), verb)
+			cacheKey = binary + " " + strings.Join(finalArgs, " ")
+			if out, ok := apiResourcesOutputs.get(cacheKey, time.Now()); ok {
+				return kubectlResult{cluster: clusterName, note: impersonationNote, stdout: f.apply(out)}
+			}
+		}
 	}
 	// Get command runner
-	runner := NewCommandRunner(kubectlBinary, finalArgs)
+	runner := NewCommandRunner(binary, finalArgs)
 	out, err := runner.Run()
 	if err != nil {
 		log.Error("Error in executing kubectl command: ", err)
-		return fmt.Sprintf("Cluster: %s\n%s", clusterName, out+err.Error())
+		code := exitCode(err)
+		if verb == "top" && isMetricsUnavailable(out+err.Error()) {
+			return kubectlResult{cluster: clusterName, stderr: fmt.Sprintf(metricsUnavailableMsg, clusterName), exitCode: code}
+		}
+		// Return the containers to choose from instead of the raw error, pods are looked up in BotKube cluster only
+		if msg := enrichContainerError(finalArgs, out); len(msg) != 0 && len(contextFlags) == 0 {
+			return kubectlResult{cluster: clusterName, stderr: msg, exitCode: code}
+		}
+		// Reply with a short message for the common failures, the full error is logged
+		if msg := classifyKubectlError(out+err.Error(), clusterName); len(msg) != 0 {
+			log.Debugf("kubectl %s failed on cluster %s: %s", strings.Join(finalArgs, " "), clusterName, out+err.Error())
+			return kubectlResult{cluster: clusterName, note: impersonationNote, stderr: msg, exitCode: code}
+		}
+		// Partial output of the failed command is redacted too
+		if len(redactFields) != 0 && len(strings.TrimSpace(out)) != 0 {
+			if out = redactOutput(out, outputContentType(finalArgs[len(contextFlags):]), redactFields); !strings.HasSuffix(out, "\n") {
+				out += "\n"
+			}
+		}
+		return kubectlResult{cluster: clusterName, note: impersonationNote, stderr: out + err.Error(), exitCode: code}
+	}
+	if verb == "top" {
+		out = formatTopOutput(out, sortBy)
+	}
+	if verb == "explain" {
+		explainOutputs.set(cacheKey, out, time.Now())
 	}
-	return fmt.Sprintf("Cluster: %s\n%s", clusterName, out)
+	if apiFilter != nil {
+		apiResourcesOutputs.set(cacheKey, out, time.Now())
+		out = apiFilter.apply(out)
+	}
+	// Fields in settings.kubectl.redactFields are removed from YAML and JSON output
+	if len(redactFields) != 0 {
+		out = redactOutput(out, outputContentType(finalArgs[len(contextFlags):]), redactFields)
+	}
+	return kubectlResult{cluster: clusterName, note: impersonationNote, stdout: out}
 }
 
 // TODO: Have a separate cli which runs bot commands
@@ -313,7 +768,14 @@ func (e *DefaultExecutor) runNotifierCommand(args []string, clusterName string,
 		return ""
 	}
 	if len(args) < 2 {
-		return IncompleteCmdMsg
+		return IncompleteCmdMsg(e.Platform)
+	}
+
+	switch args[1] {
+	case Start.String(), Stop.String():
+		if res := checkConfirmation(e.stateKey(), NotifierAction(args[1]), args[2:], clusterName, requireConfirmation); len(res) != 0 {
+			return res
+		}
 	}
 
 	switch args[1] {
@@ -331,9 +793,27 @@ func (e *DefaultExecutor) runNotifierCommand(args []string, clusterName string,
 		}
 		return fmt.Sprintf("Notifications are on for cluster '%s'", clusterName)
 	case ShowConfig.String():
-		out, err := showControllerConfig()
+		summary, section := false, ""
+		for _, arg := range args[2:] {
+			switch {
+			case arg == SummaryFlag.String():
+				summary = true
+			case arg == YamlFlag.String():
+			case strings.HasPrefix(arg, "-") || len(section) != 0:
+				return fmt.Sprintf(showConfigInvalidFlagMsg, arg)
+			default:
+				section = arg
+			}
+		}
+		if summary && len(section) != 0 {
+			return fmt.Sprintf(showConfigInvalidFlagMsg, section)
+		}
+		out, err := showControllerConfig(summary, section)
 		if err != nil {
 			log.Error("Error in executing showconfig command: ", err)
+			if _, ok := err.(invalidSectionError); ok {
+				return err.Error()
+			}
 			return "Error in getting configuration!"
 		}
 		return fmt.Sprintf("Showing config for cluster '%s'\n\n%s", clusterName, out)
@@ -341,13 +821,20 @@ func (e *DefaultExecutor) runNotifierCommand(args []string, clusterName string,
 	return printDefaultMsg(e.Platform)
 }
 
+// InitConfirmation sets whether the notifier start and stop commands must be confirmed
+func InitConfirmation(require bool) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	requireConfirmation = require
+}
+
 // runFilterCommand to list, enable or disable filters
 func (e *DefaultExecutor) runFilterCommand(args []string, clusterName string, isAuthChannel bool) string {
 	if isAuthChannel == false {
 		return ""
 	}
 	if len(args) < 2 {
-		return IncompleteCmdMsg
+		return IncompleteCmdMsg(e.Platform)
 	}
 
 	switch args[1] {
@@ -360,22 +847,52 @@ func (e *DefaultExecutor) runFilterCommand(args []string, clusterName string, is
 		if len(args) < 3 {
 			return fmt.Sprintf(filterNameMissing, makeFiltersList())
 		}
-		log.Debug("Enable filters", args[2])
-		if err := filterengine.DefaultFilterEngine.SetFilter(args[2], true); err != nil {
+		name, err := resolveFilterName(args[2])
+		if err != nil {
+			return err.Error()
+		}
+		log.Debug("Enable filters", name)
+		if err := filterengine.DefaultFilterEngine.SetFilter(name, true); err != nil {
 			return err.Error()
 		}
-		return fmt.Sprintf(filterEnabled, args[2], clusterName)
+		return fmt.Sprintf(filterEnabled, name, clusterName)
 
 	// Disable filter
 	case FilterDisable.String():
 		if len(args) < 3 {
 			return fmt.Sprintf(filterNameMissing, makeFiltersList())
 		}
-		log.Debug("Disabled filters", args[2])
-		if err := filterengine.DefaultFilterEngine.SetFilter(args[2], false); err != nil {
+		name, err := resolveFilterName(args[2])
+		if err != nil {
+			return err.Error()
+		}
+		log.Debug("Disabled filters", name)
+		if err := filterengine.DefaultFilterEngine.SetFilter(name, false); err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf(filterDisabled, name, clusterName)
+
+	// Describe filter
+	case FilterDescribe.String():
+		if len(args) < 3 {
+			return fmt.Sprintf(filterNameMissing, makeFiltersList())
+		}
+		name, err := resolveFilterName(args[2])
+		if err != nil {
 			return err.Error()
 		}
-		return fmt.Sprintf(filterDisabled, args[2], clusterName)
+		log.Debug("Describe filter", name)
+		return describeFilter(name)
+
+	// Reset filters to the enabled state in config
+	case FilterReset.String():
+		c, err := loadedConfig()
+		if err != nil {
+			log.Errorf("Error in loading configuration. %s", err.Error())
+			return err.Error()
+		}
+		log.Debug("Reset filters")
+		return resetFilters(c.Settings.Filters, clusterName)
 	}
 	return printDefaultMsg(e.Platform)
 }
@@ -385,8 +902,8 @@ func (e *DefaultExecutor) runInfoCommand(args []string, isAuthChannel bool) stri
 	if isAuthChannel == false {
 		return ""
 	}
-	if len(args) < 2 && args[1] != string(infoList) {
-		return IncompleteCmdMsg
+	if len(args) < 2 || args[1] != string(infoList) {
+		return IncompleteCmdMsg(e.Platform)
 	}
 
 	if len(args) > 3 && args[2] == ClusterFlag.String() && args[3] != e.ClusterName {
@@ -396,9 +913,327 @@ func (e *DefaultExecutor) runInfoCommand(args []string, isAuthChannel bool) stri
 	return makeCommandInfoList()
 }
 
+// runStatusCommand to show monitored resources, enabled notifiers and events sent since startup
+func (e *DefaultExecutor) runStatusCommand(args []string, isAuthChannel bool) string {
+	if isAuthChannel == false {
+		return ""
+	}
+	if len(args) > 2 && args[1] == ClusterFlag.String() && args[2] != e.ClusterName {
+		return fmt.Sprintf(WrongClusterCmdMsg, args[2])
+	}
+
+	c, err := loadedConfig()
+	if err != nil {
+		log.Error("Error in executing status command: ", err)
+		return "Error in getting configuration!"
+	}
+	return fmt.Sprintf("Status of cluster '%s'\n\n%s", e.ClusterName, makeStatusList(c))
+}
+
+// runEventsCommand to list recent events sent to notifiers or tail the new ones
+func (e *DefaultExecutor) runEventsCommand(args []string, isAuthChannel bool) string {
+	if isAuthChannel == false {
+		return ""
+	}
+	if len(args) < 2 {
+		return IncompleteCmdMsg(e.Platform)
+	}
+	switch eventsAction(args[1]) {
+	case eventsList:
+	case eventsTail:
+		return e.runEventsTail(args[2:])
+	default:
+		return IncompleteCmdMsg(e.Platform)
+	}
+
+	q, clusterName, err := parseEventsQuery(args[2:])
+	if err != nil {
+		return err.Error()
+	}
+	if len(clusterName) != 0 && clusterName != e.ClusterName {
+		return ""
+	}
+
+	recent := events.Recent(q)
+	if len(recent) == 0 {
+		return fmt.Sprintf(noEventsMsg, e.ClusterName)
+	}
+	return fmt.Sprintf("Recent events on cluster '%s'\n\n%s", e.ClusterName, makeEventsList(recent))
+}
+
+// runConfigCommand to validate the configuration
+func (e *DefaultExecutor) runConfigCommand(args []string, isAuthChannel bool) string {
+	if isAuthChannel == false {
+		return ""
+	}
+	if len(args) < 2 || args[1] != string(configValidate) {
+		return IncompleteCmdMsg(e.Platform)
+	}
+	if len(args) > 3 && args[2] == ClusterFlag.String() && trimQuotes(args[3]) != e.ClusterName {
+		return ""
+	}
+
+	c, err := loadedConfig()
+	if err != nil {
+		return fmt.Sprintf("Validation report of cluster '%s'\n\nERROR: %s", e.ClusterName, err.Error())
+	}
+	return makeValidationReport(e.ClusterName, c.Validate())
+}
+
+// makeValidationReport returns the list of configuration issues
+func makeValidationReport(clusterName string, issues []config.ValidationIssue) string {
+	if len(issues) == 0 {
+		return fmt.Sprintf(configValidMsg, clusterName)
+	}
+	lines := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		lines = append(lines, issue.String())
+	}
+	return fmt.Sprintf("Validation report of cluster '%s'\n\n%s", clusterName, strings.Join(lines, "\n"))
+}
+
+// runMaintenanceCommand to show if event notifications are suppressed by settings.maintenanceWindows
+func (e *DefaultExecutor) runMaintenanceCommand(args []string, isAuthChannel bool) string {
+	if isAuthChannel == false {
+		return ""
+	}
+	if len(args) < 2 || args[1] != string(maintenanceStatus) {
+		return IncompleteCmdMsg(e.Platform)
+	}
+	if len(args) > 3 && args[2] == ClusterFlag.String() && trimQuotes(args[3]) != e.ClusterName {
+		return ""
+	}
+
+	c, err := loadedConfig()
+	if err != nil {
+		log.Error("Error in executing maintenance command: ", err)
+		return "Error in getting configuration!"
+	}
+	return makeMaintenanceStatus(e.ClusterName, c.Settings.MaintenanceWindows, time.Now())
+}
+
+// makeMaintenanceStatus returns the window active at the time
+func makeMaintenanceStatus(clusterName string, windows []config.MaintenanceWindow, now time.Time) string {
+	w, end, ok := config.ActiveMaintenanceWindow(windows, now)
+	if !ok {
+		return fmt.Sprintf(maintenanceInactiveMsg, clusterName)
+	}
+	name := ""
+	if len(w.Name) != 0 {
+		name = fmt.Sprintf(" '%s'", w.Name)
+	}
+	return fmt.Sprintf(maintenanceActiveMsg, name, clusterName, end.Format(time.RFC3339))
+}
+
+// runDebugCommand to show or change BotKube log level
+func (e *DefaultExecutor) runDebugCommand(args []string, isAuthChannel bool) string {
+	if isAuthChannel == false {
+		return ""
+	}
+	if len(args) < 2 || args[1] != string(debugLogLevel) {
+		return IncompleteCmdMsg(e.Platform)
+	}
+
+	// Remove --cluster-name flag and its value
+	level := ""
+	for i := 2; i < len(args); i++ {
+		if args[i] == ClusterFlag.String() {
+			if i+1 < len(args) && trimQuotes(args[i+1]) != e.ClusterName {
+				return ""
+			}
+			i++
+			continue
+		}
+		if strings.HasPrefix(args[i], ClusterFlag.String()+"=") {
+			if trimQuotes(strings.SplitAfterN(args[i], ClusterFlag.String()+"=", 2)[1]) != e.ClusterName {
+				return ""
+			}
+			continue
+		}
+		level = args[i]
+	}
+
+	if len(level) == 0 {
+		return fmt.Sprintf(logLevelMsg, log.GetLevel(), e.ClusterName)
+	}
+	if err := log.SetLevel(strings.ToLower(level)); err != nil {
+		return fmt.Sprintf(invalidLogLevelMsg, level)
+	}
+	log.Infof("Log level changed to %s", log.GetLevel())
+	return fmt.Sprintf(logLevelChangedMsg, log.GetLevel(), e.ClusterName)
+}
+
+// parseEventsQuery parses events command options into a query
+func parseEventsQuery(args []string) (events.Query, string, error) {
+	q := events.Query{Limit: defaultEventsCount}
+	clusterName := ""
+	for i := 0; i < len(args); i++ {
+		flag, value := args[i], ""
+		if parts := strings.SplitN(flag, "=", 2); len(parts) == 2 {
+			flag, value = parts[0], parts[1]
+		} else if i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		value = trimQuotes(value)
+		if len(value) == 0 {
+			return q, "", fmt.Errorf(eventsInvalidFlagMsg, flag)
+		}
+
+		switch CommandFlags(flag) {
+		case KindFlag:
+			q.Kind = value
+		case NamespaceFlag, AbbrNamespaceFlag:
+			q.Namespace = value
+		case LevelFlag:
+			q.Level = value
+		case CountFlag:
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return q, "", fmt.Errorf(eventsInvalidFlagMsg, flag+" "+value)
+			}
+			q.Limit = count
+		case ClusterFlag:
+			clusterName = value
+		default:
+			return q, "", fmt.Errorf(eventsInvalidFlagMsg, flag)
+		}
+	}
+	if q.Limit > maxEventsCount {
+		q.Limit = maxEventsCount
+	}
+	return q, clusterName, nil
+}
+
+// makeEventsList returns events in tabular form
+func makeEventsList(list []events.Event) string {
+	buf := new(bytes.Buffer)
+	w := tabwriter.NewWriter(buf, 5, 0, 1, ' ', 0)
+
+	fmt.Fprintln(w, "TIME\tLEVEL\tTYPE\tKIND\tNAMESPACE\tNAME\tREASON")
+	for _, e := range list {
+		namespace := e.Namespace
+		if len(namespace) == 0 {
+			namespace = "-"
+		}
+		reason := e.Reason
+		if len(reason) == 0 {
+			reason = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", e.TimeStamp.UTC().Format(time.RFC3339), e.Level, e.Type, e.Kind, namespace, e.Name, reason)
+	}
+
+	w.Flush()
+	return buf.String()
+}
+
+// makeStatusList returns monitored resources and notifiers in tabular form
+func makeStatusList(c *config.Config) string {
+	buf := new(bytes.Buffer)
+	w := tabwriter.NewWriter(buf, 5, 0, 1, ' ', 0)
+
+	notifications := "on"
+	if !config.Notify {
+		notifications = "off"
+	}
+	fmt.Fprintf(w, "Notifications:\t%s\n", notifications)
+	fmt.Fprintf(w, "Events sent since startup:\t%d\n", events.SentCount())
+	fmt.Fprintf(w, "Enabled notifiers:\t%s\n", strings.Join(enabledNotifiers(c.Communications), ", "))
+	fmt.Fprintln(w)
+	writeResources(w, c.Resources)
+
+	w.Flush()
+	return buf.String()
+}
+
+// makeConfigSummary returns enabled notifiers, monitored resources and filters in tabular form
+func makeConfigSummary(c config.Config) string {
+	buf := new(bytes.Buffer)
+	w := tabwriter.NewWriter(buf, 5, 0, 1, ' ', 0)
+
+	kubectl := "disabled"
+	if c.Settings.Kubectl.Enabled {
+		kubectl = "enabled"
+	}
+	fmt.Fprintf(w, "Cluster name:\t%s\n", c.Settings.ClusterName)
+	fmt.Fprintf(w, "Enabled notifiers:\t%s\n", strings.Join(enabledNotifiers(c.Communications), ", "))
+	fmt.Fprintf(w, "Kubectl commands:\t%s\n", kubectl)
+	fmt.Fprintf(w, "Recommendations:\t%v\n", c.Recommendations)
+	fmt.Fprintln(w)
+	writeResources(w, c.Resources)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "FILTER\tENABLED")
+	filters := filterengine.DefaultFilterEngine.ShowFilters()
+	names := make([]string, 0, len(filters))
+	states := map[string]bool{}
+	for k, v := range filters {
+		name := reflect.TypeOf(k).Name()
+		names = append(names, name)
+		states[name] = v
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s\t%v\n", name, states[name])
+	}
+
+	w.Flush()
+	return buf.String()
+}
+
+// writeResources writes monitored resources table
+func writeResources(w io.Writer, resources []config.Resource) {
+	fmt.Fprintln(w, "RESOURCE\tNAMESPACES\tIGNORED NAMESPACES\tEVENTS")
+	for _, r := range resources {
+		eventTypes := make([]string, 0, len(r.Events))
+		for _, e := range r.Events {
+			eventTypes = append(eventTypes, e.String())
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Name, joinOrNone(r.Namespaces.Include), joinOrNone(r.Namespaces.Ignore), joinOrNone(eventTypes))
+	}
+}
+
+// enabledNotifiers returns names of the enabled communication backends
+func enabledNotifiers(comm config.CommunicationsConfig) []string {
+	notifiers := []string{}
+	if comm.Slack.Enabled {
+		notifiers = append(notifiers, "Slack")
+	}
+	if comm.Mattermost.Enabled {
+		notifiers = append(notifiers, "Mattermost")
+	}
+	if comm.Discord.Enabled {
+		notifiers = append(notifiers, "Discord")
+	}
+	if comm.Teams.Enabled {
+		notifiers = append(notifiers, "Teams")
+	}
+	if comm.ElasticSearch.Enabled {
+		notifiers = append(notifiers, "ElasticSearch")
+	}
+	if comm.Webhook.Enabled {
+		notifiers = append(notifiers, "Webhook")
+	}
+	if comm.Jira.Enabled {
+		notifiers = append(notifiers, "Jira")
+	}
+	if len(notifiers) == 0 {
+		notifiers = append(notifiers, "none")
+	}
+	return notifiers
+}
+
+func joinOrNone(list []string) string {
+	if len(list) == 0 {
+		return "-"
+	}
+	return strings.Join(list, ",")
+}
+
 func makeCommandInfoList() string {
-	allowedVerbs := utils.GetStringInYamlFormat("allowed verbs:", utils.AllowedKubectlVerbMap)
-	allowedResources := utils.GetStringInYamlFormat("allowed resources:", utils.AllowedKubectlResourceMap)
+	kubectlMaps := utils.GetKubectlMaps()
+	allowedVerbs := utils.GetStringInYamlFormat("allowed verbs:", kubectlMaps.Verbs)
+	allowedResources := utils.GetStringInYamlFormat("allowed resources:", kubectlMaps.Resources)
 	return allowedVerbs + allowedResources
 }
 
@@ -417,15 +1252,89 @@ func makeFiltersList() string {
 	return buf.String()
 }
 
+// resolveFilterName matches the name with the registered filters case-insensitively
+// A unique prefix is accepted too, e.g imagetag for ImageTagChecker
+// Unknown names are returned as is so that the filter engine reports them
+func resolveFilterName(name string) (string, error) {
+	var matches []string
+	for f := range filterengine.DefaultFilterEngine.ShowFilters() {
+		filterName := reflect.TypeOf(f).Name()
+		if filterName == name || strings.EqualFold(filterName, name) {
+			return filterName, nil
+		}
+		if strings.HasPrefix(strings.ToLower(filterName), strings.ToLower(name)) {
+			matches = append(matches, filterName)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return name, nil
+	case 1:
+		return matches[0], nil
+	}
+	sort.Strings(matches)
+	return "", fmt.Errorf(filterNameAmbiguous, name, strings.Join(matches, ", "))
+}
+
+// describeFilter returns the full description, applicable kinds and event types and the state of the filter
+func describeFilter(name string) string {
+	for f, enabled := range filterengine.DefaultFilterEngine.ShowFilters() {
+		if reflect.TypeOf(f).Name() != name {
+			continue
+		}
+		kinds, types := "all", "all"
+		if kf, ok := f.(filterengine.KindFilter); ok {
+			k, t := kf.AppliesTo()
+			if len(k) != 0 {
+				kinds = strings.Join(k, ", ")
+			}
+			if len(t) != 0 {
+				names := make([]string, 0, len(t))
+				for _, eventType := range t {
+					names = append(names, eventType.String())
+				}
+				types = strings.Join(names, ", ")
+			}
+		}
+		return fmt.Sprintf("Filter: %s\nEnabled: %v\nKinds: %s\nEvent types: %s\nDescription: %s", name, enabled, kinds, types, f.Describe())
+	}
+	return fmt.Sprintf(filterNameInvalid, name, makeFiltersList())
+}
+
+// resetFilters applies the enabled state of settings.filters and returns the filters which changed
+func resetFilters(settings map[string]config.FilterSetting, clusterName string) string {
+	changed := filterengine.DefaultFilterEngine.ResetFilters(settings)
+	if len(changed) == 0 {
+		return fmt.Sprintf(filtersUnchangedMsg, clusterName)
+	}
+	var enabled, disabled []string
+	for name, state := range changed {
+		if state {
+			enabled = append(enabled, name)
+		} else {
+			disabled = append(disabled, name)
+		}
+	}
+	details := ""
+	if len(enabled) != 0 {
+		sort.Strings(enabled)
+		details += "\nEnabled: " + strings.Join(enabled, ", ")
+	}
+	if len(disabled) != 0 {
+		sort.Strings(disabled)
+		details += "\nDisabled: " + strings.Join(disabled, ", ")
+	}
+	return fmt.Sprintf(filtersResetMsg, clusterName, details)
+}
+
 func findBotKubeVersion() (versions string) {
-	args := []string{"-c", fmt.Sprintf("%s version --short=true | grep Server", kubectlBinary)}
-	runner := NewCommandRunner("sh", args)
-	// Returns "Server Version: xxxx"
-	k8sVersion, err := runner.Run()
+	runner := NewCommandRunner(kubectlBinary, []string{"version", "--short=true"})
+	out, err := runner.Run()
 	if err != nil {
 		log.Warn(fmt.Sprintf("Failed to get Kubernetes version: %s", err.Error()))
-		k8sVersion = "Server Version: Unknown\n"
 	}
+	// Returns "Server Version: xxxx"
+	k8sVersion := fmt.Sprintf("%s\n", parseServerVersion(out))
 
 	botkubeVersion := os.Getenv("BOTKUBE_VERSION")
 	if len(botkubeVersion) == 0 {
@@ -434,6 +1343,17 @@ func findBotKubeVersion() (versions string) {
 	return fmt.Sprintf("K8s %sBotKube version: %s", k8sVersion, botkubeVersion)
 }
 
+// parseServerVersion returns the server version line from kubectl version output
+func parseServerVersion(out string) string {
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Server Version:") {
+			return line
+		}
+	}
+	return "Server Version: Unknown"
+}
+
 func runVersionCommand(args []string, clusterName string) string {
 	checkFlag := false
 	for _, arg := range args {
@@ -453,24 +1373,118 @@ func runVersionCommand(args []string, clusterName string) string {
 			continue
 		}
 	}
-	return findBotKubeVersion()
+	return findBotKubeVersion() + "\n" + instanceInfo(clusterName)
 }
 
-func showControllerConfig() (configYaml string, err error) {
-	c, err := config.New()
+// instanceInfo returns details to identify the BotKube instance and check its health
+func instanceInfo(clusterName string) string {
+	return fmt.Sprintf("Cluster: %s\nPod: %s\nUptime: %s\nRestarts: %s\nEnabled filters: %d",
+		clusterName, podName(), formatUptime(time.Since(startTime)), podRestartCount(), enabledFiltersCount())
+}
+
+// podRestartCount returns total restart count of the containers in BotKube pod
+// POD_NAMESPACE env must be set by the deployment
+func podRestartCount() string {
+	namespace := os.Getenv("POD_NAMESPACE")
+	if len(namespace) == 0 || utils.DynamicKubeClient == nil {
+		return "Unknown"
+	}
+	obj, err := utils.DynamicKubeClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "pods"}).Namespace(namespace).Get(context.Background(), podName(), metaV1.GetOptions{})
 	if err != nil {
-		log.Fatal(fmt.Sprintf("Error in loading configuration. Error:%s", err.Error()))
+		log.Warnf("Failed to get BotKube pod: %s", err.Error())
+		return "Unknown"
 	}
+	var pod coreV1.Pod
+	if err := utils.TransformIntoTypedObject(obj, &pod); err != nil {
+		log.Errorf("Unable to transform object type: %v, into type: %v", reflect.TypeOf(obj), reflect.TypeOf(pod))
+		return "Unknown"
+	}
+	var restarts int32
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts += status.RestartCount
+	}
+	return fmt.Sprintf("%d", restarts)
+}
 
-	// hide sensitive info
-	c.Communications.Slack.Token = ""
-	c.Communications.ElasticSearch.Password = ""
+// enabledFiltersCount returns number of enabled filters
+func enabledFiltersCount() int {
+	count := 0
+	for _, enabled := range filterengine.DefaultFilterEngine.ShowFilters() {
+		if enabled {
+			count++
+		}
+	}
+	return count
+}
+
+// podName returns POD_NAME env set by the deployment, falls back to the hostname
+func podName() string {
+	if name := os.Getenv("POD_NAME"); len(name) != 0 {
+		return name
+	}
+	name, err := os.Hostname()
+	if err != nil {
+		return "Unknown"
+	}
+	return name
+}
+
+// formatUptime returns duration in short form e.g 3h12m
+func formatUptime(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return strings.TrimSuffix(d.Truncate(time.Minute).String(), "0s")
+}
+
+// invalidSectionError is returned if requested config section doesn't exist
+type invalidSectionError struct {
+	section string
+	valid   []string
+}
+
+func (e invalidSectionError) Error() string {
+	return fmt.Sprintf("Invalid config section '%s'. Please use one of the following sections: %s", e.section, strings.Join(e.valid, ", "))
+}
 
-	b, err := yaml.Marshal(c)
+// showControllerConfig returns config in YAML format or a summary, with secrets removed
+// If section is set, only the matching top-level key is returned
+func showControllerConfig(summary bool, section string) (configYaml string, err error) {
+	c, err := loadedConfig()
+	if err != nil {
+		return configYaml, fmt.Errorf("Error in loading configuration. Error:%s", err.Error())
+	}
+
+	// hide sensitive info
+	if summary {
+		return makeConfigSummary(c.Redacted()), nil
+	}
+	b, err := yaml.Marshal(c.Redacted())
 	if err != nil {
 		return configYaml, err
 	}
+	if len(section) != 0 {
+		return configSection(b, section)
+	}
 	configYaml = string(b)
 
 	return configYaml, nil
 }
+
+// configSection returns YAML of the top-level key of config YAML
+func configSection(configYaml []byte, section string) (string, error) {
+	var sections yaml.MapSlice
+	if err := yaml.Unmarshal(configYaml, &sections); err != nil {
+		return "", err
+	}
+	var valid []string
+	for _, item := range sections {
+		key := fmt.Sprintf("%v", item.Key)
+		if key == section {
+			b, err := yaml.Marshal(yaml.MapSlice{item})
+			return string(b), err
+		}
+		valid = append(valid, key)
+	}
+	return "", invalidSectionError{section: section, valid: valid}
+}
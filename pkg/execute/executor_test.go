@@ -0,0 +1,633 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	fakeDiscovery "k8s.io/client-go/discovery/fake"
+	k8sTesting "k8s.io/client-go/testing"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/filterengine"
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/metrics"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+func TestFormatUptime(t *testing.T) {
+	tests := map[string]struct {
+		duration time.Duration
+		expected string
+	}{
+		`seconds`:               {45*time.Second + 300*time.Millisecond, "45s"},
+		`minutes`:               {2*time.Minute + 10*time.Second, "2m"},
+		`hours and minutes`:     {3*time.Hour + 12*time.Minute + 59*time.Second, "3h12m"},
+		`hours without minutes`: {time.Hour, "1h0m"},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := formatUptime(test.duration); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEnabledNotifiers(t *testing.T) {
+	tests := map[string]struct {
+		comm     config.CommunicationsConfig
+		expected []string
+	}{
+		`no notifier enabled`: {config.CommunicationsConfig{}, []string{"none"}},
+		`slack enabled`:       {config.CommunicationsConfig{Slack: config.Slack{Enabled: true}}, []string{"Slack"}},
+		`multiple enabled`: {
+			config.CommunicationsConfig{Slack: config.Slack{Enabled: true}, Webhook: config.Webhook{Enabled: true}, ElasticSearch: config.ElasticSearch{Enabled: true}},
+			[]string{"Slack", "ElasticSearch", "Webhook"},
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := enabledNotifiers(test.comm); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCommandStatus(t *testing.T) {
+	tests := map[string]struct {
+		platform config.BotPlatform
+		out      string
+		expected string
+	}{
+		`empty response`:             {config.SlackBot, "", metrics.StatusIgnored},
+		`unsupported command`:        {config.SlackBot, printDefaultMsg(config.SlackBot), metrics.StatusUnsupported},
+		`teams unsupported`:          {config.TeamsBot, printDefaultMsg(config.TeamsBot), metrics.StatusUnsupported},
+		`discord unsupported`:        {config.DiscordBot, printDefaultMsg(config.DiscordBot), metrics.StatusUnsupported},
+		`other platform unsupported`: {config.DiscordBot, printDefaultMsg(config.SlackBot), metrics.StatusSuccess},
+		`successful execution`:       {config.SlackBot, "Cluster: test\nNAME READY", metrics.StatusSuccess},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := commandStatus(test.platform, test.out); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestHelpHint(t *testing.T) {
+	tests := map[string]struct {
+		platform    config.BotPlatform
+		unsupported string
+		incomplete  string
+	}{
+		`slack`: {config.SlackBot,
+			"Command not supported. Please run /botkubehelp to see supported commands.",
+			"You missed to pass options for the command. Please run /botkubehelp to see command options."},
+		`mattermost`: {config.MattermostBot,
+			"Command not supported. Please run @BotKube commands list or visit botkube.io/usage to see supported commands.",
+			"You missed to pass options for the command. Please run @BotKube commands list or visit botkube.io/usage to see command options."},
+		`discord`: {config.DiscordBot,
+			"Command not supported. Please run @BotKube commands list or visit botkube.io/usage to see supported commands.",
+			"You missed to pass options for the command. Please run @BotKube commands list or visit botkube.io/usage to see command options."},
+		`teams`: {config.TeamsBot,
+			"Command not supported. Please visit botkube.io/usage to see supported commands.",
+			"You missed to pass options for the command. Please visit botkube.io/usage to see command options."},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			e := NewDefaultExecutor("foo", true, false, "", "test-cluster", test.platform, "", "", "", true, nil)
+			if actual := e.Execute(); actual != test.unsupported {
+				t.Errorf("expected: %+v != actual: %+v\n", test.unsupported, actual)
+			}
+			e = NewDefaultExecutor("filters", true, false, "", "test-cluster", test.platform, "", "", "", true, nil)
+			if actual := e.Execute(); actual != test.incomplete {
+				t.Errorf("expected: %+v != actual: %+v\n", test.incomplete, actual)
+			}
+		})
+	}
+}
+
+func TestExecuteWithoutArgs(t *testing.T) {
+	defer utils.SetKubectlMaps(utils.GetKubectlMaps())
+	utils.SetKubectlMaps(&utils.KubectlMaps{Verbs: map[string]bool{"get": true}})
+	tests := map[string]struct {
+		msg           string
+		isAuthChannel bool
+		expected      string
+	}{
+		`empty message in auth channel`:            {"", true, printDefaultMsg(config.SlackBot)},
+		`empty message in other channel`:           {"", false, ""},
+		`whitespace only message in auth channel`:  {" \t\n ", true, printDefaultMsg(config.SlackBot)},
+		`whitespace only message in other channel`: {"   ", false, ""},
+		`verb without resource in auth channel`:    {"get", true, printDefaultMsg(config.SlackBot)},
+		`verb without resource in other channel`:   {"get ", false, ""},
+		`commands without option in auth channel`:  {"commands", true, IncompleteCmdMsg(config.SlackBot)},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			e := NewDefaultExecutor(test.msg, true, false, "", "test-cluster", config.SlackBot, "", "", "", test.isAuthChannel, nil)
+			if actual := e.Execute(); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestExecuteWithCommandPrefix(t *testing.T) {
+	defer InitCommandPrefix("")
+	InitCommandPrefix("!bk")
+
+	tests := map[string]struct {
+		msg           string
+		isAuthChannel bool
+		expected      string
+	}{
+		`prefixed command`:             {"!bk debug loglevel", true, fmt.Sprintf(logLevelMsg, log.GetLevel(), "test-cluster")},
+		`prefix in other case`:         {"!BK debug loglevel", true, fmt.Sprintf(logLevelMsg, log.GetLevel(), "test-cluster")},
+		`unprefixed command`:           {"debug loglevel", true, ""},
+		`prefix without space`:         {"!bkdebug loglevel", true, ""},
+		`prefix only in auth channel`:  {"!bk", true, printDefaultMsg(config.SlackBot)},
+		`prefix only in other channel`: {" !bk ", false, ""},
+		`unprefixed empty message`:     {"", true, ""},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			e := NewDefaultExecutor(test.msg, true, false, "", "test-cluster", config.SlackBot, "", "", "", test.isAuthChannel, nil)
+			if actual := e.Execute(); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseServerVersion(t *testing.T) {
+	tests := map[string]struct {
+		out      string
+		expected string
+	}{
+		`client and server version`: {"Client Version: v1.19.2\nServer Version: v1.18.8\n", "Server Version: v1.18.8"},
+		`server version first`:      {"Server Version: v1.18.8\nClient Version: v1.19.2", "Server Version: v1.18.8"},
+		`with warnings`:             {"WARNING: version difference\nClient Version: v1.22.0\n  Server Version: v1.18.8+k3s1\n", "Server Version: v1.18.8+k3s1"},
+		`server unreachable`:        {"Client Version: v1.19.2\nThe connection to the server was refused", "Server Version: Unknown"},
+		`empty output`:              {"", "Server Version: Unknown"},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := parseServerVersion(test.out); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMakeConfigSummary(t *testing.T) {
+	c := config.Config{
+		Resources: []config.Resource{
+			{Name: "v1/pods", Namespaces: config.Namespaces{Include: []string{"all"}}, Events: []config.EventType{config.CreateEvent, config.DeleteEvent}},
+		},
+		Communications: config.CommunicationsConfig{Slack: config.Slack{Enabled: true, Token: "xoxb-secret"}},
+		Settings:       config.Settings{ClusterName: "test-cluster"},
+	}
+	summary := makeConfigSummary(c.Redacted())
+	for _, expected := range []string{"test-cluster", "Slack", "v1/pods", "create,delete", "FILTER"} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("expected %q in summary:\n%s", expected, summary)
+		}
+	}
+	if strings.Contains(summary, "xoxb-secret") {
+		t.Errorf("summary should not contain secrets:\n%s", summary)
+	}
+}
+
+func TestConfigSection(t *testing.T) {
+	configYaml := []byte("resources:\n- name: v1/pods\nrecommendations: true\nsettings:\n  clustername: test\n")
+	tests := map[string]struct {
+		section     string
+		expected    string
+		expectedErr string
+	}{
+		`resources section`: {"resources", "resources:\n- name: v1/pods\n", ""},
+		`settings section`:  {"settings", "settings:\n  clustername: test\n", ""},
+		`unknown section`:   {"secrets", "", "Invalid config section 'secrets'. Please use one of the following sections: resources, recommendations, settings"},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			actual, err := configSection(configYaml, test.section)
+			if err != nil && err.Error() != test.expectedErr || err == nil && len(test.expectedErr) != 0 {
+				t.Errorf("expected error: %+v != actual: %+v\n", test.expectedErr, err)
+			}
+			if actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseEventsQuery(t *testing.T) {
+	tests := map[string]struct {
+		args        []string
+		expected    events.Query
+		clusterName string
+		err         bool
+	}{
+		`defaults`: {
+			args:     []string{},
+			expected: events.Query{Limit: defaultEventsCount},
+		},
+		`all filters`: {
+			args:     []string{"--level", "error", "--namespace=prod", "--kind", "Pod", "--count", "5"},
+			expected: events.Query{Kind: "Pod", Namespace: "prod", Level: "error", Limit: 5},
+		},
+		`count is capped`: {
+			args:     []string{"--count=1000", "-n", "prod"},
+			expected: events.Query{Namespace: "prod", Limit: maxEventsCount},
+		},
+		`cluster name`: {
+			args:        []string{"--cluster-name", "'dev'"},
+			expected:    events.Query{Limit: defaultEventsCount},
+			clusterName: "dev",
+		},
+		`unknown flag`: {
+			args: []string{"--foo", "bar"},
+			err:  true,
+		},
+		`missing value`: {
+			args: []string{"--level"},
+			err:  true,
+		},
+		`invalid count`: {
+			args: []string{"--count", "-1"},
+			err:  true,
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			q, clusterName, err := parseEventsQuery(test.args)
+			if test.err {
+				if err == nil {
+					t.Errorf("expected error for args %v", test.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(q, test.expected) || clusterName != test.clusterName {
+				t.Errorf("expected: %+v, %s != actual: %+v, %s\n", test.expected, test.clusterName, q, clusterName)
+			}
+		})
+	}
+}
+
+func TestMakeEventsList(t *testing.T) {
+	list := []events.Event{
+		{
+			Kind:      "Pod",
+			Name:      "nginx",
+			Namespace: "prod",
+			Type:      config.ErrorEvent,
+			Level:     config.Error,
+			Reason:    "BackOff",
+			TimeStamp: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{
+			Kind:      "Node",
+			Name:      "node-1",
+			Type:      config.CreateEvent,
+			Level:     config.Info,
+			TimeStamp: time.Date(2021, 1, 2, 3, 0, 0, 0, time.UTC),
+		},
+	}
+	expected := "TIME                 LEVEL TYPE   KIND NAMESPACE NAME   REASON\n" +
+		"2021-01-02T03:04:05Z error error  Pod  prod      nginx  BackOff\n" +
+		"2021-01-02T03:00:00Z info  create Node -         node-1 -\n"
+	if actual := makeEventsList(list); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
+
+func TestRunDebugCommand(t *testing.T) {
+	defer func(level string) { _ = log.SetLevel(level) }(log.GetLevel())
+
+	e := &DefaultExecutor{ClusterName: "dev"}
+	tests := map[string]struct {
+		args          []string
+		isAuthChannel bool
+		expected      string
+	}{
+		`not an auth channel`:   {[]string{"debug", "loglevel", "debug"}, false, ""},
+		`missing option`:        {[]string{"debug"}, true, IncompleteCmdMsg(config.SlackBot)},
+		`set level`:             {[]string{"debug", "loglevel", "DEBUG"}, true, "Done. Log level changed to 'debug' on cluster 'dev'."},
+		`show level`:            {[]string{"debug", "loglevel"}, true, "Log level is 'debug' on cluster 'dev'."},
+		`invalid level`:         {[]string{"debug", "loglevel", "panic"}, true, "Invalid log level 'panic'. Please pass one of debug, info, warn or error."},
+		`other cluster`:         {[]string{"debug", "loglevel", "warn", "--cluster-name", "prod"}, true, ""},
+		`matching cluster name`: {[]string{"debug", "loglevel", "--cluster-name=dev", "warn"}, true, "Done. Log level changed to 'warning' on cluster 'dev'."},
+	}
+	// Run in order since the cases depend on the previously set level
+	for _, name := range []string{`not an auth channel`, `missing option`, `set level`, `show level`, `invalid level`, `other cluster`, `matching cluster name`} {
+		test := tests[name]
+		t.Run(name, func(t *testing.T) {
+			if actual := e.runDebugCommand(test.args, test.isAuthChannel); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+	if actual := log.GetLevel(); actual != "warning" {
+		t.Errorf("expected: %+v != actual: %+v\n", "warning", actual)
+	}
+}
+
+func TestMakeValidationReport(t *testing.T) {
+	tests := map[string]struct {
+		issues   []config.ValidationIssue
+		expected string
+	}{
+		`valid config`: {nil, "Configuration of cluster 'dev' is valid."},
+		`issues`: {
+			[]config.ValidationIssue{{Fatal: true, Message: "communications.slack.token is required"}, {Message: "resources.pods is not a known resource kind"}},
+			"Validation report of cluster 'dev'\n\nERROR: communications.slack.token is required\nWARNING: resources.pods is not a known resource kind",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := makeValidationReport("dev", test.issues); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+type podFilter struct{}
+
+func (f podFilter) Run(object interface{}, event *events.Event) {}
+func (f podFilter) Describe() string                            { return "Checks pods." }
+func (f podFilter) AppliesTo() ([]string, []config.EventType) {
+	return []string{"Pod"}, []config.EventType{config.CreateEvent, config.UpdateEvent}
+}
+
+type anyFilter struct{}
+
+func (f anyFilter) Run(object interface{}, event *events.Event) {}
+func (f anyFilter) Describe() string                            { return "Checks everything." }
+
+func TestDescribeFilter(t *testing.T) {
+	defer func(engine filterengine.FilterEngine) { filterengine.DefaultFilterEngine = engine }(filterengine.DefaultFilterEngine)
+	filterengine.DefaultFilterEngine = filterengine.NewDefaultFilter()
+	filterengine.DefaultFilterEngine.Register(podFilter{})
+	filterengine.DefaultFilterEngine.Register(anyFilter{})
+	filterengine.DefaultFilterEngine.SetFilter("anyFilter", false)
+
+	tests := map[string]struct {
+		name     string
+		expected string
+	}{
+		`kind filter`: {"podFilter", "Filter: podFilter\nEnabled: true\nKinds: Pod\nEvent types: create, update\nDescription: Checks pods."},
+		`any kind`:    {"anyFilter", "Filter: anyFilter\nEnabled: false\nKinds: all\nEvent types: all\nDescription: Checks everything."},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := describeFilter(test.name); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+	if actual := describeFilter("unknown"); !strings.HasPrefix(actual, "Filter 'unknown' not found.") {
+		t.Errorf("expected invalid filter message, got: %s", actual)
+	}
+}
+
+func TestRunFilterCommandInvalidName(t *testing.T) {
+	defer func(engine filterengine.FilterEngine) { filterengine.DefaultFilterEngine = engine }(filterengine.DefaultFilterEngine)
+	filterengine.DefaultFilterEngine = filterengine.NewDefaultFilter()
+	filterengine.DefaultFilterEngine.Register(podFilter{})
+	filterengine.DefaultFilterEngine.Register(anyFilter{})
+
+	e := &DefaultExecutor{ClusterName: "test"}
+	expected := "No such filter 'foo'. Valid filters: anyFilter, podFilter"
+	for _, action := range []FiltersAction{FilterEnable, FilterDisable} {
+		if actual := e.runFilterCommand([]string{"filters", action.String(), "foo"}, "test", true); actual != expected {
+			t.Errorf("%s: expected: %+v != actual: %+v\n", action, expected, actual)
+		}
+	}
+}
+
+type podLabelFilter struct{}
+
+func (f podLabelFilter) Run(object interface{}, event *events.Event) {}
+func (f podLabelFilter) Describe() string                            { return "Checks pod labels." }
+
+func TestResolveFilterName(t *testing.T) {
+	defer func(engine filterengine.FilterEngine) { filterengine.DefaultFilterEngine = engine }(filterengine.DefaultFilterEngine)
+	filterengine.DefaultFilterEngine = filterengine.NewDefaultFilter()
+	filterengine.DefaultFilterEngine.Register(podFilter{})
+	filterengine.DefaultFilterEngine.Register(podLabelFilter{})
+	filterengine.DefaultFilterEngine.Register(anyFilter{})
+
+	tests := map[string]struct {
+		name        string
+		expected    string
+		expectedErr string
+	}{
+		`exact`:             {name: "podFilter", expected: "podFilter"},
+		`case-insensitive`:  {name: "ANYFILTER", expected: "anyFilter"},
+		`exact over prefix`: {name: "PODFILTER", expected: "podFilter"},
+		`unique prefix`:     {name: "podl", expected: "podLabelFilter"},
+		`ambiguous prefix`:  {name: "Pod", expectedErr: "Filter name 'Pod' matches more than one filter: podFilter, podLabelFilter"},
+		`unknown`:           {name: "foo", expected: "foo"},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			actual, err := resolveFilterName(test.name)
+			if err != nil {
+				if err.Error() != test.expectedErr {
+					t.Errorf("expected: %+v != actual: %+v\n", test.expectedErr, err)
+				}
+				return
+			}
+			if actual != test.expected || len(test.expectedErr) != 0 {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRunFilterCommandPrefix(t *testing.T) {
+	defer func(engine filterengine.FilterEngine) { filterengine.DefaultFilterEngine = engine }(filterengine.DefaultFilterEngine)
+	filterengine.DefaultFilterEngine = filterengine.NewDefaultFilter()
+	filterengine.DefaultFilterEngine.Register(podFilter{})
+
+	e := &DefaultExecutor{ClusterName: "test"}
+	actual := e.runFilterCommand([]string{"filters", "disable", "podf"}, "test", true)
+	expected := "Done. I won't run 'podFilter' filter on 'test' cluster."
+	if actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+	if filterengine.DefaultFilterEngine.ShowFilters()[podFilter{}] {
+		t.Errorf("expected podFilter to be disabled")
+	}
+}
+
+func TestResetFilters(t *testing.T) {
+	defer func(engine filterengine.FilterEngine) { filterengine.DefaultFilterEngine = engine }(filterengine.DefaultFilterEngine)
+	filterengine.DefaultFilterEngine = filterengine.NewDefaultFilter()
+	filterengine.DefaultFilterEngine.Register(podFilter{})
+	filterengine.DefaultFilterEngine.Register(podLabelFilter{})
+	filterengine.DefaultFilterEngine.Register(anyFilter{})
+	disabled := false
+	settings := map[string]config.FilterSetting{"podLabelFilter": {Enabled: &disabled}}
+
+	e := &DefaultExecutor{ClusterName: "test"}
+	e.runFilterCommand([]string{"filters", "disable", "podFilter"}, "test", true)
+	expected := "Done. Filters are reset to the config on 'test' cluster.\nEnabled: podFilter\nDisabled: podLabelFilter"
+	if actual := resetFilters(settings, "test"); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+	expected = "Filters already match the config on 'test' cluster."
+	if actual := resetFilters(settings, "test"); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
+
+func TestMakeMaintenanceStatus(t *testing.T) {
+	now := time.Date(2021, 3, 1, 23, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		windows  []config.MaintenanceWindow
+		expected string
+	}{
+		`no windows`: {
+			expected: "No maintenance window is active on cluster 'test'.",
+		},
+		`window not active`: {
+			windows:  []config.MaintenanceWindow{{Start: "02:00", End: "04:00"}},
+			expected: "No maintenance window is active on cluster 'test'.",
+		},
+		`named window active`: {
+			windows:  []config.MaintenanceWindow{{Start: "02:00", End: "04:00"}, {Name: "upgrade", Start: "22:00", End: "02:00"}},
+			expected: "Maintenance window 'upgrade' is active on cluster 'test' until 2021-03-02T02:00:00Z. Event notifications are not sent.",
+		},
+		`unnamed window active`: {
+			windows:  []config.MaintenanceWindow{{Start: "2021-03-01T20:00:00Z", End: "2021-03-02T00:00:00Z"}},
+			expected: "Maintenance window is active on cluster 'test' until 2021-03-02T00:00:00Z. Event notifications are not sent.",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := makeMaintenanceStatus("test", test.windows, now); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+// TestReloadWhileExecuting reloads the settings while commands are executed, run with -race to catch unsynchronized access
+func TestReloadWhileExecuting(t *testing.T) {
+	defer func(client discovery.DiscoveryInterface) {
+		utils.DiscoveryClient = client
+	}(utils.DiscoveryClient)
+	defer utils.SetKubectlMaps(utils.GetKubectlMaps())
+	defer InitCommandPrefix("")
+	defer InitImpersonation(false)
+	defer InitNodeOps(false, false)
+	defer InitInstance("", "")
+
+	client := &fakeDiscovery.FakeDiscovery{Fake: &k8sTesting.Fake{}}
+	client.Resources = []*metaV1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metaV1.APIResource{{Name: "pods", Kind: "Pod", ShortNames: []string{"po"}}},
+	}}
+	utils.DiscoveryClient = client
+	conf := &config.Config{}
+	conf.Settings.Kubectl.Enabled = true
+	conf.Settings.Kubectl.Commands.Verbs = []string{"get"}
+	conf.Settings.Kubectl.Commands.Resources = []string{"pods"}
+	utils.InitResourceMap(conf)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			utils.InitResourceMap(conf)
+			InitCommandPrefix("")
+			InitImpersonation(i%2 == 0)
+			InitNodeOps(i%2 == 0, false)
+			InitInstance("", "")
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		for _, msg := range []string{"get po", "get Pod"} {
+			e := NewDefaultExecutor(msg, true, false, "default", "test-cluster", config.SlackBot, "", "", "", true, nil)
+			if out := e.Execute(); out == printDefaultMsg(config.SlackBot) {
+				t.Fatalf("command %q rejected during reload", msg)
+			}
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestExecuteWithLoadedConfig(t *testing.T) {
+	defer InitConfig(nil)
+	e := NewDefaultExecutor("maintenance status", true, false, "", "dev", config.SlackBot, "", "", "", true, nil)
+
+	// Commands use the config set by InitConfig instead of the files which reload may have rejected
+	InitConfig(nil)
+	if expected, actual := "Error in getting configuration!", e.Execute(); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+	InitConfig(&config.Config{})
+	if expected, actual := fmt.Sprintf(maintenanceInactiveMsg, "dev"), e.Execute(); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+	InitConfig(&config.Config{Settings: config.Settings{MaintenanceWindows: []config.MaintenanceWindow{{Start: "00:00", End: "00:00"}}}})
+	if actual := e.Execute(); !strings.Contains(actual, "is active on cluster 'dev'") {
+		t.Errorf("expected active maintenance window, got: %s", actual)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"sync"
+	"time"
+)
+
+// explainOutputs caches explain command output, it is replaced by InitKubectl
+var explainOutputs = newExplainCache(0)
+
+// explainCache stores explain output for ttl, nothing is cached if ttl is 0
+type explainCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]explainEntry
+}
+
+type explainEntry struct {
+	out     string
+	expires time.Time
+}
+
+func newExplainCache(ttl time.Duration) *explainCache {
+	return &explainCache{ttl: ttl, entries: map[string]explainEntry{}}
+}
+
+// get returns the cached output for the key if it hasn't expired
+func (c *explainCache) get(key string, now time.Time) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || !now.Before(entry.expires) {
+		return "", false
+	}
+	return entry.out, true
+}
+
+// set caches the output for the key, expired entries are removed
+func (c *explainCache) set(key, out string, now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = explainEntry{out: out, expires: now.Add(c.ttl)}
+}
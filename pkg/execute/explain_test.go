@@ -0,0 +1,81 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExplainCache(t *testing.T) {
+	now := time.Now()
+	cache := newExplainCache(time.Minute)
+	if _, ok := cache.get("explain pods", now); ok {
+		t.Error("expected miss for empty cache")
+	}
+	cache.set("explain pods", "KIND: Pod", now)
+
+	tests := map[string]struct {
+		key      string
+		at       time.Time
+		expected bool
+	}{
+		`hit`:          {"explain pods", now.Add(30 * time.Second), true},
+		`other key`:    {"explain nodes", now, false},
+		`expired`:      {"explain pods", now.Add(time.Minute), false},
+		`after expiry`: {"explain pods", now.Add(time.Hour), false},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			out, ok := cache.get(test.key, test.at)
+			if ok != test.expected || (ok && out != "KIND: Pod") {
+				t.Errorf("expected: %+v != actual: %+v %s\n", test.expected, ok, out)
+			}
+		})
+	}
+}
+
+func TestExplainCacheDisabled(t *testing.T) {
+	now := time.Now()
+	cache := newExplainCache(0)
+	cache.set("explain pods", "KIND: Pod", now)
+	if _, ok := cache.get("explain pods", now); ok {
+		t.Error("expected nothing to be cached if ttl is 0")
+	}
+}
+
+func TestRunKubectlCommandExplainCache(t *testing.T) {
+	defer func(cache *explainCache) { explainOutputs = cache }(explainOutputs)
+	explainOutputs = newExplainCache(time.Hour)
+	KubectlResponse["explain pod.spec.containers"] = "KIND: Pod"
+	defer delete(KubectlResponse, "explain pod.spec.containers")
+
+	args := []string{"explain", "pod.spec.containers"}
+	expected := "Cluster: dev\nKIND: Pod"
+	if actual := runKubectlCommand(args, "dev", "", true, nil); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+	// Cached output is returned even if kubectl response changes
+	KubectlResponse["explain pod.spec.containers"] = "changed"
+	if actual := runKubectlCommand(args, "dev", "", true, nil); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
@@ -22,6 +22,7 @@
 package execute
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
@@ -31,11 +32,16 @@ const K8sVersion = "v1.15.3"
 
 // KubectlResponse map for fake Kubectl responses
 var KubectlResponse = map[string]string{
-	"-n default get pods": "NAME                           READY   STATUS    RESTARTS   AGE\n" +
+	"-n default get pods --chunk-size=500": "NAME                           READY   STATUS    RESTARTS   AGE\n" +
 		"nginx-xxxxxxx-yyyyyyy          1/1     Running   1          1d",
-	"-c " + kubectlBinary + " version --short=true | grep Server": fmt.Sprintf("Server Version: %s\n", K8sVersion),
+	"-n default logs nginx-xxxxxxx-yyyyyyy --since=10m --tail=100": "10.1.0.12 - - [14/Oct/2020:10:01:12 +0000] \"GET / HTTP/1.1\" 200 612",
+	"-n default logs nginx-xxxxxxx-yyyyyyy --tail=20":              "10.1.0.12 - - [14/Oct/2020:10:01:13 +0000] \"GET / HTTP/1.1\" 200 612",
+	"version --short=true": fmt.Sprintf("Client Version: %s\nServer Version: %s\n", K8sVersion, K8sVersion),
 }
 
+// KubectlErrors map for the errors returned with fake Kubectl responses
+var KubectlErrors = map[string]error{}
+
 // FakeRunner mocks Run
 type FakeRunner struct {
 	command string
@@ -53,5 +59,28 @@ func NewCommandRunner(command string, args []string) CommandRunner {
 // Run executes bash command
 func (r FakeRunner) Run() (string, error) {
 	cmd := strings.Join(r.args, " ")
-	return KubectlResponse[cmd], nil
+	return KubectlResponse[cmd], KubectlErrors[cmd]
+}
+
+// FakeStreamRunner mocks Stream
+type FakeStreamRunner struct {
+	command string
+	args    []string
+}
+
+// NewStreamRunner returns new FakeStreamRunner
+func NewStreamRunner(command string, args []string) StreamRunner {
+	return FakeStreamRunner{
+		command: command,
+		args:    args,
+	}
+}
+
+// Stream calls handle for each line of the fake response
+func (r FakeStreamRunner) Stream(ctx context.Context, handle func(line string)) error {
+	cmd := strings.Join(r.args, " ")
+	for _, line := range strings.Split(KubectlResponse[cmd], "\n") {
+		handle(line)
+	}
+	return nil
 }
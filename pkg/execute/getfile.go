@@ -0,0 +1,160 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+var validGetFileCommand = map[string]bool{
+	"getfile": true,
+}
+
+const (
+	getFileUsageMsg         = "Usage: getfile <namespace>/<configmap> <key>"
+	configMapsNotAllowedMsg = "Sorry, the admin hasn't allowed configmaps resource on cluster '%s'."
+	configMapNotFoundMsg    = "ConfigMap '%s/%s' not found on cluster '%s'."
+	configMapKeyNotFoundMsg = "Key '%s' not found in ConfigMap '%s/%s'. Available keys: %s"
+	configMapBinaryKeyMsg   = "Key '%s' of ConfigMap '%s/%s' contains binary data which can't be shown as text."
+	configMapFileSentMsg    = "Sent '%s' from ConfigMap '%s/%s' on cluster '%s'."
+	configMapErrorMsg       = "Error in getting ConfigMap '%s/%s'!"
+)
+
+var configMapGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+// runGetFileCommand returns the value of the ConfigMap key, it is uploaded as a file if the platform supports it
+func (e *DefaultExecutor) runGetFileCommand(args []string) string {
+	var params []string
+	clusterName := ""
+	for i := 1; i < len(args); i++ {
+		switch {
+		case args[i] == ClusterFlag.String():
+			if i+1 < len(args) {
+				clusterName = trimQuotes(args[i+1])
+			}
+			i++
+		case strings.HasPrefix(args[i], ClusterFlag.String()+"="):
+			clusterName = trimQuotes(strings.SplitAfterN(args[i], ClusterFlag.String()+"=", 2)[1])
+		default:
+			params = append(params, args[i])
+		}
+	}
+	if len(clusterName) != 0 && clusterName != e.ClusterName {
+		return ""
+	}
+	if len(clusterName) == 0 && !e.IsAuthChannel {
+		return ""
+	}
+	if e.RestrictAccess && !e.IsAuthChannel {
+		return ""
+	}
+	if !e.AllowKubectl {
+		return fmt.Sprintf(kubectlDisabledMsg, e.ClusterName)
+	}
+	if !utils.GetKubectlMaps().Resources["configmaps"] {
+		return fmt.Sprintf(configMapsNotAllowedMsg, e.ClusterName)
+	}
+	if len(params) != 2 {
+		return getFileUsageMsg
+	}
+
+	namespace, name := e.DefaultNamespace, params[0]
+	if parts := strings.SplitN(params[0], "/", 2); len(parts) == 2 {
+		namespace, name = parts[0], parts[1]
+	}
+	if len(namespace) == 0 {
+		namespace = "default"
+	}
+	key := params[1]
+	if len(name) == 0 || len(key) == 0 {
+		return getFileUsageMsg
+	}
+
+	content, msg := e.configMapValue(namespace, name, key)
+	if len(msg) != 0 {
+		return msg
+	}
+	if e.Sender == nil || e.Sender.SendFile == nil {
+		return content
+	}
+	if err := e.Sender.SendFile(key, content); err != nil {
+		log.Errorf("Failed to upload %s from ConfigMap %s/%s. %s", key, namespace, name, err.Error())
+		return content
+	}
+	return fmt.Sprintf(configMapFileSentMsg, key, namespace, name, e.ClusterName)
+}
+
+// configMapValue returns the value of the key, or a message for the user if it can't be returned
+// Binary data is returned only if it can be uploaded as a file
+func (e *DefaultExecutor) configMapValue(namespace, name, key string) (string, string) {
+	if utils.DynamicKubeClient == nil {
+		log.Error("Error in executing getfile command: kube client is not initialized")
+		return "", fmt.Sprintf(configMapErrorMsg, namespace, name)
+	}
+	obj, err := utils.DynamicKubeClient.Resource(configMapGVR).Namespace(namespace).Get(context.Background(), name, metaV1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", fmt.Sprintf(configMapNotFoundMsg, namespace, name, e.ClusterName)
+	}
+	if err != nil {
+		log.Errorf("Error in executing getfile command: %s", err.Error())
+		return "", fmt.Sprintf(configMapErrorMsg, namespace, name)
+	}
+	var configMap coreV1.ConfigMap
+	if err := utils.TransformIntoTypedObject(obj, &configMap); err != nil {
+		log.Errorf("Unable to transform object type: %v, into type: %v", reflect.TypeOf(obj), reflect.TypeOf(configMap))
+		return "", fmt.Sprintf(configMapErrorMsg, namespace, name)
+	}
+
+	if value, ok := configMap.Data[key]; ok {
+		return value, ""
+	}
+	if value, ok := configMap.BinaryData[key]; ok {
+		if e.Sender == nil || e.Sender.SendFile == nil {
+			return "", fmt.Sprintf(configMapBinaryKeyMsg, key, namespace, name)
+		}
+		return string(value), ""
+	}
+
+	keys := []string{}
+	for k := range configMap.Data {
+		keys = append(keys, k)
+	}
+	for k := range configMap.BinaryData {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	available := strings.Join(keys, ", ")
+	if len(keys) == 0 {
+		available = "none"
+	}
+	return "", fmt.Sprintf(configMapKeyNotFoundMsg, key, namespace, name, available)
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"fmt"
+	"testing"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+func TestRunGetFileCommand(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := coreV1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	configMap := &coreV1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{Name: "nginx-conf", Namespace: "web"},
+		Data:       map[string]string{"nginx.conf": "worker_processes 1;\n", "mime.types": "types {}\n"},
+		BinaryData: map[string][]byte{"favicon.ico": {0x00, 0x01}},
+	}
+	defaultConf := &coreV1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{Name: "app", Namespace: "default"},
+		Data:       map[string]string{"app.yaml": "debug: true\n"},
+	}
+	defer func(client dynamic.Interface, maps *utils.KubectlMaps) {
+		utils.DynamicKubeClient = client
+		utils.SetKubectlMaps(maps)
+	}(utils.DynamicKubeClient, utils.GetKubectlMaps())
+	utils.DynamicKubeClient = fake.NewSimpleDynamicClient(scheme, configMap, defaultConf)
+	utils.SetKubectlMaps(&utils.KubectlMaps{Resources: map[string]bool{"configmaps": true}})
+
+	tests := map[string]struct {
+		command      string
+		withUpload   bool
+		expected     string
+		expectedFile string
+	}{
+		`text returned without upload`: {
+			command:  "getfile web/nginx-conf nginx.conf",
+			expected: "worker_processes 1;\n",
+		},
+		`default namespace`: {
+			command:  "getfile app app.yaml",
+			expected: "debug: true\n",
+		},
+		`file uploaded`: {
+			command:      "getfile web/nginx-conf nginx.conf",
+			withUpload:   true,
+			expected:     "Sent 'nginx.conf' from ConfigMap 'web/nginx-conf' on cluster 'test'.",
+			expectedFile: "nginx.conf:worker_processes 1;\n",
+		},
+		`binary data uploaded`: {
+			command:      "getfile web/nginx-conf favicon.ico",
+			withUpload:   true,
+			expected:     "Sent 'favicon.ico' from ConfigMap 'web/nginx-conf' on cluster 'test'.",
+			expectedFile: "favicon.ico:\x00\x01",
+		},
+		`binary data without upload`: {
+			command:  "getfile web/nginx-conf favicon.ico",
+			expected: "Key 'favicon.ico' of ConfigMap 'web/nginx-conf' contains binary data which can't be shown as text.",
+		},
+		`missing configmap`: {
+			command:  "getfile web/missing nginx.conf",
+			expected: "ConfigMap 'web/missing' not found on cluster 'test'.",
+		},
+		`missing key`: {
+			command:  "getfile web/nginx-conf default.conf",
+			expected: "Key 'default.conf' not found in ConfigMap 'web/nginx-conf'. Available keys: favicon.ico, mime.types, nginx.conf",
+		},
+		`missing key argument`: {
+			command:  "getfile web/nginx-conf",
+			expected: getFileUsageMsg,
+		},
+		`other cluster`: {
+			command: "getfile web/nginx-conf nginx.conf --cluster-name other",
+		},
+		`matching cluster`: {
+			command:  "getfile web/nginx-conf nginx.conf --cluster-name test",
+			expected: "worker_processes 1;\n",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			uploaded := ""
+			sender := &ChannelSender{Channel: "general", Send: func(msg string) error { return nil }}
+			if test.withUpload {
+				sender.SendFile = func(name, content string) error {
+					uploaded = fmt.Sprintf("%s:%s", name, content)
+					return nil
+				}
+			}
+			e := &DefaultExecutor{Message: test.command, AllowKubectl: true, ClusterName: "test", IsAuthChannel: true, Sender: sender}
+			if actual := e.Execute(); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+			if uploaded != test.expectedFile {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expectedFile, uploaded)
+			}
+		})
+	}
+}
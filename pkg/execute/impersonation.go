@@ -0,0 +1,78 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"strings"
+)
+
+const impersonationDisabledMsg = "Impersonation is disabled on cluster '%s', ignoring --as flags. The command ran as BotKube's service account."
+
+// impersonationFlags are the kubectl flags to run a command as another user or group
+var impersonationFlags = []string{"--as", "--as-group", "--as-uid"}
+
+// allowImpersonation passes impersonation flags to kubectl if true, they are removed otherwise
+var allowImpersonation bool
+
+// InitImpersonation sets whether kubectl commands can impersonate other users with --as flags
+func InitImpersonation(allow bool) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	allowImpersonation = allow
+}
+
+// stripImpersonationFlags removes impersonation flags with their values from args
+// It returns true if any flag is removed
+func stripImpersonationFlags(args []string) ([]string, bool) {
+	stripped := false
+	finalArgs := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			// Arguments of the command run with exec
+			finalArgs = append(finalArgs, args[i:]...)
+			break
+		}
+		isFlag, hasValue := impersonationFlag(arg)
+		if !isFlag {
+			finalArgs = append(finalArgs, arg)
+			continue
+		}
+		stripped = true
+		if !hasValue {
+			// Skip the value passed in next argument
+			i++
+		}
+	}
+	return finalArgs, stripped
+}
+
+// impersonationFlag checks if arg is an impersonation flag and if the value is passed with "="
+func impersonationFlag(arg string) (isFlag, hasValue bool) {
+	for _, flag := range impersonationFlags {
+		if arg == flag {
+			return true, false
+		}
+		if strings.HasPrefix(arg, flag+"=") {
+			return true, true
+		}
+	}
+	return false, false
+}
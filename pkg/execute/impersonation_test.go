@@ -0,0 +1,89 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStripImpersonationFlags(t *testing.T) {
+	tests := map[string]struct {
+		args             []string
+		expectedArgs     []string
+		expectedStripped bool
+	}{
+		`no flags`: {
+			args:         []string{"auth", "can-i", "get", "pods"},
+			expectedArgs: []string{"auth", "can-i", "get", "pods"},
+		},
+		`flags with values in next argument`: {
+			args:             []string{"auth", "can-i", "get", "pods", "--as", "jane", "--as-group", "dev"},
+			expectedArgs:     []string{"auth", "can-i", "get", "pods"},
+			expectedStripped: true,
+		},
+		`flags with values after equal sign`: {
+			args:             []string{"auth", "can-i", "--as=jane", "--as-uid=42", "get", "pods"},
+			expectedArgs:     []string{"auth", "can-i", "get", "pods"},
+			expectedStripped: true,
+		},
+		`flags after separator are kept`: {
+			args:         []string{"exec", "nginx", "--", "echo", "--as", "jane"},
+			expectedArgs: []string{"exec", "nginx", "--", "echo", "--as", "jane"},
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			args, stripped := stripImpersonationFlags(test.args)
+			if !reflect.DeepEqual(args, test.expectedArgs) || stripped != test.expectedStripped {
+				t.Errorf("expected: %+v %+v != actual: %+v %+v\n", test.expectedArgs, test.expectedStripped, args, stripped)
+			}
+		})
+	}
+}
+
+func TestRunKubectlCommandImpersonation(t *testing.T) {
+	defer func(allow bool) { allowImpersonation = allow }(allowImpersonation)
+	KubectlResponse["auth can-i get pods"] = "yes"
+	KubectlResponse["auth can-i get pods --as jane"] = "no"
+	defer func() {
+		delete(KubectlResponse, "auth can-i get pods")
+		delete(KubectlResponse, "auth can-i get pods --as jane")
+	}()
+
+	tests := map[string]struct {
+		allow    bool
+		expected string
+	}{
+		`impersonation allowed`:  {true, "Cluster: dev\nno"},
+		`impersonation disabled`: {false, "Cluster: dev\nImpersonation is disabled on cluster 'dev', ignoring --as flags. The command ran as BotKube's service account.\nyes"},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			allowImpersonation = test.allow
+			actual := runKubectlCommand([]string{"auth", "can-i", "get", "pods", "--as", "jane"}, "dev", "", true, nil)
+			if actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
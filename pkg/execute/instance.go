@@ -0,0 +1,61 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/infracloudio/botkube/pkg/config"
+)
+
+var (
+	// instanceName is matched with the address of the commands, e.g ping@readonly
+	instanceName string
+	// ignoreUnaddressed drops the commands without address
+	ignoreUnaddressed bool
+)
+
+// InitInstance sets the name commands can be addressed to and the handling of commands without it
+func InitInstance(name string, unaddressed config.UnaddressedPolicy) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	instanceName = strings.TrimSpace(name)
+	ignoreUnaddressed = unaddressed == config.UnaddressedIgnore
+}
+
+// trimInstanceAddress removes the instance name from the first word of the command, e.g ping@readonly
+// It returns false if the command is addressed to other instance, or it is unaddressed and such commands are ignored
+// Names are matched case-insensitively
+func trimInstanceAddress(command string) (string, bool) {
+	command = strings.TrimLeftFunc(command, unicode.IsSpace)
+	end := strings.IndexFunc(command, unicode.IsSpace)
+	if end < 0 {
+		end = len(command)
+	}
+	at := strings.LastIndex(command[:end], "@")
+	if at < 0 {
+		return command, !ignoreUnaddressed
+	}
+	if !strings.EqualFold(command[at+1:end], instanceName) || len(instanceName) == 0 {
+		return "", false
+	}
+	return command[:at] + command[end:], true
+}
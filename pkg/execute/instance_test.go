@@ -0,0 +1,87 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/log"
+)
+
+func TestExecuteAddressedCommands(t *testing.T) {
+	defer InitInstance("", "")
+	logLevel := fmt.Sprintf(logLevelMsg, log.GetLevel(), "test-cluster")
+
+	tests := map[string]struct {
+		name        string
+		unaddressed config.UnaddressedPolicy
+		msg         string
+		expected    string
+	}{
+		`addressed to instance`:                 {"readonly", "", "debug@readonly loglevel", logLevel},
+		`addressed in other case`:               {"readonly", "", "debug@ReadOnly loglevel", logLevel},
+		`addressed to instance ignoring others`: {"readonly", config.UnaddressedIgnore, "debug@readonly loglevel", logLevel},
+		`addressed to other instance`:           {"readonly", "", "debug@admin loglevel", ""},
+		`addressed without name configured`:     {"", "", "debug@readonly loglevel", ""},
+		`empty address`:                         {"readonly", "", "debug@ loglevel", ""},
+		`single word command`:                   {"readonly", "", "ping@admin", ""},
+		`unaddressed responded by default`:      {"readonly", "", "debug loglevel", logLevel},
+		`unaddressed with respond policy`:       {"readonly", config.UnaddressedRespond, "debug loglevel", logLevel},
+		`unaddressed with ignore policy`:        {"readonly", config.UnaddressedIgnore, "debug loglevel", ""},
+		`address only in first word`:            {"", "", "debug loglevel user@example.com", fmt.Sprintf(invalidLogLevelMsg, "user@example.com")},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			InitInstance(test.name, test.unaddressed)
+			e := NewDefaultExecutor(test.msg, true, false, "", "test-cluster", config.SlackBot, "", "", "", true, nil)
+			if actual := e.Execute(); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTrimInstanceAddress(t *testing.T) {
+	defer InitInstance("", "")
+	InitInstance("readonly", "")
+
+	tests := map[string]struct {
+		command  string
+		expected string
+		ok       bool
+	}{
+		`address removed`:       {"get@readonly pods -n default", "get pods -n default", true},
+		`leading space trimmed`: {"  ping@readonly", "ping", true},
+		`unaddressed unchanged`: {"get pods", "get pods", true},
+		`other instance`:        {"get@admin pods", "", false},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			actual, ok := trimInstanceAddress(test.command)
+			if actual != test.expected || ok != test.ok {
+				t.Errorf("expected: %+v, %v != actual: %+v, %v\n", test.expected, test.ok, actual, ok)
+			}
+		})
+	}
+}
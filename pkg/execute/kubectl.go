@@ -0,0 +1,412 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+const (
+	containerRequiredMsg = "Pod '%s' has multiple containers. Please pass one of them with -c or --container: %s"
+	invalidContainerMsg  = "Container '%s' doesn't exist in pod '%s'. Please pass one of them with -c or --container: %s"
+	outputFormatMsg      = "Output format '%s' is not allowed. Please use one of %s, or the default output without -o flag"
+	getAllUnscopedMsg    = "'get all' is not allowed across all namespaces. Please pass a namespace, e.g 'get all -n default', or get one resource type, e.g 'get pods -A'"
+
+	// getAllMaxLines is the number of get all output lines sent if settings.kubectl.guardGetAll is enabled
+	getAllMaxLines = 50
+)
+
+var (
+	// kubectlBinaries is a map of kubectl client version and binary path configured in settings.kubectl.binaries
+	kubectlBinaries = map[string]string{}
+
+	// defaultLogsTail is the number of lines passed with --tail flag to logs command if the flag is missing
+	defaultLogsTail = 100
+	// defaultChunkSize is the number of objects passed with --chunk-size flag to get command if the flag is missing
+	defaultChunkSize = 500
+
+	// kubectlCacheDir is the directory passed with --cache-dir flag to reuse API discovery across kubectl runs
+	// The default ~/.kube/cache is not writable in BotKube container
+	kubectlCacheDir = defaultKubectlCacheDir
+	// defaultKubectlCacheDir is used if settings.kubectl.cacheDir is not set
+	defaultKubectlCacheDir = filepath.Join(os.TempDir(), "botkube-kubectl-cache")
+
+	// containerRequiredRegex matches kubectl error when container name is not passed for a multi-container pod
+	containerRequiredRegex = regexp.MustCompile(`a container name must be specified for pod (\S+), choose one of: \[([^\]]*)\](?: or one of the init containers: \[([^\]]*)\])?`)
+	// invalidContainerRegex matches kubectl error when the container doesn't exist in the pod
+	invalidContainerRegex = regexp.MustCompile(`container "?([^"\s]+)"? is not valid for pod "?([^"\s]+)"?`)
+
+	// podContainers returns names of the containers and init containers of a pod
+	podContainers = getPodContainers
+
+	// allowedOutputFormats contains the values allowed with -o flag, all formats are allowed if empty
+	allowedOutputFormats []string
+
+	// guardGetAll rejects get all command without a namespace and truncates its output
+	guardGetAll bool
+
+	// kubectlClusters is a map of cluster name to the kubeconfig context from settings.kubectl.clusters
+	kubectlClusters = map[string]config.KubectlCluster{}
+)
+
+// InitKubectl sets kubectl options from config and verifies that the binaries exist
+func InitKubectl(c config.Kubectl) error {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	if len(c.BinaryPath) != 0 {
+		kubectlBinary = c.BinaryPath
+	}
+	if c.DefaultLogsTail != 0 {
+		defaultLogsTail = c.DefaultLogsTail
+	}
+	if c.DefaultChunkSize != 0 {
+		defaultChunkSize = c.DefaultChunkSize
+	}
+	kubectlCacheDir = defaultKubectlCacheDir
+	if len(c.CacheDir) != 0 {
+		kubectlCacheDir = c.CacheDir
+	}
+	initWatch(c.Watch)
+	allowedOutputFormats = c.AllowedOutputFormats
+	clusters := map[string]config.KubectlCluster{}
+	for _, cluster := range c.Clusters {
+		if len(cluster.Name) != 0 && len(cluster.Context) != 0 {
+			clusters[cluster.Name] = cluster
+		}
+	}
+	kubectlClusters = clusters
+	describeMaxLines = c.DescribeMaxLines
+	guardGetAll = c.GuardGetAll
+	redactFields = compileRedactFields(c.RedactFields)
+	explainOutputs = newExplainCache(c.ExplainCacheTTL)
+	kubectlBinaries = map[string]string{}
+	for version, path := range c.Binaries {
+		kubectlBinaries[version] = path
+	}
+
+	var missing []string
+	for _, path := range append([]string{kubectlBinary}, sortedValues(kubectlBinaries)...) {
+		if err := checkBinary(path); err != nil {
+			missing = append(missing, err.Error())
+		}
+	}
+	if len(missing) != 0 {
+		return fmt.Errorf("Invalid kubectl binary path. %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func checkBinary(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() || info.Mode()&0111 == 0 {
+		return fmt.Errorf("%s is not an executable file", path)
+	}
+	return nil
+}
+
+// kubectlBinaryPath returns binary path for the requested kubectl client version
+// Default binary is returned if version is empty
+func kubectlBinaryPath(version string) (string, error) {
+	if len(version) == 0 {
+		return kubectlBinary, nil
+	}
+	if path, ok := kubectlBinaries[version]; ok {
+		return path, nil
+	}
+	versions := make([]string, 0, len(kubectlBinaries))
+	for v := range kubectlBinaries {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	if len(versions) == 0 {
+		return "", fmt.Errorf("kubectl version '%s' is not configured. Only the default kubectl binary is available", version)
+	}
+	return "", fmt.Errorf("kubectl version '%s' is not configured. Available versions: %s", version, strings.Join(versions, ", "))
+}
+
+// crashLogsTimeout bounds the logs requests made for event notifications
+const crashLogsTimeout = "5s"
+
+// ContainerLogs returns the last lines of the container logs, the logs of the previous instance if previous is true
+// Container can be empty for pods with a single container
+func ContainerLogs(namespace, pod, container string, previous bool, lines int) (string, error) {
+	args := []string{"-n", namespace, "logs", pod, fmt.Sprintf("%s=%d", TailFlag, lines), "--request-timeout=" + crashLogsTimeout}
+	if len(container) != 0 {
+		args = append(args, "-c", container)
+	}
+	if previous {
+		args = append(args, "--previous")
+	}
+	out, err := NewCommandRunner(kubectlBinary, args).Run()
+	if err != nil {
+		return "", fmt.Errorf("%s%s", out, err.Error())
+	}
+	return out, nil
+}
+
+// withDefaultTail appends --tail flag to logs command args if missing to avoid dumping huge logs in chat
+func withDefaultTail(args []string) []string {
+	if len(args) == 0 || args[0] != "logs" || defaultLogsTail < 0 {
+		return args
+	}
+	for _, arg := range args {
+		if arg == TailFlag.String() || strings.HasPrefix(arg, TailFlag.String()+"=") {
+			return args
+		}
+	}
+	return append(args, fmt.Sprintf("%s=%d", TailFlag, defaultLogsTail))
+}
+
+// withDefaultChunkSize appends --chunk-size flag to get command args if missing
+// The API server returns lists in chunks of the size, so that huge lists don't time out or exhaust the memory
+func withDefaultChunkSize(args []string) []string {
+	if len(args) == 0 || args[0] != "get" || defaultChunkSize < 0 {
+		return args
+	}
+	for _, arg := range args {
+		if arg == ChunkSizeFlag.String() || strings.HasPrefix(arg, ChunkSizeFlag.String()+"=") {
+			return args
+		}
+	}
+	return append(args, fmt.Sprintf("%s=%d", ChunkSizeFlag, defaultChunkSize))
+}
+
+// withCacheDir prepends --cache-dir flag to kubectl args if the flag is missing
+// Flags after "--" belong to the command run with exec and are not checked
+func withCacheDir(args []string) []string {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == CacheDirFlag.String() || strings.HasPrefix(arg, CacheDirFlag.String()+"=") {
+			return args
+		}
+	}
+	return append([]string{fmt.Sprintf("%s=%s", CacheDirFlag, kubectlCacheDir)}, args...)
+}
+
+func sortedValues(m map[string]string) []string {
+	values := make([]string, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// enrichContainerError returns the list of containers to choose from if kubectl failed
+// because the container name is missing or invalid. Empty string is returned for other errors
+func enrichContainerError(args []string, out string) string {
+	if m := containerRequiredRegex.FindStringSubmatch(out); m != nil {
+		return fmt.Sprintf(containerRequiredMsg, m[1], formatContainers(strings.Fields(m[2]), strings.Fields(m[3])))
+	}
+	m := invalidContainerRegex.FindStringSubmatch(out)
+	if m == nil {
+		return ""
+	}
+	containers, initContainers, err := podContainers(namespaceFromArgs(args), m[2])
+	if err != nil {
+		log.Warnf("Failed to get containers of pod %s: %s", m[2], err.Error())
+		return ""
+	}
+	return fmt.Sprintf(invalidContainerMsg, m[1], m[2], formatContainers(containers, initContainers))
+}
+
+func formatContainers(containers, initContainers []string) string {
+	res := strings.Join(containers, ", ")
+	if len(initContainers) != 0 {
+		res += fmt.Sprintf(" (init containers: %s)", strings.Join(initContainers, ", "))
+	}
+	return res
+}
+
+// namespaceFromArgs returns the namespace passed with -n or --namespace flag
+func namespaceFromArgs(args []string) string {
+	for i, arg := range args {
+		if (arg == "-n" || arg == "--namespace") && i+1 < len(args) {
+			return args[i+1]
+		}
+		if strings.HasPrefix(arg, "--namespace=") {
+			return strings.TrimPrefix(arg, "--namespace=")
+		}
+	}
+	return "default"
+}
+
+func getPodContainers(namespace, name string) ([]string, []string, error) {
+	if utils.DynamicKubeClient == nil {
+		return nil, nil, fmt.Errorf("kube client is not initialized")
+	}
+	obj, err := utils.DynamicKubeClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "pods"}).Namespace(namespace).Get(context.Background(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	var pod coreV1.Pod
+	if err := utils.TransformIntoTypedObject(obj, &pod); err != nil {
+		return nil, nil, fmt.Errorf("unable to transform object type: %v, into type: %v", reflect.TypeOf(obj), reflect.TypeOf(pod))
+	}
+	var containers, initContainers []string
+	for _, c := range pod.Spec.Containers {
+		containers = append(containers, c.Name)
+	}
+	for _, c := range pod.Spec.InitContainers {
+		initContainers = append(initContainers, c.Name)
+	}
+	return containers, initContainers, nil
+}
+
+// validateOutputFormat returns the message for the output formats not allowed in settings.kubectl.allowedOutputFormats
+func validateOutputFormat(args []string, allowed []string) string {
+	if len(allowed) == 0 {
+		return ""
+	}
+	for _, format := range outputFormats(args) {
+		found := false
+		for _, a := range allowed {
+			if strings.EqualFold(a, format) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf(outputFormatMsg, format, strings.Join(allowed, ", "))
+		}
+	}
+	return ""
+}
+
+// outputFormats returns the values of -o and --output flags without templates, e.g jsonpath for -o=jsonpath='{.kind}'
+func outputFormats(args []string) []string {
+	var formats []string
+	for _, value := range outputValues(args) {
+		format := strings.SplitN(value, "=", 2)[0]
+		formats = append(formats, strings.ToLower(format))
+	}
+	return formats
+}
+
+// shortValueFlags are the kubectl shorthand flags taking a value, the rest of a shorthand cluster is their value
+// e.g -nmonitoring is the namespace monitoring, not -n with -o nitoring
+var shortValueFlags = map[byte]bool{'n': true, 'l': true, 'c': true, 'f': true, 'L': true, 's': true, 'k': true}
+
+// outputValues returns the values of -o and --output flags with quotes trimmed
+// Shorthand clusters are parsed as pflag does, e.g -Aoyaml and -Ao json are -A -o yaml
+// Arguments after "--" belong to the command run in the container and are skipped
+func outputValues(args []string) []string {
+	var values []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return values
+		case arg == "--output":
+			if i+1 < len(args) {
+				i++
+				values = append(values, trimQuotes(args[i]))
+			}
+		case strings.HasPrefix(arg, "--output="):
+			values = append(values, trimQuotes(strings.TrimPrefix(arg, "--output=")))
+		case strings.HasPrefix(arg, "--") || !strings.HasPrefix(arg, "-"):
+		default:
+			for j := 1; j < len(arg); j++ {
+				if shortValueFlags[arg[j]] {
+					break
+				}
+				if arg[j] != 'o' {
+					continue
+				}
+				value := strings.TrimPrefix(arg[j+1:], "=")
+				if j+1 == len(arg) {
+					if i+1 == len(args) {
+						break
+					}
+					i++
+					value = args[i]
+				}
+				values = append(values, trimQuotes(value))
+				break
+			}
+		}
+	}
+	return values
+}
+
+// resolveCluster returns the kubectl flags to run commands in the cluster passed with --cluster-name flag
+// Returns false if the cluster is neither the BotKube cluster nor in settings.kubectl.clusters
+func resolveCluster(name, clusterName string) ([]string, bool) {
+	if cluster, ok := kubectlClusters[name]; ok {
+		flags := []string{"--context", cluster.Context}
+		if len(cluster.Kubeconfig) != 0 {
+			flags = append(flags, "--kubeconfig", cluster.Kubeconfig)
+		}
+		return flags, true
+	}
+	return nil, name == clusterName
+}
+
+// isGetAll returns true for get command listing all resource types, e.g get all or get all,ingresses
+func isGetAll(args []string) bool {
+	if len(args) < 2 || args[0] != "get" {
+		return false
+	}
+	for _, resource := range strings.Split(args[1], ",") {
+		if resource == "all" {
+			return true
+		}
+	}
+	return false
+}
+
+// validateGetAll returns the message for get all command not scoped to a namespace
+// args are the final kubectl args with the default namespace flag added
+func validateGetAll(args []string) string {
+	namespaced := false
+	for _, arg := range args {
+		switch {
+		case arg == AbbrAllNamespacesFlag.String() || arg == AllNamespacesFlag.String() || arg == AllNamespacesFlag.String()+"=true":
+			return getAllUnscopedMsg
+		// -n flag value can be attached, e.g -ndefault
+		case strings.HasPrefix(arg, AbbrNamespaceFlag.String()) || arg == NamespaceFlag.String() || strings.HasPrefix(arg, NamespaceFlag.String()+"="):
+			namespaced = true
+		}
+	}
+	if !namespaced {
+		return getAllUnscopedMsg
+	}
+	return ""
+}
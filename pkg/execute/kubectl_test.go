@@ -0,0 +1,366 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/infracloudio/botkube/pkg/config"
+)
+
+func TestInitKubectl(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubectl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	executable := filepath.Join(dir, "kubectl")
+	if err := ioutil.WriteFile(executable, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	notExecutable := filepath.Join(dir, "kubectl.txt")
+	if err := ioutil.WriteFile(notExecutable, []byte(""), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defaultBinary := kubectlBinary
+	defer func() { kubectlBinary = defaultBinary }()
+
+	tests := map[string]struct {
+		kubectl     config.Kubectl
+		expectedErr bool
+	}{
+		`executable binary path`:          {config.Kubectl{BinaryPath: executable}, false},
+		`executable versioned binary`:     {config.Kubectl{BinaryPath: executable, Binaries: map[string]string{"v1.18": executable}}, false},
+		`missing binary path`:             {config.Kubectl{BinaryPath: filepath.Join(dir, "missing")}, true},
+		`directory as binary path`:        {config.Kubectl{BinaryPath: dir}, true},
+		`not executable versioned binary`: {config.Kubectl{BinaryPath: executable, Binaries: map[string]string{"v1.18": notExecutable}}, true},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			err := InitKubectl(test.kubectl)
+			if (err != nil) != test.expectedErr {
+				t.Errorf("expected error: %+v != actual: %+v\n", test.expectedErr, err)
+			}
+			if kubectlBinary != test.kubectl.BinaryPath {
+				t.Errorf("expected: %+v != actual: %+v\n", test.kubectl.BinaryPath, kubectlBinary)
+			}
+		})
+	}
+}
+
+func TestKubectlBinaryPath(t *testing.T) {
+	defaultBinary := kubectlBinary
+	defer func() {
+		kubectlBinary = defaultBinary
+		kubectlBinaries = map[string]string{}
+	}()
+	kubectlBinary = "/usr/local/bin/kubectl"
+	kubectlBinaries = map[string]string{"v1.18": "/usr/local/bin/kubectl-v1.18", "v1.16": "/usr/local/bin/kubectl-v1.16"}
+
+	tests := map[string]struct {
+		version     string
+		expected    string
+		expectedErr string
+	}{
+		`default binary`:       {"", "/usr/local/bin/kubectl", ""},
+		`configured version`:   {"v1.18", "/usr/local/bin/kubectl-v1.18", ""},
+		`unconfigured version`: {"v1.20", "", "kubectl version 'v1.20' is not configured. Available versions: v1.16, v1.18"},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			actual, err := kubectlBinaryPath(test.version)
+			if err != nil && err.Error() != test.expectedErr || err == nil && len(test.expectedErr) != 0 {
+				t.Errorf("expected error: %+v != actual: %+v\n", test.expectedErr, err)
+			}
+			if actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWithDefaultTail(t *testing.T) {
+	defer func() { defaultLogsTail = 100 }()
+	tests := map[string]struct {
+		args     []string
+		tail     int
+		expected []string
+	}{
+		`logs without tail`:           {[]string{"logs", "nginx", "--since=10m"}, 100, []string{"logs", "nginx", "--since=10m", "--tail=100"}},
+		`logs with tail value`:        {[]string{"logs", "nginx", "--tail=20", "--since=1h"}, 100, []string{"logs", "nginx", "--tail=20", "--since=1h"}},
+		`logs with separate tail arg`: {[]string{"logs", "nginx", "--tail", "20"}, 100, []string{"logs", "nginx", "--tail", "20"}},
+		`logs with configured tail`:   {[]string{"logs", "nginx"}, 50, []string{"logs", "nginx", "--tail=50"}},
+		`default tail disabled`:       {[]string{"logs", "nginx"}, -1, []string{"logs", "nginx"}},
+		`other command`:               {[]string{"get", "pods"}, 100, []string{"get", "pods"}},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			defaultLogsTail = test.tail
+			if actual := withDefaultTail(test.args); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWithDefaultChunkSize(t *testing.T) {
+	defer func() { defaultChunkSize = 500 }()
+	tests := map[string]struct {
+		args      []string
+		chunkSize int
+		expected  []string
+	}{
+		`get without chunk size`:         {[]string{"get", "pods", "-A"}, 500, []string{"get", "pods", "-A", "--chunk-size=500"}},
+		`get with chunk size value`:      {[]string{"get", "pods", "--chunk-size=100", "-A"}, 500, []string{"get", "pods", "--chunk-size=100", "-A"}},
+		`get with separate chunk size`:   {[]string{"get", "pods", "--chunk-size", "0"}, 500, []string{"get", "pods", "--chunk-size", "0"}},
+		`get with configured chunk size`: {[]string{"get", "pods"}, 1000, []string{"get", "pods", "--chunk-size=1000"}},
+		`default chunk size disabled`:    {[]string{"get", "pods"}, -1, []string{"get", "pods"}},
+		`other command`:                  {[]string{"describe", "pods"}, 500, []string{"describe", "pods"}},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			defaultChunkSize = test.chunkSize
+			if actual := withDefaultChunkSize(test.args); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWithCacheDir(t *testing.T) {
+	defer func(dir string) { kubectlCacheDir = dir }(kubectlCacheDir)
+	kubectlCacheDir = "/cache/kubectl"
+	tests := map[string]struct {
+		args     []string
+		expected []string
+	}{
+		`cache dir is passed`:       {[]string{"get", "pods"}, []string{"--cache-dir=/cache/kubectl", "get", "pods"}},
+		`cache dir set by user`:     {[]string{"get", "pods", "--cache-dir=/tmp/cache"}, []string{"get", "pods", "--cache-dir=/tmp/cache"}},
+		`separate cache dir arg`:    {[]string{"--cache-dir", "/tmp/cache", "version"}, []string{"--cache-dir", "/tmp/cache", "version"}},
+		`exec command flag ignored`: {[]string{"exec", "nginx", "--", "app", "--cache-dir=/data"}, []string{"--cache-dir=/cache/kubectl", "exec", "nginx", "--", "app", "--cache-dir=/data"}},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := withCacheDir(test.args); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEnrichContainerError(t *testing.T) {
+	defer func(f func(string, string) ([]string, []string, error)) { podContainers = f }(podContainers)
+	podContainers = func(namespace, name string) ([]string, []string, error) {
+		if namespace != "prod" || name != "nginx" {
+			return nil, nil, fmt.Errorf("pods %q not found", name)
+		}
+		return []string{"nginx", "sidecar"}, []string{"init-db"}, nil
+	}
+
+	tests := map[string]struct {
+		args     []string
+		out      string
+		expected string
+	}{
+		`container name missing`: {
+			args:     []string{"-n", "prod", "logs", "nginx"},
+			out:      "error: a container name must be specified for pod nginx, choose one of: [nginx sidecar]\n",
+			expected: "Pod 'nginx' has multiple containers. Please pass one of them with -c or --container: nginx, sidecar",
+		},
+		`container name missing with init containers`: {
+			args:     []string{"-n", "prod", "logs", "nginx"},
+			out:      "error: a container name must be specified for pod nginx, choose one of: [nginx sidecar] or one of the init containers: [init-db]\n",
+			expected: "Pod 'nginx' has multiple containers. Please pass one of them with -c or --container: nginx, sidecar (init containers: init-db)",
+		},
+		`invalid container`: {
+			args:     []string{"logs", "nginx", "--namespace=prod", "-c", "app"},
+			out:      "Error from server (BadRequest): container \"app\" is not valid for pod \"nginx\"\n",
+			expected: "Container 'app' doesn't exist in pod 'nginx'. Please pass one of them with -c or --container: nginx, sidecar (init containers: init-db)",
+		},
+		`invalid container of unknown pod`: {
+			args:     []string{"logs", "nginx", "-c", "app"},
+			out:      "Error from server (BadRequest): container \"app\" is not valid for pod \"nginx\"\n",
+			expected: "",
+		},
+		`other error`: {
+			args:     []string{"-n", "prod", "logs", "nginx"},
+			out:      "Error from server (NotFound): pods \"nginx\" not found\n",
+			expected: "",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := enrichContainerError(test.args, test.out); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	allowed := []string{"wide"}
+	tests := map[string]struct {
+		args     []string
+		allowed  []string
+		expected string
+	}{
+		`default output`:                   {[]string{"get", "pods"}, allowed, ""},
+		`allowed format`:                   {[]string{"get", "pods", "-o", "wide"}, allowed, ""},
+		`allowed attached value`:           {[]string{"get", "pods", "-owide"}, allowed, ""},
+		`rejected format`:                  {[]string{"get", "secrets", "-o", "yaml"}, allowed, "Output format 'yaml' is not allowed. Please use one of wide, or the default output without -o flag"},
+		`rejected long flag`:               {[]string{"get", "secrets", "--output=json"}, allowed, "Output format 'json' is not allowed. Please use one of wide, or the default output without -o flag"},
+		`rejected template`:                {[]string{"get", "secrets", "-o=jsonpath='{.data}'"}, allowed, "Output format 'jsonpath' is not allowed. Please use one of wide, or the default output without -o flag"},
+		`any format if unset`:              {[]string{"get", "secrets", "-o", "yaml"}, nil, ""},
+		`exec command flags`:               {[]string{"exec", "nginx", "--", "ls", "-o", "yaml"}, allowed, ""},
+		`rejected shorthand cluster`:       {[]string{"get", "secrets", "-Aoyaml"}, allowed, "Output format 'yaml' is not allowed. Please use one of wide, or the default output without -o flag"},
+		`rejected cluster with next value`: {[]string{"get", "secrets", "-Ao", "json"}, allowed, "Output format 'json' is not allowed. Please use one of wide, or the default output without -o flag"},
+		`rejected cluster with equals`:     {[]string{"get", "secrets", "-Ao=json"}, allowed, "Output format 'json' is not allowed. Please use one of wide, or the default output without -o flag"},
+		`allowed shorthand cluster`:        {[]string{"get", "pods", "-Aowide"}, allowed, ""},
+		`attached namespace value`:         {[]string{"get", "pods", "-nmonitoring"}, allowed, ""},
+		`cluster ending with value flag`:   {[]string{"get", "pods", "-Anmonitoring"}, allowed, ""},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := validateOutputFormat(test.args, test.allowed); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRunKubectlCommandOutputFormat(t *testing.T) {
+	defer func(formats []string) { allowedOutputFormats = formats }(allowedOutputFormats)
+	allowedOutputFormats = []string{"wide"}
+
+	actual := runKubectlCommand([]string{"get", "secrets", "-o", "yaml"}, "test", "", true, nil)
+	expected := "Cluster: test\nOutput format 'yaml' is not allowed. Please use one of wide, or the default output without -o flag"
+	if actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
+
+func TestValidateGetAll(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		expected string
+	}{
+		`namespace flag`:           {[]string{"-n", "default", "get", "all"}, ""},
+		`long namespace flag`:      {[]string{"get", "all", "--namespace=kube-system"}, ""},
+		`attached namespace`:       {[]string{"get", "all", "-nkube-system"}, ""},
+		`no namespace`:             {[]string{"get", "all"}, getAllUnscopedMsg},
+		`all namespaces`:           {[]string{"-n", "default", "get", "all", "-A"}, getAllUnscopedMsg},
+		`all namespaces long`:      {[]string{"get", "all", "--all-namespaces"}, getAllUnscopedMsg},
+		`all namespaces and value`: {[]string{"get", "all", "-n", "default", "--all-namespaces=true"}, getAllUnscopedMsg},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := validateGetAll(test.args); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRunKubectlCommandGetAll(t *testing.T) {
+	defer func(guard bool) { guardGetAll = guard }(guardGetAll)
+	KubectlResponse["get all -n kube-system --chunk-size=500"] = "kube-system resources"
+	KubectlResponse["-n default get all --chunk-size=500"] = "default resources"
+	KubectlResponse["get all --chunk-size=500"] = "all resources"
+	KubectlResponse["get all -A --chunk-size=500"] = "all resources"
+	defer func() {
+		delete(KubectlResponse, "get all -n kube-system --chunk-size=500")
+		delete(KubectlResponse, "-n default get all --chunk-size=500")
+		delete(KubectlResponse, "get all --chunk-size=500")
+		delete(KubectlResponse, "get all -A --chunk-size=500")
+	}()
+
+	tests := map[string]struct {
+		guard            bool
+		args             []string
+		defaultNamespace string
+		expected         string
+	}{
+		`scoped`:               {true, []string{"get", "all", "-n", "kube-system"}, "", "Cluster: dev\nkube-system resources"},
+		`default namespace`:    {true, []string{"get", "all"}, "default", "Cluster: dev\ndefault resources"},
+		`unscoped`:             {true, []string{"get", "all"}, "", "Cluster: dev\n" + getAllUnscopedMsg},
+		`all namespaces`:       {true, []string{"get", "all", "-A"}, "default", "Cluster: dev\n" + getAllUnscopedMsg},
+		`other resource types`: {true, []string{"get", "pods"}, "", "Cluster: dev\n"},
+		`guard disabled`:       {false, []string{"get", "all"}, "", "Cluster: dev\nall resources"},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			guardGetAll = test.guard
+			if actual := runKubectlCommand(test.args, "dev", test.defaultNamespace, true, nil); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRunKubectlCommandClusters(t *testing.T) {
+	defer func(clusters map[string]config.KubectlCluster) { kubectlClusters = clusters }(kubectlClusters)
+	kubectlClusters = map[string]config.KubectlCluster{
+		"prod":    {Name: "prod", Context: "prod-ctx"},
+		"staging": {Name: "staging", Context: "staging-ctx", Kubeconfig: "/config/staging"},
+	}
+	KubectlResponse["get pods --chunk-size=500"] = "dev pods"
+	KubectlResponse["--context prod-ctx get pods --chunk-size=500"] = "prod pods"
+	KubectlResponse["--context staging-ctx --kubeconfig /config/staging get pods --chunk-size=500"] = "staging pods"
+	defer func() {
+		delete(KubectlResponse, "get pods --chunk-size=500")
+		delete(KubectlResponse, "--context prod-ctx get pods --chunk-size=500")
+		delete(KubectlResponse, "--context staging-ctx --kubeconfig /config/staging get pods --chunk-size=500")
+	}()
+
+	tests := map[string]struct {
+		args          []string
+		isAuthChannel bool
+		expected      string
+	}{
+		`botkube cluster`:            {[]string{"get", "pods"}, true, "Cluster: dev\ndev pods"},
+		`botkube cluster by name`:    {[]string{"get", "pods", "--cluster-name", "dev"}, false, "Cluster: dev\ndev pods"},
+		`configured cluster`:         {[]string{"get", "pods", "--cluster-name", "prod"}, false, "Cluster: prod\nprod pods"},
+		`cluster with kubeconfig`:    {[]string{"get", "pods", "--cluster-name=staging"}, false, "Cluster: staging\nstaging pods"},
+		`cluster of other instances`: {[]string{"get", "pods", "--cluster-name", "qa"}, true, ""},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := runKubectlCommand(test.args, "dev", "", test.isAuthChannel, nil); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
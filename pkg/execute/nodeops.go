@@ -0,0 +1,242 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/log"
+)
+
+const (
+	nodeOpsDisabledMsg  = "Sorry, the admin hasn't enabled node operations on cluster '%s'."
+	nodeOpsUsageMsg     = "Please pass a single node name, e.g `%s node-1`."
+	nodeOpsFlagMsg      = "Sorry, flag '%s' is not allowed with %s command."
+	nodeOpsPromptMsg    = "Are you sure you want to run `kubectl %s` on cluster '%s'? Reply `%s %s` within %d minutes to proceed."
+	nodeOpsNoPendingMsg = "There is no pending `%s` to confirm. Please run `%s` first."
+	nodeOpsNoUserMsg    = "Sorry, node operations need confirmation but I couldn't identify the user."
+
+	// defaultDrainTimeout is passed to drain if --timeout is not set, kubectl waits forever by default
+	defaultDrainTimeout = 5 * time.Minute
+)
+
+var (
+	validNodeOpCommand = map[string]bool{
+		"cordon":   true,
+		"uncordon": true,
+		"drain":    true,
+	}
+
+	// nodeNameRegex matches node names, they are DNS subdomains
+	nodeNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+
+	// allowNodeOps and allowDeleteEmptyDirData are set from settings.allowNodeOps and settings.drainDeleteEmptyDirData
+	allowNodeOps            bool
+	allowDeleteEmptyDirData bool
+)
+
+// InitNodeOps sets whether cordon, uncordon and drain commands are enabled and if drain can delete emptyDir data
+func InitNodeOps(allow, deleteEmptyDirData bool) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	allowNodeOps = allow
+	allowDeleteEmptyDirData = deleteEmptyDirData
+}
+
+// runNodeOpCommand runs cordon, uncordon or drain on a single node after the user confirms it
+// The command runs with the kubectl binary and context resolved by runKubectl
+func (e *DefaultExecutor) runNodeOpCommand(args []string) string {
+	args, confirmed := stripConfirmArg(args)
+	nodeArgs, clusterName, msg := parseNodeOpArgs(args)
+	if _, ok := resolveCluster(clusterName, e.ClusterName); len(clusterName) != 0 && !ok {
+		return ""
+	}
+	// Like kubectl commands, other channels can run them with --cluster-name flag unless access is restricted
+	if !e.IsAuthChannel && (len(clusterName) == 0 || e.RestrictAccess) {
+		return ""
+	}
+	if !allowNodeOps || !e.AllowKubectl {
+		return fmt.Sprintf(nodeOpsDisabledMsg, e.ClusterName)
+	}
+	if len(msg) != 0 {
+		return msg
+	}
+	// Confirmations are kept per user, anyone could confirm them without one
+	if len(e.User) == 0 {
+		return nodeOpsNoUserMsg
+	}
+
+	command := strings.Join(nodeArgs, " ")
+	target := e.ClusterName
+	if len(clusterName) != 0 {
+		target = clusterName
+		nodeArgs = append(nodeArgs, ClusterFlag.String()+"="+clusterName)
+	}
+	if !confirmed {
+		requestNodeOpConfirmation(e.stateKey(), e.User, command)
+		return fmt.Sprintf(nodeOpsPromptMsg, command, target, strings.Join(args, " "), confirmArg, int(confirmationTTL.Minutes()))
+	}
+	if !confirmNodeOp(e.stateKey(), e.User, command) {
+		return fmt.Sprintf(nodeOpsNoPendingMsg, strings.Join(args, " "), strings.Join(args, " "))
+	}
+	// Node operations are mutating, another user approves them if settings.requireApproval is set
+	if requireApproval {
+		auditNodeOp(e.User, e.ChannelName, target, command, "approval requested")
+		return requestApproval(e.User, nodeArgs, e.ClusterName)
+	}
+
+	res := runKubectl(nodeArgs, e.ClusterName, "", true, nil)
+	if res.exitCode != 0 {
+		auditNodeOp(e.User, e.ChannelName, target, command, "failed: "+strings.TrimSpace(res.stderr))
+		return res.String()
+	}
+	auditNodeOp(e.User, e.ChannelName, target, command, "succeeded")
+	return res.String()
+}
+
+// parseNodeOpArgs returns the kubectl args with safe defaults and the --cluster-name flag value
+// A message is returned instead if a flag is not allowed or the node is not a single name
+func parseNodeOpArgs(args []string) ([]string, string, string) {
+	verb := args[0]
+	var node, clusterName string
+	flags := []string{}
+	hasTimeout, hasIgnoreDaemonSets := false, false
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			if len(node) != 0 || !nodeNameRegex.MatchString(arg) {
+				return nil, clusterName, fmt.Sprintf(nodeOpsUsageMsg, verb)
+			}
+			node = arg
+			continue
+		}
+
+		flag, value, hasValue := arg, "", false
+		if parts := strings.SplitN(arg, "=", 2); len(parts) == 2 {
+			flag, value, hasValue = parts[0], trimQuotes(parts[1]), true
+		}
+		// Flags with a value may pass it as the next argument
+		if !hasValue && nodeOpValueFlags[flag] && i+1 < len(args) {
+			i++
+			value, hasValue = trimQuotes(args[i]), true
+		}
+		if flag == ClusterFlag.String() {
+			clusterName = value
+			continue
+		}
+		if !validNodeOpFlag(verb, flag, value, hasValue) {
+			return nil, clusterName, fmt.Sprintf(nodeOpsFlagMsg, arg, verb)
+		}
+		switch flag {
+		case "--timeout":
+			hasTimeout = true
+		case "--ignore-daemonsets":
+			hasIgnoreDaemonSets = true
+		}
+		if hasValue {
+			flags = append(flags, flag+"="+value)
+		} else {
+			flags = append(flags, flag)
+		}
+	}
+	if len(node) == 0 {
+		return nil, clusterName, fmt.Sprintf(nodeOpsUsageMsg, verb)
+	}
+
+	if verb == "drain" {
+		// DaemonSet pods can't be evicted, drain fails without the flag
+		if !hasIgnoreDaemonSets {
+			flags = append(flags, "--ignore-daemonsets")
+		}
+		if !hasTimeout {
+			flags = append(flags, fmt.Sprintf("--timeout=%s", defaultDrainTimeout))
+		}
+	}
+	return append([]string{verb, node}, flags...), clusterName, ""
+}
+
+// nodeOpValueFlags are the flags which take a value
+var nodeOpValueFlags = map[string]bool{
+	ClusterFlag.String():        true,
+	KubectlVersionFlag.String(): true,
+	"--timeout":                 true,
+	"--grace-period":            true,
+	"--dry-run":                 true,
+}
+
+// validNodeOpFlag returns true if the flag is allowed with the verb
+// Flags bypassing PodDisruptionBudgets or deleting unmanaged pods, e.g --force or --disable-eviction, and selectors are never allowed
+func validNodeOpFlag(verb, flag, value string, hasValue bool) bool {
+	switch flag {
+	case KubectlVersionFlag.String():
+		return hasValue && len(value) != 0
+	case "--dry-run":
+		return hasValue && (value == "none" || value == "client" || value == "server")
+	case "--timeout":
+		d, err := time.ParseDuration(value)
+		return verb == "drain" && err == nil && d > 0
+	case "--grace-period":
+		seconds, err := strconv.Atoi(value)
+		return verb == "drain" && err == nil && seconds > 0
+	case "--ignore-daemonsets":
+		return verb == "drain" && !hasValue
+	case "--delete-emptydir-data":
+		return verb == "drain" && !hasValue && allowDeleteEmptyDirData
+	}
+	return false
+}
+
+// stripConfirmArg removes the trailing confirm argument, returns true if it was passed
+func stripConfirmArg(args []string) ([]string, bool) {
+	if len(args) > 1 && args[len(args)-1] == confirmArg {
+		return args[:len(args)-1], true
+	}
+	return args, false
+}
+
+func nodeOpConfirmationKey(channel, user, command string) string {
+	return "nodeop/" + channel + "/" + user + "/" + command
+}
+
+// requestNodeOpConfirmation stores the command waiting for the confirmation of the user
+func requestNodeOpConfirmation(channel, user, command string) {
+	pendingConfirmations.Lock()
+	defer pendingConfirmations.Unlock()
+	pendingConfirmations.actions[nodeOpConfirmationKey(channel, user, command)] = time.Now().Add(confirmationTTL)
+}
+
+// confirmNodeOp returns true if the same user requested the command in the channel and the confirmation hasn't expired
+func confirmNodeOp(channel, user, command string) bool {
+	key := nodeOpConfirmationKey(channel, user, command)
+	pendingConfirmations.Lock()
+	defer pendingConfirmations.Unlock()
+	expiry, found := pendingConfirmations.actions[key]
+	delete(pendingConfirmations.actions, key)
+	return found && !time.Now().After(expiry)
+}
+
+// auditNodeOp logs who ran the node operation and the result
+func auditNodeOp(user, channel, clusterName, command, result string) {
+	log.Infof("Audit: node operation `kubectl %s` by user '%s' in channel '%s' on cluster '%s' %s", command, user, channel, clusterName, result)
+}
@@ -0,0 +1,286 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseNodeOpArgs(t *testing.T) {
+	tests := map[string]struct {
+		args               []string
+		deleteEmptyDirData bool
+		expected           []string
+		clusterName        string
+		msg                string
+	}{
+		`cordon`: {
+			args:     []string{"cordon", "node-1"},
+			expected: []string{"cordon", "node-1"},
+		},
+		`cordon with dry run`: {
+			args:     []string{"cordon", "node-1", "--dry-run", "server"},
+			expected: []string{"cordon", "node-1", "--dry-run=server"},
+		},
+		`drain with safe defaults`: {
+			args:     []string{"drain", "node-1"},
+			expected: []string{"drain", "node-1", "--ignore-daemonsets", "--timeout=5m0s"},
+		},
+		`drain with timeout and grace period`: {
+			args:     []string{"drain", "--timeout=10m", "node-1", "--grace-period", "30", "--ignore-daemonsets"},
+			expected: []string{"drain", "node-1", "--timeout=10m", "--grace-period=30", "--ignore-daemonsets"},
+		},
+		`cluster name is removed`: {
+			args:        []string{"uncordon", "node-1", "--cluster-name", "dev"},
+			expected:    []string{"uncordon", "node-1"},
+			clusterName: "dev",
+		},
+		`kubectl version is kept`: {
+			args:     []string{"cordon", "node-1", "--kubectl-version", "1.20"},
+			expected: []string{"cordon", "node-1", "--kubectl-version=1.20"},
+		},
+		`delete emptydir data if allowed`: {
+			args:               []string{"drain", "node-1", "--delete-emptydir-data"},
+			deleteEmptyDirData: true,
+			expected:           []string{"drain", "node-1", "--delete-emptydir-data", "--ignore-daemonsets", "--timeout=5m0s"},
+		},
+		`delete emptydir data not allowed`: {
+			args: []string{"drain", "node-1", "--delete-emptydir-data"},
+			msg:  fmt.Sprintf(nodeOpsFlagMsg, "--delete-emptydir-data", "drain"),
+		},
+		`force is rejected`: {
+			args:               []string{"drain", "node-1", "--force"},
+			deleteEmptyDirData: true,
+			msg:                fmt.Sprintf(nodeOpsFlagMsg, "--force", "drain"),
+		},
+		`disable eviction is rejected`: {
+			args: []string{"drain", "node-1", "--disable-eviction"},
+			msg:  fmt.Sprintf(nodeOpsFlagMsg, "--disable-eviction", "drain"),
+		},
+		`deprecated delete local data is rejected`: {
+			args:               []string{"drain", "node-1", "--delete-local-data"},
+			deleteEmptyDirData: true,
+			msg:                fmt.Sprintf(nodeOpsFlagMsg, "--delete-local-data", "drain"),
+		},
+		`zero grace period is rejected`: {
+			args: []string{"drain", "node-1", "--grace-period=0"},
+			msg:  fmt.Sprintf(nodeOpsFlagMsg, "--grace-period=0", "drain"),
+		},
+		`disabling ignore daemonsets is rejected`: {
+			args: []string{"drain", "node-1", "--ignore-daemonsets=false"},
+			msg:  fmt.Sprintf(nodeOpsFlagMsg, "--ignore-daemonsets=false", "drain"),
+		},
+		`drain flags are rejected with cordon`: {
+			args: []string{"cordon", "node-1", "--timeout=1m"},
+			msg:  fmt.Sprintf(nodeOpsFlagMsg, "--timeout=1m", "cordon"),
+		},
+		`selector is rejected`: {
+			args: []string{"cordon", "-l", "pool=spot"},
+			msg:  fmt.Sprintf(nodeOpsFlagMsg, "-l", "cordon"),
+		},
+		`multiple nodes are rejected`: {
+			args: []string{"cordon", "node-1", "node-2"},
+			msg:  fmt.Sprintf(nodeOpsUsageMsg, "cordon"),
+		},
+		`missing node`: {
+			args: []string{"drain", "--timeout=1m"},
+			msg:  fmt.Sprintf(nodeOpsUsageMsg, "drain"),
+		},
+		`invalid node name`: {
+			args: []string{"cordon", "node-1;reboot"},
+			msg:  fmt.Sprintf(nodeOpsUsageMsg, "cordon"),
+		},
+	}
+	defer InitNodeOps(false, false)
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			InitNodeOps(true, test.deleteEmptyDirData)
+			actual, clusterName, msg := parseNodeOpArgs(test.args)
+			if msg != test.msg {
+				t.Fatalf("expected: %+v != actual: %+v\n", test.msg, msg)
+			}
+			if len(test.msg) == 0 && (!reflect.DeepEqual(actual, test.expected) || clusterName != test.clusterName) {
+				t.Errorf("expected: %+v %s != actual: %+v %s\n", test.expected, test.clusterName, actual, clusterName)
+			}
+		})
+	}
+}
+
+func TestRunNodeOpCommandConfirmation(t *testing.T) {
+	KubectlResponse["cordon node-1"] = "node/node-1 cordoned"
+	defer delete(KubectlResponse, "cordon node-1")
+	InitNodeOps(true, false)
+	defer InitNodeOps(false, false)
+
+	alice := &DefaultExecutor{AllowKubectl: true, ClusterName: "dev", ChannelName: "ops", User: "alice", IsAuthChannel: true}
+	bob := &DefaultExecutor{AllowKubectl: true, ClusterName: "dev", ChannelName: "ops", User: "bob", IsAuthChannel: true}
+	args := []string{"cordon", "node-1"}
+	confirm := []string{"cordon", "node-1", confirmArg}
+	noPending := fmt.Sprintf(nodeOpsNoPendingMsg, "cordon node-1", "cordon node-1")
+
+	// Confirmation without the command is rejected
+	if actual := alice.runNodeOpCommand(confirm); actual != noPending {
+		t.Errorf("expected: %+v != actual: %+v\n", noPending, actual)
+	}
+
+	expected := fmt.Sprintf(nodeOpsPromptMsg, "cordon node-1", "dev", "cordon node-1", confirmArg, int(confirmationTTL.Minutes()))
+	if actual := alice.runNodeOpCommand(args); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+	// Only the requester confirms the command
+	if actual := bob.runNodeOpCommand(confirm); actual != noPending {
+		t.Errorf("expected: %+v != actual: %+v\n", noPending, actual)
+	}
+	// Confirmation must match the requested command
+	other := fmt.Sprintf(nodeOpsNoPendingMsg, "cordon node-2", "cordon node-2")
+	if actual := alice.runNodeOpCommand([]string{"cordon", "node-2", confirmArg}); actual != other {
+		t.Errorf("expected: %+v != actual: %+v\n", other, actual)
+	}
+	if actual := alice.runNodeOpCommand(confirm); actual != "Cluster: dev\nnode/node-1 cordoned" {
+		t.Errorf("expected: %+v != actual: %+v\n", "Cluster: dev\nnode/node-1 cordoned", actual)
+	}
+	// Confirmation is used once
+	if actual := alice.runNodeOpCommand(confirm); actual != noPending {
+		t.Errorf("expected: %+v != actual: %+v\n", noPending, actual)
+	}
+}
+
+func TestRunNodeOpCommandConfirmationThread(t *testing.T) {
+	KubectlResponse["cordon node-1"] = "node/node-1 cordoned"
+	defer delete(KubectlResponse, "cordon node-1")
+	InitNodeOps(true, false)
+	defer InitNodeOps(false, false)
+
+	inThread := &DefaultExecutor{AllowKubectl: true, ClusterName: "dev", ChannelName: "ops", Thread: "1600000000.000100", User: "alice", IsAuthChannel: true}
+	inChannel := &DefaultExecutor{AllowKubectl: true, ClusterName: "dev", ChannelName: "ops", User: "alice", IsAuthChannel: true}
+	confirm := []string{"cordon", "node-1", confirmArg}
+	noPending := fmt.Sprintf(nodeOpsNoPendingMsg, "cordon node-1", "cordon node-1")
+
+	inThread.runNodeOpCommand([]string{"cordon", "node-1"})
+	// Command requested in a thread is confirmed in the same thread only
+	if actual := inChannel.runNodeOpCommand(confirm); actual != noPending {
+		t.Errorf("expected: %+v != actual: %+v\n", noPending, actual)
+	}
+	if actual := inThread.runNodeOpCommand(confirm); actual != "Cluster: dev\nnode/node-1 cordoned" {
+		t.Errorf("expected: %+v != actual: %+v\n", "Cluster: dev\nnode/node-1 cordoned", actual)
+	}
+}
+
+func TestRunNodeOpCommandConfirmationExpired(t *testing.T) {
+	InitNodeOps(true, false)
+	defer InitNodeOps(false, false)
+	e := &DefaultExecutor{AllowKubectl: true, ClusterName: "dev", ChannelName: "ops", User: "alice", IsAuthChannel: true}
+	e.runNodeOpCommand([]string{"drain", "node-1"})
+
+	key := nodeOpConfirmationKey("ops", "alice", "drain node-1 --ignore-daemonsets --timeout=5m0s")
+	pendingConfirmations.Lock()
+	if _, ok := pendingConfirmations.actions[key]; !ok {
+		t.Errorf("expected pending confirmation for %s", key)
+	}
+	pendingConfirmations.actions[key] = time.Now().Add(-time.Second)
+	pendingConfirmations.Unlock()
+
+	expected := fmt.Sprintf(nodeOpsNoPendingMsg, "drain node-1", "drain node-1")
+	if actual := e.runNodeOpCommand([]string{"drain", "node-1", confirmArg}); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
+
+func TestRunNodeOpCommandAccess(t *testing.T) {
+	tests := map[string]struct {
+		executor DefaultExecutor
+		allow    bool
+		args     []string
+		expected string
+	}{
+		`disabled`: {
+			executor: DefaultExecutor{AllowKubectl: true, ClusterName: "dev", IsAuthChannel: true},
+			args:     []string{"cordon", "node-1"},
+			expected: fmt.Sprintf(nodeOpsDisabledMsg, "dev"),
+		},
+		`kubectl disabled`: {
+			executor: DefaultExecutor{ClusterName: "dev", IsAuthChannel: true},
+			allow:    true,
+			args:     []string{"cordon", "node-1"},
+			expected: fmt.Sprintf(nodeOpsDisabledMsg, "dev"),
+		},
+		`other cluster`: {
+			executor: DefaultExecutor{AllowKubectl: true, ClusterName: "dev", IsAuthChannel: true},
+			allow:    true,
+			args:     []string{"cordon", "node-1", "--cluster-name=prod"},
+		},
+		`unauthorized channel`: {
+			executor: DefaultExecutor{AllowKubectl: true, ClusterName: "dev"},
+			allow:    true,
+			args:     []string{"cordon", "node-1"},
+		},
+		`restricted access`: {
+			executor: DefaultExecutor{AllowKubectl: true, ClusterName: "dev", RestrictAccess: true},
+			allow:    true,
+			args:     []string{"cordon", "node-1", "--cluster-name", "dev"},
+		},
+		`unknown user`: {
+			executor: DefaultExecutor{AllowKubectl: true, ClusterName: "dev", ChannelName: "ops", IsAuthChannel: true},
+			allow:    true,
+			args:     []string{"drain", "node-1"},
+			expected: nodeOpsNoUserMsg,
+		},
+		`unknown user confirming`: {
+			executor: DefaultExecutor{AllowKubectl: true, ClusterName: "dev", ChannelName: "ops", IsAuthChannel: true},
+			allow:    true,
+			args:     []string{"drain", "node-1", confirmArg},
+			expected: nodeOpsNoUserMsg,
+		},
+		`flag is rejected before confirmation`: {
+			executor: DefaultExecutor{AllowKubectl: true, ClusterName: "dev", IsAuthChannel: true},
+			allow:    true,
+			args:     []string{"drain", "node-1", "--force", confirmArg},
+			expected: fmt.Sprintf(nodeOpsFlagMsg, "--force", "drain"),
+		},
+	}
+	defer InitNodeOps(false, false)
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			InitNodeOps(test.allow, false)
+			if actual := test.executor.runNodeOpCommand(test.args); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRunNodeOpCommandKubectlVersion(t *testing.T) {
+	InitNodeOps(true, false)
+	defer InitNodeOps(false, false)
+	e := &DefaultExecutor{AllowKubectl: true, ClusterName: "dev", ChannelName: "ops", User: "alice", IsAuthChannel: true}
+	args := []string{"cordon", "node-1", "--kubectl-version=1.20"}
+	e.runNodeOpCommand(args)
+
+	// The binary is resolved like for kubectl commands
+	expected := "Cluster: dev\nkubectl version '1.20' is not configured. Only the default kubectl binary is available"
+	if actual := e.runNodeOpCommand(append(args, confirmArg)); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
@@ -0,0 +1,346 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+const (
+	// redactFailedMsg is returned instead of the output which can't be redacted
+	redactFailedMsg = "Output can't be shown because redaction of settings.kubectl.redactFields failed."
+	// redactFormatMsg is returned for the output formats printing fields which can't be redacted
+	redactFormatMsg = "Output format '%s' is not allowed since settings.kubectl.redactFields is set. Please use -o yaml or -o json instead"
+	// redactWatchMsg is returned for watching with YAML or JSON output, the streamed output isn't redacted
+	redactWatchMsg = "Watching with -o yaml or -o json is not allowed since settings.kubectl.redactFields is set. Please run the command without --watch"
+)
+
+// fieldSelectingFormats print the fields selected by the template, they could print the redacted fields as they are
+var fieldSelectingFormats = map[string]bool{
+	"jsonpath":            true,
+	"jsonpath-file":       true,
+	"jsonpath-as-json":    true,
+	"go-template":         true,
+	"go-template-file":    true,
+	"template":            true,
+	"templatefile":        true,
+	"custom-columns":      true,
+	"custom-columns-file": true,
+}
+
+// redactFields are the paths set from settings.kubectl.redactFields, their values are redacted in YAML and JSON output
+var redactFields []fieldPath
+
+type segmentKind int
+
+const (
+	fieldSegment segmentKind = iota
+	wildcardSegment
+	indexSegment
+	filterSegment
+)
+
+// pathSegment is a step of the JSONPath expression
+type pathSegment struct {
+	kind segmentKind
+	// recursive matches the segment at any depth, e.g ..env
+	recursive bool
+	name      string
+	index     int
+	// filterField and filterPattern select list items with the field matching the glob pattern, e.g [?(@.name=="*_PASSWORD")]
+	filterField   []string
+	filterPattern string
+}
+
+// fieldPath is a parsed JSONPath expression
+type fieldPath struct {
+	expr     string
+	segments []pathSegment
+}
+
+// compileRedactFields parses the JSONPath expressions, invalid ones are logged and ignored
+func compileRedactFields(exprs []string) []fieldPath {
+	var paths []fieldPath
+	for _, expr := range exprs {
+		p, err := parseFieldPath(expr)
+		if err != nil {
+			log.Warnf("Ignoring settings.kubectl.redactFields '%s'. %s", expr, err.Error())
+			continue
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// parseFieldPath parses the subset of kubectl JSONPath syntax selecting fields, e.g {.spec.containers[*].env[?(@.name=="*_PASSWORD")].value}
+func parseFieldPath(expr string) (fieldPath, error) {
+	s := strings.TrimSpace(expr)
+	if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+		s = s[1 : len(s)-1]
+	}
+	s = strings.TrimPrefix(s, "$")
+	p := fieldPath{expr: expr}
+	for len(s) != 0 {
+		recursive := false
+		switch {
+		case strings.HasPrefix(s, ".."):
+			recursive = true
+			s = s[2:]
+		case s[0] == '.':
+			s = s[1:]
+		case s[0] != '[':
+			return p, fmt.Errorf("Unexpected '%s'", s)
+		}
+		if len(s) == 0 {
+			return p, fmt.Errorf("Missing field name at the end")
+		}
+
+		if s[0] == '[' {
+			end := strings.Index(s, "]")
+			if end < 0 {
+				return p, fmt.Errorf("Missing ']' in '%s'", s)
+			}
+			// Filter values may contain ']', so the filter ends at ')]'
+			if strings.HasPrefix(s, "[?(") {
+				end = strings.Index(s, ")]") + 1
+				if end < 1 {
+					return p, fmt.Errorf("Missing ')]' in '%s'", s)
+				}
+			}
+			seg, err := parseBracketSegment(s[1:end])
+			if err != nil {
+				return p, err
+			}
+			seg.recursive = recursive
+			p.segments = append(p.segments, seg)
+			s = s[end+1:]
+			continue
+		}
+
+		end := strings.IndexAny(s, ".[")
+		if end < 0 {
+			end = len(s)
+		}
+		seg := pathSegment{kind: fieldSegment, name: s[:end], recursive: recursive}
+		if seg.name == "*" {
+			seg.kind = wildcardSegment
+		}
+		p.segments = append(p.segments, seg)
+		s = s[end:]
+	}
+	if len(p.segments) == 0 {
+		return p, fmt.Errorf("Empty path")
+	}
+	return p, nil
+}
+
+// parseBracketSegment parses the content of [], e.g *, 0, 'name' or ?(@.name=="value")
+func parseBracketSegment(s string) (pathSegment, error) {
+	switch {
+	case s == "*":
+		return pathSegment{kind: wildcardSegment}, nil
+	case len(s) > 1 && (s[0] == '\'' || s[0] == '"'):
+		return pathSegment{kind: fieldSegment, name: trimQuotes(s)}, nil
+	case strings.HasPrefix(s, "?(") && strings.HasSuffix(s, ")"):
+		cond := strings.SplitN(s[2:len(s)-1], "==", 2)
+		field := strings.TrimSpace(cond[0])
+		if len(cond) != 2 || !strings.HasPrefix(field, "@.") {
+			return pathSegment{}, fmt.Errorf("Unsupported filter '%s', use ?(@.field==\"pattern\")", s)
+		}
+		pattern := trimQuotes(strings.TrimSpace(cond[1]))
+		if _, err := path.Match(pattern, ""); err != nil {
+			return pathSegment{}, fmt.Errorf("Invalid pattern '%s'", pattern)
+		}
+		return pathSegment{kind: filterSegment, filterField: strings.Split(field[2:], "."), filterPattern: pattern}, nil
+	}
+	index, err := strconv.Atoi(s)
+	if err != nil || index < 0 {
+		return pathSegment{}, fmt.Errorf("Unsupported subscript '[%s]'", s)
+	}
+	return pathSegment{kind: indexSegment, index: index}, nil
+}
+
+// isRedactable returns true if the fields are redacted in the output of the content type
+func isRedactable(contentType ContentType) bool {
+	return contentType == ContentTypeYAML || contentType == ContentTypeJSON
+}
+
+// validateRedactableFormat returns the message for the output formats selecting fields if there are paths to redact
+func validateRedactableFormat(args []string, paths []fieldPath) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	for _, format := range outputFormats(args) {
+		if fieldSelectingFormats[format] {
+			return fmt.Sprintf(redactFormatMsg, format)
+		}
+	}
+	return ""
+}
+
+// redactOutput replaces the values selected by paths in YAML or JSON output
+// Output which can't be parsed is returned unchanged
+func redactOutput(out string, contentType ContentType, paths []fieldPath) string {
+	if len(paths) == 0 || !isRedactable(contentType) {
+		return out
+	}
+	var doc interface{}
+	var err error
+	if contentType == ContentTypeJSON {
+		// Numbers are kept as they are instead of converting to float
+		d := json.NewDecoder(strings.NewReader(out))
+		d.UseNumber()
+		err = d.Decode(&doc)
+	} else {
+		err = yaml.Unmarshal([]byte(out), &doc)
+	}
+	// The output can contain kubectl warnings, it is not shown unless the fields are redacted
+	if err != nil {
+		log.Debugf("Failed to parse output for redaction: %s", err.Error())
+		return redactFailedMsg
+	}
+
+	redacted := false
+	for _, p := range paths {
+		if redactPath(doc, p.segments) {
+			redacted = true
+		}
+	}
+	if !redacted {
+		return out
+	}
+
+	buf := new(bytes.Buffer)
+	if contentType == ContentTypeJSON {
+		// Indented like kubectl output, without escaping < and > of the redacted value
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "    ")
+		err = enc.Encode(doc)
+	} else {
+		err = yaml.NewEncoder(buf).Encode(doc)
+	}
+	if err != nil {
+		log.Errorf("Failed to marshal redacted output: %s", err.Error())
+		return redactFailedMsg
+	}
+	return buf.String()
+}
+
+// redactPath replaces the values selected by the segments in the node, returns true if any value is replaced
+func redactPath(node interface{}, segments []pathSegment) bool {
+	seg, rest := segments[0], segments[1:]
+	redacted := false
+	for _, child := range selectChildren(node, seg) {
+		if len(rest) == 0 {
+			child.set(utils.RedactedValue)
+			redacted = true
+			continue
+		}
+		if redactPath(child.get(), rest) {
+			redacted = true
+		}
+	}
+	// Recursive segment is matched again in every descendant
+	if seg.recursive {
+		for _, child := range selectChildren(node, pathSegment{kind: wildcardSegment}) {
+			if redactPath(child.get(), segments) {
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
+
+// nodeRef refers to a value in a map or a list so it can be replaced
+type nodeRef struct {
+	get func() interface{}
+	set func(value interface{})
+}
+
+// selectChildren returns the values of the node matching the segment
+// JSON is decoded to map[string]interface{} and YAML to map[interface{}]interface{}
+func selectChildren(node interface{}, seg pathSegment) []nodeRef {
+	var refs []nodeRef
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key := range n {
+			key := key
+			if seg.kind == wildcardSegment || (seg.kind == fieldSegment && key == seg.name) {
+				refs = append(refs, nodeRef{
+					get: func() interface{} { return n[key] },
+					set: func(value interface{}) { n[key] = value },
+				})
+			}
+		}
+	case map[interface{}]interface{}:
+		for key := range n {
+			key := key
+			if seg.kind == wildcardSegment || (seg.kind == fieldSegment && fmt.Sprint(key) == seg.name) {
+				refs = append(refs, nodeRef{
+					get: func() interface{} { return n[key] },
+					set: func(value interface{}) { n[key] = value },
+				})
+			}
+		}
+	case []interface{}:
+		for i := range n {
+			i := i
+			switch {
+			case seg.kind == wildcardSegment,
+				seg.kind == indexSegment && i == seg.index,
+				seg.kind == filterSegment && matchFilter(n[i], seg):
+				refs = append(refs, nodeRef{
+					get: func() interface{} { return n[i] },
+					set: func(value interface{}) { n[i] = value },
+				})
+			}
+		}
+	}
+	return refs
+}
+
+// matchFilter returns true if the field of the list item matches the filter pattern
+func matchFilter(item interface{}, seg pathSegment) bool {
+	value := item
+	for _, name := range seg.filterField {
+		children := selectChildren(value, pathSegment{kind: fieldSegment, name: name})
+		if len(children) == 0 {
+			return false
+		}
+		value = children[0].get()
+	}
+	switch value.(type) {
+	case map[string]interface{}, map[interface{}]interface{}, []interface{}, nil:
+		return false
+	}
+	ok, _ := path.Match(seg.filterPattern, fmt.Sprint(value))
+	return ok
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const podYAML = `apiVersion: v1
+kind: Pod
+metadata:
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: '{}'
+  name: api
+spec:
+  containers:
+  - env:
+    - name: DB_PASSWORD
+      value: s3cr3t
+    - name: DB_HOST
+      value: postgres
+    image: api:1.0
+    name: api
+`
+
+func TestRedactOutput(t *testing.T) {
+	tests := map[string]struct {
+		exprs       []string
+		out         string
+		contentType ContentType
+		expected    string
+	}{
+		`nested env value matching the pattern`: {
+			exprs:       []string{`{.spec.containers[*].env[?(@.name=="*_PASSWORD")].value}`},
+			out:         podYAML,
+			contentType: ContentTypeYAML,
+			expected: `apiVersion: v1
+kind: Pod
+metadata:
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: '{}'
+  name: api
+spec:
+  containers:
+  - env:
+    - name: DB_PASSWORD
+      value: <redacted>
+    - name: DB_HOST
+      value: postgres
+    image: api:1.0
+    name: api
+`,
+		},
+		`recursive descent and quoted field`: {
+			exprs:       []string{`..image`, `.metadata.annotations['kubectl.kubernetes.io/last-applied-configuration']`},
+			out:         podYAML,
+			contentType: ContentTypeYAML,
+			expected: `apiVersion: v1
+kind: Pod
+metadata:
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: <redacted>
+  name: api
+spec:
+  containers:
+  - env:
+    - name: DB_PASSWORD
+      value: s3cr3t
+    - name: DB_HOST
+      value: postgres
+    image: <redacted>
+    name: api
+`,
+		},
+		`json output`: {
+			exprs:       []string{`$.spec.containers[0].env[?(@.name=="DB_PASSWORD")].value`},
+			out:         `{"spec":{"containers":[{"env":[{"name":"DB_PASSWORD","value":"s3cr3t"}],"ports":[{"containerPort":8080}]}]}}`,
+			contentType: ContentTypeJSON,
+			expected: `{
+    "spec": {
+        "containers": [
+            {
+                "env": [
+                    {
+                        "name": "DB_PASSWORD",
+                        "value": "<redacted>"
+                    }
+                ],
+                "ports": [
+                    {
+                        "containerPort": 8080
+                    }
+                ]
+            }
+        ]
+    }
+}
+`,
+		},
+		`no match keeps output intact`: {
+			exprs:       []string{`{.spec.containers[*].env[?(@.name=="*_TOKEN")].value}`},
+			out:         podYAML,
+			contentType: ContentTypeYAML,
+			expected:    podYAML,
+		},
+		`unparsable output with kubectl warning`: {
+			exprs:       []string{`{.data}`},
+			out:         "W1014 12:00:00.000000 1 request.go:665] Waited for 1.1s due to client-side throttling\n{\"kind\": \"Secret\", \"data\": {\"password\": \"czNjcjN0\"}}\n",
+			contentType: ContentTypeJSON,
+			expected:    redactFailedMsg,
+		},
+		`table output is not redacted`: {
+			exprs:       []string{`..image`},
+			out:         "NAME   READY\napi    1/1\n",
+			contentType: ContentTypeTable,
+			expected:    "NAME   READY\napi    1/1\n",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			actual := redactOutput(test.out, test.contentType, compileRedactFields(test.exprs))
+			if actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseFieldPathInvalid(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`{.spec.containers[}`,
+		`.spec.containers[-1]`,
+		`.spec.env[?(@.name!="x")]`,
+		`.spec.env[?(@.name=="[")]`,
+		`.spec.`,
+	} {
+		if _, err := parseFieldPath(expr); err == nil {
+			t.Errorf("expected error for '%s'", expr)
+		}
+	}
+}
+
+func TestRunKubectlCommandRedactFields(t *testing.T) {
+	redactFields = compileRedactFields([]string{`{.spec.containers[*].env[?(@.name=="*_PASSWORD")].value}`})
+	defer func() { redactFields = nil }()
+	KubectlResponse["get pod api -o yaml --chunk-size=500"] = podYAML
+	defer delete(KubectlResponse, "get pod api -o yaml --chunk-size=500")
+
+	actual := runKubectlCommand([]string{"get", "pod", "api", "-o", "yaml"}, "test-cluster", "", true, nil)
+	if !strings.Contains(actual, "value: <redacted>") || strings.Contains(actual, "value: s3cr3t") {
+		t.Errorf("expected redacted output, got: %s", actual)
+	}
+}
+
+func TestRunKubectlCommandRedactShorthandFlags(t *testing.T) {
+	redactFields = compileRedactFields([]string{`{.spec.containers[*].env[?(@.name=="*_PASSWORD")].value}`})
+	defer func() { redactFields = nil }()
+	for _, args := range [][]string{{"get", "pod", "api", "-Aoyaml"}, {"get", "pod", "api", "-Ao", "yaml"}, {"get", "pod", "api", "-Ao=YAML"}} {
+		cmd := strings.Join(args, " ") + " --chunk-size=500"
+		KubectlResponse[cmd] = podYAML
+		actual := runKubectlCommand(args, "test-cluster", "", true, nil)
+		delete(KubectlResponse, cmd)
+		if !strings.Contains(actual, "value: <redacted>") || strings.Contains(actual, "value: s3cr3t") {
+			t.Errorf("expected redacted output of %s, got: %s", cmd, actual)
+		}
+	}
+}
+
+func TestRunKubectlCommandRedactWatch(t *testing.T) {
+	redactFields = compileRedactFields([]string{`{.data}`})
+	defer func() { redactFields = nil }()
+	watch := func(binary string, args []string) string { return "watched" }
+
+	tests := map[string]struct {
+		args     []string
+		expected string
+	}{
+		`watch with yaml output`:    {[]string{"get", "secrets", "-w", "-o", "yaml"}, "Cluster: test-cluster\n" + redactWatchMsg},
+		`watch with json output`:    {[]string{"get", "secrets", "--watch", "-ojson"}, "Cluster: test-cluster\n" + redactWatchMsg},
+		`watch with default output`: {[]string{"get", "secrets", "-w"}, "Cluster: test-cluster\nwatched"},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := runKubectlCommand(test.args, "test-cluster", "", true, watch); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRunKubectlCommandRedactFailedOutput(t *testing.T) {
+	redactFields = compileRedactFields([]string{`{.spec.containers[*].env[?(@.name=="*_PASSWORD")].value}`})
+	defer func() { redactFields = nil }()
+	cmd := "get pod api -o yaml --chunk-size=500"
+	KubectlResponse[cmd] = podYAML
+	KubectlErrors[cmd] = errors.New("exit status 1")
+	defer delete(KubectlResponse, cmd)
+	defer delete(KubectlErrors, cmd)
+
+	actual := runKubectlCommand([]string{"get", "pod", "api", "-o", "yaml"}, "test-cluster", "", true, nil)
+	if !strings.Contains(actual, "value: <redacted>") || strings.Contains(actual, "value: s3cr3t") || !strings.HasSuffix(actual, "exit status 1") {
+		t.Errorf("expected redacted output with the error, got: %s", actual)
+	}
+}
+
+func TestValidateRedactableFormat(t *testing.T) {
+	paths := compileRedactFields([]string{`{.data}`})
+	tests := map[string]struct {
+		args     []string
+		paths    []fieldPath
+		expected string
+	}{
+		`yaml output`:                {[]string{"get", "secret", "db", "-o", "yaml"}, paths, ""},
+		`default output`:             {[]string{"get", "secrets"}, paths, ""},
+		`jsonpath`:                   {[]string{"get", "secret", "db", "-o", "jsonpath={.data}"}, paths, "Output format 'jsonpath' is not allowed since settings.kubectl.redactFields is set. Please use -o yaml or -o json instead"},
+		`go template`:                {[]string{"get", "secret", "db", "--output=go-template={{.data}}"}, paths, "Output format 'go-template' is not allowed since settings.kubectl.redactFields is set. Please use -o yaml or -o json instead"},
+		`template`:                   {[]string{"get", "secret", "db", "-otemplate={{.data}}"}, paths, "Output format 'template' is not allowed since settings.kubectl.redactFields is set. Please use -o yaml or -o json instead"},
+		`custom columns`:             {[]string{"get", "secrets", "-Ao", "custom-columns=DATA:.data"}, paths, "Output format 'custom-columns' is not allowed since settings.kubectl.redactFields is set. Please use -o yaml or -o json instead"},
+		`jsonpath without redaction`: {[]string{"get", "secret", "db", "-o", "jsonpath={.data}"}, nil, ""},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := validateRedactableFormat(test.args, test.paths); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
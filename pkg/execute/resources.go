@@ -0,0 +1,153 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+var validResourcesCommand = map[string]bool{
+	"resources": true,
+}
+
+// resourcesAction for options in resources commands
+type resourcesAction string
+
+// Resources command options
+const (
+	resourcesList   resourcesAction = "list"
+	resourcesMute   resourcesAction = "mute"
+	resourcesUnmute resourcesAction = "unmute"
+)
+
+const (
+	resourceNameMissing  = "You forgot to pass resource name. Please pass one of the following monitored resources:\n\n%s"
+	resourceNameInvalid  = "Resource '%s' is not monitored on cluster '%s'. Please pass one of the following monitored resources:\n\n%s"
+	resourceMuted        = "Done. I won't send notifications for '%s' on cluster '%s' until it is unmuted."
+	resourceAlreadyMuted = "Notifications for '%s' are already muted on cluster '%s'."
+	resourceUnmuted      = "I have resumed notifications for '%s' on cluster '%s'."
+	resourceNotMuted     = "Notifications for '%s' are not muted on cluster '%s'."
+)
+
+// runResourcesCommand lists the monitored resources and mutes or unmutes their notifications
+// Muted resources are kept in memory and are unmuted on restart
+func (e *DefaultExecutor) runResourcesCommand(args []string, isAuthChannel bool) string {
+	if isAuthChannel == false {
+		return ""
+	}
+	if len(args) < 2 {
+		return IncompleteCmdMsg(e.Platform)
+	}
+
+	// Remove --cluster-name flag and its value
+	var name string
+	for i := 2; i < len(args); i++ {
+		if args[i] == ClusterFlag.String() {
+			if i+1 < len(args) && trimQuotes(args[i+1]) != e.ClusterName {
+				return ""
+			}
+			i++
+			continue
+		}
+		if strings.HasPrefix(args[i], ClusterFlag.String()+"=") {
+			if trimQuotes(strings.SplitAfterN(args[i], ClusterFlag.String()+"=", 2)[1]) != e.ClusterName {
+				return ""
+			}
+			continue
+		}
+		name = args[i]
+	}
+
+	c, err := loadedConfig()
+	if err != nil {
+		log.Error("Error in executing resources command: ", err)
+		return "Error in getting configuration!"
+	}
+	monitored := monitoredResources(c.Resources)
+
+	switch resourcesAction(args[1]) {
+	case resourcesList:
+		return makeResourcesList(monitored)
+
+	case resourcesMute, resourcesUnmute:
+		if len(name) == 0 {
+			return fmt.Sprintf(resourceNameMissing, makeResourcesList(monitored))
+		}
+		resource, ok := matchResource(monitored, name)
+		if !ok {
+			return fmt.Sprintf(resourceNameInvalid, name, e.ClusterName, makeResourcesList(monitored))
+		}
+		if resourcesAction(args[1]) == resourcesMute {
+			log.Info("Muting notifications for ", resource)
+			if !utils.MutedResources.Add(resource) {
+				return fmt.Sprintf(resourceAlreadyMuted, resource, e.ClusterName)
+			}
+			return fmt.Sprintf(resourceMuted, resource, e.ClusterName)
+		}
+		log.Info("Unmuting notifications for ", resource)
+		if !utils.MutedResources.Remove(resource) {
+			return fmt.Sprintf(resourceNotMuted, resource, e.ClusterName)
+		}
+		return fmt.Sprintf(resourceUnmuted, resource, e.ClusterName)
+	}
+	return printDefaultMsg(e.Platform)
+}
+
+// monitoredResources returns the names of the resources configured to be watched
+func monitoredResources(resources []config.Resource) []string {
+	var names []string
+	for _, r := range resources {
+		if _, ok := matchResource(names, r.Name); !ok {
+			names = append(names, r.Name)
+		}
+	}
+	return names
+}
+
+// matchResource returns the monitored resource matching the name case-insensitively
+func matchResource(monitored []string, name string) (string, bool) {
+	for _, m := range monitored {
+		if strings.EqualFold(m, name) {
+			return m, true
+		}
+	}
+	return "", false
+}
+
+// makeResourcesList returns the monitored resources with their mute status in tabular form
+func makeResourcesList(monitored []string) string {
+	buf := new(bytes.Buffer)
+	w := tabwriter.NewWriter(buf, 5, 0, 1, ' ', 0)
+
+	fmt.Fprintln(w, "RESOURCE\tMUTED")
+	for _, name := range monitored {
+		fmt.Fprintf(w, "%s\t%v\n", name, utils.MutedResources.Contains(name))
+	}
+
+	w.Flush()
+	return buf.String()
+}
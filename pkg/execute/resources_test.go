@@ -0,0 +1,78 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"testing"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+func TestRunResourcesCommand(t *testing.T) {
+	defer func() { utils.MutedResources = utils.NewResourceSet() }()
+	defer InitConfig(nil)
+	InitConfig(&config.Config{Resources: []config.Resource{{Name: "apps/v1/deployments"}}})
+
+	e := &DefaultExecutor{ClusterName: "dev"}
+	tests := map[string]struct {
+		args          []string
+		isAuthChannel bool
+		expected      string
+		expectedMuted bool
+	}{
+		`not an auth channel`: {[]string{"resources", "mute", "apps/v1/deployments"}, false, "", false},
+		`missing option`:      {[]string{"resources"}, true, IncompleteCmdMsg(config.SlackBot), false},
+		`mute`: {[]string{"resources", "mute", "apps/v1/deployments"}, true,
+			"Done. I won't send notifications for 'apps/v1/deployments' on cluster 'dev' until it is unmuted.", true},
+		`mute again`: {[]string{"resources", "mute", "Apps/v1/Deployments"}, true,
+			"Notifications for 'apps/v1/deployments' are already muted on cluster 'dev'.", true},
+		`other cluster`: {[]string{"resources", "unmute", "apps/v1/deployments", "--cluster-name", "prod"}, true, "", true},
+		`unmute`: {[]string{"resources", "unmute", "apps/v1/deployments", "--cluster-name=dev"}, true,
+			"I have resumed notifications for 'apps/v1/deployments' on cluster 'dev'.", false},
+		`unmute again`: {[]string{"resources", "unmute", "apps/v1/deployments"}, true,
+			"Notifications for 'apps/v1/deployments' are not muted on cluster 'dev'.", false},
+	}
+	// Run in order since the cases depend on the previously muted resources
+	for _, name := range []string{`not an auth channel`, `missing option`, `mute`, `mute again`, `other cluster`, `unmute`, `unmute again`} {
+		test := tests[name]
+		t.Run(name, func(t *testing.T) {
+			if actual := e.runResourcesCommand(test.args, test.isAuthChannel); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+			if actual := utils.MutedResources.Contains("apps/v1/deployments"); actual != test.expectedMuted {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expectedMuted, actual)
+			}
+		})
+	}
+}
+
+func TestMakeResourcesList(t *testing.T) {
+	defer func() { utils.MutedResources = utils.NewResourceSet() }()
+	utils.MutedResources.Add("v1/pods")
+
+	monitored := monitoredResources([]config.Resource{{Name: "v1/pods"}, {Name: "apps/v1/deployments"}, {Name: "v1/pods"}})
+	expected := "RESOURCE            MUTED\n" +
+		"v1/pods             true\n" +
+		"apps/v1/deployments false\n"
+	if actual := makeResourcesList(monitored); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
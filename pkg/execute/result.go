@@ -0,0 +1,182 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/notify"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+// ContentType tells the bots how the command output is formatted, e.g to add language hints to code blocks
+type ContentType string
+
+const (
+	// ContentTypeText is plain text output, e.g describe or logs
+	ContentTypeText ContentType = "text"
+	// ContentTypeYAML is the output of -o yaml
+	ContentTypeYAML ContentType = "yaml"
+	// ContentTypeJSON is the output of -o json
+	ContentTypeJSON ContentType = "json"
+	// ContentTypeTable is the tabular output of get, top and api-resources
+	ContentTypeTable ContentType = "table"
+)
+
+// tableVerbs print tables unless other output format is set
+var tableVerbs = map[string]bool{
+	"get":           true,
+	"top":           true,
+	"api-resources": true,
+}
+
+// Result is the command output with its content type
+type Result struct {
+	Output      string
+	ContentType ContentType
+}
+
+// ExecuteResult executes commands and returns output with the content type inferred from the command
+// Output is the JSON payload of commandResult type if the command has --json-result flag
+func (e *DefaultExecutor) ExecuteResult() Result {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	msg, jsonResult := stripJSONResultFlag(e.Message)
+	if !jsonResult {
+		return Result{Output: e.execute(), ContentType: commandContentType(e.Message)}
+	}
+	e.Message = msg
+	out := e.execute()
+	// Commands for other clusters or from unauthorized channels stay unanswered
+	if len(out) == 0 {
+		return Result{}
+	}
+	return Result{Output: e.jsonResult(out), ContentType: ContentTypeJSON}
+}
+
+// kubectlResult is the outcome of a kubectl command, String returns the reply to the command
+type kubectlResult struct {
+	// ignored is true if the command isn't for this BotKube, e.g for other cluster
+	ignored bool
+	cluster string
+	// note is added before the output, e.g if impersonation flags are removed
+	note     string
+	stdout   string
+	stderr   string
+	exitCode int
+}
+
+func (r kubectlResult) String() string {
+	if r.ignored {
+		return ""
+	}
+	return fmt.Sprintf("Cluster: %s\n%s%s%s", r.cluster, r.note, r.stdout, r.stderr)
+}
+
+// exitCode returns the exit code of the failed command, 1 if the command didn't start
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+	return 1
+}
+
+// stripJSONResultFlag removes --json-result flag from the message, returns true if it was passed
+func stripJSONResultFlag(msg string) (string, bool) {
+	fields := strings.Fields(msg)
+	rest := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f != JSONResultFlag.String() {
+			rest = append(rest, f)
+		}
+	}
+	if len(rest) == len(fields) {
+		return msg, false
+	}
+	return strings.Join(rest, " "), true
+}
+
+// jsonResult returns the command result in the webhook payload format
+// Failed kubectl commands have their stderr and BotKube's rejections in error with a non-zero exit code
+func (e *DefaultExecutor) jsonResult(out string) string {
+	result := notify.WebhookCommandResult{
+		Command: strings.TrimSpace(e.Message),
+		Cluster: e.ClusterName,
+		Output:  out,
+	}
+	if e.kubectl != nil {
+		result.Cluster = e.kubectl.cluster
+		result.Output = e.kubectl.note + e.kubectl.stdout
+		result.Error = e.kubectl.stderr
+		result.ExitCode = e.kubectl.exitCode
+	}
+	b, err := json.MarshalIndent(notify.WebhookPayload{
+		APIVersion:    notify.WebhookAPIVersion,
+		Type:          notify.WebhookCommandResultType,
+		CommandResult: &result,
+	}, "", "  ")
+	if err != nil {
+		log.Errorf("Failed to marshal command result. Error: %s", err.Error())
+		return out
+	}
+	return string(b)
+}
+
+// commandContentType returns the content type of the kubectl command output in the message
+func commandContentType(msg string) ContentType {
+	command, ok := trimCommandPrefix(utils.RemoveHyperlink(msg), commandPrefix)
+	if !ok {
+		return ContentTypeText
+	}
+	if command, ok = trimInstanceAddress(command); !ok {
+		return ContentTypeText
+	}
+	args := strings.Fields(strings.TrimSpace(command))
+	if len(args) == 0 || !utils.GetKubectlMaps().AllowedVerb(args[0]) {
+		return ContentTypeText
+	}
+	return outputContentType(args)
+}
+
+// outputContentType infers the content type from -o flag of the kubectl args
+func outputContentType(args []string) ContentType {
+	// The last -o flag wins as in kubectl
+	format := ""
+	if values := outputValues(args); len(values) != 0 {
+		format = strings.ToLower(values[len(values)-1])
+	}
+	switch {
+	case format == "yaml":
+		return ContentTypeYAML
+	case format == "json":
+		return ContentTypeJSON
+	case format == "" || format == "wide" || strings.HasPrefix(format, "custom-columns"):
+		if tableVerbs[args[0]] {
+			return ContentTypeTable
+		}
+	}
+	return ContentTypeText
+}
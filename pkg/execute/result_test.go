@@ -0,0 +1,154 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"testing"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+func TestCommandContentType(t *testing.T) {
+	tests := map[string]struct {
+		msg      string
+		expected ContentType
+	}{
+		`yaml output`:            {"get pods nginx -o yaml", ContentTypeYAML},
+		`json output`:            {"get deploy -n default -o json", ContentTypeJSON},
+		`attached short flag`:    {"get pods -oyaml", ContentTypeYAML},
+		`short flag with value`:  {"get pods -o=json", ContentTypeJSON},
+		`long flag`:              {"get pods --output=yaml", ContentTypeYAML},
+		`long flag with space`:   {"get pods --output json", ContentTypeJSON},
+		`quoted format`:          {`get pods -o "yaml"`, ContentTypeYAML},
+		`last flag wins`:         {"get pods -o json -o yaml", ContentTypeYAML},
+		`shorthand cluster`:      {"get secrets -Aoyaml", ContentTypeYAML},
+		`cluster with value`:     {"get secrets -Ao json", ContentTypeJSON},
+		`uppercase format`:       {"get secrets -o JSON", ContentTypeJSON},
+		`default get output`:     {"get pods -n kube-system", ContentTypeTable},
+		`wide output`:            {"get nodes -o wide", ContentTypeTable},
+		`custom columns`:         {"get pods -o custom-columns=NAME:.metadata.name", ContentTypeTable},
+		`top`:                    {"top pods", ContentTypeTable},
+		`api-resources`:          {"api-resources --namespaced=true", ContentTypeTable},
+		`jsonpath output`:        {"get pods -o jsonpath={.items[*].metadata.name}", ContentTypeText},
+		`name output`:            {"get pods -o name", ContentTypeText},
+		`describe`:               {"describe pod nginx", ContentTypeText},
+		`logs`:                   {"logs nginx --tail=10", ContentTypeText},
+		`botkube command`:        {"filters list", ContentTypeText},
+		`missing flag value`:     {"get pods -o", ContentTypeTable},
+		`empty command`:          {"", ContentTypeText},
+		`not a kubectl argument`: {"notifier -o yaml", ContentTypeText},
+	}
+	defer utils.SetKubectlMaps(utils.GetKubectlMaps())
+	utils.SetKubectlMaps(&utils.KubectlMaps{Verbs: map[string]bool{"api-resources": true, "describe": true, "get": true, "logs": true, "top": true}})
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := commandContentType(test.msg); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestExecuteResultJSON(t *testing.T) {
+	tests := map[string]struct {
+		msg           string
+		isAuthChannel bool
+		expected      string
+	}{
+		`kubectl command`: {
+			msg:           "get pods --json-result",
+			isAuthChannel: true,
+			expected: `{
+  "apiVersion": "botkube.io/v1",
+  "type": "commandResult",
+  "commandResult": {
+    "command": "get pods",
+    "cluster": "dev",
+    "exitCode": 0,
+    "output": "NAME                           READY   STATUS    RESTARTS   AGE\nnginx-xxxxxxx-yyyyyyy          1/1     Running   1          1d"
+  }
+}`,
+		},
+		`rejected kubectl command`: {
+			msg:           "get pods --kubectl-version=1.8 --json-result",
+			isAuthChannel: true,
+			expected: `{
+  "apiVersion": "botkube.io/v1",
+  "type": "commandResult",
+  "commandResult": {
+    "command": "get pods --kubectl-version=1.8",
+    "cluster": "dev",
+    "exitCode": 1,
+    "output": "",
+    "error": "kubectl version '1.8' is not configured. Only the default kubectl binary is available"
+  }
+}`,
+		},
+		`botkube command`: {
+			msg:           "--json-result events",
+			isAuthChannel: true,
+			expected: `{
+  "apiVersion": "botkube.io/v1",
+  "type": "commandResult",
+  "commandResult": {
+    "command": "events",
+    "cluster": "dev",
+    "exitCode": 0,
+    "output": "` + IncompleteCmdMsg(config.SlackBot) + `"
+  }
+}`,
+		},
+		`unauthorized channel is not answered`: {
+			msg: "get pods --json-result",
+		},
+	}
+	defer utils.SetKubectlMaps(utils.GetKubectlMaps())
+	utils.SetKubectlMaps(&utils.KubectlMaps{Verbs: map[string]bool{"get": true}, Resources: map[string]bool{"pods": true}})
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			e := NewDefaultExecutor(test.msg, true, false, "default", "dev", config.SlackBot, "ops", "", "alice", test.isAuthChannel, nil)
+			actual := e.ExecuteResult()
+			expectedType := ContentTypeJSON
+			if len(test.expected) == 0 {
+				expectedType = ""
+			}
+			if actual.Output != test.expected || actual.ContentType != expectedType {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestExecuteResultWithoutJSONFlag(t *testing.T) {
+	defer utils.SetKubectlMaps(utils.GetKubectlMaps())
+	utils.SetKubectlMaps(&utils.KubectlMaps{Verbs: map[string]bool{"get": true}, Resources: map[string]bool{"pods": true}})
+
+	e := NewDefaultExecutor("get pods", true, false, "default", "dev", config.SlackBot, "ops", "", "alice", true, nil)
+	expected := Result{
+		Output:      "Cluster: dev\nNAME                           READY   STATUS    RESTARTS   AGE\nnginx-xxxxxxx-yyyyyyy          1/1     Running   1          1d",
+		ContentType: ContentTypeTable,
+	}
+	if actual := e.ExecuteResult(); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
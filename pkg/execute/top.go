@@ -0,0 +1,138 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	topSortFlag           = "--sort-by"
+	topInvalidSortMsg     = "Invalid value '%s' for --sort-by flag. Please pass cpu or memory."
+	metricsUnavailableMsg = "Metrics are not available on cluster '%s'. Please make sure metrics-server is installed and running."
+)
+
+// topSortColumns maps --sort-by values to the prefix of the top output column to sort on
+var topSortColumns = map[string]string{
+	"cpu":    "CPU",
+	"memory": "MEMORY",
+}
+
+// metricsUnavailableErrors are parts of kubectl top errors returned when metrics server is missing or not ready
+var metricsUnavailableErrors = []string{
+	"Metrics API not available",
+	"metrics not available yet",
+	"the server could not find the requested resource (get services http:heapster:)",
+	"the server is currently unable to handle the request (get pods.metrics.k8s.io)",
+	"the server is currently unable to handle the request (get nodes.metrics.k8s.io)",
+}
+
+// extractTopSortFlag removes --sort-by flag from top command arguments and returns its value
+// The output is sorted by BotKube so that the flag works for nodes and with old kubectl versions
+func extractTopSortFlag(args []string) ([]string, string, error) {
+	var remaining []string
+	sortBy := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == topSortFlag && i+1 < len(args):
+			i++
+			sortBy = trimQuotes(args[i])
+		case strings.HasPrefix(arg, topSortFlag+"="):
+			sortBy = trimQuotes(strings.TrimPrefix(arg, topSortFlag+"="))
+		default:
+			remaining = append(remaining, arg)
+			continue
+		}
+		sortBy = strings.ToLower(sortBy)
+		if _, ok := topSortColumns[sortBy]; !ok {
+			return args, "", fmt.Errorf(topInvalidSortMsg, sortBy)
+		}
+	}
+	return remaining, sortBy, nil
+}
+
+// formatTopOutput aligns top output columns and sorts the rows by the column descending if sortBy is set
+func formatTopOutput(out, sortBy string) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) < 2 {
+		return out
+	}
+	header := strings.Fields(lines[0])
+	var rows [][]string
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		// Keep the output as is if it isn't a table, e.g warnings printed with the rows
+		if len(fields) != len(header) {
+			return out
+		}
+		rows = append(rows, fields)
+	}
+
+	if column := topColumn(header, topSortColumns[sortBy]); len(sortBy) != 0 && column != -1 {
+		sort.SliceStable(rows, func(i, j int) bool {
+			return quantityValue(rows[i][column]) > quantityValue(rows[j][column])
+		})
+	}
+
+	buf := new(bytes.Buffer)
+	w := tabwriter.NewWriter(buf, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+	return buf.String()
+}
+
+// topColumn returns the index of the first column with the prefix, e.g CPU for CPU(cores), -1 if not found
+func topColumn(header []string, prefix string) int {
+	for i, name := range header {
+		if strings.HasPrefix(name, prefix) && !strings.HasSuffix(name, "%") {
+			return i
+		}
+	}
+	return -1
+}
+
+// quantityValue returns the value of a resource quantity like 250m or 128Mi, unknown values are sorted last
+func quantityValue(value string) float64 {
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return -1
+	}
+	return q.AsApproximateFloat64()
+}
+
+// isMetricsUnavailable checks if kubectl top failed because metrics server is missing or not ready
+func isMetricsUnavailable(out string) bool {
+	for _, e := range metricsUnavailableErrors {
+		if strings.Contains(out, e) {
+			return true
+		}
+	}
+	return false
+}
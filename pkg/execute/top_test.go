@@ -0,0 +1,153 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"reflect"
+	"testing"
+)
+
+const topPodsOutput = `NAME        CPU(cores)   MEMORY(bytes)
+nginx       1m           12Mi
+redis       250m         1Gi
+postgres    1            512Mi
+`
+
+func TestExtractTopSortFlag(t *testing.T) {
+	tests := map[string]struct {
+		args         []string
+		expectedArgs []string
+		expectedSort string
+		expectedErr  bool
+	}{
+		`no flag`:            {[]string{"top", "pods"}, []string{"top", "pods"}, "", false},
+		`flag with value`:    {[]string{"top", "pods", "--sort-by", "cpu"}, []string{"top", "pods"}, "cpu", false},
+		`flag with equals`:   {[]string{"top", "nodes", "--sort-by=Memory"}, []string{"top", "nodes"}, "memory", false},
+		`invalid value`:      {[]string{"top", "pods", "--sort-by=name"}, nil, "", true},
+		`flag without value`: {[]string{"top", "pods", "--sort-by"}, []string{"top", "pods", "--sort-by"}, "", false},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			args, sortBy, err := extractTopSortFlag(test.args)
+			if test.expectedErr {
+				if err == nil {
+					t.Errorf("expected error for %v", test.args)
+				}
+				return
+			}
+			if !reflect.DeepEqual(args, test.expectedArgs) || sortBy != test.expectedSort {
+				t.Errorf("expected: %+v, %+v != actual: %+v, %+v\n", test.expectedArgs, test.expectedSort, args, sortBy)
+			}
+		})
+	}
+}
+
+func TestFormatTopOutput(t *testing.T) {
+	tests := map[string]struct {
+		out      string
+		sortBy   string
+		expected string
+	}{
+		`no sorting`: {
+			out:    topPodsOutput,
+			sortBy: "",
+			expected: "NAME       CPU(cores)   MEMORY(bytes)\n" +
+				"nginx      1m           12Mi\n" +
+				"redis      250m         1Gi\n" +
+				"postgres   1            512Mi\n",
+		},
+		`sort by cpu`: {
+			out:    topPodsOutput,
+			sortBy: "cpu",
+			expected: "NAME       CPU(cores)   MEMORY(bytes)\n" +
+				"postgres   1            512Mi\n" +
+				"redis      250m         1Gi\n" +
+				"nginx      1m           12Mi\n",
+		},
+		`sort by memory`: {
+			out:    topPodsOutput,
+			sortBy: "memory",
+			expected: "NAME       CPU(cores)   MEMORY(bytes)\n" +
+				"redis      250m         1Gi\n" +
+				"postgres   1            512Mi\n" +
+				"nginx      1m           12Mi\n",
+		},
+		`nodes sorted by memory`: {
+			out: "NAME     CPU(cores)   CPU%   MEMORY(bytes)   MEMORY%\n" +
+				"node-1   100m         5%     1000Mi          25%\n" +
+				"node-2   200m         10%    2Gi             50%\n",
+			sortBy: "memory",
+			expected: "NAME     CPU(cores)   CPU%   MEMORY(bytes)   MEMORY%\n" +
+				"node-2   200m         10%    2Gi             50%\n" +
+				"node-1   100m         5%     1000Mi          25%\n",
+		},
+		`not a table`: {
+			out:      "W0301 10:00:00 top pod is deprecated\nNAME CPU(cores) MEMORY(bytes)\n",
+			sortBy:   "cpu",
+			expected: "W0301 10:00:00 top pod is deprecated\nNAME CPU(cores) MEMORY(bytes)\n",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := formatTopOutput(test.out, test.sortBy); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestIsMetricsUnavailable(t *testing.T) {
+	tests := map[string]struct {
+		out      string
+		expected bool
+	}{
+		`metrics api not available`: {"error: Metrics API not available", true},
+		`metrics not ready`:         {"error: metrics not available yet", true},
+		`metrics api unavailable`: {
+			"Error from server (ServiceUnavailable): the server is currently unable to handle the request (get pods.metrics.k8s.io)", true,
+		},
+		`other error`: {`Error from server (NotFound): pods "nginx" not found`, false},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := isMetricsUnavailable(test.out); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRunKubectlCommandTop(t *testing.T) {
+	KubectlResponse["top pods"] = topPodsOutput
+	defer delete(KubectlResponse, "top pods")
+
+	actual := runKubectlCommand([]string{"top", "pods", "--sort-by=cpu"}, "dev", "", true, nil)
+	expected := "Cluster: dev\n" +
+		"NAME       CPU(cores)   MEMORY(bytes)\n" +
+		"postgres   1            512Mi\n" +
+		"redis      250m         1Gi\n" +
+		"nginx      1m           12Mi\n"
+	if actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
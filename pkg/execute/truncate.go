@@ -0,0 +1,85 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var validFullOutputCommand = map[string]bool{
+	"get-full": true,
+}
+
+const (
+	outputTruncatedMsg = "\n... %d more lines truncated. Run 'get-full' to see the full output."
+	noFullOutputMsg    = "No truncated output to show on cluster '%s'."
+)
+
+var (
+	// describeMaxLines is the number of describe output lines sent, output is not truncated if 0
+	describeMaxLines int
+
+	// fullOutputs holds the last truncated output of each channel, returned with get-full command
+	fullOutputs   = map[string]string{}
+	fullOutputsMu sync.Mutex
+)
+
+// truncateOutput returns the first maxLines lines of out with a note, the full output is stored for the channel
+// Output is returned as is if it has at most maxLines lines or maxLines is 0
+func truncateOutput(channel, out string, maxLines int) string {
+	if maxLines <= 0 {
+		return out
+	}
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) <= maxLines {
+		return out
+	}
+
+	fullOutputsMu.Lock()
+	fullOutputs[channel] = out
+	fullOutputsMu.Unlock()
+	return strings.Join(lines[:maxLines], "\n") + fmt.Sprintf(outputTruncatedMsg, len(lines)-maxLines)
+}
+
+// runFullOutputCommand returns the full output of the last truncated command in the channel
+func (e *DefaultExecutor) runFullOutputCommand(args []string) string {
+	if !e.AllowKubectl {
+		return ""
+	}
+	for i := 1; i < len(args); i++ {
+		if args[i] == ClusterFlag.String() && i+1 < len(args) && trimQuotes(args[i+1]) != e.ClusterName {
+			return ""
+		}
+		if strings.HasPrefix(args[i], ClusterFlag.String()+"=") &&
+			trimQuotes(strings.SplitAfterN(args[i], ClusterFlag.String()+"=", 2)[1]) != e.ClusterName {
+			return ""
+		}
+	}
+
+	fullOutputsMu.Lock()
+	out, ok := fullOutputs[e.stateKey()]
+	fullOutputsMu.Unlock()
+	if !ok {
+		return fmt.Sprintf(noFullOutputMsg, e.ClusterName)
+	}
+	return out
+}
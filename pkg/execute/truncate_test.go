@@ -0,0 +1,128 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"testing"
+)
+
+func TestTruncateOutput(t *testing.T) {
+	tests := map[string]struct {
+		out      string
+		maxLines int
+		expected string
+		stored   bool
+	}{
+		`truncation disabled`: {
+			out:      "line1\nline2\nline3\n",
+			maxLines: 0,
+			expected: "line1\nline2\nline3\n",
+		},
+		`output below the limit`: {
+			out:      "line1\nline2\n",
+			maxLines: 3,
+			expected: "line1\nline2\n",
+		},
+		`output at the limit`: {
+			out:      "line1\nline2\nline3\n",
+			maxLines: 3,
+			expected: "line1\nline2\nline3\n",
+		},
+		`output one line over the limit`: {
+			out:      "line1\nline2\nline3\nline4\n",
+			maxLines: 3,
+			expected: "line1\nline2\nline3\n... 1 more lines truncated. Run 'get-full' to see the full output.",
+			stored:   true,
+		},
+		`output over the limit`: {
+			out:      "line1\nline2\nline3\nline4\nline5",
+			maxLines: 2,
+			expected: "line1\nline2\n... 3 more lines truncated. Run 'get-full' to see the full output.",
+			stored:   true,
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			fullOutputs = map[string]string{}
+			if actual := truncateOutput("general", test.out, test.maxLines); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+			if _, ok := fullOutputs["general"]; ok != test.stored {
+				t.Errorf("expected: %+v != actual: %+v\n", test.stored, ok)
+			}
+		})
+	}
+}
+
+func TestRunFullOutputCommand(t *testing.T) {
+	defer func() { fullOutputs = map[string]string{} }()
+	fullOutputs = map[string]string{"general": "line1\nline2\nline3\n", "general/1600000000.000100": "thread line\n"}
+
+	tests := map[string]struct {
+		executor DefaultExecutor
+		args     []string
+		expected string
+	}{
+		`kubectl disabled`: {
+			executor: DefaultExecutor{ClusterName: "dev", ChannelName: "general"},
+			args:     []string{"get-full"},
+			expected: "",
+		},
+		`stored output`: {
+			executor: DefaultExecutor{ClusterName: "dev", ChannelName: "general", AllowKubectl: true},
+			args:     []string{"get-full"},
+			expected: "line1\nline2\nline3\n",
+		},
+		`other channel`: {
+			executor: DefaultExecutor{ClusterName: "dev", ChannelName: "random", AllowKubectl: true},
+			args:     []string{"get-full"},
+			expected: "No truncated output to show on cluster 'dev'.",
+		},
+		`thread of the channel`: {
+			executor: DefaultExecutor{ClusterName: "dev", ChannelName: "general", Thread: "1600000000.000100", AllowKubectl: true},
+			args:     []string{"get-full"},
+			expected: "thread line\n",
+		},
+		`other thread`: {
+			executor: DefaultExecutor{ClusterName: "dev", ChannelName: "general", Thread: "1600000000.000200", AllowKubectl: true},
+			args:     []string{"get-full"},
+			expected: "No truncated output to show on cluster 'dev'.",
+		},
+		`other cluster`: {
+			executor: DefaultExecutor{ClusterName: "dev", ChannelName: "general", AllowKubectl: true},
+			args:     []string{"get-full", "--cluster-name", "prod"},
+			expected: "",
+		},
+		`matching cluster`: {
+			executor: DefaultExecutor{ClusterName: "dev", ChannelName: "general", AllowKubectl: true},
+			args:     []string{"get-full", "--cluster-name=dev"},
+			expected: "line1\nline2\nline3\n",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := test.executor.runFullOutputCommand(test.args); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
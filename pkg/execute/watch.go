@@ -0,0 +1,223 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/log"
+)
+
+const (
+	watchStartedMsg     = "Watching for changes for %s. I will post a snapshot here on every change."
+	watchStoppedMsg     = "Stopped watching after %s."
+	watchFailedMsg      = "Watch stopped with error: %s"
+	watchLimitMsg       = "Sorry, only %d watch(es) can run in a channel at a time. Please wait for the running watch to finish."
+	watchUnsupportedMsg = "Sorry, watch is not supported on %s."
+
+	defaultWatchMaxDuration = time.Minute
+	defaultWatchDebounce    = 5 * time.Second
+)
+
+var (
+	// watchSettings is set from settings.kubectl.watch, watch flags are removed if it is not enabled
+	watchSettings = config.KubectlWatch{}
+
+	// runningWatches is a number of running watches per channel
+	runningWatches = struct {
+		sync.Mutex
+		count map[string]int
+	}{count: map[string]int{}}
+)
+
+// ChannelSender posts messages to the channel where the command was received
+type ChannelSender struct {
+	// Channel identifies the channel to limit the concurrent watches
+	Channel string
+	Send    func(msg string) error
+	// SendFile uploads the content as a file to the channel, it is nil if the platform doesn't support files
+	SendFile func(name, content string) error
+}
+
+// initWatch sets watch settings with defaults for unset values
+func initWatch(c config.KubectlWatch) {
+	if c.MaxDuration <= 0 {
+		c.MaxDuration = defaultWatchMaxDuration
+	}
+	if c.Debounce <= 0 {
+		c.Debounce = defaultWatchDebounce
+	}
+	if c.MaxPerChannel <= 0 {
+		c.MaxPerChannel = 1
+	}
+	watchSettings = c
+}
+
+// watchFunc returns function to start a watch, nil if watch is disabled
+func (e *DefaultExecutor) watchFunc() func(binary string, args []string) string {
+	if !watchSettings.Enabled {
+		return nil
+	}
+	return e.startWatch
+}
+
+// startWatch runs kubectl command with --watch flag in background and returns the response to the command
+func (e *DefaultExecutor) startWatch(binary string, args []string) string {
+	if e.Sender == nil {
+		return fmt.Sprintf(watchUnsupportedMsg, e.Platform)
+	}
+	if !acquireWatch(e.Sender.Channel, watchSettings.MaxPerChannel) {
+		return fmt.Sprintf(watchLimitMsg, watchSettings.MaxPerChannel)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), watchSettings.MaxDuration)
+	sender, clusterName := e.Sender, e.ClusterName
+	go func() {
+		defer releaseWatch(sender.Channel)
+		defer cancel()
+		send := func(msg string) {
+			if err := sender.Send(fmt.Sprintf("Cluster: %s\n%s", clusterName, msg)); err != nil {
+				log.Errorf("Failed to send watch snapshot. Error: %s", err.Error())
+			}
+		}
+		runner := NewStreamRunner(binary, append(args, WatchFlag.String()))
+		if err := runWatch(ctx, runner, send, watchSettings.Debounce); err != nil {
+			send(fmt.Sprintf(watchFailedMsg, err.Error()))
+			return
+		}
+		send(fmt.Sprintf(watchStoppedMsg, watchSettings.MaxDuration))
+	}()
+	return fmt.Sprintf(watchStartedMsg, watchSettings.MaxDuration)
+}
+
+// runWatch streams command output and sends the latest snapshot at most once per debounce interval
+func runWatch(ctx context.Context, runner StreamRunner, send func(string), debounce time.Duration) error {
+	lines := make(chan string)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- runner.Stream(ctx, func(line string) {
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+			}
+		})
+		close(lines)
+	}()
+
+	ticker := time.NewTicker(debounce)
+	defer ticker.Stop()
+	table := newWatchTable()
+	dirty := false
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				if dirty {
+					send(table.String())
+				}
+				return <-errc
+			}
+			if table.add(line) {
+				dirty = true
+			}
+		case <-ticker.C:
+			if dirty {
+				send(table.String())
+				dirty = false
+			}
+		}
+	}
+}
+
+func acquireWatch(channel string, max int) bool {
+	runningWatches.Lock()
+	defer runningWatches.Unlock()
+	if runningWatches.count[channel] >= max {
+		return false
+	}
+	runningWatches.count[channel]++
+	return true
+}
+
+func releaseWatch(channel string) {
+	runningWatches.Lock()
+	defer runningWatches.Unlock()
+	runningWatches.count[channel]--
+	if runningWatches.count[channel] <= 0 {
+		delete(runningWatches.count, channel)
+	}
+}
+
+// watchTable keeps the latest row of each resource from kubectl get --watch output
+type watchTable struct {
+	header string
+	// keyFields is the number of columns identifying a resource, 2 if output has NAMESPACE column
+	keyFields int
+	keys      []string
+	rows      map[string]string
+}
+
+func newWatchTable() *watchTable {
+	return &watchTable{keyFields: 1, rows: map[string]string{}}
+}
+
+// add updates the table with the output line, returns true if the table changed
+func (t *watchTable) add(line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false
+	}
+	// Resource names are lowercase, so these are always the header
+	if fields[0] == "NAME" || fields[0] == "NAMESPACE" {
+		t.header = line
+		if fields[0] == "NAMESPACE" {
+			t.keyFields = 2
+		}
+		return false
+	}
+	if len(fields) < t.keyFields {
+		return false
+	}
+	key := strings.Join(fields[:t.keyFields], "/")
+	if row, ok := t.rows[key]; ok && row == line {
+		return false
+	}
+	if _, ok := t.rows[key]; !ok {
+		t.keys = append(t.keys, key)
+	}
+	t.rows[key] = line
+	return true
+}
+
+func (t *watchTable) String() string {
+	lines := make([]string, 0, len(t.keys)+1)
+	if len(t.header) != 0 {
+		lines = append(lines, t.header)
+	}
+	for _, key := range t.keys {
+		lines = append(lines, t.rows[key])
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+)
+
+type fakeStreamRunner struct {
+	lines []string
+	err   error
+}
+
+func (r fakeStreamRunner) Stream(ctx context.Context, handle func(line string)) error {
+	for _, line := range r.lines {
+		handle(line)
+	}
+	return r.err
+}
+
+func TestWatchTable(t *testing.T) {
+	tests := map[string]struct {
+		lines    []string
+		expected string
+	}{
+		`latest row of each resource`: {
+			lines: []string{
+				"NAME    READY   STATUS    RESTARTS   AGE",
+				"nginx   0/1     Pending   0          1s",
+				"redis   1/1     Running   0          5m",
+				"nginx   1/1     Running   0          3s",
+			},
+			expected: "NAME    READY   STATUS    RESTARTS   AGE\n" +
+				"nginx   1/1     Running   0          3s\n" +
+				"redis   1/1     Running   0          5m",
+		},
+		`all namespaces`: {
+			lines: []string{
+				"NAMESPACE   NAME    READY   STATUS    RESTARTS   AGE",
+				"dev         nginx   1/1     Running   0          1s",
+				"prod        nginx   1/1     Running   0          5m",
+				"",
+				"dev         nginx   0/1     Error     1          9s",
+			},
+			expected: "NAMESPACE   NAME    READY   STATUS    RESTARTS   AGE\n" +
+				"dev         nginx   0/1     Error     1          9s\n" +
+				"prod        nginx   1/1     Running   0          5m",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			table := newWatchTable()
+			for _, line := range test.lines {
+				table.add(line)
+			}
+			if actual := table.String(); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWatchTableUnchangedRow(t *testing.T) {
+	table := newWatchTable()
+	table.add("NAME    READY   STATUS    RESTARTS   AGE")
+	if !table.add("nginx   1/1     Running   0          1s") {
+		t.Errorf("expected new row to change the table")
+	}
+	if table.add("nginx   1/1     Running   0          1s") {
+		t.Errorf("expected same row not to change the table")
+	}
+}
+
+func TestRunWatch(t *testing.T) {
+	runner := fakeStreamRunner{lines: []string{
+		"NAME    READY   STATUS    RESTARTS   AGE",
+		"nginx   0/1     Pending   0          1s",
+		"nginx   1/1     Running   0          3s",
+	}}
+	var sent []string
+	err := runWatch(context.Background(), runner, func(msg string) { sent = append(sent, msg) }, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// Snapshots are debounced, so only the final one is sent
+	expected := []string{"NAME    READY   STATUS    RESTARTS   AGE\nnginx   1/1     Running   0          3s"}
+	if !reflect.DeepEqual(sent, expected) {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, sent)
+	}
+
+	runner = fakeStreamRunner{err: errors.New("unknown resource type")}
+	sent = nil
+	if err := runWatch(context.Background(), runner, func(msg string) { sent = append(sent, msg) }, time.Hour); err == nil || len(sent) != 0 {
+		t.Errorf("expected error without snapshots, got error: %v, sent: %+v", err, sent)
+	}
+}
+
+func TestStartWatch(t *testing.T) {
+	defer func(s config.KubectlWatch) { watchSettings = s }(watchSettings)
+	initWatch(config.KubectlWatch{Enabled: true})
+
+	e := &DefaultExecutor{Platform: config.TeamsBot}
+	if actual := e.startWatch("kubectl", []string{"get", "pods"}); actual != "Sorry, watch is not supported on teams." {
+		t.Errorf("expected: %+v != actual: %+v\n", "Sorry, watch is not supported on teams.", actual)
+	}
+
+	// Only one watch is allowed per channel by default
+	if !acquireWatch("C1", watchSettings.MaxPerChannel) {
+		t.Fatalf("expected watch to be allowed")
+	}
+	defer releaseWatch("C1")
+	e.Sender = &ChannelSender{Channel: "C1", Send: func(msg string) error { return nil }}
+	expected := "Sorry, only 1 watch(es) can run in a channel at a time. Please wait for the running watch to finish."
+	if actual := e.startWatch("kubectl", []string{"get", "pods"}); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
+
+func TestWatchFuncDisabled(t *testing.T) {
+	defer func(s bool) { watchSettings.Enabled = s }(watchSettings.Enabled)
+	watchSettings.Enabled = false
+	e := &DefaultExecutor{}
+	if e.watchFunc() != nil {
+		t.Errorf("expected watch to be disabled")
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	authorizationV1 "k8s.io/api/authorization/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+var validWhoamiCommand = map[string]bool{
+	"whoami": true,
+}
+
+var (
+	// whoamiVerbs are checked for every resource allowed in settings.kubectl.commands.resources
+	whoamiVerbs = []string{"get", "list", "watch", "create", "update", "delete"}
+	// whoamiConcurrency limits the access reviews sent at once
+	whoamiConcurrency = 5
+
+	selfSubjectAccessReviewGVR = schema.GroupVersionResource{Group: "authorization.k8s.io", Version: "v1", Resource: "selfsubjectaccessreviews"}
+)
+
+// runWhoamiCommand reports the service account of BotKube and what it can do with the allowed resources
+func (e *DefaultExecutor) runWhoamiCommand(args []string, isAuthChannel bool) string {
+	if isAuthChannel == false {
+		return ""
+	}
+	namespace := ""
+	for i := 1; i < len(args); i++ {
+		switch {
+		case args[i] == ClusterFlag.String():
+			if i+1 < len(args) && trimQuotes(args[i+1]) != e.ClusterName {
+				return ""
+			}
+			i++
+		case args[i] == NamespaceFlag.String() || args[i] == AbbrNamespaceFlag.String():
+			if i+1 < len(args) {
+				namespace = args[i+1]
+			}
+			i++
+		case strings.HasPrefix(args[i], NamespaceFlag.String()+"="):
+			namespace = strings.TrimPrefix(args[i], NamespaceFlag.String()+"=")
+		}
+	}
+
+	if utils.DynamicKubeClient == nil {
+		log.Error("Error in executing whoami command: kube client is not initialized")
+		return "Error in checking permissions!"
+	}
+	allowed := utils.GetKubectlMaps().Resources
+	resources := make([]string, 0, len(allowed))
+	for r := range allowed {
+		resources = append(resources, r)
+	}
+	sort.Strings(resources)
+
+	scope := "all namespaces"
+	if len(namespace) != 0 {
+		scope = fmt.Sprintf("namespace '%s'", namespace)
+	}
+	return fmt.Sprintf("BotKube on cluster '%s'\n\nService account: %s\nNamespace: %s\n\nAccess in %s:\n%s",
+		e.ClusterName, serviceAccountName(), botkubeNamespace(), scope, makeAccessTable(resources, checkAccess(resources, namespace)))
+}
+
+// accessKey identifies an access review result
+type accessKey struct {
+	resource string
+	verb     string
+}
+
+// checkAccess sends a SelfSubjectAccessReview for each verb and resource, errors are reported as unknown access
+func checkAccess(resources []string, namespace string) map[accessKey]string {
+	results := map[accessKey]string{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, whoamiConcurrency)
+	for _, r := range resources {
+		group := resourceGroup(r)
+		for _, verb := range whoamiVerbs {
+			key := accessKey{resource: r, verb: verb}
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				result := "?"
+				allowed, err := selfSubjectAccessReview(authorizationV1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      key.verb,
+					Group:     group,
+					Resource:  key.resource,
+				})
+				if err != nil {
+					log.Errorf("Failed to check if BotKube can %s %s. %s", key.verb, key.resource, err.Error())
+				} else if allowed {
+					result = "yes"
+				} else {
+					result = "no"
+				}
+				mu.Lock()
+				results[key] = result
+				mu.Unlock()
+			}()
+		}
+	}
+	wg.Wait()
+	return results
+}
+
+// selfSubjectAccessReview returns true if BotKube is allowed to do the action
+func selfSubjectAccessReview(attrs authorizationV1.ResourceAttributes) (bool, error) {
+	review := &authorizationV1.SelfSubjectAccessReview{
+		TypeMeta: metaV1.TypeMeta{APIVersion: "authorization.k8s.io/v1", Kind: "SelfSubjectAccessReview"},
+		Spec:     authorizationV1.SelfSubjectAccessReviewSpec{ResourceAttributes: &attrs},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(review)
+	if err != nil {
+		return false, err
+	}
+	res, err := utils.DynamicKubeClient.Resource(selfSubjectAccessReviewGVR).Create(context.Background(), &unstructured.Unstructured{Object: obj}, metaV1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	if err := utils.TransformIntoTypedObject(res, review); err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+// resourceGroup returns the API group of the resource, core group is returned if it is not known
+func resourceGroup(resource string) string {
+	if utils.Mapper == nil {
+		return ""
+	}
+	gvr, err := utils.Mapper.ResourceFor(schema.GroupVersionResource{Resource: resource})
+	if err != nil {
+		log.Debugf("Failed to find API group of %s. %s", resource, err.Error())
+		return ""
+	}
+	return gvr.Group
+}
+
+// makeAccessTable returns the access review results in tabular form
+func makeAccessTable(resources []string, results map[accessKey]string) string {
+	if len(resources) == 0 {
+		return "No resources are allowed in settings.kubectl.commands.resources"
+	}
+	buf := new(bytes.Buffer)
+	w := tabwriter.NewWriter(buf, 5, 0, 1, ' ', 0)
+	fmt.Fprintf(w, "RESOURCE\t%s\n", strings.ToUpper(strings.Join(whoamiVerbs, "\t")))
+	for _, r := range resources {
+		row := []string{r}
+		for _, verb := range whoamiVerbs {
+			row = append(row, results[accessKey{resource: r, verb: verb}])
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+	return buf.String()
+}
+
+// botkubeNamespace returns the namespace of BotKube pod, POD_NAMESPACE env must be set by the deployment
+func botkubeNamespace() string {
+	if namespace := os.Getenv("POD_NAMESPACE"); len(namespace) != 0 {
+		return namespace
+	}
+	return "Unknown"
+}
+
+// serviceAccountName returns the service account of BotKube pod
+func serviceAccountName() string {
+	namespace := os.Getenv("POD_NAMESPACE")
+	if len(namespace) == 0 {
+		return "Unknown"
+	}
+	obj, err := utils.DynamicKubeClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "pods"}).Namespace(namespace).Get(context.Background(), podName(), metaV1.GetOptions{})
+	if err != nil {
+		log.Warnf("Failed to get BotKube pod: %s", err.Error())
+		return "Unknown"
+	}
+	var pod coreV1.Pod
+	if err := utils.TransformIntoTypedObject(obj, &pod); err != nil {
+		log.Errorf("Unable to transform BotKube pod into type: %T", pod)
+		return "Unknown"
+	}
+	if len(pod.Spec.ServiceAccountName) == 0 {
+		return "default"
+	}
+	return pod.Spec.ServiceAccountName
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package execute
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+	k8sTesting "k8s.io/client-go/testing"
+
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+func TestRunWhoamiCommand(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := coreV1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	pod := &coreV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: "botkube-5d8b9c7f4-x2x9z", Namespace: "botkube"},
+		Spec:       coreV1.PodSpec{ServiceAccountName: "botkube-sa"},
+	}
+
+	defer func(client dynamic.Interface, maps *utils.KubectlMaps, verbs []string) {
+		utils.DynamicKubeClient = client
+		utils.SetKubectlMaps(maps)
+		whoamiVerbs = verbs
+	}(utils.DynamicKubeClient, utils.GetKubectlMaps(), whoamiVerbs)
+	defer os.Setenv("POD_NAMESPACE", os.Getenv("POD_NAMESPACE"))
+	defer os.Setenv("POD_NAME", os.Getenv("POD_NAME"))
+	os.Setenv("POD_NAME", pod.Name)
+	whoamiVerbs = []string{"get", "list", "delete"}
+
+	client := fake.NewSimpleDynamicClient(scheme, pod)
+	var reviewedNamespaces []string
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8sTesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		attrs, _, _ := unstructured.NestedStringMap(review.Object, "spec", "resourceAttributes")
+		if attrs["resource"] == "secrets" {
+			return true, nil, fmt.Errorf("Connection refused")
+		}
+		reviewedNamespaces = append(reviewedNamespaces, attrs["namespace"])
+		allowed := attrs["verb"] != "delete" || attrs["resource"] == "pods"
+		if err := unstructured.SetNestedField(review.Object, allowed, "status", "allowed"); err != nil {
+			return true, nil, err
+		}
+		return true, review, nil
+	})
+	utils.DynamicKubeClient = client
+	utils.SetKubectlMaps(&utils.KubectlMaps{Resources: map[string]bool{"pods": true, "deployments": true, "secrets": true}})
+
+	tests := map[string]struct {
+		command       string
+		podNamespace  string
+		namespace     string
+		isAuthChannel bool
+		expected      string
+	}{
+		`whoami in all namespaces`: {
+			command:       "whoami",
+			podNamespace:  "botkube",
+			isAuthChannel: true,
+			expected: "BotKube on cluster 'test'\n\nService account: botkube-sa\nNamespace: botkube\n\nAccess in all namespaces:\n" +
+				"RESOURCE    GET  LIST DELETE\n" +
+				"deployments yes  yes  no\n" +
+				"pods        yes  yes  yes\n" +
+				"secrets     ?    ?    ?\n",
+		},
+		`whoami in namespace`: {
+			command:       "whoami -n web --cluster-name test",
+			podNamespace:  "botkube",
+			namespace:     "web",
+			isAuthChannel: true,
+			expected: "BotKube on cluster 'test'\n\nService account: botkube-sa\nNamespace: botkube\n\nAccess in namespace 'web':\n" +
+				"RESOURCE    GET  LIST DELETE\n" +
+				"deployments yes  yes  no\n" +
+				"pods        yes  yes  yes\n" +
+				"secrets     ?    ?    ?\n",
+		},
+		`whoami without pod namespace`: {
+			command:       "whoami",
+			isAuthChannel: true,
+			expected: "BotKube on cluster 'test'\n\nService account: Unknown\nNamespace: Unknown\n\nAccess in all namespaces:\n" +
+				"RESOURCE    GET  LIST DELETE\n" +
+				"deployments yes  yes  no\n" +
+				"pods        yes  yes  yes\n" +
+				"secrets     ?    ?    ?\n",
+		},
+		`whoami for other cluster`: {
+			command:       "whoami --cluster-name other",
+			isAuthChannel: true,
+			expected:      "",
+		},
+		`whoami in unauthorized channel`: {
+			command:  "whoami",
+			expected: "",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			reviewedNamespaces = nil
+			os.Setenv("POD_NAMESPACE", test.podNamespace)
+			e := &DefaultExecutor{ClusterName: "test"}
+			actual := e.runWhoamiCommand(strings.Fields(test.command), test.isAuthChannel)
+			if actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+			for _, ns := range reviewedNamespaces {
+				if ns != test.namespace {
+					t.Errorf("expected: %+v != actual: %+v\n", test.namespace, ns)
+				}
+			}
+		})
+	}
+}
@@ -22,9 +22,15 @@ package filterengine
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/infracloudio/botkube/pkg/config"
 	"github.com/infracloudio/botkube/pkg/events"
 	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/utils"
 )
 
 var (
@@ -38,10 +44,16 @@ type FilterEngine interface {
 	Register(Filter)
 	ShowFilters() map[Filter]bool
 	SetFilter(string, bool) error
+	SetFilterScope(string, config.Namespaces) error
+	ResetFilters(map[string]config.FilterSetting) map[string]bool
 }
 
 type defaultFilters struct {
+	// mu guards the filters state, filters commands change it while events are processed
+	mu         sync.RWMutex
 	FiltersMap map[Filter]bool
+	// FiltersScope is a map of filter name to namespaces the filter is allowed to run in
+	FiltersScope map[string]config.Namespaces
 }
 
 // Filter has method to run filter
@@ -50,6 +62,12 @@ type Filter interface {
 	Describe() string
 }
 
+// KindFilter is implemented by filters which run only for some resource kinds and event types
+// Empty lists mean the filter runs for all of them
+type KindFilter interface {
+	AppliesTo() (kinds []string, types []config.EventType)
+}
+
 func init() {
 	DefaultFilterEngine = NewDefaultFilter()
 }
@@ -58,34 +76,169 @@ func init() {
 func NewDefaultFilter() FilterEngine {
 	var df defaultFilters
 	df.FiltersMap = make(map[Filter]bool)
+	df.FiltersScope = make(map[string]config.Namespaces)
 	return &df
 }
 
-// Run run the filters
+// filterTimeout is the maximum time a filter can take to process an event
+var filterTimeout = 5 * time.Second
+
+// deduplication is how the repeated recommendations and warnings are found after the filters run
+var (
+	deduplication   = config.DeduplicateExact
+	deduplicationMu sync.RWMutex
+)
+
+// InitDeduplication sets how the recommendations and warnings added by the filters are deduplicated
+// Invalid values fall back to config.DeduplicateExact
+func InitDeduplication(d config.Deduplication) {
+	deduplicationMu.Lock()
+	defer deduplicationMu.Unlock()
+	switch d {
+	case config.DeduplicateNormalized, config.DeduplicateOff:
+		deduplication = d
+	default:
+		deduplication = config.DeduplicateExact
+	}
+}
+
+// Run runs the enabled filters concurrently and merges their changes into the event
 func (f *defaultFilters) Run(object interface{}, event events.Event) events.Event {
 	log.Debug("Filterengine running filters")
 	// Run registered filters
+	names := []string{}
+	filters := map[string]Filter{}
+	f.mu.RLock()
 	for k, v := range f.FiltersMap {
-		if v {
-			k.Run(object, &event)
+		if !v {
+			continue
 		}
+		// Skip filter if event namespace is out of the filter scope
+		name := reflect.TypeOf(k).Name()
+		if scope, ok := f.FiltersScope[name]; ok && !isNamespaceInScope(scope, event.Namespace) {
+			log.Debugf("Skipping filter %s for namespace %s", name, event.Namespace)
+			continue
+		}
+		names = append(names, name)
+		filters[name] = k
 	}
+	f.mu.RUnlock()
+	// Merge in the order of filter names to keep recommendations and warnings deterministic
+	sort.Strings(names)
+
+	results := make([]chan events.Event, len(names))
+	for i, name := range names {
+		// Buffered so that a timed out filter doesn't block forever
+		results[i] = make(chan events.Event, 1)
+		go func(filter Filter, e events.Event, result chan<- events.Event) {
+			filter.Run(object, &e)
+			result <- e
+		}(filters[name], copyEvent(event), results[i])
+	}
+
+	original := copyEvent(event)
+	timeout := time.NewTimer(filterTimeout)
+	defer timeout.Stop()
+	timedOut := false
+	for i, result := range results {
+		if !timedOut {
+			select {
+			case r := <-result:
+				mergeEvent(&event, original, r)
+				continue
+			case <-timeout.C:
+				// All filters started together, so only the finished ones are merged from now on
+				timedOut = true
+			}
+		}
+		select {
+		case r := <-result:
+			mergeEvent(&event, original, r)
+		default:
+			log.Warnf("Filter %s timed out after %s, skipping its result", names[i], filterTimeout)
+		}
+	}
+	deduplicationMu.RLock()
+	d := deduplication
+	deduplicationMu.RUnlock()
+	event.Recommendations = deduplicate(event.Recommendations, d)
+	event.Warnings = deduplicate(event.Warnings, d)
 	return event
 }
 
+// deduplicate returns the strings without the repeated ones, the first occurrence is kept
+func deduplicate(items []string, d config.Deduplication) []string {
+	if d == config.DeduplicateOff || len(items) < 2 {
+		return items
+	}
+	seen := make(map[string]bool, len(items))
+	unique := make([]string, 0, len(items))
+	for _, item := range items {
+		key := item
+		if d == config.DeduplicateNormalized {
+			key = strings.TrimSuffix(strings.ToLower(strings.Join(strings.Fields(item), " ")), ".")
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, item)
+	}
+	return unique
+}
+
+// copyEvent returns a copy of the event without the recommendations and warnings
+// so that a filter's additions can be merged back
+func copyEvent(event events.Event) events.Event {
+	e := event
+	e.Messages = append([]string(nil), event.Messages...)
+	e.Recommendations = nil
+	e.Warnings = nil
+	return e
+}
+
+// mergeEvent applies the changes made by a filter on the original event
+// Filters get a copy of the slices, e.g Messages, only the items appended by the filter are merged
+// Other fields are set if the filter changed them, the filter merged last wins
+func mergeEvent(event *events.Event, original, result events.Event) {
+	merged, o, r := reflect.ValueOf(event).Elem(), reflect.ValueOf(original), reflect.ValueOf(result)
+	for i := 0; i < merged.NumField(); i++ {
+		before, after := o.Field(i), r.Field(i)
+		if after.Kind() == reflect.Slice {
+			if after.Len() > before.Len() {
+				merged.Field(i).Set(reflect.AppendSlice(merged.Field(i), after.Slice(before.Len(), after.Len())))
+			}
+			continue
+		}
+		if !reflect.DeepEqual(before.Interface(), after.Interface()) {
+			merged.Field(i).Set(after)
+		}
+	}
+}
+
 // Register filter to engine
 func (f *defaultFilters) Register(filter Filter) {
 	log.Info("Registering the filter ", reflect.TypeOf(filter).Name())
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.FiltersMap[filter] = true
 }
 
-// ShowFilters return map of filter name and status
-func (f defaultFilters) ShowFilters() map[Filter]bool {
-	return f.FiltersMap
+// ShowFilters return a copy of the map of filter and status
+func (f *defaultFilters) ShowFilters() map[Filter]bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	filters := make(map[Filter]bool, len(f.FiltersMap))
+	for k, v := range f.FiltersMap {
+		filters[k] = v
+	}
+	return filters
 }
 
 // SetFilter sets filter value in FilterMap to enable or disable filter
 func (f *defaultFilters) SetFilter(name string, flag bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	// Find filter struct name
 	for k := range f.FiltersMap {
 		if reflect.TypeOf(k).Name() == name {
@@ -93,5 +246,69 @@ func (f *defaultFilters) SetFilter(name string, flag bool) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("Invalid filter name %s", name)
+	return f.unknownFilterError(name)
+}
+
+// ResetFilters enables or disables the filters as set in settings, filters without a setting are enabled
+// It returns the filters whose state changed with their new state
+func (f *defaultFilters) ResetFilters(settings map[string]config.FilterSetting) map[string]bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	changed := map[string]bool{}
+	for k, enabled := range f.FiltersMap {
+		name := reflect.TypeOf(k).Name()
+		if state := settings[name].IsEnabled(); state != enabled {
+			f.FiltersMap[k] = state
+			changed[name] = state
+		}
+	}
+	return changed
+}
+
+// SetFilterScope restricts filter to run only on events from the given namespaces
+func (f *defaultFilters) SetFilterScope(name string, namespaces config.Namespaces) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for k := range f.FiltersMap {
+		if reflect.TypeOf(k).Name() == name {
+			f.FiltersScope[name] = namespaces
+			return nil
+		}
+	}
+	return f.unknownFilterError(name)
+}
+
+// unknownFilterError returns the error for invalid filter name with the names of registered filters, f.mu must be held
+func (f *defaultFilters) unknownFilterError(name string) error {
+	names := make([]string, 0, len(f.FiltersMap))
+	for k := range f.FiltersMap {
+		names = append(names, reflect.TypeOf(k).Name())
+	}
+	sort.Strings(names)
+	return fmt.Errorf("No such filter '%s'. Valid filters: %s", name, strings.Join(names, ", "))
+}
+
+// isNamespaceInScope checks if namespace matches the include list and does not match the ignore list
+// Empty include list or "all" matches all the namespaces
+// Cluster scoped events (empty namespace) are always in scope
+func isNamespaceInScope(scope config.Namespaces, namespace string) bool {
+	if len(namespace) == 0 {
+		return true
+	}
+	included := len(scope.Include) == 0
+	for _, ns := range scope.Include {
+		if ns == "all" || utils.MatchNamespace(ns, namespace) {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+	for _, ns := range scope.Ignore {
+		if utils.MatchNamespace(ns, namespace) {
+			return false
+		}
+	}
+	return true
 }
@@ -0,0 +1,336 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filterengine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+type fakeFilter struct {
+	runs *int
+}
+
+func (f fakeFilter) Run(object interface{}, event *events.Event) {
+	*f.runs++
+}
+
+func (f fakeFilter) Describe() string {
+	return "Fake filter"
+}
+
+type warningFilter struct{}
+
+func (f warningFilter) Run(object interface{}, event *events.Event) {
+	event.Warnings = append(event.Warnings, "warning")
+	event.Recommendations = append(event.Recommendations, "warning filter recommendation")
+	event.Messages = append(event.Messages, "warning filter message")
+}
+
+func (f warningFilter) Describe() string {
+	return "Warning filter"
+}
+
+type skipFilter struct{}
+
+func (f skipFilter) Run(object interface{}, event *events.Event) {
+	event.Recommendations = append(event.Recommendations, "skip filter recommendation")
+	event.Level = config.Critical
+	event.Skip = true
+}
+
+func (f skipFilter) Describe() string {
+	return "Skip filter"
+}
+
+type resourceFilter struct{}
+
+func (f resourceFilter) Run(object interface{}, event *events.Event) {
+	event.Resource = "apps/v1/deployments"
+	event.Title = "Deployment created"
+	event.CorrelationID = "release-42"
+	event.Count = 2
+}
+
+func (f resourceFilter) Describe() string {
+	return "Resource filter"
+}
+
+type slowFilter struct {
+	delay time.Duration
+}
+
+func (f slowFilter) Run(object interface{}, event *events.Event) {
+	time.Sleep(f.delay)
+	event.Recommendations = append(event.Recommendations, "slow filter recommendation")
+}
+
+func (f slowFilter) Describe() string {
+	return "Slow filter"
+}
+
+type duplicateFilter struct{}
+
+func (f duplicateFilter) Run(object interface{}, event *events.Event) {
+	event.Recommendations = append(event.Recommendations, "warning filter recommendation", "Warning  filter recommendation.")
+	event.Warnings = append(event.Warnings, "warning", "warning")
+}
+
+func (f duplicateFilter) Describe() string {
+	return "Duplicate filter"
+}
+
+func TestIsNamespaceInScope(t *testing.T) {
+	tests := map[string]struct {
+		scope     config.Namespaces
+		namespace string
+		expected  bool
+	}{
+		`empty scope --> run in all namespaces`:                 {config.Namespaces{}, "prod", true},
+		`include all --> run in all namespaces`:                 {config.Namespaces{Include: []string{"all"}}, "prod", true},
+		`include exact match --> run`:                           {config.Namespaces{Include: []string{"dev"}}, "dev", true},
+		`include exact mismatch --> skip`:                       {config.Namespaces{Include: []string{"dev"}}, "prod", false},
+		`include with wildcard match --> run`:                   {config.Namespaces{Include: []string{"dev-*"}}, "dev-team-a", true},
+		`include with wildcard mismatch --> skip`:               {config.Namespaces{Include: []string{"dev-*"}}, "prod-dev-a", false},
+		`ignore exact match --> skip`:                           {config.Namespaces{Ignore: []string{"prod"}}, "prod", false},
+		`ignore with wildcard match --> skip`:                   {config.Namespaces{Include: []string{"all"}, Ignore: []string{"prod-*"}}, "prod-eu", false},
+		`include wildcard and ignore exact --> skip ignored`:    {config.Namespaces{Include: []string{"dev-*"}, Ignore: []string{"dev-secure"}}, "dev-secure", false},
+		`include wildcard and ignore exact --> run not ignored`: {config.Namespaces{Include: []string{"dev-*"}, Ignore: []string{"dev-secure"}}, "dev-a", true},
+		`ignore is "" --> run`:                                  {config.Namespaces{Include: []string{"all"}, Ignore: []string{""}}, "dev", true},
+		`cluster scoped event --> run`:                          {config.Namespaces{Include: []string{"dev"}}, "", true},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := isNamespaceInScope(test.scope, test.namespace); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRunWithFilterScope(t *testing.T) {
+	tests := map[string]struct {
+		scope     *config.Namespaces
+		namespace string
+		expected  int
+	}{
+		`filter without scope`:            {nil, "prod", 1},
+		`filter with matching include`:    {&config.Namespaces{Include: []string{"dev"}}, "dev", 1},
+		`filter with mismatching include`: {&config.Namespaces{Include: []string{"dev"}}, "prod", 0},
+		`filter with matching ignore`:     {&config.Namespaces{Include: []string{"all"}, Ignore: []string{"prod"}}, "prod", 0},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			runs := 0
+			fe := NewDefaultFilter()
+			fe.Register(fakeFilter{runs: &runs})
+			if test.scope != nil {
+				if err := fe.SetFilterScope("fakeFilter", *test.scope); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			fe.Run(nil, events.Event{Namespace: test.namespace})
+			if runs != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, runs)
+			}
+		})
+	}
+}
+
+func TestSetFilterScopeInvalidName(t *testing.T) {
+	fe := NewDefaultFilter()
+	if err := fe.SetFilterScope("UnknownFilter", config.Namespaces{}); err == nil {
+		t.Errorf("expected error for unknown filter name")
+	}
+}
+
+func TestSetFilterInvalidName(t *testing.T) {
+	fe := NewDefaultFilter()
+	fe.Register(warningFilter{})
+	fe.Register(skipFilter{})
+	err := fe.SetFilter("foo", true)
+	expected := "No such filter 'foo'. Valid filters: skipFilter, warningFilter"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, err)
+	}
+	if enabled := fe.ShowFilters(); !enabled[warningFilter{}] || !enabled[skipFilter{}] {
+		t.Errorf("expected filters to stay enabled, got: %+v", enabled)
+	}
+}
+
+func TestResetFilters(t *testing.T) {
+	disabled, enabled := false, true
+	fe := NewDefaultFilter()
+	fe.Register(warningFilter{})
+	fe.Register(skipFilter{})
+	fe.Register(duplicateFilter{})
+	settings := map[string]config.FilterSetting{
+		"warningFilter":   {Enabled: &disabled},
+		"duplicateFilter": {Enabled: &enabled},
+		"skipFilter":      {Namespaces: config.Namespaces{Include: []string{"dev"}}},
+	}
+
+	// Config state is applied first, then the filters toggled at runtime are restored
+	if changed := fe.ResetFilters(settings); !reflect.DeepEqual(changed, map[string]bool{"warningFilter": false}) {
+		t.Errorf("expected: %+v != actual: %+v\n", map[string]bool{"warningFilter": false}, changed)
+	}
+	fe.SetFilter("warningFilter", true)
+	fe.SetFilter("skipFilter", false)
+	expected := map[string]bool{"warningFilter": false, "skipFilter": true}
+	if changed := fe.ResetFilters(settings); !reflect.DeepEqual(changed, expected) {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, changed)
+	}
+	state := map[Filter]bool{warningFilter{}: false, skipFilter{}: true, duplicateFilter{}: true}
+	if actual := fe.ShowFilters(); !reflect.DeepEqual(actual, state) {
+		t.Errorf("expected: %+v != actual: %+v\n", state, actual)
+	}
+	if changed := fe.ResetFilters(settings); len(changed) != 0 {
+		t.Errorf("expected: no changes != actual: %+v\n", changed)
+	}
+}
+
+func TestRunMergesFilterResults(t *testing.T) {
+	fe := NewDefaultFilter()
+	fe.Register(warningFilter{})
+	fe.Register(skipFilter{})
+
+	expected := events.Event{
+		Name:            "nginx",
+		Level:           config.Critical,
+		Skip:            true,
+		Messages:        []string{"Back-off restarting failed container", "warning filter message"},
+		Recommendations: []string{"skip filter recommendation", "warning filter recommendation"},
+		Warnings:        []string{"warning"},
+	}
+	// Repeat to make sure merge order doesn't depend on scheduling
+	for i := 0; i < 20; i++ {
+		actual := fe.Run(nil, events.Event{Name: "nginx", Level: config.Info, Messages: []string{"Back-off restarting failed container"}})
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("expected: %+v != actual: %+v\n", expected, actual)
+		}
+	}
+}
+
+func TestRunMergesAllChangedFields(t *testing.T) {
+	fe := NewDefaultFilter()
+	fe.Register(resourceFilter{})
+	fe.Register(warningFilter{})
+
+	expected := events.Event{
+		Name:            "nginx",
+		Resource:        "apps/v1/deployments",
+		Title:           "Deployment created",
+		CorrelationID:   "release-42",
+		Count:           2,
+		Messages:        []string{"warning filter message"},
+		Recommendations: []string{"warning filter recommendation"},
+		Warnings:        []string{"warning"},
+	}
+	if actual := fe.Run(nil, events.Event{Name: "nginx", Resource: "v1/pods"}); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
+
+func TestRunWhileChangingFilters(t *testing.T) {
+	fe := NewDefaultFilter()
+	fe.Register(warningFilter{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			_ = fe.SetFilter("warningFilter", i%2 == 0)
+			_ = fe.SetFilterScope("warningFilter", config.Namespaces{Include: []string{"default"}})
+			fe.ResetFilters(nil)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		fe.Run(nil, events.Event{Name: "nginx", Namespace: "default"})
+		for range fe.ShowFilters() {
+		}
+	}
+	<-done
+}
+
+func TestRunSkipsTimedOutFilter(t *testing.T) {
+	defer func(timeout time.Duration) { filterTimeout = timeout }(filterTimeout)
+	filterTimeout = 50 * time.Millisecond
+
+	fe := NewDefaultFilter()
+	fe.Register(slowFilter{delay: time.Second})
+	fe.Register(warningFilter{})
+
+	start := time.Now()
+	actual := fe.Run(nil, events.Event{Name: "nginx"})
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("expected filters to time out, took %s", elapsed)
+	}
+	expected := []string{"warning filter recommendation"}
+	if !reflect.DeepEqual(actual.Recommendations, expected) {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual.Recommendations)
+	}
+}
+
+func TestRunDeduplicatesRecommendations(t *testing.T) {
+	defer InitDeduplication(deduplication)
+
+	tests := map[string]struct {
+		deduplication           config.Deduplication
+		expectedRecommendations []string
+		expectedWarnings        []string
+	}{
+		`exact by default`: {
+			expectedRecommendations: []string{"warning filter recommendation", "Warning  filter recommendation."},
+			expectedWarnings:        []string{"warning"},
+		},
+		`normalized`: {
+			deduplication:           config.DeduplicateNormalized,
+			expectedRecommendations: []string{"warning filter recommendation"},
+			expectedWarnings:        []string{"warning"},
+		},
+		`off`: {
+			deduplication:           config.DeduplicateOff,
+			expectedRecommendations: []string{"warning filter recommendation", "Warning  filter recommendation.", "warning filter recommendation"},
+			expectedWarnings:        []string{"warning", "warning", "warning"},
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			InitDeduplication(test.deduplication)
+			fe := NewDefaultFilter()
+			fe.Register(duplicateFilter{})
+			fe.Register(warningFilter{})
+
+			actual := fe.Run(nil, events.Event{Name: "nginx"})
+			if !reflect.DeepEqual(actual.Recommendations, test.expectedRecommendations) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expectedRecommendations, actual.Recommendations)
+			}
+			if !reflect.DeepEqual(actual.Warnings, test.expectedWarnings) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expectedWarnings, actual.Warnings)
+			}
+		})
+	}
+}
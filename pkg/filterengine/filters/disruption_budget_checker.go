@@ -0,0 +1,205 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	appsV1 "k8s.io/api/apps/v1"
+	policyV1beta1 "k8s.io/api/policy/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/filterengine"
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+const (
+	belowDisruptionBudgetMsg   = "%s %s has %d available replicas, below %d required by PodDisruptionBudget %s. Voluntary disruptions like node drains are blocked"
+	fewerReplicasThanBudgetMsg = "%s %s is scaled to %d replicas, fewer than %d required by PodDisruptionBudget %s. Node drains will be blocked until it is scaled up"
+	atDisruptionBudgetMsg      = "%s %s has %d available replicas, the minimum required by PodDisruptionBudget %s. Any voluntary disruption will be blocked"
+)
+
+var disruptionBudgetGVR = schema.GroupVersionResource{Group: "policy", Version: "v1beta1", Resource: "poddisruptionbudgets"}
+
+// listDisruptionBudgets returns the PodDisruptionBudgets in the namespace
+var listDisruptionBudgets = getDisruptionBudgets
+
+// DisruptionBudgetChecker warns when available replicas of a workload approach or fall below the minimum of its PodDisruptionBudget
+type DisruptionBudgetChecker struct {
+	Description string
+}
+
+// Register filter
+func init() {
+	filterengine.DefaultFilterEngine.Register(DisruptionBudgetChecker{
+		Description: "Warns when available replicas of Deployment or ReplicaSet reach the minimum of its PodDisruptionBudget.",
+	})
+}
+
+// Run filters and modifies event struct
+func (f DisruptionBudgetChecker) Run(object interface{}, event *events.Event) {
+	w, ok := workloadOf(object)
+	if !ok {
+		return
+	}
+	budgets, err := listDisruptionBudgets(context.Background(), w.namespace)
+	if err != nil {
+		log.Debugf("Unable to list PodDisruptionBudgets in namespace %s. %s", w.namespace, err.Error())
+		return
+	}
+	if warning := disruptionBudgetWarning(w, budgets); len(warning) != 0 {
+		event.Warnings = append(event.Warnings, warning)
+	}
+	log.Debug("Disruption budget filter successful!")
+}
+
+// Describe filter
+func (f DisruptionBudgetChecker) Describe() string {
+	return f.Description
+}
+
+// AppliesTo returns kinds and event types the filter runs for
+// Notifications of the updates depend on updateSetting of the resources, e.g with status.availableReplicas field
+func (f DisruptionBudgetChecker) AppliesTo() ([]string, []config.EventType) {
+	return []string{"Deployment", "ReplicaSet"}, []config.EventType{config.UpdateEvent}
+}
+
+// workload contains the fields of Deployment and ReplicaSet compared with PodDisruptionBudgets
+type workload struct {
+	kind      string
+	name      string
+	namespace string
+	replicas  int32
+	available int32
+	podLabels labels.Set
+}
+
+// workloadOf returns the workload of Deployment or ReplicaSet object
+// ReplicaSets managed by a Deployment are skipped since the Deployment is checked
+func workloadOf(object interface{}) (workload, bool) {
+	unstructuredObj, ok := object.(*unstructured.Unstructured)
+	if !ok {
+		log.Debugf("Unexpected object type %v", reflect.TypeOf(object))
+		return workload{}, false
+	}
+	w := workload{kind: unstructuredObj.GetKind(), name: unstructuredObj.GetName(), namespace: unstructuredObj.GetNamespace()}
+	switch w.kind {
+	case "Deployment":
+		var deployment appsV1.Deployment
+		if err := utils.TransformIntoTypedObject(unstructuredObj, &deployment); err != nil {
+			log.Errorf("Unable to transform object type: %v, into type: %v", reflect.TypeOf(object), reflect.TypeOf(deployment))
+			return workload{}, false
+		}
+		w.replicas, w.available, w.podLabels = replicasOrDefault(deployment.Spec.Replicas), deployment.Status.AvailableReplicas, deployment.Spec.Template.Labels
+	case "ReplicaSet":
+		if metaV1.GetControllerOf(unstructuredObj) != nil {
+			return workload{}, false
+		}
+		var replicaSet appsV1.ReplicaSet
+		if err := utils.TransformIntoTypedObject(unstructuredObj, &replicaSet); err != nil {
+			log.Errorf("Unable to transform object type: %v, into type: %v", reflect.TypeOf(object), reflect.TypeOf(replicaSet))
+			return workload{}, false
+		}
+		w.replicas, w.available, w.podLabels = replicasOrDefault(replicaSet.Spec.Replicas), replicaSet.Status.AvailableReplicas, replicaSet.Spec.Template.Labels
+	default:
+		return workload{}, false
+	}
+	return w, true
+}
+
+// replicasOrDefault returns the replicas of the spec, API server defaults missing value to 1
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// disruptionBudgetWarning returns the warning for the PodDisruptionBudget selecting the workload pods with the highest risk
+// Empty string is returned if the available replicas are above the minimum of all the budgets
+func disruptionBudgetWarning(w workload, budgets []policyV1beta1.PodDisruptionBudget) string {
+	var atMinimum string
+	for _, pdb := range budgets {
+		selector, err := metaV1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() || !selector.Matches(w.podLabels) {
+			continue
+		}
+		required, err := requiredReplicas(pdb.Spec, w.replicas)
+		if err != nil {
+			log.Debugf("Invalid PodDisruptionBudget %s/%s. %s", pdb.Namespace, pdb.Name, err.Error())
+			continue
+		}
+		switch {
+		case w.replicas < required:
+			return fmt.Sprintf(fewerReplicasThanBudgetMsg, w.kind, w.name, w.replicas, required, pdb.Name)
+		case w.available < required:
+			return fmt.Sprintf(belowDisruptionBudgetMsg, w.kind, w.name, w.available, required, pdb.Name)
+		case w.available == required && required > 0 && len(atMinimum) == 0:
+			atMinimum = fmt.Sprintf(atDisruptionBudgetMsg, w.kind, w.name, w.available, pdb.Name)
+		}
+	}
+	return atMinimum
+}
+
+// requiredReplicas returns the replicas which have to be available as per minAvailable or maxUnavailable of the budget
+// Percentages are scaled on the desired replicas of the workload and rounded up like the disruption controller does
+func requiredReplicas(spec policyV1beta1.PodDisruptionBudgetSpec, replicas int32) (int32, error) {
+	if spec.MinAvailable != nil {
+		required, err := intstr.GetScaledValueFromIntOrPercent(spec.MinAvailable, int(replicas), true)
+		return int32(required), err
+	}
+	if spec.MaxUnavailable != nil {
+		unavailable, err := intstr.GetScaledValueFromIntOrPercent(spec.MaxUnavailable, int(replicas), true)
+		if err != nil {
+			return 0, err
+		}
+		if required := replicas - int32(unavailable); required > 0 {
+			return required, nil
+		}
+		return 0, nil
+	}
+	return 0, nil
+}
+
+// getDisruptionBudgets lists the PodDisruptionBudgets in the namespace from the API server
+func getDisruptionBudgets(ctx context.Context, namespace string) ([]policyV1beta1.PodDisruptionBudget, error) {
+	list, err := utils.DynamicKubeClient.Resource(disruptionBudgetGVR).Namespace(namespace).List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	budgets := make([]policyV1beta1.PodDisruptionBudget, 0, len(list.Items))
+	for i := range list.Items {
+		var pdb policyV1beta1.PodDisruptionBudget
+		if err := utils.TransformIntoTypedObject(&list.Items[i], &pdb); err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, pdb)
+	}
+	return budgets, nil
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	policyV1beta1 "k8s.io/api/policy/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+func workloadObject(kind, name string, replicas, available int64, owners []metaV1.OwnerReference) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": "web"},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "nginx"}},
+			},
+		},
+		"status": map[string]interface{}{"availableReplicas": available},
+	}}
+	obj.SetOwnerReferences(owners)
+	return obj
+}
+
+func disruptionBudget(name string, minAvailable, maxUnavailable *intstr.IntOrString, app string) policyV1beta1.PodDisruptionBudget {
+	return policyV1beta1.PodDisruptionBudget{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "web"},
+		Spec: policyV1beta1.PodDisruptionBudgetSpec{
+			MinAvailable:   minAvailable,
+			MaxUnavailable: maxUnavailable,
+			Selector:       &metaV1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+		},
+	}
+}
+
+func TestDisruptionBudgetCheckerRun(t *testing.T) {
+	two, half, one := intstr.FromInt(2), intstr.FromString("50%"), intstr.FromInt(1)
+	budgets := []policyV1beta1.PodDisruptionBudget{
+		disruptionBudget("redis-pdb", &one, nil, "redis"),
+		disruptionBudget("nginx-pdb", &two, nil, "nginx"),
+	}
+	defer func(f func(context.Context, string) ([]policyV1beta1.PodDisruptionBudget, error)) {
+		listDisruptionBudgets = f
+	}(listDisruptionBudgets)
+	listDisruptionBudgets = func(ctx context.Context, namespace string) ([]policyV1beta1.PodDisruptionBudget, error) {
+		return budgets, nil
+	}
+
+	tests := map[string]struct {
+		object   *unstructured.Unstructured
+		budgets  []policyV1beta1.PodDisruptionBudget
+		expected []string
+	}{
+		`deployment above threshold`: {
+			object: workloadObject("Deployment", "nginx", 3, 3, nil),
+		},
+		`deployment at threshold`: {
+			object:   workloadObject("Deployment", "nginx", 3, 2, nil),
+			expected: []string{"Deployment nginx has 2 available replicas, the minimum required by PodDisruptionBudget nginx-pdb. Any voluntary disruption will be blocked"},
+		},
+		`deployment under threshold`: {
+			object:   workloadObject("Deployment", "nginx", 3, 1, nil),
+			expected: []string{"Deployment nginx has 1 available replicas, below 2 required by PodDisruptionBudget nginx-pdb. Voluntary disruptions like node drains are blocked"},
+		},
+		`deployment scaled below budget`: {
+			object:   workloadObject("Deployment", "nginx", 1, 1, nil),
+			expected: []string{"Deployment nginx is scaled to 1 replicas, fewer than 2 required by PodDisruptionBudget nginx-pdb. Node drains will be blocked until it is scaled up"},
+		},
+		`replicaset with percentage budget`: {
+			object:   workloadObject("ReplicaSet", "nginx-5d8f", 4, 1, nil),
+			budgets:  []policyV1beta1.PodDisruptionBudget{disruptionBudget("nginx-pdb", &half, nil, "nginx")},
+			expected: []string{"ReplicaSet nginx-5d8f has 1 available replicas, below 2 required by PodDisruptionBudget nginx-pdb. Voluntary disruptions like node drains are blocked"},
+		},
+		`max unavailable budget`: {
+			object:   workloadObject("Deployment", "nginx", 3, 2, nil),
+			budgets:  []policyV1beta1.PodDisruptionBudget{disruptionBudget("nginx-pdb", nil, &one, "nginx")},
+			expected: []string{"Deployment nginx has 2 available replicas, the minimum required by PodDisruptionBudget nginx-pdb. Any voluntary disruption will be blocked"},
+		},
+		`replicaset managed by deployment`: {
+			object: workloadObject("ReplicaSet", "nginx-5d8f", 3, 1, controlledBy("apps/v1", "Deployment", "nginx")),
+		},
+		`no matching budget`: {
+			object:  workloadObject("Deployment", "nginx", 3, 1, nil),
+			budgets: []policyV1beta1.PodDisruptionBudget{disruptionBudget("redis-pdb", &two, nil, "redis")},
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if test.budgets != nil {
+				saved := budgets
+				budgets = test.budgets
+				defer func() { budgets = saved }()
+			}
+			event := events.Event{Kind: test.object.GetKind(), Name: test.object.GetName(), Namespace: "web"}
+			DisruptionBudgetChecker{}.Run(test.object, &event)
+			if !reflect.DeepEqual(event.Warnings, test.expected) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, event.Warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/filterengine"
+	"github.com/infracloudio/botkube/pkg/log"
+)
+
+// FailedScheduling is the reason of the events reported by the scheduler for pods which can't be placed on a node
+const FailedScheduling = "FailedScheduling"
+
+var (
+	// insufficientRegex matches the resources nodes lack, e.g "2 Insufficient cpu" or "Insufficient nvidia.com/gpu"
+	insufficientRegex = regexp.MustCompile(`(?i)insufficient\s+([a-z0-9][a-z0-9./_-]*[a-z0-9])`)
+	// taintRegex matches the taints not tolerated by the pod, e.g "had taint {node-role.kubernetes.io/master: }"
+	taintRegex = regexp.MustCompile(`(?i)taints?\s*\{([^}]*)\}`)
+)
+
+// schedulingCause is a reason of the scheduling failure recognized in the scheduler message
+type schedulingCause struct {
+	// phrases are lowercase parts of the message, any of them identifies the cause
+	phrases        []string
+	recommendation string
+}
+
+// schedulingCauses are checked in order, causes with details like resources and taints are handled separately
+var schedulingCauses = []schedulingCause{
+	{
+		phrases:        []string{"didn't match node selector", "didn't match pod's node affinity", "node affinity/selector"},
+		recommendation: "No node matches the pod nodeSelector or node affinity. Check the node labels or relax the affinity rules.",
+	},
+	{
+		phrases:        []string{"didn't match pod affinity", "didn't match pod anti-affinity", "pod affinity rules", "anti-affinity rules"},
+		recommendation: "Pod affinity or anti-affinity rules can't be satisfied. Check the rules against the pods running on the nodes.",
+	},
+	{
+		phrases:        []string{"unbound immediate persistentvolumeclaims", "unbound persistentvolumeclaims"},
+		recommendation: "PersistentVolumeClaim of the pod is not bound. Check the claim status and its storage class.",
+	},
+	{
+		phrases:        []string{"volume node affinity conflict"},
+		recommendation: "PersistentVolume of the pod is available in a different zone than the free nodes.",
+	},
+	{
+		phrases:        []string{"didn't have free ports"},
+		recommendation: "Requested hostPort is already used on the nodes. Remove the hostPort or add nodes.",
+	},
+	{
+		phrases:        []string{"too many pods"},
+		recommendation: "Nodes reached the maximum number of pods. Add nodes or increase max pods of the nodes.",
+	},
+	{
+		phrases:        []string{"were unschedulable", "node(s) unschedulable"},
+		recommendation: "Some nodes are cordoned. Uncordon them if the maintenance is over.",
+	},
+}
+
+// FailedSchedulingChecker adds recommendations for the likely causes of FailedScheduling pod events
+type FailedSchedulingChecker struct {
+	Description string
+}
+
+// Register filter
+func init() {
+	filterengine.DefaultFilterEngine.Register(FailedSchedulingChecker{
+		Description: "Adds recommendations based on the scheduler message if Pod can't be scheduled.",
+	})
+}
+
+// Run filters and modifies event struct
+func (f FailedSchedulingChecker) Run(object interface{}, event *events.Event) {
+	if event.Kind != "Pod" || event.Reason != FailedScheduling {
+		return
+	}
+	event.Recommendations = append(event.Recommendations, schedulingRecommendations(strings.Join(event.Messages, " "))...)
+	log.Debug("Scheduling failure filter successful!")
+}
+
+// Describe filter
+func (f FailedSchedulingChecker) Describe() string {
+	return f.Description
+}
+
+// AppliesTo returns kinds and event types the filter runs for
+func (f FailedSchedulingChecker) AppliesTo() ([]string, []config.EventType) {
+	return []string{"Pod"}, []config.EventType{config.WarningEvent, config.ErrorEvent}
+}
+
+// schedulingRecommendations returns recommendations for the causes found in the scheduler message
+// The message format differs between Kubernetes versions, so only short phrases are matched
+func schedulingRecommendations(msg string) []string {
+	var recommendations []string
+	seen := map[string]bool{}
+	for _, m := range insufficientRegex.FindAllStringSubmatch(msg, -1) {
+		resource := strings.ToLower(m[1])
+		if seen[resource] {
+			continue
+		}
+		seen[resource] = true
+		recommendations = append(recommendations, insufficientRecommendation(resource))
+	}
+
+	var taints []string
+	seenTaints := map[string]bool{}
+	for _, m := range taintRegex.FindAllStringSubmatch(msg, -1) {
+		taint := strings.TrimSpace(m[1])
+		if len(taint) != 0 && !seenTaints[taint] {
+			seenTaints[taint] = true
+			taints = append(taints, "{"+taint+"}")
+		}
+	}
+	if len(taints) > 0 {
+		recommendations = append(recommendations, fmt.Sprintf("Nodes have taint %s not tolerated by the pod. Add a toleration or use other nodes.", strings.Join(taints, ", ")))
+	}
+
+	lower := strings.ToLower(msg)
+	for _, cause := range schedulingCauses {
+		for _, phrase := range cause.phrases {
+			if strings.Contains(lower, phrase) {
+				recommendations = append(recommendations, cause.recommendation)
+				break
+			}
+		}
+	}
+	return recommendations
+}
+
+func insufficientRecommendation(resource string) string {
+	switch resource {
+	case "cpu":
+		return "Nodes don't have enough CPU for the pod requests. Lower the CPU requests or add nodes."
+	case "memory":
+		return "Nodes don't have enough memory for the pod requests. Lower the memory requests or add nodes."
+	}
+	return fmt.Sprintf("Nodes don't have enough %s for the pod requests. Lower the requests or add nodes providing it.", resource)
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+func TestSchedulingRecommendations(t *testing.T) {
+	tests := map[string]struct {
+		msg      string
+		expected []string
+	}{
+		`insufficient cpu and taint`: {
+			msg: "0/3 nodes are available: 1 node(s) had taint {node-role.kubernetes.io/master: }, that the pod didn't tolerate, 2 Insufficient cpu.",
+			expected: []string{
+				"Nodes don't have enough CPU for the pod requests. Lower the CPU requests or add nodes.",
+				"Nodes have taint {node-role.kubernetes.io/master:} not tolerated by the pod. Add a toleration or use other nodes.",
+			},
+		},
+		`untolerated taint with preemption details`: {
+			msg: "0/3 nodes are available: 1 node(s) had untolerated taint {node-role.kubernetes.io/control-plane: }, 2 Insufficient memory. " +
+				"preemption: 0/3 nodes are available: 1 Preemption is not helpful for scheduling, 2 No preemption victims found for incoming pod.",
+			expected: []string{
+				"Nodes don't have enough memory for the pod requests. Lower the memory requests or add nodes.",
+				"Nodes have taint {node-role.kubernetes.io/control-plane:} not tolerated by the pod. Add a toleration or use other nodes.",
+			},
+		},
+		`extended resource mentioned twice`: {
+			msg: "0/2 nodes are available: 2 Insufficient nvidia.com/gpu. preemption: 0/2 nodes are available: 2 Insufficient nvidia.com/gpu.",
+			expected: []string{
+				"Nodes don't have enough nvidia.com/gpu for the pod requests. Lower the requests or add nodes providing it.",
+			},
+		},
+		`node selector and cordoned node`: {
+			msg: "0/4 nodes are available: 1 node(s) were unschedulable, 3 node(s) didn't match node selector.",
+			expected: []string{
+				"No node matches the pod nodeSelector or node affinity. Check the node labels or relax the affinity rules.",
+				"Some nodes are cordoned. Uncordon them if the maintenance is over.",
+			},
+		},
+		`node affinity in newer format`: {
+			msg: "0/3 nodes are available: 3 node(s) didn't match Pod's node affinity/selector.",
+			expected: []string{
+				"No node matches the pod nodeSelector or node affinity. Check the node labels or relax the affinity rules.",
+			},
+		},
+		`unbound claim`: {
+			msg: "pod has unbound immediate PersistentVolumeClaims (repeated 3 times)",
+			expected: []string{
+				"PersistentVolumeClaim of the pod is not bound. Check the claim status and its storage class.",
+			},
+		},
+		`volume zone and ports`: {
+			msg: "0/3 nodes are available: 1 node(s) didn't have free ports for the requested pod ports, 2 node(s) had volume node affinity conflict.",
+			expected: []string{
+				"PersistentVolume of the pod is available in a different zone than the free nodes.",
+				"Requested hostPort is already used on the nodes. Remove the hostPort or add nodes.",
+			},
+		},
+		`unknown message`: {
+			msg: "0/3 nodes are available: 3 something new happened.",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := schedulingRecommendations(test.msg); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFailedSchedulingCheckerRun(t *testing.T) {
+	msg := "0/1 nodes are available: 1 Insufficient cpu."
+	tests := map[string]struct {
+		event    events.Event
+		expected []string
+	}{
+		`failed scheduling pod`: {
+			event:    events.Event{Kind: "Pod", Type: config.WarningEvent, Reason: FailedScheduling, Messages: []string{msg}},
+			expected: []string{"Nodes don't have enough CPU for the pod requests. Lower the CPU requests or add nodes."},
+		},
+		`other reason`: {
+			event: events.Event{Kind: "Pod", Type: config.WarningEvent, Reason: "BackOff", Messages: []string{msg}},
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			event := test.event
+			FailedSchedulingChecker{}.Run(nil, &event)
+			if !reflect.DeepEqual(event.Recommendations, test.expected) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, event.Recommendations)
+			}
+		})
+	}
+}
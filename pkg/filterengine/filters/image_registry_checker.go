@@ -0,0 +1,140 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/filterengine"
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+const (
+	// defaultRegistry is used for the images without a registry host, e.g nginx or library/nginx
+	defaultRegistry = "docker.io"
+
+	registryWarningMsg        = "Image '%s' of container '%s' is pulled from registry '%s' which is not in the allowed registries."
+	registryRecommendationMsg = "Pod '%s' should be blocked, use images from the allowed registries: %s."
+)
+
+// ImageRegistryChecker adds warnings to the Pod create events if the container images are not from the allowed registries
+type ImageRegistryChecker struct {
+	Description string
+}
+
+// Register filter
+func init() {
+	filterengine.DefaultFilterEngine.Register(ImageRegistryChecker{
+		Description: "Checks and adds warning if container images are pulled from registries not listed in settings.allowedRegistries.",
+	})
+}
+
+// Run filters and modifies event struct
+func (f ImageRegistryChecker) Run(object interface{}, event *events.Event) {
+	if event.Kind != "Pod" || event.Type != config.CreateEvent || utils.GetObjectTypeMetaData(object).Kind == "Event" {
+		return
+	}
+
+	// load config.yaml
+	botkubeConfig, err := config.New()
+	if err != nil {
+		log.Errorf("Error in loading configuration. %s", err.Error())
+		return
+	}
+	if botkubeConfig == nil || len(botkubeConfig.Settings.AllowedRegistries) == 0 {
+		return
+	}
+
+	var pod coreV1.Pod
+	if err := utils.TransformIntoTypedObject(object.(*unstructured.Unstructured), &pod); err != nil {
+		log.Errorf("Unable to transform object type: %v, into type: %v", reflect.TypeOf(object), reflect.TypeOf(pod))
+		return
+	}
+	disallowed := disallowedImages(append(pod.Spec.InitContainers, pod.Spec.Containers...), botkubeConfig.Settings.AllowedRegistries)
+	for _, c := range disallowed {
+		event.Warnings = append(event.Warnings, fmt.Sprintf(registryWarningMsg, c.Image, c.Name, imageRegistry(c.Image)))
+	}
+	if len(disallowed) > 0 && botkubeConfig.Settings.StrictRegistries {
+		event.Recommendations = append(event.Recommendations, fmt.Sprintf(registryRecommendationMsg, pod.Namespace+"/"+pod.Name, strings.Join(botkubeConfig.Settings.AllowedRegistries, ", ")))
+	}
+	log.Debug("Image registry filter successful!")
+}
+
+// Describe filter
+func (f ImageRegistryChecker) Describe() string {
+	return f.Description
+}
+
+// AppliesTo returns kinds and event types the filter runs for
+func (f ImageRegistryChecker) AppliesTo() ([]string, []config.EventType) {
+	return []string{"Pod"}, []config.EventType{config.CreateEvent}
+}
+
+// disallowedImages returns the containers with images from registries not in allowed
+func disallowedImages(containers []coreV1.Container, allowed []string) []coreV1.Container {
+	registries := make(map[string]bool, len(allowed))
+	for _, r := range allowed {
+		registries[normalizeRegistry(r)] = true
+	}
+	var disallowed []coreV1.Container
+	for _, c := range containers {
+		if len(c.Image) > 0 && !registries[imageRegistry(c.Image)] {
+			disallowed = append(disallowed, c)
+		}
+	}
+	return disallowed
+}
+
+// imageRegistry returns the registry host of the image reference
+// Like docker, the first path component is the registry only if it has a '.' or a port, or is localhost.
+// Otherwise the image is from docker.io, e.g nginx:1.19 or bitnami/redis
+func imageRegistry(image string) string {
+	// the digest may contain ':' and is not part of the name
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	i := strings.Index(image, "/")
+	if i < 0 {
+		return defaultRegistry
+	}
+	host := image[:i]
+	if !strings.ContainsAny(host, ".:") && host != "localhost" {
+		return defaultRegistry
+	}
+	return normalizeRegistry(host)
+}
+
+// normalizeRegistry lower cases the registry host and uses docker.io for its aliases
+func normalizeRegistry(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	switch host {
+	case "index.docker.io", "registry-1.docker.io":
+		return defaultRegistry
+	}
+	return host
+}
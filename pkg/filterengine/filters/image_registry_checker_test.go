@@ -0,0 +1,83 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"reflect"
+	"testing"
+
+	coreV1 "k8s.io/api/core/v1"
+)
+
+func TestImageRegistry(t *testing.T) {
+	tests := map[string]struct {
+		image    string
+		expected string
+	}{
+		`official image`:          {"nginx", "docker.io"},
+		`official image with tag`: {"nginx:1.19", "docker.io"},
+		`docker hub user image`:   {"bitnami/redis:6.0", "docker.io"},
+		`docker hub alias`:        {"index.docker.io/library/nginx", "docker.io"},
+		`registry host`:           {"gcr.io/my-project/app:v1", "gcr.io"},
+		`registry with port`:      {"registry.example.com:5000/team/app", "registry.example.com:5000"},
+		`localhost registry`:      {"localhost/app", "localhost"},
+		`upper case host`:         {"Quay.IO/coreos/etcd", "quay.io"},
+		`image digest`:            {"nginx@sha256:4c2ff8e7d1ff27ab2e4ef7e56e7e6e36a5d0b0e4a9b1c2d3e4f5a6b7c8d9e0f1", "docker.io"},
+		`registry and digest`:     {"ghcr.io/org/app:v1@sha256:4c2ff8e7d1ff27ab2e4ef7e56e7e6e36a5d0b0e4a9b1c2d3e4f5a6b7c8d9e0f1", "ghcr.io"},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := imageRegistry(test.image); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDisallowedImages(t *testing.T) {
+	containers := []coreV1.Container{
+		{Name: "nginx", Image: "nginx:1.19"},
+		{Name: "app", Image: "gcr.io/my-project/app:v1"},
+		{Name: "proxy", Image: "quay.io/envoy/envoy:v1.18"},
+	}
+	tests := map[string]struct {
+		allowed  []string
+		expected []string
+	}{
+		`all allowed`:            {[]string{"docker.io", "gcr.io", "quay.io"}, nil},
+		`implicit docker.io`:     {[]string{"gcr.io", "quay.io"}, []string{"nginx"}},
+		`docker.io alias`:        {[]string{"index.docker.io", "GCR.io"}, []string{"proxy"}},
+		`only private registry`:  {[]string{"registry.example.com:5000"}, []string{"nginx", "app", "proxy"}},
+		`registry host mismatch`: {[]string{"my-project.gcr.io", "docker.io", "quay.io"}, []string{"app"}},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			var actual []string
+			for _, c := range disallowedImages(containers, test.allowed) {
+				actual = append(actual, c.Name)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
@@ -79,3 +79,8 @@ func (f ImageTagChecker) Run(object interface{}, event *events.Event) {
 func (f ImageTagChecker) Describe() string {
 	return f.Description
 }
+
+// AppliesTo returns kinds and event types the filter runs for
+func (f ImageTagChecker) AppliesTo() ([]string, []config.EventType) {
+	return []string{"Pod"}, []config.EventType{config.CreateEvent}
+}
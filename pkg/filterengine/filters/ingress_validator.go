@@ -93,3 +93,8 @@ func (iv IngressValidator) Run(object interface{}, event *events.Event) {
 func (iv IngressValidator) Describe() string {
 	return iv.Description
 }
+
+// AppliesTo returns kinds and event types the filter runs for
+func (iv IngressValidator) AppliesTo() ([]string, []config.EventType) {
+	return []string{"Ingress"}, []config.EventType{config.CreateEvent}
+}
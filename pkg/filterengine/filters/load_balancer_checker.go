@@ -0,0 +1,99 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"fmt"
+	"reflect"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/filterengine"
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+// LoadBalancerChecker add warnings to the event object if Service of type LoadBalancer is created
+// in the namespaces configured in settings.nonProdNamespaces
+type LoadBalancerChecker struct {
+	Description string
+}
+
+// Register filter
+func init() {
+	filterengine.DefaultFilterEngine.Register(LoadBalancerChecker{
+		Description: "Checks and adds warning if Service of type LoadBalancer is created in namespaces listed in settings.nonProdNamespaces.",
+	})
+}
+
+// Run filters and modifies event struct
+func (f LoadBalancerChecker) Run(object interface{}, event *events.Event) {
+	if event.Kind != "Service" || event.Type != config.CreateEvent || utils.GetObjectTypeMetaData(object).Kind == "Event" {
+		return
+	}
+
+	// load config.yaml
+	botkubeConfig, err := config.New()
+	if err != nil {
+		log.Errorf("Error in loading configuration. %s", err.Error())
+		return
+	}
+	if botkubeConfig == nil || len(botkubeConfig.Settings.NonProdNamespaces) == 0 {
+		return
+	}
+
+	var serviceObj coreV1.Service
+	err = utils.TransformIntoTypedObject(object.(*unstructured.Unstructured), &serviceObj)
+	if err != nil {
+		log.Errorf("Unable to transform object type: %v, into type: %v", reflect.TypeOf(object), reflect.TypeOf(serviceObj))
+		return
+	}
+
+	if warning, ok := checkLoadBalancer(serviceObj, botkubeConfig.Settings.NonProdNamespaces); ok {
+		event.Warnings = append(event.Warnings, warning)
+	}
+	log.Debug("LoadBalancer filter successful!")
+}
+
+// Describe filter
+func (f LoadBalancerChecker) Describe() string {
+	return f.Description
+}
+
+// AppliesTo returns kinds and event types the filter runs for
+func (f LoadBalancerChecker) AppliesTo() ([]string, []config.EventType) {
+	return []string{"Service"}, []config.EventType{config.CreateEvent}
+}
+
+// checkLoadBalancer returns warning if service is of type LoadBalancer and belongs to one of the non-prod namespaces
+func checkLoadBalancer(service coreV1.Service, nonProdNamespaces []string) (string, bool) {
+	if service.Spec.Type != coreV1.ServiceTypeLoadBalancer {
+		return "", false
+	}
+	for _, ns := range nonProdNamespaces {
+		if utils.MatchNamespace(ns, service.Namespace) {
+			return fmt.Sprintf("Service '%s' of type LoadBalancer in non-prod namespace '%s' should be avoided. Use NodePort or ClusterIP instead.", service.Name, service.Namespace), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"testing"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCheckLoadBalancer(t *testing.T) {
+	nonProd := []string{"dev", "test-*"}
+	service := func(namespace string, serviceType coreV1.ServiceType) coreV1.Service {
+		return coreV1.Service{
+			ObjectMeta: metaV1.ObjectMeta{Name: "my-svc", Namespace: namespace},
+			Spec:       coreV1.ServiceSpec{Type: serviceType},
+		}
+	}
+	tests := map[string]struct {
+		service  coreV1.Service
+		expected bool
+	}{
+		`LoadBalancer in non-prod namespace`:          {service("dev", coreV1.ServiceTypeLoadBalancer), true},
+		`LoadBalancer in wildcard non-prod namespace`: {service("test-a", coreV1.ServiceTypeLoadBalancer), true},
+		`LoadBalancer in prod namespace`:              {service("prod", coreV1.ServiceTypeLoadBalancer), false},
+		`ClusterIP in non-prod namespace`:             {service("dev", coreV1.ServiceTypeClusterIP), false},
+		`NodePort in non-prod namespace`:              {service("dev", coreV1.ServiceTypeNodePort), false},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			warning, actual := checkLoadBalancer(test.service, nonProd)
+			if actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+			if actual && warning != "Service 'my-svc' of type LoadBalancer in non-prod namespace '"+test.service.Namespace+"' should be avoided. Use NodePort or ClusterIP instead." {
+				t.Errorf("unexpected warning: %s", warning)
+			}
+		})
+	}
+}
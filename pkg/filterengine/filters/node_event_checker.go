@@ -82,3 +82,8 @@ func (f NodeEventsChecker) Run(object interface{}, event *events.Event) {
 func (f NodeEventsChecker) Describe() string {
 	return f.Description
 }
+
+// AppliesTo returns kinds and event types the filter runs for
+func (f NodeEventsChecker) AppliesTo() ([]string, []config.EventType) {
+	return []string{"Node"}, nil
+}
@@ -20,6 +20,8 @@
 package filters
 
 import (
+	"strings"
+
 	"github.com/infracloudio/botkube/pkg/events"
 	"github.com/infracloudio/botkube/pkg/filterengine"
 	"github.com/infracloudio/botkube/pkg/log"
@@ -85,10 +87,11 @@ func isObjectNotifDisabled(obj metaV1.ObjectMeta) bool {
 // reconfigureChannel checks annotation botkube.io/channel
 // annotation botkube.io/channel directs event notifications to channels
 // based on the channel names present in them
+// An empty annotation value keeps the default channel
 // Note: Add botkube app into the desired channel to receive notifications
 func reconfigureChannel(obj metaV1.ObjectMeta) (string, bool) {
 	// redirect messages to channels based on annotations
-	if channel, ok := obj.Annotations[ChannelAnnotation]; ok {
+	if channel := strings.TrimSpace(obj.Annotations[ChannelAnnotation]); channel != "" {
 		return channel, true
 	}
 	return "", false
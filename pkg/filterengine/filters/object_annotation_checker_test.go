@@ -22,7 +22,9 @@ package filters
 import (
 	"testing"
 
+	"github.com/infracloudio/botkube/pkg/events"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func TestIsObjectNotifDisabled(t *testing.T) {
@@ -55,14 +57,52 @@ func TestReconfigureChannel(t *testing.T) {
 		`ObjectMeta with some annotations`:    {metaV1.ObjectMeta{Annotations: map[string]string{"foo": "bar"}}, "", false},
 		`ObjectMeta with channel ""`:          {metaV1.ObjectMeta{Annotations: map[string]string{"botkube.io/channel": ""}}, "", false},
 		`ObjectMeta with channel foo-channel`: {metaV1.ObjectMeta{Annotations: map[string]string{"botkube.io/channel": "foo-channel"}}, "foo-channel", true},
+		`ObjectMeta with channel #team-x`:     {metaV1.ObjectMeta{Annotations: map[string]string{"botkube.io/channel": " #team-x "}}, "#team-x", true},
 	}
 	for name, test := range tests {
 		name, test := name, test
 		t.Run(name, func(t *testing.T) {
-			if actualChannel, actualBool := reconfigureChannel(test.objectMeta); actualBool != test.expectedBool {
-				if actualChannel != test.expectedChannel {
-					t.Errorf("expected: %+v != actual: %+v\n", test.expectedChannel, actualChannel)
-				}
+			actualChannel, actualBool := reconfigureChannel(test.objectMeta)
+			if actualBool != test.expectedBool {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expectedBool, actualBool)
+			}
+			if actualChannel != test.expectedChannel {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expectedChannel, actualChannel)
+			}
+		})
+	}
+}
+
+func TestObjectAnnotationCheckerRun(t *testing.T) {
+	tests := map[string]struct {
+		annotations     map[string]string
+		expectedSkip    bool
+		expectedChannel string
+	}{
+		`Object without annotations`: {nil, false, ""},
+		`Object with disable annotation`: {
+			map[string]string{"botkube.io/disable": "true"}, true, "",
+		},
+		`Object with channel annotation`: {
+			map[string]string{"botkube.io/channel": "#team-x"}, false, "#team-x",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{}
+			obj.SetKind("Deployment")
+			obj.SetName("nginx")
+			obj.SetNamespace("default")
+			obj.SetAnnotations(test.annotations)
+
+			event := events.Event{Kind: "Deployment", Name: "nginx", Namespace: "default"}
+			ObjectAnnotationChecker{}.Run(obj, &event)
+			if event.Skip != test.expectedSkip {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expectedSkip, event.Skip)
+			}
+			if event.Channel != test.expectedChannel {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expectedChannel, event.Channel)
 			}
 		})
 	}
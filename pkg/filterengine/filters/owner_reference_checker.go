@@ -0,0 +1,212 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/filterengine"
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+const (
+	// ownerCacheTTL is the time owner lookups are cached for, pods of a workload usually fail together
+	ownerCacheTTL = 30 * time.Second
+	// maxOwnerDepth limits the owners followed, e.g Pod -> ReplicaSet -> Deployment is 2
+	maxOwnerDepth = 5
+)
+
+// ownerGetter returns the owner references of the object referenced by ref
+type ownerGetter func(ctx context.Context, namespace string, ref metaV1.OwnerReference) ([]metaV1.OwnerReference, error)
+
+// owners caches the owner references of the objects looked up by OwnerReferenceChecker
+var owners = newOwnerCache(getOwnerReferences, ownerCacheTTL)
+
+// OwnerReferenceChecker adds the top-level controller of the Pod to the event messages
+type OwnerReferenceChecker struct {
+	Description string
+}
+
+// Register filter
+func init() {
+	filterengine.DefaultFilterEngine.Register(OwnerReferenceChecker{
+		Description: "Adds the top-level controller owning the Pod, e.g Deployment, to the event messages.",
+	})
+}
+
+// Run filters and modifies event struct
+func (f OwnerReferenceChecker) Run(object interface{}, event *events.Event) {
+	namespace, refs, err := ownerReferencesOf(object)
+	if err != nil {
+		// The involved object of k8s event can be deleted already
+		log.Debugf("Unable to get owner references of %s %s/%s. %s", event.Kind, event.Namespace, event.Name, err.Error())
+		return
+	}
+	if owner, ok := topLevelOwner(context.Background(), owners.get, namespace, refs); ok {
+		event.Messages = append(event.Messages, fmt.Sprintf("Owned by %s/%s", owner.Kind, owner.Name))
+	}
+	log.Debug("Owner reference filter successful!")
+}
+
+// Describe filter
+func (f OwnerReferenceChecker) Describe() string {
+	return f.Description
+}
+
+// AppliesTo returns kinds and event types the filter runs for
+func (f OwnerReferenceChecker) AppliesTo() ([]string, []config.EventType) {
+	return []string{"Pod"}, nil
+}
+
+// ownerReferencesOf returns the owner references of the object, or of the object involved in the k8s event
+func ownerReferencesOf(object interface{}) (string, []metaV1.OwnerReference, error) {
+	if utils.GetObjectTypeMetaData(object).Kind != "Event" {
+		objectMeta := utils.GetObjectMetaData(object)
+		return objectMeta.Namespace, objectMeta.OwnerReferences, nil
+	}
+	unstructuredObj, ok := object.(*unstructured.Unstructured)
+	if !ok {
+		return "", nil, fmt.Errorf("Unexpected object type %v", reflect.TypeOf(object))
+	}
+	var eventObj coreV1.Event
+	if err := utils.TransformIntoTypedObject(unstructuredObj, &eventObj); err != nil {
+		return "", nil, err
+	}
+	involved := eventObj.InvolvedObject
+	refs, err := owners.get(context.Background(), involved.Namespace, metaV1.OwnerReference{
+		APIVersion: involved.APIVersion,
+		Kind:       involved.Kind,
+		Name:       involved.Name,
+	})
+	return involved.Namespace, refs, err
+}
+
+// topLevelOwner follows the controller references up to the object which isn't controlled by anything
+// The last owner found is returned if a lookup fails, e.g the ReplicaSet of a Pod is already deleted
+func topLevelOwner(ctx context.Context, get ownerGetter, namespace string, refs []metaV1.OwnerReference) (metaV1.OwnerReference, bool) {
+	owner, ok := controllerRef(refs)
+	if !ok {
+		return metaV1.OwnerReference{}, false
+	}
+	for i := 0; i < maxOwnerDepth; i++ {
+		parentRefs, err := get(ctx, namespace, owner)
+		if err != nil {
+			log.Debugf("Unable to get owner %s/%s. %s", owner.Kind, owner.Name, err.Error())
+			break
+		}
+		parent, ok := controllerRef(parentRefs)
+		if !ok {
+			break
+		}
+		owner = parent
+	}
+	return owner, true
+}
+
+// controllerRef returns the managing controller reference, falling back to the first owner
+func controllerRef(refs []metaV1.OwnerReference) (metaV1.OwnerReference, bool) {
+	for _, ref := range refs {
+		if ref.Controller != nil && *ref.Controller {
+			return ref, true
+		}
+	}
+	if len(refs) > 0 {
+		return refs[0], true
+	}
+	return metaV1.OwnerReference{}, false
+}
+
+// getOwnerReferences gets the object referenced by ref from the API server
+func getOwnerReferences(ctx context.Context, namespace string, ref metaV1.OwnerReference) ([]metaV1.OwnerReference, error) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := utils.Mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: ref.Kind}, gv.Version)
+	if err != nil {
+		return nil, err
+	}
+	resource := utils.DynamicKubeClient.Resource(mapping.Resource)
+	var obj *unstructured.Unstructured
+	if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		obj, err = resource.Get(ctx, ref.Name, metaV1.GetOptions{})
+	} else {
+		obj, err = resource.Namespace(namespace).Get(ctx, ref.Name, metaV1.GetOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return obj.GetOwnerReferences(), nil
+}
+
+// ownerCache stores the owner references looked up for ttl, failed lookups are cached too
+type ownerCache struct {
+	mu      sync.Mutex
+	lookup  ownerGetter
+	ttl     time.Duration
+	entries map[string]ownerEntry
+	now     func() time.Time
+}
+
+type ownerEntry struct {
+	refs    []metaV1.OwnerReference
+	err     error
+	expires time.Time
+}
+
+func newOwnerCache(lookup ownerGetter, ttl time.Duration) *ownerCache {
+	return &ownerCache{lookup: lookup, ttl: ttl, entries: map[string]ownerEntry{}, now: time.Now}
+}
+
+// get returns the cached owner references of the object, it looks them up if missing or expired
+func (c *ownerCache) get(ctx context.Context, namespace string, ref metaV1.OwnerReference) ([]metaV1.OwnerReference, error) {
+	key := fmt.Sprintf("%s/%s/%s/%s", ref.APIVersion, ref.Kind, namespace, ref.Name)
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expires) {
+		return entry.refs, entry.err
+	}
+
+	refs, err := c.lookup(ctx, namespace, ref)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = ownerEntry{refs: refs, err: err, expires: now.Add(c.ttl)}
+	return refs, err
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+func controlledBy(apiVersion, kind, name string) []metaV1.OwnerReference {
+	controller := true
+	return []metaV1.OwnerReference{{APIVersion: apiVersion, Kind: kind, Name: name, Controller: &controller}}
+}
+
+// fakeOwners returns owner references of the objects keyed by kind/name and counts the lookups
+type fakeOwners struct {
+	refs    map[string][]metaV1.OwnerReference
+	lookups int
+}
+
+func (f *fakeOwners) get(ctx context.Context, namespace string, ref metaV1.OwnerReference) ([]metaV1.OwnerReference, error) {
+	f.lookups++
+	refs, ok := f.refs[ref.Kind+"/"+ref.Name]
+	if !ok {
+		return nil, fmt.Errorf("%s %q not found", ref.Kind, ref.Name)
+	}
+	return refs, nil
+}
+
+func TestOwnerReferenceCheckerRun(t *testing.T) {
+	fake := &fakeOwners{refs: map[string][]metaV1.OwnerReference{
+		"ReplicaSet/nginx-5d8f": controlledBy("apps/v1", "Deployment", "nginx"),
+		"Deployment/nginx":      nil,
+		"StatefulSet/db":        nil,
+		"Pod/nginx-5d8f-x2k":    controlledBy("apps/v1", "ReplicaSet", "nginx-5d8f"),
+		"Job/backup-27000":      controlledBy("batch/v1", "CronJob", "backup"),
+		"CronJob/backup":        nil,
+	}}
+	defer func(c *ownerCache) { owners = c }(owners)
+
+	tests := map[string]struct {
+		object   map[string]interface{}
+		expected []string
+	}{
+		`pod owned by deployment`: {
+			object:   podObject("nginx-5d8f-x2k", controlledBy("apps/v1", "ReplicaSet", "nginx-5d8f")),
+			expected: []string{"Owned by Deployment/nginx"},
+		},
+		`pod owned by statefulset`: {
+			object:   podObject("db-0", controlledBy("apps/v1", "StatefulSet", "db")),
+			expected: []string{"Owned by StatefulSet/db"},
+		},
+		`pod owned by cronjob`: {
+			object:   podObject("backup-27000-abc", controlledBy("batch/v1", "Job", "backup-27000")),
+			expected: []string{"Owned by CronJob/backup"},
+		},
+		`owner deleted`: {
+			object:   podObject("web-1", controlledBy("apps/v1", "ReplicaSet", "web-7f9c")),
+			expected: []string{"Owned by ReplicaSet/web-7f9c"},
+		},
+		`pod without owner`: {
+			object: podObject("standalone", nil),
+		},
+		`k8s event of owned pod`: {
+			object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Event",
+				"metadata":   map[string]interface{}{"name": "nginx-5d8f-x2k.16b", "namespace": "default"},
+				"involvedObject": map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"name":       "nginx-5d8f-x2k",
+					"namespace":  "default",
+				},
+				"reason": "BackOff",
+			},
+			expected: []string{"Owned by Deployment/nginx"},
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			owners = newOwnerCache(fake.get, ownerCacheTTL)
+			event := events.Event{Kind: "Pod", Namespace: "default", Type: config.ErrorEvent}
+			OwnerReferenceChecker{}.Run(&unstructured.Unstructured{Object: test.object}, &event)
+			if !reflect.DeepEqual(event.Messages, test.expected) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, event.Messages)
+			}
+		})
+	}
+}
+
+func podObject(name string, refs []metaV1.OwnerReference) map[string]interface{} {
+	meta := map[string]interface{}{"name": name, "namespace": "default"}
+	if len(refs) > 0 {
+		var owners []interface{}
+		for _, ref := range refs {
+			owners = append(owners, map[string]interface{}{
+				"apiVersion": ref.APIVersion,
+				"kind":       ref.Kind,
+				"name":       ref.Name,
+				"uid":        "",
+				"controller": *ref.Controller,
+			})
+		}
+		meta["ownerReferences"] = owners
+	}
+	return map[string]interface{}{"apiVersion": "v1", "kind": "Pod", "metadata": meta}
+}
+
+func TestOwnerCache(t *testing.T) {
+	fake := &fakeOwners{refs: map[string][]metaV1.OwnerReference{
+		"ReplicaSet/nginx-5d8f": controlledBy("apps/v1", "Deployment", "nginx"),
+	}}
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	cache := newOwnerCache(fake.get, 30*time.Second)
+	cache.now = func() time.Time { return now }
+
+	rs := metaV1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "nginx-5d8f"}
+	missing := metaV1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "deleted"}
+	steps := []struct {
+		ref     metaV1.OwnerReference
+		after   time.Duration
+		lookups int
+	}{
+		{rs, 0, 1},
+		{rs, 29 * time.Second, 1},
+		{missing, 0, 2},
+		{missing, 0, 2},
+		{rs, time.Second, 3},
+	}
+	for i, step := range steps {
+		now = now.Add(step.after)
+		refs, err := cache.get(context.Background(), "default", step.ref)
+		if step.ref == rs && (err != nil || len(refs) != 1 || refs[0].Name != "nginx") {
+			t.Errorf("step %d: unexpected owner references %+v, error %v", i, refs, err)
+		}
+		if step.ref == missing && err == nil {
+			t.Errorf("step %d: expected error for missing owner", i)
+		}
+		if fake.lookups != step.lookups {
+			t.Errorf("step %d: expected: %+v != actual: %+v\n", i, step.lookups, fake.lookups)
+		}
+	}
+}
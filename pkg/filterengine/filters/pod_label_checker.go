@@ -60,3 +60,8 @@ func (f PodLabelChecker) Run(object interface{}, event *events.Event) {
 func (f PodLabelChecker) Describe() string {
 	return f.Description
 }
+
+// AppliesTo returns kinds and event types the filter runs for
+func (f PodLabelChecker) AppliesTo() ([]string, []config.EventType) {
+	return []string{"Pod"}, []config.EventType{config.CreateEvent}
+}
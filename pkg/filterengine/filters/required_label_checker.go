@@ -0,0 +1,96 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/filterengine"
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+// RequiredLabelChecker add recommendations to the event object if resource created without the labels
+// configured in settings.requiredLabels
+type RequiredLabelChecker struct {
+	Description string
+}
+
+// Register filter
+func init() {
+	filterengine.DefaultFilterEngine.Register(RequiredLabelChecker{
+		Description: "Checks and adds recommendations if labels listed in settings.requiredLabels are missing in the Deployment, Pod or Service specs.",
+	})
+}
+
+// Run filters and modifies event struct
+func (f RequiredLabelChecker) Run(object interface{}, event *events.Event) {
+	if event.Type != config.CreateEvent || utils.GetObjectTypeMetaData(object).Kind == "Event" {
+		return
+	}
+	switch event.Kind {
+	case "Deployment", "Pod", "Service":
+	default:
+		return
+	}
+
+	// load config.yaml
+	botkubeConfig, err := config.New()
+	if err != nil {
+		log.Errorf("Error in loading configuration. %s", err.Error())
+		return
+	}
+	if botkubeConfig == nil || len(botkubeConfig.Settings.RequiredLabels) == 0 {
+		return
+	}
+
+	objectMeta := utils.GetObjectMetaData(object)
+	if missing := missingLabels(botkubeConfig.Settings.RequiredLabels, objectMeta.Labels); len(missing) > 0 {
+		event.Recommendations = append(event.Recommendations, fmt.Sprintf("%s '%s' is missing required labels: %s.", strings.ToLower(event.Kind), objectMeta.Name, strings.Join(missing, ", ")))
+	}
+	log.Debug("Required label filter successful!")
+}
+
+// Describe filter
+func (f RequiredLabelChecker) Describe() string {
+	return f.Description
+}
+
+// AppliesTo returns kinds and event types the filter runs for
+func (f RequiredLabelChecker) AppliesTo() ([]string, []config.EventType) {
+	return []string{"Deployment", "Pod", "Service"}, []config.EventType{config.CreateEvent}
+}
+
+// missingLabels returns the required labels which are not present in labels
+func missingLabels(required []string, labels map[string]string) []string {
+	var missing []string
+	for _, l := range required {
+		if len(l) == 0 {
+			continue
+		}
+		if _, ok := labels[l]; !ok {
+			missing = append(missing, l)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMissingLabels(t *testing.T) {
+	tests := map[string]struct {
+		required []string
+		labels   map[string]string
+		expected []string
+	}{
+		`no required labels`:         {nil, map[string]string{"team": "a"}, nil},
+		`all required labels exist`:  {[]string{"app.kubernetes.io/name", "team"}, map[string]string{"app.kubernetes.io/name": "foo", "team": "a"}, nil},
+		`some required labels exist`: {[]string{"app.kubernetes.io/name", "team"}, map[string]string{"team": "a"}, []string{"app.kubernetes.io/name"}},
+		`no labels in object`:        {[]string{"app.kubernetes.io/name", "team"}, nil, []string{"app.kubernetes.io/name", "team"}},
+		`empty required label`:       {[]string{""}, nil, nil},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := missingLabels(test.required, test.labels); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
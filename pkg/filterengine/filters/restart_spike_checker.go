@@ -0,0 +1,152 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/filterengine"
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+const (
+	defaultRestartSpikeThreshold = 3
+	defaultRestartSpikeWindow    = 10 * time.Minute
+	restartSpikeWarningMsg       = "Container '%s' of pod '%s' restarted %d times in the last %s."
+)
+
+// restarts tracks the restart counts of the containers seen by RestartSpikeChecker
+var restarts = &restartTracker{}
+
+// RestartSpikeChecker adds warning to the Pod update events if a container restarted too many times within the window
+type RestartSpikeChecker struct {
+	Description string
+}
+
+// Register filter
+func init() {
+	filterengine.DefaultFilterEngine.Register(RestartSpikeChecker{
+		Description: "Checks and adds warning if a container restarted settings.restartSpike.threshold times or more within the window.",
+	})
+}
+
+// Run filters and modifies event struct
+func (f RestartSpikeChecker) Run(object interface{}, event *events.Event) {
+	if event.Type != config.UpdateEvent || event.Kind != "Pod" || utils.GetObjectTypeMetaData(object).Kind == "Event" {
+		return
+	}
+	var pod coreV1.Pod
+	if err := utils.TransformIntoTypedObject(object.(*unstructured.Unstructured), &pod); err != nil {
+		log.Errorf("Unable to transform object type: %v, into type: %v", reflect.TypeOf(object), reflect.TypeOf(pod))
+		return
+	}
+
+	// load config.yaml
+	botkubeConfig, err := config.New()
+	if err != nil {
+		log.Errorf("Error in loading configuration. %s", err.Error())
+		return
+	}
+	threshold, window := restartSpikeSettings(botkubeConfig)
+	now := time.Now()
+	for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		key := fmt.Sprintf("%s/%s", pod.UID, status.Name)
+		if delta, ok := restarts.observe(key, status.RestartCount, threshold, window, now); ok {
+			event.Warnings = append(event.Warnings, fmt.Sprintf(restartSpikeWarningMsg, status.Name, pod.Namespace+"/"+pod.Name, delta, window))
+		}
+	}
+	log.Debug("Restart spike filter successful!")
+}
+
+// Describe filter
+func (f RestartSpikeChecker) Describe() string {
+	return f.Description
+}
+
+// AppliesTo returns kinds and event types the filter runs for
+func (f RestartSpikeChecker) AppliesTo() ([]string, []config.EventType) {
+	return []string{"Pod"}, []config.EventType{config.UpdateEvent}
+}
+
+// restartSpikeSettings returns settings.restartSpike with the defaults for the values not set
+func restartSpikeSettings(c *config.Config) (int32, time.Duration) {
+	threshold, window := int32(defaultRestartSpikeThreshold), defaultRestartSpikeWindow
+	if c == nil {
+		return threshold, window
+	}
+	if c.Settings.RestartSpike.Threshold > 0 {
+		threshold = c.Settings.RestartSpike.Threshold
+	}
+	if c.Settings.RestartSpike.Window > 0 {
+		window = c.Settings.RestartSpike.Window
+	}
+	return threshold, window
+}
+
+// restartTracker stores the restart counts seen for each container within the window
+type restartTracker struct {
+	mu      sync.Mutex
+	samples map[string][]restartSample
+}
+
+type restartSample struct {
+	count int32
+	at    time.Time
+}
+
+// observe records the restart count of the container and returns the restarts within the window if they reach threshold
+// The samples of the container are reset after a spike, so it is warned about once
+func (r *restartTracker) observe(key string, count, threshold int32, window time.Duration, now time.Time) (int32, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.samples == nil {
+		r.samples = map[string][]restartSample{}
+	}
+	// Containers of deleted pods are removed once they were not seen for the window
+	for k, samples := range r.samples {
+		if k != key && now.Sub(samples[len(samples)-1].at) > window {
+			delete(r.samples, k)
+		}
+	}
+
+	samples := r.samples[key]
+	// The oldest sample within the window is the baseline
+	for len(samples) > 0 && now.Sub(samples[0].at) > window {
+		samples = samples[1:]
+	}
+	samples = append(samples, restartSample{count: count, at: now})
+
+	delta := count - samples[0].count
+	if delta >= threshold {
+		r.samples[key] = []restartSample{{count: count, at: now}}
+		return delta, true
+	}
+	r.samples[key] = samples
+	return delta, false
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+)
+
+func TestRestartTrackerObserve(t *testing.T) {
+	type observation struct {
+		after time.Duration
+		count int32
+	}
+	tests := map[string]struct {
+		observations []observation
+		expected     []int32
+	}{
+		`restart spike`: {
+			observations: []observation{{0, 1}, {time.Minute, 2}, {2 * time.Minute, 3}, {3 * time.Minute, 4}, {4 * time.Minute, 5}},
+			// Warned once when the threshold is reached, counting starts again from there
+			expected: []int32{3},
+		},
+		`steady restarts`: {
+			observations: []observation{{0, 1}, {4 * time.Minute, 2}, {8 * time.Minute, 3}, {12 * time.Minute, 4}, {16 * time.Minute, 5}, {20 * time.Minute, 6}},
+			expected:     nil,
+		},
+		`restarts before the window`: {
+			observations: []observation{{0, 10}, {11 * time.Minute, 14}},
+			expected:     nil,
+		},
+		`spike after steady state`: {
+			observations: []observation{{0, 1}, {6 * time.Minute, 2}, {12 * time.Minute, 3}, {13 * time.Minute, 6}},
+			expected:     []int32{4},
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			tracker := &restartTracker{}
+			start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+			var actual []int32
+			for _, o := range test.observations {
+				if delta, ok := tracker.observe("uid/nginx", o.count, 3, 10*time.Minute, start.Add(o.after)); ok {
+					actual = append(actual, delta)
+				}
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRestartTrackerForgetsDeletedContainers(t *testing.T) {
+	tracker := &restartTracker{}
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker.observe("deleted/nginx", 1, 3, 10*time.Minute, start)
+	tracker.observe("running/nginx", 1, 3, 10*time.Minute, start.Add(11*time.Minute))
+	if _, ok := tracker.samples["deleted/nginx"]; ok {
+		t.Errorf("expected samples of deleted/nginx to be removed")
+	}
+}
+
+func TestRestartSpikeSettings(t *testing.T) {
+	tests := map[string]struct {
+		settings          config.RestartSpike
+		expectedThreshold int32
+		expectedWindow    time.Duration
+	}{
+		`defaults`:         {config.RestartSpike{}, 3, 10 * time.Minute},
+		`configured`:       {config.RestartSpike{Threshold: 5, Window: time.Hour}, 5, time.Hour},
+		`negative ignored`: {config.RestartSpike{Threshold: -1, Window: -time.Hour}, 3, 10 * time.Minute},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			c := &config.Config{Settings: config.Settings{RestartSpike: test.settings}}
+			threshold, window := restartSpikeSettings(c)
+			if threshold != test.expectedThreshold || window != test.expectedWindow {
+				t.Errorf("expected: %+v %+v != actual: %+v %+v\n", test.expectedThreshold, test.expectedWindow, threshold, window)
+			}
+		})
+	}
+}
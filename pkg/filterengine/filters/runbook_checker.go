@@ -0,0 +1,84 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/filterengine"
+	"github.com/infracloudio/botkube/pkg/log"
+)
+
+// RunbookChecker adds runbook link to the event recommendations if the event reason is listed in settings.runbooks
+type RunbookChecker struct {
+	Description string
+}
+
+// Register filter
+func init() {
+	filterengine.DefaultFilterEngine.Register(RunbookChecker{
+		Description: "Adds runbook link to the recommendations if event reason is listed in settings.runbooks.",
+	})
+}
+
+// Run filters and modifies event struct
+func (f RunbookChecker) Run(object interface{}, event *events.Event) {
+	if len(event.Reason) == 0 {
+		return
+	}
+
+	// load config.yaml
+	botkubeConfig, err := config.New()
+	if err != nil {
+		log.Errorf("Error in loading configuration. %s", err.Error())
+		return
+	}
+	if botkubeConfig == nil || len(botkubeConfig.Settings.Runbooks) == 0 {
+		return
+	}
+
+	if url := runbookURL(botkubeConfig.Settings.Runbooks, event.Kind, event.Reason); len(url) > 0 {
+		event.Recommendations = append(event.Recommendations, fmt.Sprintf("Runbook: %s", url))
+	}
+	log.Debug("Runbook filter successful!")
+}
+
+// Describe filter
+func (f RunbookChecker) Describe() string {
+	return f.Description
+}
+
+// runbookURL returns the runbook configured for the kind and reason, falling back to the one for the reason only
+// Keys are matched case-insensitively
+func runbookURL(runbooks map[string]string, kind, reason string) string {
+	var reasonURL string
+	for key, url := range runbooks {
+		if strings.EqualFold(key, kind+"/"+reason) {
+			return url
+		}
+		if strings.EqualFold(key, reason) {
+			reasonURL = url
+		}
+	}
+	return reasonURL
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"testing"
+)
+
+func TestRunbookURL(t *testing.T) {
+	runbooks := map[string]string{
+		"BackOff":              "https://runbooks.example.com/backoff",
+		"Pod/FailedScheduling": "https://runbooks.example.com/pod-scheduling",
+		"FailedScheduling":     "https://runbooks.example.com/scheduling",
+		"Deployment/Unhealthy": "https://runbooks.example.com/unhealthy",
+	}
+	tests := map[string]struct {
+		kind     string
+		reason   string
+		expected string
+	}{
+		`reason matches`:                  {"Pod", "BackOff", "https://runbooks.example.com/backoff"},
+		`reason matches case-insensitive`: {"Pod", "backoff", "https://runbooks.example.com/backoff"},
+		`kind and reason take precedence`: {"Pod", "FailedScheduling", "https://runbooks.example.com/pod-scheduling"},
+		`reason matches for other kind`:   {"Job", "FailedScheduling", "https://runbooks.example.com/scheduling"},
+		`reason does not match`:           {"Pod", "Killing", ""},
+		`kind and reason do not match`:    {"Pod", "Unhealthy", ""},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := runbookURL(runbooks, test.kind, test.reason); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
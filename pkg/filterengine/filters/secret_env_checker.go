@@ -0,0 +1,93 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/filterengine"
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+const secretEnvMsg = "Pod '%s' sources Secrets as environment variables: %s. Please mount the Secrets as volumes instead."
+
+// SecretEnvChecker adds warning to the Pod create events if containers get Secrets as environment variables
+type SecretEnvChecker struct {
+	Description string
+}
+
+// Register filter
+func init() {
+	filterengine.DefaultFilterEngine.Register(SecretEnvChecker{
+		Description: "Checks and adds warning if Pod containers source Secrets as environment variables.",
+	})
+}
+
+// Run filters and modifies event struct
+func (f SecretEnvChecker) Run(object interface{}, event *events.Event) {
+	if event.Kind != "Pod" || event.Type != config.CreateEvent || utils.GetObjectTypeMetaData(object).Kind == "Event" {
+		return
+	}
+	var pod coreV1.Pod
+	if err := utils.TransformIntoTypedObject(object.(*unstructured.Unstructured), &pod); err != nil {
+		log.Errorf("Unable to transform object type: %v, into type: %v", reflect.TypeOf(object), reflect.TypeOf(pod))
+		return
+	}
+	if refs := secretEnvRefs(pod.Spec); len(refs) != 0 {
+		event.Warnings = append(event.Warnings, fmt.Sprintf(secretEnvMsg, pod.Name, strings.Join(refs, ", ")))
+	}
+	log.Debug("Secret env filter successful!")
+}
+
+// Describe filter
+func (f SecretEnvChecker) Describe() string {
+	return f.Description
+}
+
+// AppliesTo returns kinds and event types the filter runs for
+func (f SecretEnvChecker) AppliesTo() ([]string, []config.EventType) {
+	return []string{"Pod"}, []config.EventType{config.CreateEvent}
+}
+
+// secretEnvRefs returns the env vars set from secretKeyRef and the Secrets loaded with envFrom in init and app containers
+func secretEnvRefs(spec coreV1.PodSpec) []string {
+	var refs []string
+	for _, c := range append(append([]coreV1.Container{}, spec.InitContainers...), spec.Containers...) {
+		for _, env := range c.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				refs = append(refs, fmt.Sprintf("%s from Secret '%s' in container '%s'", env.Name, env.ValueFrom.SecretKeyRef.Name, c.Name))
+			}
+		}
+		for _, from := range c.EnvFrom {
+			if from.SecretRef != nil {
+				refs = append(refs, fmt.Sprintf("all keys of Secret '%s' in container '%s'", from.SecretRef.Name, c.Name))
+			}
+		}
+	}
+	return refs
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"reflect"
+	"testing"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+func secretKeyEnv(name, secret string) coreV1.EnvVar {
+	return coreV1.EnvVar{Name: name, ValueFrom: &coreV1.EnvVarSource{
+		SecretKeyRef: &coreV1.SecretKeySelector{LocalObjectReference: coreV1.LocalObjectReference{Name: secret}, Key: "password"},
+	}}
+}
+
+func TestSecretEnvRefs(t *testing.T) {
+	tests := map[string]struct {
+		spec     coreV1.PodSpec
+		expected []string
+	}{
+		`no env`: {
+			spec: coreV1.PodSpec{Containers: []coreV1.Container{{Name: "api"}}},
+		},
+		`plain and config map env`: {
+			spec: coreV1.PodSpec{Containers: []coreV1.Container{{
+				Name: "api",
+				Env: []coreV1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "MODE", ValueFrom: &coreV1.EnvVarSource{
+					ConfigMapKeyRef: &coreV1.ConfigMapKeySelector{LocalObjectReference: coreV1.LocalObjectReference{Name: "app"}, Key: "mode"},
+				}}},
+				EnvFrom: []coreV1.EnvFromSource{{ConfigMapRef: &coreV1.ConfigMapEnvSource{LocalObjectReference: coreV1.LocalObjectReference{Name: "app"}}}},
+			}}},
+		},
+		`secret key ref`: {
+			spec: coreV1.PodSpec{Containers: []coreV1.Container{{
+				Name: "api",
+				Env:  []coreV1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, secretKeyEnv("DB_PASSWORD", "db")},
+			}}},
+			expected: []string{"DB_PASSWORD from Secret 'db' in container 'api'"},
+		},
+		`env from secret`: {
+			spec: coreV1.PodSpec{Containers: []coreV1.Container{{
+				Name:    "api",
+				EnvFrom: []coreV1.EnvFromSource{{SecretRef: &coreV1.SecretEnvSource{LocalObjectReference: coreV1.LocalObjectReference{Name: "app-secrets"}}}},
+			}}},
+			expected: []string{"all keys of Secret 'app-secrets' in container 'api'"},
+		},
+		`both forms in init and app containers`: {
+			spec: coreV1.PodSpec{
+				InitContainers: []coreV1.Container{{Name: "migrate", Env: []coreV1.EnvVar{secretKeyEnv("DB_PASSWORD", "db")}}},
+				Containers: []coreV1.Container{{
+					Name:    "api",
+					Env:     []coreV1.EnvVar{secretKeyEnv("API_TOKEN", "api")},
+					EnvFrom: []coreV1.EnvFromSource{{SecretRef: &coreV1.SecretEnvSource{LocalObjectReference: coreV1.LocalObjectReference{Name: "app-secrets"}}}},
+				}},
+			},
+			expected: []string{
+				"DB_PASSWORD from Secret 'db' in container 'migrate'",
+				"API_TOKEN from Secret 'api' in container 'api'",
+				"all keys of Secret 'app-secrets' in container 'api'",
+			},
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := secretEnvRefs(test.spec); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSecretEnvCheckerRun(t *testing.T) {
+	spec := coreV1.PodSpec{Containers: []coreV1.Container{{
+		Name:    "api",
+		Env:     []coreV1.EnvVar{secretKeyEnv("DB_PASSWORD", "db")},
+		EnvFrom: []coreV1.EnvFromSource{{SecretRef: &coreV1.SecretEnvSource{LocalObjectReference: coreV1.LocalObjectReference{Name: "app-secrets"}}}},
+	}}}
+	tests := map[string]struct {
+		spec      coreV1.PodSpec
+		eventType config.EventType
+		expected  []string
+	}{
+		`secrets on create`:    {spec, config.CreateEvent, []string{"Pod 'nginx' sources Secrets as environment variables: DB_PASSWORD from Secret 'db' in container 'api', all keys of Secret 'app-secrets' in container 'api'. Please mount the Secrets as volumes instead."}},
+		`no secrets on create`: {coreV1.PodSpec{Containers: []coreV1.Container{{Name: "api"}}}, config.CreateEvent, nil},
+		`secrets on update`:    {spec, config.UpdateEvent, nil},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			pod := &coreV1.Pod{
+				TypeMeta:   metaV1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+				ObjectMeta: metaV1.ObjectMeta{Name: "nginx", Namespace: "default"},
+				Spec:       test.spec,
+			}
+			obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+			if err != nil {
+				t.Fatal(err)
+			}
+			event := events.Event{Kind: "Pod", Name: "nginx", Namespace: "default", Type: test.eventType}
+			SecretEnvChecker{}.Run(&unstructured.Unstructured{Object: obj}, &event)
+			if !reflect.DeepEqual(event.Warnings, test.expected) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, event.Warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"fmt"
+	"reflect"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/filterengine"
+	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/utils"
+)
+
+const defaultServiceAccountMsg = "Pod '%s' uses the default ServiceAccount. Please use a dedicated ServiceAccount with the least privileges the pod needs."
+
+// ServiceAccountChecker adds warning to the Pod create events if the pod runs with the default ServiceAccount
+type ServiceAccountChecker struct {
+	Description string
+}
+
+// Register filter
+func init() {
+	filterengine.DefaultFilterEngine.Register(ServiceAccountChecker{
+		Description: "Checks and adds warning if Pod uses the default ServiceAccount.",
+	})
+}
+
+// Run filters and modifies event struct
+func (f ServiceAccountChecker) Run(object interface{}, event *events.Event) {
+	if event.Kind != "Pod" || event.Type != config.CreateEvent || utils.GetObjectTypeMetaData(object).Kind == "Event" {
+		return
+	}
+	var pod coreV1.Pod
+	if err := utils.TransformIntoTypedObject(object.(*unstructured.Unstructured), &pod); err != nil {
+		log.Errorf("Unable to transform object type: %v, into type: %v", reflect.TypeOf(object), reflect.TypeOf(pod))
+		return
+	}
+	if usesDefaultServiceAccount(pod.Spec) {
+		event.Warnings = append(event.Warnings, fmt.Sprintf(defaultServiceAccountMsg, pod.Name))
+	}
+	log.Debug("Service account filter successful!")
+}
+
+// Describe filter
+func (f ServiceAccountChecker) Describe() string {
+	return f.Description
+}
+
+// AppliesTo returns kinds and event types the filter runs for
+func (f ServiceAccountChecker) AppliesTo() ([]string, []config.EventType) {
+	return []string{"Pod"}, []config.EventType{config.CreateEvent}
+}
+
+// usesDefaultServiceAccount returns true if the pod spec doesn't set a ServiceAccount or sets the default one
+// The deprecated serviceAccount field is used if serviceAccountName is empty, like the API server does
+func usesDefaultServiceAccount(spec coreV1.PodSpec) bool {
+	name := spec.ServiceAccountName
+	if len(name) == 0 {
+		name = spec.DeprecatedServiceAccount
+	}
+	return len(name) == 0 || name == "default"
+}
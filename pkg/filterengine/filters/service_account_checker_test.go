@@ -0,0 +1,87 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filters
+
+import (
+	"reflect"
+	"testing"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+func TestUsesDefaultServiceAccount(t *testing.T) {
+	tests := map[string]struct {
+		spec     coreV1.PodSpec
+		expected bool
+	}{
+		`empty service account`:      {coreV1.PodSpec{}, true},
+		`default service account`:    {coreV1.PodSpec{ServiceAccountName: "default"}, true},
+		`named service account`:      {coreV1.PodSpec{ServiceAccountName: "nginx"}, false},
+		`deprecated field`:           {coreV1.PodSpec{DeprecatedServiceAccount: "nginx"}, false},
+		`deprecated default`:         {coreV1.PodSpec{DeprecatedServiceAccount: "default"}, true},
+		`name over deprecated field`: {coreV1.PodSpec{ServiceAccountName: "default", DeprecatedServiceAccount: "nginx"}, true},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := usesDefaultServiceAccount(test.spec); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestServiceAccountCheckerRun(t *testing.T) {
+	tests := map[string]struct {
+		serviceAccount string
+		eventType      config.EventType
+		expected       []string
+	}{
+		`default on create`: {"default", config.CreateEvent, []string{"Pod 'nginx' uses the default ServiceAccount. Please use a dedicated ServiceAccount with the least privileges the pod needs."}},
+		`empty on create`:   {"", config.CreateEvent, []string{"Pod 'nginx' uses the default ServiceAccount. Please use a dedicated ServiceAccount with the least privileges the pod needs."}},
+		`named on create`:   {"nginx", config.CreateEvent, nil},
+		`default on update`: {"default", config.UpdateEvent, nil},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			pod := &coreV1.Pod{
+				TypeMeta:   metaV1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+				ObjectMeta: metaV1.ObjectMeta{Name: "nginx", Namespace: "default"},
+				Spec:       coreV1.PodSpec{ServiceAccountName: test.serviceAccount},
+			}
+			obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+			if err != nil {
+				t.Fatal(err)
+			}
+			event := events.Event{Kind: "Pod", Name: "nginx", Namespace: "default", Type: test.eventType}
+			ServiceAccountChecker{}.Run(&unstructured.Unstructured{Object: obj}, &event)
+			if !reflect.DeepEqual(event.Warnings, test.expected) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, event.Warnings)
+			}
+		})
+	}
+}
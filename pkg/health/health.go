@@ -0,0 +1,73 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/infracloudio/botkube/pkg/notify"
+)
+
+// Server exposes liveness and readiness endpoints
+type Server struct {
+	Port string
+	// Notifiers returns the notifiers in use, they are replaced on config reload
+	Notifiers func() []notify.Notifier
+}
+
+// NewServer returns new health Server object
+func NewServer(port string, notifiers func() []notify.Notifier) *Server {
+	return &Server{
+		Port:      port,
+		Notifiers: notifiers,
+	}
+}
+
+// Start serves /healthz and /readyz endpoints
+func (s *Server) Start() error {
+	return http.ListenAndServe(":"+s.Port, s.handler())
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	// Process is alive if it is able to serve the request
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	})
+	mux.HandleFunc("/readyz", s.ready)
+	return mux
+}
+
+// ready responds with 503 if any of the notifiers is not ready
+func (s *Server) ready(w http.ResponseWriter, r *http.Request) {
+	var failed []string
+	for _, n := range s.Notifiers() {
+		if err := n.Ready(); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", notify.Name(n), err.Error()))
+		}
+	}
+	if len(failed) != 0 {
+		http.Error(w, strings.Join(failed, "\n"), http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprint(w, "ok")
+}
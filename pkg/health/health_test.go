@@ -0,0 +1,61 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/notify"
+)
+
+type fakeNotifier struct {
+	err error
+}
+
+func (f fakeNotifier) SendEvent(events.Event) error { return nil }
+func (f fakeNotifier) SendMessage(string) error     { return nil }
+func (f fakeNotifier) Ready() error                 { return f.err }
+
+func TestEndpoints(t *testing.T) {
+	tests := map[string]struct {
+		path      string
+		notifiers []notify.Notifier
+		expected  int
+	}{
+		`healthz with failing notifier`: {"/healthz", []notify.Notifier{fakeNotifier{errors.New("invalid_auth")}}, http.StatusOK},
+		`readyz without notifiers`:      {"/readyz", nil, http.StatusOK},
+		`readyz with ready notifiers`:   {"/readyz", []notify.Notifier{fakeNotifier{}, fakeNotifier{}}, http.StatusOK},
+		`readyz with failing notifier`:  {"/readyz", []notify.Notifier{fakeNotifier{}, fakeNotifier{errors.New("invalid_auth")}}, http.StatusServiceUnavailable},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			NewServer("", func() []notify.Notifier { return test.notifiers }).handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.path, nil))
+			if rec.Code != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, rec.Code)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@
 package log
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/sirupsen/logrus"
@@ -111,3 +112,24 @@ func Fatalf(format string, v ...interface{}) {
 func Panicf(format string, v ...interface{}) {
 	log.Panicf(format, v...)
 }
+
+// SetLevel changes the log level at runtime. Only debug, info, warn and error levels are accepted
+func SetLevel(level string) error {
+	logLevel, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	switch logLevel {
+	case logrus.DebugLevel, logrus.InfoLevel, logrus.WarnLevel, logrus.ErrorLevel:
+	default:
+		return fmt.Errorf("unsupported log level %q", level)
+	}
+	// logrus updates the level atomically, so this is safe while other goroutines are logging
+	log.SetLevel(logLevel)
+	return nil
+}
+
+// GetLevel returns the current log level
+func GetLevel() string {
+	return log.GetLevel().String()
+}
@@ -20,12 +20,63 @@
 package metrics
 
 import (
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Notification and command status label values
+const (
+	StatusSuccess     = "success"
+	StatusError       = "error"
+	StatusIgnored     = "ignored"
+	StatusUnsupported = "unsupported"
+)
+
+var (
+	eventsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "botkube_events_total",
+		Help: "Number of events processed by BotKube to send notifications",
+	}, []string{"kind", "type", "level"})
+
+	notificationsSentTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "botkube_notifications_sent_total",
+		Help: "Number of notifications sent to the communication backends",
+	}, []string{"backend", "status"})
+
+	commandsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "botkube_commands_total",
+		Help: "Number of commands executed by BotKube",
+	}, []string{"command", "status"})
 )
 
+func init() {
+	prometheus.MustRegister(eventsTotal, notificationsSentTotal, commandsTotal)
+}
+
+// IncEvents increments the processed events counter
+func IncEvents(kind, eventType, level string) {
+	eventsTotal.WithLabelValues(kind, eventType, level).Inc()
+}
+
+// IncNotifications increments the sent notifications counter
+func IncNotifications(backend string, err error) {
+	status := StatusSuccess
+	if err != nil {
+		status = StatusError
+	}
+	notificationsSentTotal.WithLabelValues(backend, status).Inc()
+}
+
+// IncCommands increments the executed commands counter
+func IncCommands(command, status string) {
+	commandsTotal.WithLabelValues(command, status).Inc()
+}
+
 // ServeMetrics exposes metrics in Prometheus format
-func ServeMetrics(metricsPort string) {
-	http.Handle("/metrics", promhttp.Handler())
-	http.ListenAndServe(":"+metricsPort, nil)
+func ServeMetrics(metricsPort string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return http.ListenAndServe(":"+metricsPort, mux)
 }
@@ -0,0 +1,200 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/log"
+)
+
+// defaultCooldown is used if circuit breaker cooldown is not configured
+const defaultCooldown = 5 * time.Minute
+
+const circuitOpenMsg = "BotKube stopped sending notifications to %s on cluster '%s' after %d consecutive failures. Last error: %s. Retrying in %s."
+
+// ErrCircuitOpen is returned when the notifier is skipped because of persistent failures
+var ErrCircuitOpen = errors.New("circuit is open, skipping notifier")
+
+// CircuitBreaker wraps a notifier and stops sending to it for a cooldown after consecutive failures
+// After the cooldown a single send is allowed as a probe, a successful probe closes the circuit
+type CircuitBreaker struct {
+	notifier  Notifier
+	fallback  Notifier
+	threshold int
+	cooldown  time.Duration
+	cluster   string
+
+	mu       sync.Mutex
+	failures int
+	open     bool
+	probing  bool
+	openedAt time.Time
+}
+
+// NewCircuitBreaker returns a notifier which trips after c.FailureThreshold consecutive failures
+// The diagnostic message is posted on fallback if it is not nil
+func NewCircuitBreaker(n Notifier, c config.CircuitBreaker, fallback Notifier, clusterName string) *CircuitBreaker {
+	cooldown := c.Cooldown
+	if cooldown <= 0 {
+		cooldown = defaultCooldown
+	}
+	return &CircuitBreaker{
+		notifier:  n,
+		fallback:  fallback,
+		threshold: c.FailureThreshold,
+		cooldown:  cooldown,
+		cluster:   clusterName,
+	}
+}
+
+// WithCircuitBreaker wraps the notifiers with circuit breaker if failure threshold is configured
+func WithCircuitBreaker(notifiers []Notifier, c config.CircuitBreaker, clusterName string) []Notifier {
+	if c.FailureThreshold <= 0 {
+		return notifiers
+	}
+	var fallback Notifier
+	for _, n := range notifiers {
+		if len(c.Fallback) != 0 && strings.EqualFold(Name(n), c.Fallback) {
+			fallback = n
+		}
+	}
+	if len(c.Fallback) != 0 && fallback == nil {
+		log.Warnf("Circuit breaker fallback %s is not an enabled notifier", c.Fallback)
+	}
+
+	wrapped := make([]Notifier, 0, len(notifiers))
+	for _, n := range notifiers {
+		f := fallback
+		if strings.EqualFold(Name(n), c.Fallback) {
+			// Failing notifier can't report its own failure
+			f = nil
+		}
+		wrapped = append(wrapped, NewCircuitBreaker(n, c, f, clusterName))
+	}
+	return wrapped
+}
+
+// SendEvent sends event if the circuit is closed
+func (b *CircuitBreaker) SendEvent(event events.Event) error {
+	return b.call(func() error { return b.notifier.SendEvent(event) })
+}
+
+// SendMessage sends message if the circuit is closed
+func (b *CircuitBreaker) SendMessage(msg string) error {
+	return b.call(func() error { return b.notifier.SendMessage(msg) })
+}
+
+// Ready checks the readiness of the wrapped notifier
+func (b *CircuitBreaker) Ready() error {
+	return b.notifier.Ready()
+}
+
+// MinSeverity returns minimum severity of the wrapped notifier
+func (b *CircuitBreaker) MinSeverity() config.Level {
+	if f, ok := b.notifier.(SeverityFilter); ok {
+		return f.MinSeverity()
+	}
+	return ""
+}
+
+// Unwrap returns the wrapped notifier
+func (b *CircuitBreaker) Unwrap() Notifier {
+	return b.notifier
+}
+
+func (b *CircuitBreaker) call(send func() error) error {
+	if !b.allow() {
+		return ErrCircuitOpen
+	}
+	err := send()
+	b.record(err)
+	return err
+}
+
+// allow returns false if the circuit is open and cooldown hasn't passed or a probe is in progress
+func (b *CircuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.open {
+		return true
+	}
+	if b.probing || time.Since(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	if err == nil {
+		if b.open {
+			log.Infof("Circuit closed for %s notifier", Name(b.notifier))
+		}
+		b.failures, b.open, b.probing = 0, false, false
+		b.mu.Unlock()
+		return
+	}
+
+	b.failures++
+	wasOpen := b.open
+	if b.probing || b.failures >= b.threshold {
+		b.open, b.probing = true, false
+		b.openedAt = time.Now()
+	}
+	opened := b.open && !wasOpen
+	failures := b.failures
+	b.mu.Unlock()
+
+	if !opened {
+		return
+	}
+	log.Errorf("Circuit opened for %s notifier after %d consecutive failures. Error: %s", Name(b.notifier), failures, err.Error())
+	if b.fallback != nil {
+		msg := fmt.Sprintf(circuitOpenMsg, Name(b.notifier), b.cluster, failures, err.Error(), b.cooldown)
+		if ferr := b.fallback.SendMessage(msg); ferr != nil {
+			log.Errorf("Failed to send circuit breaker diagnostic to %s. Error: %s", Name(b.fallback), ferr.Error())
+		}
+	}
+}
+
+// Name returns the type name of the notifier, wrapped notifiers are unwrapped
+func Name(n Notifier) string {
+	return reflect.Indirect(reflect.ValueOf(unwrap(n))).Type().Name()
+}
+
+// unwrap returns the notifier wrapped by circuit breakers
+func unwrap(n Notifier) Notifier {
+	for {
+		w, ok := n.(interface{ Unwrap() Notifier })
+		if !ok {
+			return n
+		}
+		n = w.Unwrap()
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+type fakeNotifier struct {
+	err      error
+	sent     int
+	messages []string
+}
+
+func (f *fakeNotifier) SendEvent(events.Event) error {
+	f.sent++
+	return f.err
+}
+
+func (f *fakeNotifier) SendMessage(msg string) error {
+	f.messages = append(f.messages, msg)
+	return f.err
+}
+
+func (f *fakeNotifier) Ready() error {
+	return nil
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	n := &fakeNotifier{err: errors.New("conversation ref not set")}
+	fallback := &fakeNotifier{}
+	b := NewCircuitBreaker(n, config.CircuitBreaker{FailureThreshold: 3, Cooldown: time.Hour}, fallback, "dev")
+
+	for i := 0; i < 5; i++ {
+		b.SendEvent(events.Event{})
+	}
+	if n.sent != 3 {
+		t.Errorf("expected: %+v != actual: %+v\n", 3, n.sent)
+	}
+	if err := b.SendEvent(events.Event{}); err != ErrCircuitOpen {
+		t.Errorf("expected: %+v != actual: %+v\n", ErrCircuitOpen, err)
+	}
+	if len(fallback.messages) != 1 || !strings.Contains(fallback.messages[0], "conversation ref not set") {
+		t.Errorf("expected a single diagnostic message, got %+v", fallback.messages)
+	}
+
+	// Failed probe after cooldown keeps the circuit open without another diagnostic
+	b.openedAt = time.Now().Add(-2 * time.Hour)
+	b.SendEvent(events.Event{})
+	if n.sent != 4 || len(fallback.messages) != 1 {
+		t.Errorf("expected one probe and no new diagnostic, got sent: %d, messages: %d", n.sent, len(fallback.messages))
+	}
+	b.SendEvent(events.Event{})
+	if n.sent != 4 {
+		t.Errorf("expected: %+v != actual: %+v\n", 4, n.sent)
+	}
+
+	// Successful probe closes the circuit
+	n.err = nil
+	b.openedAt = time.Now().Add(-2 * time.Hour)
+	for i := 0; i < 3; i++ {
+		if err := b.SendEvent(events.Event{}); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	}
+	if n.sent != 7 {
+		t.Errorf("expected: %+v != actual: %+v\n", 7, n.sent)
+	}
+}
+
+func TestCircuitBreakerResetsOnSuccess(t *testing.T) {
+	n := &fakeNotifier{}
+	b := NewCircuitBreaker(n, config.CircuitBreaker{FailureThreshold: 2}, nil, "dev")
+	for i := 0; i < 4; i++ {
+		n.err = nil
+		if i%2 == 0 {
+			n.err = errors.New("timeout")
+		}
+		b.SendMessage("hello")
+	}
+	if err := b.SendMessage("hello"); err == ErrCircuitOpen {
+		t.Errorf("expected circuit to stay closed on non consecutive failures")
+	}
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	slack := &Slack{}
+	webhook := &Webhook{}
+	notifiers := []Notifier{slack, webhook}
+
+	if actual := WithCircuitBreaker(notifiers, config.CircuitBreaker{}, "dev"); actual[0] != slack {
+		t.Errorf("expected notifiers not to be wrapped without failure threshold")
+	}
+
+	wrapped := WithCircuitBreaker(notifiers, config.CircuitBreaker{FailureThreshold: 1, Fallback: "slack"}, "dev")
+	for i, n := range wrapped {
+		b, ok := n.(*CircuitBreaker)
+		if !ok {
+			t.Fatalf("expected notifier to be wrapped with circuit breaker")
+		}
+		if b.Unwrap() != notifiers[i] {
+			t.Errorf("expected: %+v != actual: %+v\n", notifiers[i], b.Unwrap())
+		}
+	}
+	if wrapped[0].(*CircuitBreaker).fallback != nil {
+		t.Errorf("expected fallback notifier not to report its own failure")
+	}
+	if wrapped[1].(*CircuitBreaker).fallback != slack {
+		t.Errorf("expected: %+v != actual: %+v\n", slack, wrapped[1].(*CircuitBreaker).fallback)
+	}
+	if actual := Name(wrapped[1]); actual != "Webhook" {
+		t.Errorf("expected: %+v != actual: %+v\n", "Webhook", actual)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/log"
+)
+
+// deadLetter is the sink for undeliverable events, nil if not configured
+var deadLetter *deadLetterSink
+
+// DeadLetterRecord is written to the dead letter sink for each undeliverable event
+type DeadLetterRecord struct {
+	Time    time.Time    `json:"time"`
+	Backend string       `json:"backend"`
+	Error   string       `json:"error"`
+	Event   events.Event `json:"event"`
+}
+
+type deadLetterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (d *deadLetterSink) write(record DeadLetterRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, err = d.w.Write(append(data, '\n'))
+	return err
+}
+
+// InitDeadLetter opens the dead letter file if configured
+func InitDeadLetter(c config.DeadLetter) error {
+	if len(c.Path) == 0 {
+		return nil
+	}
+	f, err := os.OpenFile(c.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	deadLetter = &deadLetterSink{w: f}
+	return nil
+}
+
+// SendToDeadLetter records the event which notifier failed to deliver
+func SendToDeadLetter(n Notifier, event events.Event, sendErr error) {
+	if deadLetter == nil || sendErr == nil {
+		return
+	}
+	record := DeadLetterRecord{
+		Time:    time.Now(),
+		Backend: Name(n),
+		Error:   sendErr.Error(),
+		Event:   event,
+	}
+	if err := deadLetter.write(record); err != nil {
+		log.Errorf("Failed to write %s event to dead letter sink. Error: %s", record.Backend, err.Error())
+	}
+}
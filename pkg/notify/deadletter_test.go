@@ -0,0 +1,66 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+func TestSendToDeadLetter(t *testing.T) {
+	defer func(d *deadLetterSink) { deadLetter = d }(deadLetter)
+	buf := new(bytes.Buffer)
+	deadLetter = &deadLetterSink{w: buf}
+
+	SendToDeadLetter(&Webhook{}, events.Event{Name: "nginx", Kind: "Pod"}, nil)
+	if buf.Len() != 0 {
+		t.Errorf("expected delivered event not to be recorded, got %s", buf.String())
+	}
+
+	SendToDeadLetter(&Webhook{}, events.Event{Name: "nginx", Kind: "Pod"}, errors.New("connection refused"))
+	var record DeadLetterRecord
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if record.Backend != "Webhook" || record.Error != "connection refused" || record.Event.Name != "nginx" {
+		t.Errorf("unexpected record: %+v", record)
+	}
+}
+
+func TestInitDeadLetter(t *testing.T) {
+	defer func(d *deadLetterSink) { deadLetter = d }(deadLetter)
+	deadLetter = nil
+
+	if err := InitDeadLetter(config.DeadLetter{}); err != nil || deadLetter != nil {
+		t.Errorf("expected dead letter sink to be disabled without path")
+	}
+	if err := InitDeadLetter(config.DeadLetter{Path: filepath.Join(t.TempDir(), "dead-letter.jsonl")}); err != nil || deadLetter == nil {
+		t.Errorf("expected dead letter sink to be enabled, error: %v", err)
+	}
+	if err := InitDeadLetter(config.DeadLetter{Path: filepath.Join(t.TempDir(), "missing", "dead-letter.jsonl")}); err == nil {
+		t.Errorf("expected error for invalid path")
+	}
+}
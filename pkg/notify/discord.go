@@ -21,6 +21,7 @@ package notify
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/infracloudio/botkube/pkg/config"
@@ -31,6 +32,12 @@ import (
 // customTimeFormat holds custom time format string
 const customTimeFormat = "2006-01-02T15:04:05Z"
 
+// discordMessageLimit is the max number of characters Discord accepts in a single message
+const discordMessageLimit = 2000
+
+// codeBlockFormat wraps a chunk of long output in a code block
+const codeBlockFormat = "```\n%s\n```"
+
 var embedColor = map[config.Level]int{
 	config.Info:     8311585,  // green
 	config.Warn:     16312092, // yellow
@@ -44,6 +51,9 @@ type Discord struct {
 	Token     string
 	ChannelID string
 	NotifType config.NotifType
+
+	minSeverity config.Level
+	ready       readyCheck
 }
 
 // NewDiscord returns new Discord object
@@ -52,9 +62,16 @@ func NewDiscord(c config.Discord) Notifier {
 		Token:     c.Token,
 		ChannelID: c.Channel,
 		NotifType: c.NotifType,
+
+		minSeverity: c.MinSeverity,
 	}
 }
 
+// MinSeverity returns minimum level of events sent to Discord
+func (d *Discord) MinSeverity() config.Level {
+	return d.minSeverity
+}
+
 // SendEvent sends event notification to Discord Channel
 func (d *Discord) SendEvent(event events.Event) (err error) {
 	log.Debug(fmt.Sprintf(">> Sending to discord: %+v", event))
@@ -74,6 +91,18 @@ func (d *Discord) SendEvent(event events.Event) (err error) {
 	return nil
 }
 
+// Ready verifies the token by fetching the bot user from Discord
+func (d *Discord) Ready() error {
+	return d.ready.run(func() error {
+		api, err := discordgo.New("Bot " + d.Token)
+		if err != nil {
+			return err
+		}
+		_, err = api.User("@me")
+		return err
+	})
+}
+
 // SendMessage sends message to Discord Channel
 func (d *Discord) SendMessage(msg string) error {
 	log.Debug(fmt.Sprintf(">> Sending to discord: %+v", msg))
@@ -83,14 +112,61 @@ func (d *Discord) SendMessage(msg string) error {
 		return err
 	}
 
-	if _, err := api.ChannelMessageSend(d.ChannelID, msg); err != nil {
-		log.Error("Error in sending message:", err)
-		return err
+	// Messages over the limit are sent as a sequence of code blocks, in order
+	for _, chunk := range discordMessages(msg) {
+		if _, err := api.ChannelMessageSend(d.ChannelID, chunk); err != nil {
+			log.Error("Error in sending message:", err)
+			return err
+		}
 	}
 	log.Debugf("Event successfully sent to Discord %v", msg)
 	return nil
 }
 
+// discordMessages returns the messages to be sent for msg. Short messages are returned as is,
+// longer ones are split into chunks wrapped in code blocks, each within discordMessageLimit
+func discordMessages(msg string) []string {
+	if len(msg) <= discordMessageLimit {
+		return []string{msg}
+	}
+	limit := discordMessageLimit - len(fmt.Sprintf(codeBlockFormat, ""))
+	var messages []string
+	for _, chunk := range chunkMessage(msg, limit) {
+		messages = append(messages, fmt.Sprintf(codeBlockFormat, chunk))
+	}
+	return messages
+}
+
+// chunkMessage splits msg into chunks of at most limit characters, breaking at newlines
+// where possible. Lines longer than limit are split at the limit
+func chunkMessage(msg string, limit int) []string {
+	var chunks []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSpace(msg), "\n") {
+		for len(line) > limit {
+			flush()
+			chunks = append(chunks, line[:limit])
+			line = line[limit:]
+		}
+		// +1 for the newline joining the line to the current chunk
+		if current.Len() > 0 && current.Len()+1+len(line) > limit {
+			flush()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n")
+		}
+		current.WriteString(line)
+	}
+	flush()
+	return chunks
+}
+
 func formatDiscordMessage(event events.Event, notifyType config.NotifType) discordgo.MessageSend {
 
 	var messageEmbed discordgo.MessageEmbed
@@ -140,7 +216,7 @@ func discordLongNotification(event events.Event) discordgo.MessageEmbed {
 			Text: "BotKube",
 		},
 	}
-	if event.Namespace != "" {
+	if event.Namespace != "" && !IsClusterScoped(event) {
 		messageEmbed.Fields = append(messageEmbed.Fields, &discordgo.MessageEmbedField{
 			Name:   "Namespace",
 			Value:  event.Namespace,
@@ -167,6 +243,13 @@ func discordLongNotification(event events.Event) discordgo.MessageEmbed {
 		})
 	}
 
+	if len(event.Diff) > 0 {
+		messageEmbed.Fields = append(messageEmbed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Diff",
+			Value: fmt.Sprintf("```\n%s```", event.Diff),
+		})
+	}
+
 	if event.Action != "" {
 		messageEmbed.Fields = append(messageEmbed.Fields, &discordgo.MessageEmbedField{
 			Name:  "Action",
@@ -0,0 +1,81 @@
+// Copyright (c) 2020 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDiscordMessages(t *testing.T) {
+	var lines []string
+	for i := 0; i < 300; i++ {
+		lines = append(lines, fmt.Sprintf("pod-%03d   1/1   Running   0   10d", i))
+	}
+	longOutput := strings.Join(lines, "\n")
+
+	tests := map[string]struct {
+		msg      string
+		expected int
+	}{
+		`Short message is sent as is`: {
+			msg:      "BotKube is now active",
+			expected: 1,
+		},
+		`Long output is split at newlines`: {
+			msg:      longOutput,
+			expected: 6,
+		},
+		`Long line is split at the limit`: {
+			msg:      strings.Repeat("x", 5000),
+			expected: 3,
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			messages := discordMessages(test.msg)
+			if len(messages) != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, len(messages))
+			}
+			if len(messages) == 1 {
+				if messages[0] != test.msg {
+					t.Errorf("expected: %+v != actual: %+v\n", test.msg, messages[0])
+				}
+				return
+			}
+			var content []string
+			for _, m := range messages {
+				if len(m) > discordMessageLimit {
+					t.Errorf("message of %d chars exceeds the limit", len(m))
+				}
+				if !strings.HasPrefix(m, "```\n") || !strings.HasSuffix(m, "\n```") {
+					t.Errorf("message is not wrapped in a code block: %q", m)
+				}
+				content = append(content, strings.TrimSuffix(strings.TrimPrefix(m, "```\n"), "\n```"))
+			}
+			// Chunks must preserve order and content of the original output
+			if strings.ReplaceAll(strings.Join(content, "\n"), "\n", "") != strings.ReplaceAll(test.msg, "\n", "") {
+				t.Errorf("chunks do not preserve the original output")
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"fmt"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/log"
+)
+
+// DryRun wraps a notifier and logs the notifications instead of sending them, set with settings.dryRun
+type DryRun struct {
+	notifier Notifier
+}
+
+// WithDryRun wraps the notifiers with DryRun if dry run is enabled
+func WithDryRun(notifiers []Notifier, dryRun bool) []Notifier {
+	if !dryRun {
+		return notifiers
+	}
+	wrapped := make([]Notifier, 0, len(notifiers))
+	for _, n := range notifiers {
+		wrapped = append(wrapped, &DryRun{notifier: n})
+	}
+	return wrapped
+}
+
+// SendEvent logs the event rendered in short format
+func (d *DryRun) SendEvent(event events.Event) error {
+	log.Infof("Dry run: %s notification to %s:\n%s\n%s", Name(d.notifier), target(d.notifier), event.Title, FormatShortMessage(event))
+	return nil
+}
+
+// SendMessage logs the message
+func (d *DryRun) SendMessage(msg string) error {
+	log.Infof("Dry run: %s message to %s:\n%s", Name(d.notifier), target(d.notifier), msg)
+	return nil
+}
+
+// Ready returns nil, the backend is not contacted in dry run
+func (d *DryRun) Ready() error {
+	return nil
+}
+
+// MinSeverity returns minimum severity of the wrapped notifier so that events are filtered as they would be sent
+func (d *DryRun) MinSeverity() config.Level {
+	if f, ok := d.notifier.(SeverityFilter); ok {
+		return f.MinSeverity()
+	}
+	return ""
+}
+
+// Unwrap returns the wrapped notifier
+func (d *DryRun) Unwrap() Notifier {
+	return d.notifier
+}
+
+// target returns where the notifier sends to, e.g the channel
+func target(n Notifier) string {
+	switch t := unwrap(n).(type) {
+	case *Slack:
+		return fmt.Sprintf("channel '%s'", t.Channel)
+	case *Mattermost:
+		return fmt.Sprintf("channel '%s'", t.Channel)
+	case *Discord:
+		return fmt.Sprintf("channel '%s'", t.ChannelID)
+	case *Webhook:
+		return fmt.Sprintf("URL '%s'", t.URL)
+	case *ElasticSearch:
+		return fmt.Sprintf("index '%s'", t.Index)
+	case *Jira:
+		return fmt.Sprintf("project '%s'", t.Project)
+	}
+	return "default channel"
+}
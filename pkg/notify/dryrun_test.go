@@ -0,0 +1,73 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+func TestDryRun(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer ts.Close()
+
+	fake := &fakeNotifier{}
+	webhook := &Webhook{URL: ts.URL}
+	notifiers := WithDryRun([]Notifier{fake, webhook}, true)
+	for _, n := range notifiers {
+		if err := n.SendEvent(events.Event{Kind: "Pod", Name: "nginx", Type: config.CreateEvent}); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+		if err := n.SendMessage("BotKube is started"); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+		if err := n.Ready(); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	}
+	if fake.sent != 0 || len(fake.messages) != 0 || requests != 0 {
+		t.Errorf("expected nothing to be sent in dry run, got events: %d, messages: %d, requests: %d", fake.sent, len(fake.messages), requests)
+	}
+	if actual := Name(notifiers[1]); actual != "Webhook" {
+		t.Errorf("expected: %+v != actual: %+v\n", "Webhook", actual)
+	}
+	if actual := target(notifiers[1]); actual != "URL '"+ts.URL+"'" {
+		t.Errorf("expected: %+v != actual: %+v\n", "URL '"+ts.URL+"'", actual)
+	}
+}
+
+func TestWithDryRunDisabled(t *testing.T) {
+	slack := &Slack{minSeverity: config.Error}
+	if actual := WithDryRun([]Notifier{slack}, false); actual[0] != slack {
+		t.Errorf("expected notifiers not to be wrapped without dry run")
+	}
+	// Events are filtered by severity as they would be sent
+	wrapped := WithDryRun([]Notifier{slack}, true)
+	if IsSevereEnough(wrapped[0], config.Info, "") {
+		t.Errorf("expected info event to be filtered by minimum severity of the wrapped notifier")
+	}
+}
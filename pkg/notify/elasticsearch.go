@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -35,6 +36,7 @@ import (
 	"github.com/infracloudio/botkube/pkg/config"
 	"github.com/infracloudio/botkube/pkg/events"
 	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/utils"
 	"github.com/olivere/elastic"
 	"github.com/sha1sum/aws_signing_client"
 )
@@ -48,6 +50,11 @@ const (
 	awsRoleARNEnvName = "AWS_ROLE_ARN"
 	// The token file mount path in POD env variable while using IAM Role for service account
 	awsWebIDTokenFileEnvName = "AWS_WEB_IDENTITY_TOKEN_FILE"
+
+	// Defaults of communications.elasticsearch.bulk thresholds
+	defaultBulkActions       = 100
+	defaultBulkSize          = 5 << 20
+	defaultBulkFlushInterval = 5 * time.Second
 )
 
 // ElasticSearch contains auth cred and index setting
@@ -59,6 +66,18 @@ type ElasticSearch struct {
 	Shards        int
 	Replicas      int
 	Type          string
+
+	minSeverity config.Level
+
+	// indices contains the index names known to exist, so that they are checked once
+	indicesMu sync.Mutex
+	indices   map[string]bool
+
+	// bulk buffers the events if communications.elasticsearch.bulk is enabled
+	// Events are indexed one by one once it is closed
+	bulkMu     sync.RWMutex
+	bulk       *elastic.BulkProcessor
+	bulkClosed bool
 }
 
 // NewElasticSearch returns new ElasticSearch object
@@ -107,9 +126,8 @@ func NewElasticSearch(c config.ElasticSearch) (Notifier, error) {
 		}
 
 		if c.SkipTLSVerify {
-			tr := &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			}
+			tr := utils.NewHTTPTransport()
+			tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 			httpClient := &http.Client{Transport: tr}
 			elsClientParams = append(elsClientParams, elastic.SetHttpClient(httpClient))
 		}
@@ -119,13 +137,69 @@ func NewElasticSearch(c config.ElasticSearch) (Notifier, error) {
 			return nil, err
 		}
 	}
-	return &ElasticSearch{
-		ELSClient: elsClient,
+	return newElasticSearch(elsClient, c)
+}
+
+func newElasticSearch(client *elastic.Client, c config.ElasticSearch) (*ElasticSearch, error) {
+	e := &ElasticSearch{
+		ELSClient: client,
 		Index:     c.Index.Name,
 		Type:      c.Index.Type,
 		Shards:    c.Index.Shards,
 		Replicas:  c.Index.Replicas,
-	}, nil
+
+		minSeverity: c.MinSeverity,
+		indices:     map[string]bool{},
+	}
+	if !c.Bulk.Enabled {
+		return e, nil
+	}
+
+	actions, size, interval := c.Bulk.Actions, c.Bulk.Size, c.Bulk.FlushInterval
+	if actions <= 0 {
+		actions = defaultBulkActions
+	}
+	if size <= 0 {
+		size = defaultBulkSize
+	}
+	if interval <= 0 {
+		interval = defaultBulkFlushInterval
+	}
+	bulk, err := client.BulkProcessor().
+		Name("botkube").
+		Workers(1).
+		BulkActions(actions).
+		BulkSize(size).
+		FlushInterval(interval).
+		After(afterBulk).
+		Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	e.bulk = bulk
+	return e, nil
+}
+
+// afterBulk logs the events which failed to be indexed
+func afterBulk(executionID int64, requests []elastic.BulkableRequest, response *elastic.BulkResponse, err error) {
+	if err != nil {
+		log.Errorf("Failed to post %d events to els. Error:%s", len(requests), err.Error())
+		return
+	}
+	if failed := response.Failed(); len(failed) > 0 {
+		reason := ""
+		if failed[0].Error != nil {
+			reason = failed[0].Error.Reason
+		}
+		log.Errorf("Failed to index %d of %d events in els. Error:%s", len(failed), len(requests), reason)
+		return
+	}
+	log.Debugf("%d events successfully sent to ElasticSearch", len(requests))
+}
+
+// MinSeverity returns minimum level of events indexed in ElasticSearch
+func (e *ElasticSearch) MinSeverity() config.Level {
+	return e.minSeverity
 }
 
 type mapping struct {
@@ -140,10 +214,13 @@ type index struct {
 	Replicas int `json:"number_of_replicas"`
 }
 
-func (e *ElasticSearch) flushIndex(ctx context.Context, event interface{}) error {
-	// Construct the ELS Index Name with timestamp suffix
-	indexName := e.Index + "-" + time.Now().Format(indexSuffixFormat)
-	// Create index if not exists
+// createIndex creates the index with the configured shards and replicas if it doesn't exist
+func (e *ElasticSearch) createIndex(ctx context.Context, indexName string) error {
+	e.indicesMu.Lock()
+	defer e.indicesMu.Unlock()
+	if e.indices[indexName] {
+		return nil
+	}
 	exists, err := e.ELSClient.IndexExists(indexName).Do(ctx)
 	if err != nil {
 		log.Error(fmt.Sprintf("Failed to get index. Error:%s", err.Error()))
@@ -165,9 +242,13 @@ func (e *ElasticSearch) flushIndex(ctx context.Context, event interface{}) error
 			return err
 		}
 	}
+	e.indices[indexName] = true
+	return nil
+}
 
+func (e *ElasticSearch) flushIndex(ctx context.Context, indexName string, event interface{}) error {
 	// Send event to els
-	_, err = e.ELSClient.Index().Index(indexName).Type(e.Type).BodyJson(event).Do(ctx)
+	_, err := e.ELSClient.Index().Index(indexName).Type(e.Type).BodyJson(event).Do(ctx)
 	if err != nil {
 		log.Error(fmt.Sprintf("Failed to post data to els. Error:%s", err.Error()))
 		return err
@@ -186,14 +267,40 @@ func (e *ElasticSearch) SendEvent(event events.Event) (err error) {
 	log.Debug(fmt.Sprintf(">> Sending to ElasticSearch: %+v", event))
 	ctx := context.Background()
 
+	// Construct the ELS Index Name with timestamp suffix
+	indexName := e.Index + "-" + time.Now().Format(indexSuffixFormat)
 	// Create index if not exists
-	if err := e.flushIndex(ctx, event); err != nil {
+	if err := e.createIndex(ctx, indexName); err != nil {
 		return err
 	}
-	return nil
+
+	e.bulkMu.RLock()
+	if e.bulk != nil && !e.bulkClosed {
+		e.bulk.Add(elastic.NewBulkIndexRequest().Index(indexName).Type(e.Type).Doc(event))
+		e.bulkMu.RUnlock()
+		return nil
+	}
+	e.bulkMu.RUnlock()
+	return e.flushIndex(ctx, indexName, event)
+}
+
+// Close sends the buffered events, the events sent after it are indexed one by one
+func (e *ElasticSearch) Close() error {
+	e.bulkMu.Lock()
+	defer e.bulkMu.Unlock()
+	if e.bulk == nil || e.bulkClosed {
+		return nil
+	}
+	e.bulkClosed = true
+	return e.bulk.Close()
 }
 
 // SendMessage sends message to slack channel
 func (e *ElasticSearch) SendMessage(msg string) error {
 	return nil
 }
+
+// Ready returns nil since events are indexed over stateless HTTP requests
+func (e *ElasticSearch) Ready() error {
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/olivere/elastic"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+// fakeElasticSearch is an ElasticSearch server which records the size of bulk requests and the events indexed one by one
+type fakeElasticSearch struct {
+	mu         sync.Mutex
+	bulks      []int
+	indexed    int
+	compressed bool
+}
+
+func (f *fakeElasticSearch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodHead:
+		// All indices exist
+	case r.URL.Path == "/_bulk":
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body = gz
+			f.compressed = true
+		}
+		lines := 0
+		for scanner := bufio.NewScanner(body); scanner.Scan(); {
+			lines++
+		}
+		// Every index action is followed by the document
+		docs := lines / 2
+		f.bulks = append(f.bulks, docs)
+		items := make([]string, docs)
+		for i := range items {
+			items[i] = `{"index":{"_index":"botkube","_type":"event","status":201}}`
+		}
+		fmt.Fprintf(w, `{"took":1,"errors":false,"items":[%s]}`, strings.Join(items, ","))
+	case strings.HasSuffix(r.URL.Path, "/_flush"):
+		fmt.Fprint(w, `{}`)
+	case r.Method == http.MethodPost:
+		f.indexed++
+		fmt.Fprint(w, `{"_index":"botkube","_type":"event","_id":"1","result":"created"}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeElasticSearch) state() ([]int, int, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]int(nil), f.bulks...), f.indexed, f.compressed
+}
+
+// waitForBulks waits until the server receives the number of bulk requests
+func (f *fakeElasticSearch) waitForBulks(count int) []int {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		bulks, _, _ := f.state()
+		if len(bulks) >= count || time.Now().After(deadline) {
+			return bulks
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func newTestElasticSearch(t *testing.T, url string, bulk config.ElasticSearchBulk) *ElasticSearch {
+	client, err := elastic.NewClient(elastic.SetURL(url), elastic.SetSniff(false), elastic.SetHealthcheck(false), elastic.SetGzip(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := newElasticSearch(client, config.ElasticSearch{Index: config.Index{Name: "botkube", Type: "event"}, Bulk: bulk})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestElasticSearchBulkActions(t *testing.T) {
+	fake := &fakeElasticSearch{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	e := newTestElasticSearch(t, server.URL, config.ElasticSearchBulk{Enabled: true, Actions: 3, FlushInterval: time.Hour})
+
+	for i := 0; i < 5; i++ {
+		if err := e.SendEvent(events.Event{Kind: "Pod", Name: fmt.Sprintf("nginx-%d", i)}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if bulks := fake.waitForBulks(1); len(bulks) != 1 || bulks[0] != 3 {
+		t.Fatalf("expected one bulk request with 3 events, got %v", bulks)
+	}
+
+	// Remaining events are sent on shutdown
+	Close([]Notifier{e})
+	bulks, indexed, compressed := fake.state()
+	if len(bulks) != 2 || bulks[1] != 2 {
+		t.Errorf("expected second bulk request with 2 events on close, got %v", bulks)
+	}
+	if !compressed {
+		t.Error("expected gzip compressed bulk requests")
+	}
+
+	// Events sent after close are indexed one by one
+	if err := e.SendEvent(events.Event{Kind: "Pod", Name: "late"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, indexed, _ = fake.state(); indexed != 1 {
+		t.Errorf("expected: %+v != actual: %+v\n", 1, indexed)
+	}
+}
+
+func TestElasticSearchBulkFlushInterval(t *testing.T) {
+	fake := &fakeElasticSearch{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	e := newTestElasticSearch(t, server.URL, config.ElasticSearchBulk{Enabled: true, FlushInterval: 50 * time.Millisecond})
+	defer e.Close()
+
+	if err := e.SendEvent(events.Event{Kind: "Pod", Name: "nginx"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if bulks := fake.waitForBulks(1); len(bulks) != 1 || bulks[0] != 1 {
+		t.Errorf("expected bulk request with 1 event after flush interval, got %v", bulks)
+	}
+}
+
+func TestElasticSearchWithoutBulk(t *testing.T) {
+	fake := &fakeElasticSearch{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	e := newTestElasticSearch(t, server.URL, config.ElasticSearchBulk{})
+
+	for i := 0; i < 2; i++ {
+		if err := e.SendEvent(events.Event{Kind: "Pod", Name: "nginx"}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if bulks, indexed, _ := fake.state(); len(bulks) != 0 || indexed != 2 {
+		t.Errorf("expected 2 events indexed one by one, got %d and bulk requests %v", indexed, bulks)
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/infracloudio/botkube/pkg/log"
+)
+
+const (
+	// jiraDefaultIssueType is used if communications.jira.issueType is not configured
+	jiraDefaultIssueType = "Bug"
+	// jiraLabel is added to all the issues created by BotKube
+	jiraLabel = "botkube"
+	// jiraSummaryLimit is the maximum length of issue summary allowed by Jira
+	jiraSummaryLimit = 255
+)
+
+// Jira creates issues for events, events of a resource with an open issue are added to it as comments
+type Jira struct {
+	URL          string
+	Username     string
+	Token        string
+	Project      string
+	IssueType    string
+	CommentIssue string
+
+	minSeverity config.Level
+	client      *http.Client
+	ready       readyCheck
+}
+
+type jiraIssueFields struct {
+	Project     jiraKey  `json:"project"`
+	IssueType   jiraName `json:"issuetype"`
+	Summary     string   `json:"summary"`
+	Description string   `json:"description"`
+	Labels      []string `json:"labels"`
+}
+
+type jiraKey struct {
+	Key string `json:"key"`
+}
+
+type jiraName struct {
+	Name string `json:"name"`
+}
+
+type jiraSearchResult struct {
+	Issues []jiraKey `json:"issues"`
+}
+
+// NewJira returns new Jira notifier
+func NewJira(c config.Jira) *Jira {
+	issueType := c.IssueType
+	if len(issueType) == 0 {
+		issueType = jiraDefaultIssueType
+	}
+	minSeverity := c.MinSeverity
+	if len(minSeverity) == 0 {
+		minSeverity = config.Critical
+	}
+	return &Jira{
+		URL:          strings.TrimSuffix(c.URL, "/"),
+		Username:     c.Username,
+		Token:        c.Token,
+		Project:      c.Project,
+		IssueType:    issueType,
+		CommentIssue: c.CommentIssue,
+
+		minSeverity: minSeverity,
+		client:      &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// MinSeverity returns minimum level of events issues are created for
+func (j *Jira) MinSeverity() config.Level {
+	return j.minSeverity
+}
+
+// SendEvent creates an issue for the event
+// If an open issue exists for the same resource and reason, the event is added to it as a comment
+func (j *Jira) SendEvent(event events.Event) error {
+	label := jiraDedupLabel(event)
+	key, err := j.findOpenIssue(label)
+	if err != nil {
+		return fmt.Errorf("Failed to search Jira issues. Error: %s", err.Error())
+	}
+	if len(key) != 0 {
+		log.Debugf("Adding event to open Jira issue %s", key)
+		return j.addComment(key, jiraDescription(event))
+	}
+
+	fields := jiraIssueFields{
+		Project:     jiraKey{Key: j.Project},
+		IssueType:   jiraName{Name: j.IssueType},
+		Summary:     jiraSummary(event),
+		Description: jiraDescription(event),
+		Labels:      []string{jiraLabel, label},
+	}
+	var created jiraKey
+	if err := j.do(http.MethodPost, "/rest/api/2/issue", map[string]interface{}{"fields": fields}, &created); err != nil {
+		return fmt.Errorf("Failed to create Jira issue. Error: %s", err.Error())
+	}
+	log.Debugf("Created Jira issue %s for event %v", created.Key, event)
+	return nil
+}
+
+// SendMessage adds the message as a comment to communications.jira.commentIssue, it is dropped if not configured
+func (j *Jira) SendMessage(msg string) error {
+	if len(j.CommentIssue) == 0 {
+		return nil
+	}
+	return j.addComment(j.CommentIssue, msg)
+}
+
+// Ready checks the credentials with Jira API
+func (j *Jira) Ready() error {
+	return j.ready.run(func() error {
+		return j.do(http.MethodGet, "/rest/api/2/myself", nil, nil)
+	})
+}
+
+// findOpenIssue returns the key of the unresolved issue with the label, empty string if there is none
+func (j *Jira) findOpenIssue(label string) (string, error) {
+	jql := fmt.Sprintf(`project = "%s" AND labels = "%s" AND statusCategory != Done`, j.Project, label)
+	var res jiraSearchResult
+	path := "/rest/api/2/search?" + url.Values{"jql": {jql}, "fields": {"key"}, "maxResults": {"1"}}.Encode()
+	if err := j.do(http.MethodGet, path, nil, &res); err != nil {
+		return "", err
+	}
+	if len(res.Issues) == 0 {
+		return "", nil
+	}
+	return res.Issues[0].Key, nil
+}
+
+func (j *Jira) addComment(key, body string) error {
+	if err := j.do(http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(key)+"/comment", map[string]string{"body": body}, nil); err != nil {
+		return fmt.Errorf("Failed to add comment to Jira issue %s. Error: %s", key, err.Error())
+	}
+	return nil
+}
+
+// do sends the request with body as JSON and decodes the response into out if it is not nil
+func (j *Jira) do(method, path string, body, out interface{}) error {
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, j.URL+path, &reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if len(j.Username) != 0 {
+		req.SetBasicAuth(j.Username, j.Token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+j.Token)
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Jira API returned %s", resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// jiraDedupLabel returns the label identifying issues of the resource and reason of the event
+func jiraDedupLabel(event events.Event) string {
+	key := strings.Join([]string{event.Cluster, event.Namespace, event.Kind, event.Name, event.Reason}, "/")
+	sum := sha1.Sum([]byte(key))
+	return fmt.Sprintf("%s-%x", jiraLabel, sum[:6])
+}
+
+func jiraSummary(event events.Event) string {
+	reason := event.Reason
+	if len(reason) == 0 {
+		reason = string(event.Type)
+	}
+	summary := fmt.Sprintf("[%s] %s %s %s", event.Cluster, reason, event.Kind, event.Name)
+	if len(event.Namespace) != 0 {
+		summary = fmt.Sprintf("[%s] %s %s %s/%s", event.Cluster, reason, event.Kind, event.Namespace, event.Name)
+	}
+	if len(summary) > jiraSummaryLimit {
+		summary = summary[:jiraSummaryLimit]
+	}
+	return summary
+}
+
+// jiraDescription returns the event details in Jira text formatting
+func jiraDescription(event events.Event) string {
+	var b strings.Builder
+	fields := [][2]string{
+		{"Cluster", event.Cluster},
+		{"Namespace", event.Namespace},
+		{"Kind", event.Kind},
+		{"Name", event.Name},
+		{"Reason", event.Reason},
+		{"Level", string(event.Level)},
+		{"Type", string(event.Type)},
+		{"Error", event.Error},
+	}
+	for _, f := range fields {
+		if len(f[1]) != 0 {
+			fmt.Fprintf(&b, "*%s:* %s\n", f[0], f[1])
+		}
+	}
+	if !event.TimeStamp.IsZero() {
+		fmt.Fprintf(&b, "*Time:* %s\n", event.TimeStamp.UTC().Format(time.RFC3339))
+	}
+	for _, list := range []struct {
+		title string
+		items []string
+	}{
+		{"Messages", event.Messages},
+		{"Recommendations", event.Recommendations},
+		{"Warnings", event.Warnings},
+	} {
+		if len(list.items) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "*%s:*\n", list.title)
+		for _, item := range list.items {
+			fmt.Fprintf(&b, "* %s\n", item)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
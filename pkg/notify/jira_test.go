@@ -0,0 +1,160 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+// fakeJira is a Jira API server which stores the created issues and comments
+type fakeJira struct {
+	mu       sync.Mutex
+	issues   map[string][]string
+	comments map[string][]string
+}
+
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if user, token, ok := r.BasicAuth(); !ok || user != "bot@example.com" || token != "token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/search":
+		var issues []jiraKey
+		for key, labels := range f.issues {
+			for _, label := range labels {
+				if strings.Contains(r.URL.Query().Get("jql"), `labels = "`+label+`"`) && label != jiraLabel {
+					issues = append(issues, jiraKey{Key: key})
+				}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(jiraSearchResult{Issues: issues})
+	case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+		var req struct {
+			Fields jiraIssueFields `json:"fields"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		key := "OPS-" + string(rune('1'+len(f.issues)))
+		f.issues[key] = req.Fields.Labels
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(jiraKey{Key: key})
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/comment"):
+		var req map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		key := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/"), "/comment")
+		f.comments[key] = append(f.comments[key], req["body"])
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestJiraSendEvent(t *testing.T) {
+	fake := &fakeJira{issues: map[string][]string{}, comments: map[string][]string{}}
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	j := NewJira(config.Jira{URL: ts.URL + "/", Username: "bot@example.com", Token: "token", Project: "OPS", CommentIssue: "OPS-100"})
+	crash := events.Event{Cluster: "prod", Kind: "Pod", Name: "nginx", Namespace: "default", Reason: "BackOff", Level: config.Critical, Type: config.ErrorEvent}
+	other := events.Event{Cluster: "prod", Kind: "Pod", Name: "redis", Namespace: "default", Reason: "BackOff", Level: config.Critical, Type: config.ErrorEvent}
+	for _, event := range []events.Event{crash, crash, other} {
+		if err := j.SendEvent(event); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if err := j.SendMessage("BotKube stopped"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	// Repeated event is added to the open issue instead of creating a duplicate
+	expectedIssues := map[string][]string{
+		"OPS-1": {jiraLabel, jiraDedupLabel(crash)},
+		"OPS-2": {jiraLabel, jiraDedupLabel(other)},
+	}
+	if !reflect.DeepEqual(fake.issues, expectedIssues) {
+		t.Errorf("expected: %+v != actual: %+v\n", expectedIssues, fake.issues)
+	}
+	expectedComments := map[string][]string{
+		"OPS-1":   {jiraDescription(crash)},
+		"OPS-100": {"BotKube stopped"},
+	}
+	if !reflect.DeepEqual(fake.comments, expectedComments) {
+		t.Errorf("expected: %+v != actual: %+v\n", expectedComments, fake.comments)
+	}
+}
+
+func TestJiraSendEventError(t *testing.T) {
+	fake := &fakeJira{issues: map[string][]string{}, comments: map[string][]string{}}
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	j := NewJira(config.Jira{URL: ts.URL, Username: "bot@example.com", Token: "wrong", Project: "OPS"})
+	if err := j.SendEvent(events.Event{Kind: "Pod", Name: "nginx"}); err == nil {
+		t.Error("expected error for unauthorized request")
+	}
+}
+
+func TestNewJiraDefaults(t *testing.T) {
+	j := NewJira(config.Jira{URL: "https://jira.example.com"})
+	if j.IssueType != jiraDefaultIssueType || j.MinSeverity() != config.Critical {
+		t.Errorf("expected: %s %s != actual: %s %s\n", jiraDefaultIssueType, config.Critical, j.IssueType, j.MinSeverity())
+	}
+	// Messages are dropped without an issue to comment on
+	if err := j.SendMessage("BotKube started"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestJiraDescription(t *testing.T) {
+	event := events.Event{
+		Cluster:         "prod",
+		Kind:            "Pod",
+		Name:            "nginx",
+		Namespace:       "default",
+		Reason:          "BackOff",
+		Level:           config.Critical,
+		Type:            config.ErrorEvent,
+		TimeStamp:       time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		Messages:        []string{"Back-off restarting failed container"},
+		Recommendations: []string{"Check the container logs"},
+	}
+	expected := "*Cluster:* prod\n*Namespace:* default\n*Kind:* Pod\n*Name:* nginx\n*Reason:* BackOff\n*Level:* critical\n*Type:* error\n" +
+		"*Time:* 2021-01-02T03:04:05Z\n*Messages:*\n* Back-off restarting failed container\n*Recommendations:*\n* Check the container logs"
+	if actual := jiraDescription(event); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+	if actual, expected := jiraSummary(event), "[prod] BackOff Pod default/nginx"; actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
@@ -32,9 +32,18 @@ import (
 
 // Mattermost contains server URL and token
 type Mattermost struct {
-	Client    *model.Client4
-	Channel   string
-	NotifType config.NotifType
+	Client       *model.Client4
+	Channel      string
+	NotifType    config.NotifType
+	ThreadEvents bool
+
+	minSeverity config.Level
+	// channelName is the name of Channel which is the channel ID
+	channelName string
+	// channelSeverity is a map of channel name to the minimum level of events sent to the channel
+	channelSeverity map[string]config.Level
+	ready           readyCheck
+	threads         threadStore
 }
 
 // NewMattermost returns new Mattermost object
@@ -52,12 +61,27 @@ func NewMattermost(c config.Mattermost) (Notifier, error) {
 	}
 
 	return &Mattermost{
-		Client:    client,
-		Channel:   botChannel.Id,
-		NotifType: c.NotifType,
+		Client:       client,
+		Channel:      botChannel.Id,
+		NotifType:    c.NotifType,
+		ThreadEvents: c.ThreadEvents,
+
+		minSeverity:     c.MinSeverity,
+		channelName:     c.Channel,
+		channelSeverity: c.ChannelSeverity,
 	}, nil
 }
 
+// MinSeverity returns minimum level of events sent to Mattermost
+func (m *Mattermost) MinSeverity() config.Level {
+	return m.minSeverity
+}
+
+// ChannelMinSeverity returns minimum level of events sent to the Mattermost channel
+func (m *Mattermost) ChannelMinSeverity(channel string) config.Level {
+	return channelMinSeverity(m.channelSeverity, channel, m.channelName, m.minSeverity)
+}
+
 // SendEvent sends event notification to Mattermost
 func (m *Mattermost) SendEvent(event events.Event) error {
 	log.Info(fmt.Sprintf(">> Sending to Mattermost: %+v", event))
@@ -94,7 +118,7 @@ func (m *Mattermost) SendEvent(event events.Event) error {
 	if event.Channel != "" {
 		post.ChannelId = event.Channel
 
-		if _, resp := m.Client.CreatePost(post); resp.Error != nil {
+		if resp := m.createPost(post, event); resp.Error != nil {
 			log.Error("Failed to send message. Error: ", resp.Error)
 			// send error message to default channel
 			msg := fmt.Sprintf("Unable to send message to Channel `%s`: `%s`\n```add Botkube app to the Channel %s\nMissed events follows below:```", event.Channel, resp.Error, event.Channel)
@@ -109,7 +133,7 @@ func (m *Mattermost) SendEvent(event events.Event) error {
 	} else {
 		post.ChannelId = m.Channel
 		// empty value in event.channel sends notifications to default channel.
-		if _, resp := m.Client.CreatePost(post); resp.Error != nil {
+		if resp := m.createPost(post, event); resp.Error != nil {
 			log.Error("Failed to send message. Error: ", resp.Error)
 			return resp.Error
 		}
@@ -118,6 +142,28 @@ func (m *Mattermost) SendEvent(event events.Event) error {
 	return nil
 }
 
+// createPost creates post, as a reply in the thread of the event resource if ThreadEvents is enabled
+func (m *Mattermost) createPost(post *model.Post, event events.Event) *model.Response {
+	if !m.ThreadEvents {
+		_, resp := m.Client.CreatePost(post)
+		return resp
+	}
+	key := threadKey(post.ChannelId, event)
+	post.RootId = m.threads.get(key)
+	created, resp := m.Client.CreatePost(post)
+	if resp.Error != nil && len(post.RootId) != 0 {
+		// root post may have been deleted, start a new thread
+		log.Debugf("Failed to reply in thread %s. Error: %v", post.RootId, resp.Error)
+		m.threads.delete(key)
+		post.RootId = ""
+		created, resp = m.Client.CreatePost(post)
+	}
+	if resp.Error == nil && len(post.RootId) == 0 {
+		m.threads.set(key, created.Id)
+	}
+	return resp
+}
+
 // SendMessage sends message to Mattermost channel
 func (m *Mattermost) SendMessage(msg string) error {
 	post := &model.Post{}
@@ -143,7 +189,7 @@ func mmLongNotification(event events.Event) []*model.SlackAttachmentField {
 		},
 	}
 
-	if event.Namespace != "" {
+	if event.Namespace != "" && !IsClusterScoped(event) {
 		fields = append(fields, &model.SlackAttachmentField{
 			Title: "Namespace",
 			Value: event.Namespace,
@@ -170,6 +216,13 @@ func mmLongNotification(event events.Event) []*model.SlackAttachmentField {
 		})
 	}
 
+	if len(event.Diff) > 0 {
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: "Diff",
+			Value: fmt.Sprintf("```\n%s```", event.Diff),
+		})
+	}
+
 	if event.Action != "" {
 		fields = append(fields, &model.SlackAttachmentField{
 			Title: "Action",
@@ -215,3 +268,13 @@ func mmShortNotification(event events.Event) []*model.SlackAttachmentField {
 		},
 	}
 }
+
+// Ready verifies the token by fetching the bot user from Mattermost server
+func (m *Mattermost) Ready() error {
+	return m.ready.run(func() error {
+		if _, resp := m.Client.GetMe(""); resp.Error != nil {
+			return resp.Error
+		}
+		return nil
+	})
+}
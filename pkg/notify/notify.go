@@ -21,6 +21,9 @@ package notify
 
 import (
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/infracloudio/botkube/pkg/config"
 	"github.com/infracloudio/botkube/pkg/events"
@@ -31,6 +34,81 @@ import (
 type Notifier interface {
 	SendEvent(events.Event) error
 	SendMessage(string) error
+	Ready() error
+}
+
+// SeverityFilter is implemented by notifiers configured to send events of a minimum level
+type SeverityFilter interface {
+	MinSeverity() config.Level
+}
+
+// ChannelSeverityFilter is implemented by notifiers configured with minimum levels per channel
+type ChannelSeverityFilter interface {
+	// ChannelMinSeverity returns the minimum level of events sent to the channel, empty channel is the notifier channel
+	ChannelMinSeverity(channel string) config.Level
+}
+
+// IsSevereEnough checks if the event level is same or above minimum severity of the channel the event is sent to
+// The minimum severity of the notifier is used if no level is set for the channel
+func IsSevereEnough(n Notifier, level config.Level, channel string) bool {
+	if f, ok := unwrap(n).(ChannelSeverityFilter); ok {
+		return level.IsAtLeast(f.ChannelMinSeverity(channel))
+	}
+	if f, ok := n.(SeverityFilter); ok {
+		return level.IsAtLeast(f.MinSeverity())
+	}
+	return true
+}
+
+// channelMinSeverity returns the level set for the channel, or for defaultChannel if channel is empty, min otherwise
+func channelMinSeverity(levels map[string]config.Level, channel, defaultChannel string, min config.Level) config.Level {
+	if len(channel) == 0 {
+		channel = defaultChannel
+	}
+	if level, ok := levels[strings.TrimPrefix(channel, "#")]; ok {
+		return level
+	}
+	return min
+}
+
+// Closer is implemented by notifiers buffering events, Close sends the buffered events
+type Closer interface {
+	Close() error
+}
+
+// Close sends the events buffered by the notifiers
+func Close(notifiers []Notifier) {
+	for _, n := range notifiers {
+		c, ok := unwrap(n).(Closer)
+		if !ok {
+			continue
+		}
+		if err := c.Close(); err != nil {
+			log.Errorf("Failed to send the buffered events to %s. Error: %s", Name(n), err.Error())
+		}
+	}
+}
+
+// readyCheckInterval is the duration for which result of a readiness check is cached
+const readyCheckInterval = 30 * time.Second
+
+// readyCheck caches the result of a readiness check to avoid calling backend APIs on every probe
+type readyCheck struct {
+	mu        sync.Mutex
+	checkedAt time.Time
+	err       error
+}
+
+// run returns cached result or runs the check if cached result is older than readyCheckInterval
+func (r *readyCheck) run(check func() error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.checkedAt.IsZero() && time.Since(r.checkedAt) < readyCheckInterval {
+		return r.err
+	}
+	r.err = check()
+	r.checkedAt = time.Now()
+	return r.err
 }
 
 // ListNotifiers returns list of configured notifiers
@@ -59,5 +137,8 @@ func ListNotifiers(conf config.CommunicationsConfig) []Notifier {
 	if conf.Webhook.Enabled {
 		notifiers = append(notifiers, NewWebhook(conf))
 	}
+	if conf.Jira.Enabled {
+		notifiers = append(notifiers, NewJira(conf.Jira))
+	}
 	return notifiers
 }
@@ -0,0 +1,76 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"testing"
+
+	"github.com/infracloudio/botkube/pkg/config"
+)
+
+func TestIsSevereEnough(t *testing.T) {
+	tests := map[string]struct {
+		notifier Notifier
+		level    config.Level
+		expected bool
+	}{
+		`min severity not configured`: {&Slack{}, config.Debug, true},
+		`event below min severity`:    {&Slack{minSeverity: config.Warn}, config.Info, false},
+		`event at min severity`:       {&Mattermost{minSeverity: config.Warn}, config.Warn, true},
+		`event above min severity`:    {&Webhook{minSeverity: config.Error}, config.Critical, true},
+		`error below critical`:        {&Discord{minSeverity: config.Critical}, config.Error, false},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := IsSevereEnough(test.notifier, test.level, ""); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestIsSevereEnoughPerChannel(t *testing.T) {
+	slack := &Slack{Channel: "k8s-activity", channelSeverity: map[string]config.Level{"alerts": config.Warn}}
+	mattermost := &Mattermost{channelName: "k8s-activity", minSeverity: config.Error, channelSeverity: map[string]config.Level{"k8s-activity": config.Debug}}
+	tests := map[string]struct {
+		notifier Notifier
+		level    config.Level
+		channel  string
+		expected bool
+	}{
+		`event below channel min severity`:         {slack, config.Info, "alerts", false},
+		`event at channel min severity`:            {slack, config.Warn, "alerts", true},
+		`channel name with # prefix`:               {slack, config.Info, "#alerts", false},
+		`default channel without min severity`:     {slack, config.Debug, "", true},
+		`other channel without min severity`:       {slack, config.Info, "dev", true},
+		`wrapped notifier`:                         {NewCircuitBreaker(slack, config.CircuitBreaker{}, nil, "test"), config.Info, "alerts", false},
+		`default channel min severity overrides`:   {mattermost, config.Info, "", true},
+		`other channel uses notifier min severity`: {mattermost, config.Warn, "alerts", false},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := IsSevereEnough(test.notifier, test.level, test.channel); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
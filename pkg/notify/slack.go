@@ -23,6 +23,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/infracloudio/botkube/pkg/config"
 	"github.com/infracloudio/botkube/pkg/events"
@@ -43,65 +46,339 @@ type Slack struct {
 	Channel   string
 	NotifType config.NotifType
 	Client    *slack.Client
+
+	minSeverity config.Level
+	// channelSeverity is a map of channel name to the minimum level of events sent to the channel
+	channelSeverity map[string]config.Level
+	ready           readyCheck
+	// ackButton adds Acknowledge button to error and critical events
+	ackButton       bool
+	channelWarnings channelWarnings
+	// mentions is a map of channel name to the mention added to notifications
+	mentions map[string]config.SlackMention
+	// colors is the attachment color of each level
+	colors map[config.Level]string
+	// messages tracks the notifications updated in place, nil if updateInPlace is disabled
+	messages *postedMessages
+	// threads maps the correlation IDs to their thread, nil if threadEvents is disabled
+	threads *threadStore
+	// limiter throttles the messages per channel, nil if maxSendsPerMinute is not set
+	limiter *sendLimiter
 }
 
+// channelWarningInterval is the minimum time between warnings about a channel BotKube can't post to
+const channelWarningInterval = 10 * time.Minute
+
+// updateInPlaceTTL is how long a notification is updated with the later events of its resource
+const updateInPlaceTTL = time.Hour
+
+const (
+	// SlackAckCallbackID is the callback ID of the notifications with Acknowledge button
+	SlackAckCallbackID = "botkube_ack"
+	// SlackAckActionName is the name of the Acknowledge button action
+	SlackAckActionName = "ack"
+)
+
 // NewSlack returns new Slack object
 func NewSlack(c config.Slack) Notifier {
-	return &Slack{
+	s := &Slack{
 		Channel:   c.Channel,
 		NotifType: c.NotifType,
 		Client:    slack.New(c.Token),
+
+		minSeverity:     c.MinSeverity,
+		channelSeverity: c.ChannelSeverity,
+		// Button clicks are received over socket mode only
+		ackButton: c.AckButton && c.Mode == config.SlackSocketMode,
+		mentions:  c.Mentions,
+		colors:    slackColors(c.Colors),
+	}
+	if c.UpdateInPlace {
+		s.messages = &postedMessages{ttl: updateInPlaceTTL}
 	}
+	if c.ThreadEvents {
+		s.threads = &threadStore{}
+	}
+	s.limiter = newSendLimiter(c.MaxSendsPerMinute, c.ThrottleMode, s.sendDroppedSummary)
+	return s
+}
+
+// slackColors returns the default attachment colors overridden with the valid custom colors
+func slackColors(custom map[config.Level]string) map[config.Level]string {
+	colors := make(map[config.Level]string, len(attachmentColor))
+	for level, color := range attachmentColor {
+		colors[level] = color
+	}
+	for level, color := range custom {
+		if _, ok := colors[level]; ok && config.IsHexColor(color) {
+			colors[level] = color
+		}
+	}
+	return colors
+}
+
+// MinSeverity returns minimum level of events sent to slack
+func (s *Slack) MinSeverity() config.Level {
+	return s.minSeverity
+}
+
+// ChannelMinSeverity returns minimum level of events sent to the slack channel
+func (s *Slack) ChannelMinSeverity(channel string) config.Level {
+	return channelMinSeverity(s.channelSeverity, channel, s.Channel, s.minSeverity)
 }
 
 // SendEvent sends event notification to slack
 func (s *Slack) SendEvent(event events.Event) error {
 	log.Debug(fmt.Sprintf(">> Sending to slack: %+v", event))
-	attachment := formatSlackMessage(event, s.NotifType)
+	attachment := formatSlackMessage(event, s.NotifType, s.colors)
+	if s.ackButton && event.Level.IsAtLeast(config.Error) {
+		addAckButton(&attachment, event)
+	}
 
 	// non empty value in event.channel demands redirection of events to a different channel
-	if event.Channel != "" {
-		channelID, timestamp, err := s.Client.PostMessage(event.Channel, slack.MsgOptionAttachments(attachment), slack.MsgOptionAsUser(true))
-		if err != nil {
+	if event.Channel == "" || event.Channel == s.Channel {
+		return s.notify(s.Channel, attachment, event)
+	}
+	err := s.notify(event.Channel, attachment, event)
+	if err == nil || err.Error() != "channel_not_found" {
+		return err
+	}
+
+	// send error message and the missed event to default channel
+	// The event is not redirected again so the recovery runs once per event
+	if s.channelWarnings.allow(event.Channel, time.Now()) {
+		msg := fmt.Sprintf("Unable to send message to Channel `%s`: `%s`\n```add Botkube app to the Channel %s\nMissed events follows below:```", event.Channel, err.Error(), event.Channel)
+		if err := s.SendMessage(msg); err != nil {
 			log.Errorf("Error in sending slack message %s", err.Error())
-			// send error message to default channel
-			if err.Error() == "channel_not_found" {
-				msg := fmt.Sprintf("Unable to send message to Channel `%s`: `%s`\n```add Botkube app to the Channel %s\nMissed events follows below:```", event.Channel, err.Error(), event.Channel)
-				go s.SendMessage(msg)
-				// sending missed event to default channel
-				// reset event.Channel and send event
-				event.Channel = ""
-				go s.SendEvent(event)
-			}
-			return err
 		}
-		log.Debugf("Event successfully sent to channel %s at %s", channelID, timestamp)
-	} else {
-		// empty value in event.channel sends notifications to default channel.
-		channelID, timestamp, err := s.Client.PostMessage(s.Channel, slack.MsgOptionAttachments(attachment), slack.MsgOptionAsUser(true))
-		if err != nil {
-			log.Errorf("Error in sending slack message %s", err.Error())
-			return err
+	}
+	return s.notify(s.Channel, attachment, event)
+}
+
+// notify posts the event notification to the channel under maxSendsPerMinute
+func (s *Slack) notify(channel string, attachment slack.Attachment, event events.Event) error {
+	return s.limiter.send(channel, func() error { return s.postNotification(channel, attachment, event) })
+}
+
+// postNotification posts the event notification to the channel
+// With updateInPlace, create and update events edit the last notification of the resource in the channel instead
+func (s *Slack) postNotification(channel string, attachment slack.Attachment, event events.Event) error {
+	text := s.mention(channel, event.Level)
+	if s.messages == nil {
+		_, err := s.postEvent(channel, attachment, text, event)
+		return err
+	}
+
+	key := postedMessageKey(channel, event)
+	now := time.Now()
+	switch event.Type {
+	case config.CreateEvent, config.UpdateEvent:
+	case config.DeleteEvent:
+		s.messages.delete(key)
+		fallthrough
+	default:
+		_, err := s.postEvent(channel, attachment, text, event)
+		return err
+	}
+
+	if msg, ok := s.messages.get(key, now); ok {
+		err := s.updateAttachment(msg, attachment, text)
+		if err == nil {
+			return nil
 		}
-		log.Debugf("Event successfully sent to channel %s at %s", channelID, timestamp)
+		// The message may be deleted or too old to edit
+		log.Warnf("Unable to update slack message of %s, posting a new one. %s", key, err.Error())
 	}
+	msg, err := s.postEvent(channel, attachment, text, event)
+	if err != nil {
+		return err
+	}
+	s.messages.set(key, msg, now)
 	return nil
 }
 
-// SendMessage sends message to slack channel
-func (s *Slack) SendMessage(msg string) error {
-	log.Debug(fmt.Sprintf(">> Sending to slack: %+v", msg))
-	channelID, timestamp, err := s.Client.PostMessage(s.Channel, slack.MsgOptionText(msg, false), slack.MsgOptionAsUser(true))
+// postEvent posts the event notification, with threadEvents it is a reply in the thread of the event correlation ID
+// The first notification of a correlation ID becomes the root of its thread
+func (s *Slack) postEvent(channel string, attachment slack.Attachment, text string, event events.Event) (postedMessage, error) {
+	if s.threads == nil || event.CorrelationID == "" {
+		return s.postAttachment(channel, attachment, text)
+	}
+	key := correlationThreadKey(channel, event.CorrelationID)
+	if rootTS := s.threads.get(key); rootTS != "" {
+		msg, err := s.postAttachment(channel, attachment, text, slack.MsgOptionTS(rootTS))
+		if err == nil || err.Error() == "channel_not_found" {
+			return msg, err
+		}
+		// The root message may be deleted, start a new thread
+		log.Warnf("Unable to reply in slack thread of %s, starting a new one. %s", key, err.Error())
+	}
+	msg, err := s.postAttachment(channel, attachment, text)
+	if err == nil {
+		s.threads.set(key, msg.timestamp)
+	}
+	return msg, err
+}
+
+// attachmentOptions returns the message options of the attachment, text is sent with the attachment if not empty
+func attachmentOptions(attachment slack.Attachment, text string) []slack.MsgOption {
+	options := []slack.MsgOption{slack.MsgOptionAttachments(attachment), slack.MsgOptionAsUser(true)}
+	if len(text) != 0 {
+		options = append(options, slack.MsgOptionText(text, false))
+	}
+	return options
+}
+
+// postAttachment posts the attachment to the channel and returns the posted message
+func (s *Slack) postAttachment(channel string, attachment slack.Attachment, text string, extra ...slack.MsgOption) (postedMessage, error) {
+	channelID, timestamp, err := s.Client.PostMessage(channel, append(attachmentOptions(attachment, text), extra...)...)
 	if err != nil {
 		log.Errorf("Error in sending slack message %s", err.Error())
-		return err
+		return postedMessage{}, err
 	}
+	log.Debugf("Event successfully sent to channel %s at %s", channelID, timestamp)
+	return postedMessage{channelID: channelID, timestamp: timestamp}, nil
+}
 
-	log.Debugf("Message successfully sent to channel %s at %s", channelID, timestamp)
+// updateAttachment replaces the posted message with the attachment
+func (s *Slack) updateAttachment(msg postedMessage, attachment slack.Attachment, text string) error {
+	channelID, timestamp, _, err := s.Client.UpdateMessage(msg.channelID, msg.timestamp, attachmentOptions(attachment, text)...)
+	if err != nil {
+		return err
+	}
+	log.Debugf("Event successfully updated in channel %s at %s", channelID, timestamp)
 	return nil
 }
 
-func formatSlackMessage(event events.Event, notifyType config.NotifType) (attachment slack.Attachment) {
+// sendDroppedSummary posts the number of messages dropped in the channel, the summary isn't throttled
+func (s *Slack) sendDroppedSummary(channel string, dropped int) {
+	msg := fmt.Sprintf(throttleDroppedMsg, dropped, s.limiter.limit)
+	if _, _, err := s.Client.PostMessage(channel, slack.MsgOptionText(msg, false), slack.MsgOptionAsUser(true)); err != nil {
+		log.Errorf("Error in sending slack message %s", err.Error())
+	}
+}
+
+// mention returns the mention configured for the channel if the level is at or above its minSeverity
+// Channel names are matched with or without # prefix
+func (s *Slack) mention(channel string, level config.Level) string {
+	m, ok := s.mentions[strings.TrimPrefix(channel, "#")]
+	if !ok || !level.IsAtLeast(m.MinSeverity) {
+		return ""
+	}
+	return m.Text
+}
+
+// channelWarnings limits the warnings about a channel BotKube can't post to, one per channelWarningInterval
+type channelWarnings struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// allow checks if the warning about the channel can be sent at now and records it
+func (w *channelWarnings) allow(channel string, now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.last == nil {
+		w.last = make(map[string]time.Time)
+	}
+	if last, ok := w.last[channel]; ok && now.Sub(last) < channelWarningInterval {
+		return false
+	}
+	w.last[channel] = now
+	return true
+}
+
+// postedMessage identifies a slack message by its channel ID and timestamp
+type postedMessage struct {
+	channelID string
+	timestamp string
+	expires   time.Time
+}
+
+// postedMessages is the last notification of each resource per channel, entries expire after ttl from posting
+type postedMessages struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	messages map[string]postedMessage
+}
+
+// postedMessageKey returns the key of the notifications of the event resource in the channel
+func postedMessageKey(channel string, event events.Event) string {
+	return fmt.Sprintf("%s/%s/%s/%s", strings.TrimPrefix(channel, "#"), event.Kind, event.Namespace, event.Name)
+}
+
+// get returns the message of the key if it hasn't expired at now
+func (p *postedMessages) get(key string, now time.Time) (postedMessage, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	msg, ok := p.messages[key]
+	if !ok || !now.Before(msg.expires) {
+		return postedMessage{}, false
+	}
+	return msg, true
+}
+
+// set records the message posted at now and drops the expired ones
+func (p *postedMessages) set(key string, msg postedMessage, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.messages == nil {
+		p.messages = make(map[string]postedMessage)
+	}
+	for k, m := range p.messages {
+		if !now.Before(m.expires) {
+			delete(p.messages, k)
+		}
+	}
+	msg.expires = now.Add(p.ttl)
+	p.messages[key] = msg
+}
+
+// delete forgets the message of the key
+func (p *postedMessages) delete(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.messages, key)
+}
+
+// addAckButton adds the Acknowledge button to the attachment, the button value identifies the event resource
+func addAckButton(attachment *slack.Attachment, event events.Event) {
+	resource := event.Kind + " " + event.Name
+	if len(event.Namespace) != 0 {
+		resource = fmt.Sprintf("%s %s/%s", event.Kind, event.Namespace, event.Name)
+	}
+	attachment.CallbackID = SlackAckCallbackID
+	attachment.Actions = append(attachment.Actions, slack.AttachmentAction{
+		Name:  SlackAckActionName,
+		Text:  "Acknowledge",
+		Type:  "button",
+		Value: resource,
+	})
+}
+
+// Ready verifies the token with slack auth test
+func (s *Slack) Ready() error {
+	return s.ready.run(func() error {
+		_, err := s.Client.AuthTest()
+		return err
+	})
+}
+
+// SendMessage sends message to slack channel
+func (s *Slack) SendMessage(msg string) error {
+	log.Debug(fmt.Sprintf(">> Sending to slack: %+v", msg))
+	return s.limiter.send(s.Channel, func() error {
+		channelID, timestamp, err := s.Client.PostMessage(s.Channel, slack.MsgOptionText(msg, false), slack.MsgOptionAsUser(true))
+		if err != nil {
+			log.Errorf("Error in sending slack message %s", err.Error())
+			return err
+		}
+
+		log.Debugf("Message successfully sent to channel %s at %s", channelID, timestamp)
+		return nil
+	})
+}
+
+func formatSlackMessage(event events.Event, notifyType config.NotifType, colors map[config.Level]string) (attachment slack.Attachment) {
 	switch notifyType {
 	case config.LongNotify:
 		attachment = slackLongNotification(event)
@@ -119,7 +396,12 @@ func formatSlackMessage(event events.Event, notifyType config.NotifType) (attach
 	if ts > "0" {
 		attachment.Ts = ts
 	}
-	attachment.Color = attachmentColor[event.Level]
+	// Default colors are used if the notifier is not created with NewSlack
+	color, ok := colors[event.Level]
+	if !ok {
+		color = attachmentColor[event.Level]
+	}
+	attachment.Color = color
 	return attachment
 }
 
@@ -141,7 +423,7 @@ func slackLongNotification(event events.Event) slack.Attachment {
 		},
 		Footer: "BotKube",
 	}
-	if event.Namespace != "" {
+	if event.Namespace != "" && !IsClusterScoped(event) {
 		attachment.Fields = append(attachment.Fields, slack.AttachmentField{
 			Title: "Namespace",
 			Value: event.Namespace,
@@ -168,6 +450,13 @@ func slackLongNotification(event events.Event) slack.Attachment {
 		})
 	}
 
+	if len(event.Diff) > 0 {
+		attachment.Fields = append(attachment.Fields, slack.AttachmentField{
+			Title: "Diff",
+			Value: fmt.Sprintf("```\n%s```", event.Diff),
+		})
+	}
+
 	if event.Action != "" {
 		attachment.Fields = append(attachment.Fields, slack.AttachmentField{
 			Title: "Action",
@@ -218,108 +507,19 @@ func slackShortNotification(event events.Event) slack.Attachment {
 }
 
 // FormatShortMessage prepares message in short event format
+// Message is rendered with settings.templates.short, default template is used on render errors
 func FormatShortMessage(event events.Event) (msg string) {
-	additionalMsg := ""
-	if len(event.Messages) > 0 {
-		for _, m := range event.Messages {
-			additionalMsg += fmt.Sprintf("%s\n", m)
-		}
-	}
-	if len(event.Recommendations) > 0 {
-		recommend := ""
-		for _, m := range event.Recommendations {
-			recommend += fmt.Sprintf("- %s\n", m)
-		}
-		additionalMsg += fmt.Sprintf("Recommendations:\n%s", recommend)
-	}
-	if len(event.Warnings) > 0 {
-		warning := ""
-		for _, m := range event.Warnings {
-			warning += fmt.Sprintf("- %s\n", m)
-		}
-		additionalMsg += fmt.Sprintf("Warnings:\n%s", warning)
-	}
+	templatesMu.RLock()
+	tmpl := shortMessageTmpl
+	templatesMu.RUnlock()
 
-	switch event.Type {
-	case config.CreateEvent, config.DeleteEvent, config.UpdateEvent:
-		switch event.Kind {
-		case "Namespace", "Node", "PersistentVolume", "ClusterRole", "ClusterRoleBinding":
-			msg = fmt.Sprintf(
-				"%s *%s* has been %s in *%s* cluster\n",
-				event.Kind,
-				event.Name,
-				event.Type+"d",
-				event.Cluster,
-			)
-		default:
-			msg = fmt.Sprintf(
-				"%s *%s/%s* has been %s in *%s* cluster\n",
-				event.Kind,
-				event.Namespace,
-				event.Name,
-				event.Type+"d",
-				event.Cluster,
-			)
-		}
-	case config.ErrorEvent:
-		switch event.Kind {
-		case "Namespace", "Node", "PersistentVolume", "ClusterRole", "ClusterRoleBinding":
-			msg = fmt.Sprintf(
-				"Error Occurred in %s: *%s* in *%s* cluster\n",
-				event.Kind,
-				event.Name,
-				event.Cluster,
-			)
-		default:
-			msg = fmt.Sprintf(
-				"Error Occurred in %s: *%s/%s* in *%s* cluster\n",
-				event.Kind,
-				event.Namespace,
-				event.Name,
-				event.Cluster,
-			)
-		}
-	case config.WarningEvent:
-		switch event.Kind {
-		case "Namespace", "Node", "PersistentVolume", "ClusterRole", "ClusterRoleBinding":
-			msg = fmt.Sprintf(
-				"Warning %s: *%s* in *%s* cluster\n",
-				event.Kind,
-				event.Name,
-				event.Cluster,
-			)
-		default:
-			msg = fmt.Sprintf(
-				"Warning %s: *%s/%s* in *%s* cluster\n",
-				event.Kind,
-				event.Namespace,
-				event.Name,
-				event.Cluster,
-			)
-		}
-	case config.InfoEvent, config.NormalEvent:
-		switch event.Kind {
-		case "Namespace", "Node", "PersistentVolume", "ClusterRole", "ClusterRoleBinding":
-			msg = fmt.Sprintf(
-				"%s Info: *%s* in *%s* cluster\n",
-				event.Kind,
-				event.Name,
-				event.Cluster,
-			)
-		default:
-			msg = fmt.Sprintf(
-				"%s Info: *%s/%s* in *%s* cluster\n",
-				event.Kind,
-				event.Namespace,
-				event.Name,
-				event.Cluster,
-			)
-		}
+	msg, err := renderTemplate(tmpl, event)
+	if err != nil && tmpl != defaultShortMessageTemplate {
+		log.Errorf("Failed to render short message template. Using default template. Error: %s", err.Error())
+		msg, err = renderTemplate(defaultShortMessageTemplate, event)
 	}
-
-	// Add message in the attachment if there is any
-	if len(additionalMsg) > 0 {
-		msg += fmt.Sprintf("```\n%s```", additionalMsg)
+	if err != nil {
+		log.Errorf("Failed to render default short message template. Error: %s", err.Error())
 	}
 	return msg
 }
@@ -0,0 +1,385 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/nlopes/slack"
+)
+
+func TestSlackLongNotificationDiff(t *testing.T) {
+	event := events.Event{
+		Kind:      "Pod",
+		Name:      "nginx",
+		Namespace: "default",
+		Cluster:   "test",
+		Type:      config.UpdateEvent,
+		Diff:      "spec.containers[*].image:\n\t-: nginx:1.14\n\t+: nginx:1.15\n",
+	}
+	attachment := slackLongNotification(event)
+
+	var diff string
+	for _, field := range attachment.Fields {
+		if field.Title == "Diff" {
+			diff = field.Value
+		}
+		if field.Title == "Message" {
+			t.Errorf("expected no Message field, got: %s", field.Value)
+		}
+	}
+	expected := "```\nspec.containers[*].image:\n\t-: nginx:1.14\n\t+: nginx:1.15\n```"
+	if diff != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, diff)
+	}
+}
+
+func TestAddAckButton(t *testing.T) {
+	tests := map[string]struct {
+		event    events.Event
+		expected string
+	}{
+		`namespaced resource`: {
+			event:    events.Event{Kind: "Pod", Name: "nginx", Namespace: "default"},
+			expected: "Pod default/nginx",
+		},
+		`cluster scoped resource`: {
+			event:    events.Event{Kind: "Node", Name: "worker-1"},
+			expected: "Node worker-1",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			attachment := slackShortNotification(test.event)
+			addAckButton(&attachment, test.event)
+			if attachment.CallbackID != SlackAckCallbackID || len(attachment.Actions) != 1 {
+				t.Fatalf("expected ack button, got: %+v", attachment)
+			}
+			if actual := attachment.Actions[0].Value; actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFormatSlackMessageColors(t *testing.T) {
+	colors := slackColors(map[config.Level]string{
+		config.Error: "#e01e5a",
+		config.Warn:  "orange",
+		"fatal":      "#000000",
+	})
+	tests := map[string]struct {
+		level    config.Level
+		expected string
+	}{
+		`custom hex color`:         {config.Error, "#e01e5a"},
+		`default color`:            {config.Critical, "danger"},
+		`invalid color is ignored`: {config.Warn, "warning"},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			event := events.Event{Kind: "Pod", Name: "nginx", Namespace: "default", Level: test.level}
+			for _, notifType := range []config.NotifType{config.ShortNotify, config.LongNotify} {
+				if actual := formatSlackMessage(event, notifType, colors).Color; actual != test.expected {
+					t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+				}
+			}
+		})
+	}
+	if actual := formatSlackMessage(events.Event{Kind: "Pod", Level: config.Error}, config.ShortNotify, nil).Color; actual != "danger" {
+		t.Errorf("expected: %+v != actual: %+v\n", "danger", actual)
+	}
+	if _, ok := colors["fatal"]; ok {
+		t.Errorf("expected no color for unknown level")
+	}
+}
+
+func TestNewSlackAckButton(t *testing.T) {
+	tests := map[string]struct {
+		conf     config.Slack
+		expected bool
+	}{
+		`disabled`:            {config.Slack{Mode: config.SlackSocketMode}, false},
+		`enabled in rtm mode`: {config.Slack{AckButton: true}, false},
+		`enabled in socket mode`: {
+			config.Slack{AckButton: true, Mode: config.SlackSocketMode}, true,
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := NewSlack(test.conf).(*Slack).ackButton; actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSlackSendEventChannelNotFound(t *testing.T) {
+	var mu sync.Mutex
+	var posts []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		channel := r.FormValue("channel")
+		kind := "event"
+		if len(r.FormValue("text")) != 0 {
+			kind = "warning"
+		}
+		mu.Lock()
+		posts = append(posts, channel+" "+kind)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if channel == "missing" {
+			_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"channel":"` + channel + `","ts":"1.1"}`))
+	}))
+	defer ts.Close()
+
+	s := &Slack{
+		Channel: "general",
+		Client:  slack.New("token", slack.OptionAPIURL(ts.URL+"/")),
+	}
+	event := events.Event{Kind: "Pod", Name: "nginx", Namespace: "default", Type: config.CreateEvent, Channel: "missing"}
+	for i := 0; i < 3; i++ {
+		if err := s.SendEvent(event); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	// The warning is sent once, each event is retried once in the default channel
+	expected := []string{
+		"missing event", "general warning", "general event",
+		"missing event", "general event",
+		"missing event", "general event",
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(posts, expected) {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, posts)
+	}
+}
+
+func TestChannelWarningsAllow(t *testing.T) {
+	var w channelWarnings
+	now := time.Now()
+	tests := []struct {
+		channel  string
+		at       time.Time
+		expected bool
+	}{
+		{"team-a", now, true},
+		{"team-a", now.Add(time.Minute), false},
+		{"team-b", now.Add(time.Minute), true},
+		{"team-a", now.Add(channelWarningInterval), true},
+	}
+	for i, test := range tests {
+		if actual := w.allow(test.channel, test.at); actual != test.expected {
+			t.Errorf("%d: expected: %+v != actual: %+v\n", i, test.expected, actual)
+		}
+	}
+}
+
+func TestSlackSendEventMentions(t *testing.T) {
+	var mu sync.Mutex
+	var texts []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		texts = append(texts, r.FormValue("channel")+" "+r.FormValue("text"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true,"channel":"` + r.FormValue("channel") + `","ts":"1.1"}`))
+	}))
+	defer ts.Close()
+
+	s := &Slack{
+		Channel: "general",
+		Client:  slack.New("token", slack.OptionAPIURL(ts.URL+"/")),
+		mentions: map[string]config.SlackMention{
+			"general": {Text: "<!subteam^S123>", MinSeverity: config.Critical},
+			"team-a":  {Text: "<!here>", MinSeverity: config.Error},
+		},
+	}
+	sent := []events.Event{
+		{Kind: "Pod", Name: "nginx", Namespace: "default", Type: config.ErrorEvent, Level: config.Error},
+		{Kind: "Pod", Name: "nginx", Namespace: "default", Type: config.ErrorEvent, Level: config.Critical},
+		{Kind: "Pod", Name: "nginx", Namespace: "default", Type: config.ErrorEvent, Level: config.Warn, Channel: "team-a"},
+		{Kind: "Pod", Name: "nginx", Namespace: "default", Type: config.ErrorEvent, Level: config.Error, Channel: "#team-a"},
+		{Kind: "Pod", Name: "nginx", Namespace: "default", Type: config.ErrorEvent, Level: config.Critical, Channel: "team-b"},
+	}
+	for _, event := range sent {
+		if err := s.SendEvent(event); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	expected := []string{
+		"general ",
+		"general <!subteam^S123>",
+		"team-a ",
+		"#team-a <!here>",
+		"team-b ",
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(texts, expected) {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, texts)
+	}
+}
+
+func TestSlackSendEventUpdateInPlace(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.URL.Path+" "+r.FormValue("channel")+" "+r.FormValue("ts"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.FormValue("ts") == "3.1" {
+			_, _ = w.Write([]byte(`{"ok":false,"error":"message_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"channel":"C1","ts":"` + fmt.Sprintf("%d.1", len(calls)) + `"}`))
+	}))
+	defer ts.Close()
+
+	s := NewSlack(config.Slack{Channel: "general", UpdateInPlace: true}).(*Slack)
+	s.Client = slack.New("token", slack.OptionAPIURL(ts.URL+"/"))
+	pod := events.Event{Kind: "Pod", Name: "nginx", Namespace: "default"}
+	send := func(eventType config.EventType, name string) {
+		event := pod
+		event.Type = eventType
+		event.Name = name
+		if err := s.SendEvent(event); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	send(config.CreateEvent, "nginx")
+	send(config.UpdateEvent, "nginx")
+	send(config.CreateEvent, "redis")
+	send(config.ErrorEvent, "nginx")
+	send(config.UpdateEvent, "redis")
+	send(config.UpdateEvent, "nginx")
+	send(config.DeleteEvent, "nginx")
+	send(config.CreateEvent, "nginx")
+
+	expected := []string{
+		"/chat.postMessage general ",
+		"/chat.update C1 1.1",
+		"/chat.postMessage general ",
+		"/chat.postMessage general ",
+		// Posted again when the message can't be updated
+		"/chat.update C1 3.1",
+		"/chat.postMessage general ",
+		"/chat.update C1 1.1",
+		"/chat.postMessage general ",
+		"/chat.postMessage general ",
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, calls)
+	}
+}
+
+func TestSlackSendEventThreads(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.FormValue("channel")+" "+r.FormValue("thread_ts"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.FormValue("thread_ts") == "3.1" {
+			_, _ = w.Write([]byte(`{"ok":false,"error":"thread_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"channel":"C1","ts":"` + fmt.Sprintf("%d.1", len(calls)) + `"}`))
+	}))
+	defer ts.Close()
+
+	s := NewSlack(config.Slack{Channel: "general", ThreadEvents: true}).(*Slack)
+	s.Client = slack.New("token", slack.OptionAPIURL(ts.URL+"/"))
+	send := func(correlationID string) {
+		event := events.Event{Kind: "Pod", Name: "backup-x2b4k", Namespace: "default", Type: config.CreateEvent, CorrelationID: correlationID}
+		if err := s.SendEvent(event); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	send("Job/default/backup/1")
+	send("Job/default/backup/1")
+	send("Job/default/restore/2")
+	send("")
+	send("Job/default/restore/2")
+	send("Job/default/restore/2")
+	send("Job/default/backup/1")
+
+	expected := []string{
+		"general ",
+		"general 1.1",
+		"general ",
+		"general ",
+		// A new thread is started when the reply fails
+		"general 3.1",
+		"general ",
+		"general 6.1",
+		"general 1.1",
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, calls)
+	}
+}
+
+func TestPostedMessagesExpire(t *testing.T) {
+	p := postedMessages{ttl: time.Hour}
+	now := time.Now()
+	p.set("general/Pod/default/nginx", postedMessage{channelID: "C1", timestamp: "1.1"}, now)
+	tests := []struct {
+		key      string
+		at       time.Time
+		expected bool
+	}{
+		{"general/Pod/default/nginx", now.Add(time.Minute), true},
+		{"general/Pod/default/redis", now.Add(time.Minute), false},
+		{"general/Pod/default/nginx", now.Add(time.Hour), false},
+	}
+	for i, test := range tests {
+		if _, actual := p.get(test.key, test.at); actual != test.expected {
+			t.Errorf("%d: expected: %+v != actual: %+v\n", i, test.expected, actual)
+		}
+	}
+
+	p.set("general/Pod/default/redis", postedMessage{channelID: "C1", timestamp: "2.1"}, now.Add(2*time.Hour))
+	if _, ok := p.messages["general/Pod/default/nginx"]; ok {
+		t.Errorf("expected expired message to be dropped")
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+// defaultShortTemplate renders event in short format, used when settings.templates.short is not set
+const defaultShortTemplate = `
+{{- $name := printf "%s/%s" .Namespace .Name }}{{ if or .ClusterScoped (clusterScoped .Kind) }}{{ $name = .Name }}{{ end }}
+{{- if or (eq .Type "create") (eq .Type "update") (eq .Type "delete") }}{{ .Kind }} *{{ $name }}* {{ eventVerb .Type }} in *{{ .Cluster }}* cluster
+{{ else if eq .Type "error" }}Error Occurred in {{ .Kind }}: *{{ $name }}* in *{{ .Cluster }}* cluster
+{{ else if eq .Type "warning" }}Warning {{ .Kind }}: *{{ $name }}* in *{{ .Cluster }}* cluster
+{{ else if or (eq .Type "info") (eq .Type "normal") }}{{ .Kind }} Info: *{{ $name }}* in *{{ .Cluster }}* cluster
+{{ end }}
+{{- if or .Messages .Diff .Recommendations .Warnings }}` + "```" + `
+{{ range .Messages }}{{ . }}
+{{ end }}
+{{- if .Diff }}{{ .Diff }}
+{{ end }}
+{{- if .Recommendations }}Recommendations:
+{{ range .Recommendations }}- {{ . }}
+{{ end }}{{ end }}
+{{- if .Warnings }}Warnings:
+{{ range .Warnings }}- {{ . }}
+{{ end }}{{ end }}` + "```" + `{{ end }}`
+
+var (
+	templateFuncs = template.FuncMap{
+		"clusterScoped": isClusterScoped,
+		"eventVerb":     eventVerb,
+	}
+
+	defaultShortMessageTemplate = template.Must(template.New("short").Funcs(templateFuncs).Parse(defaultShortTemplate))
+
+	templatesMu      sync.RWMutex
+	shortMessageTmpl = defaultShortMessageTemplate
+	// customClusterScopedKinds contains the lowercase kinds from settings.clusterScopedKinds
+	customClusterScopedKinds = map[string]bool{}
+	// eventVerbs contains the wording from settings.eventVerbs keyed by event type
+	eventVerbs = map[config.EventType]string{}
+)
+
+// InitTemplates parses notification templates from settings.templates
+// Returns error if any of the templates is invalid
+func InitTemplates(c config.Templates) error {
+	short := defaultShortMessageTemplate
+	if len(c.Short) != 0 {
+		var err error
+		if short, err = parseTemplate("short", c.Short); err != nil {
+			return err
+		}
+	}
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+	shortMessageTmpl = short
+	return nil
+}
+
+// parseTemplate parses the template and renders it with an empty event to catch invalid fields
+func parseTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s template. %s", name, err.Error())
+	}
+	if _, err := renderTemplate(tmpl, events.Event{}); err != nil {
+		return nil, fmt.Errorf("Invalid %s template. %s", name, err.Error())
+	}
+	return tmpl, nil
+}
+
+func renderTemplate(tmpl *template.Template, event events.Event) (string, error) {
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, event); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// InitClusterScopedKinds sets the custom resource kinds from settings.clusterScopedKinds
+// Events of these kinds are rendered without the namespace
+func InitClusterScopedKinds(kinds []string) {
+	custom := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		custom[strings.ToLower(kind)] = true
+	}
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+	customClusterScopedKinds = custom
+}
+
+// isClusterScoped checks if the kind is listed in settings.clusterScopedKinds
+func isClusterScoped(kind string) bool {
+	templatesMu.RLock()
+	defer templatesMu.RUnlock()
+	return customClusterScopedKinds[strings.ToLower(kind)]
+}
+
+// IsClusterScoped checks if the event is for a cluster scoped object
+// Kinds in settings.clusterScopedKinds are cluster scoped even if the event has a namespace
+func IsClusterScoped(event events.Event) bool {
+	return event.ClusterScoped || isClusterScoped(event.Kind)
+}
+
+// InitEventVerbs sets the wording of create, update and delete events from settings.eventVerbs
+func InitEventVerbs(verbs map[string]string) {
+	custom := make(map[config.EventType]string, len(verbs))
+	for eventType, verb := range verbs {
+		if verb = strings.TrimSpace(verb); len(verb) != 0 {
+			custom[config.EventType(strings.ToLower(eventType))] = verb
+		}
+	}
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+	eventVerbs = custom
+}
+
+// eventVerb returns the wording for the event type, e.g "has been created" for create events
+func eventVerb(eventType config.EventType) string {
+	templatesMu.RLock()
+	verb, ok := eventVerbs[eventType]
+	templatesMu.RUnlock()
+	if ok {
+		return verb
+	}
+	return fmt.Sprintf("has been %sd", eventType)
+}
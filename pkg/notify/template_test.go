@@ -0,0 +1,168 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"testing"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+func TestFormatShortMessageDefaultTemplate(t *testing.T) {
+	tests := map[string]struct {
+		event    events.Event
+		expected string
+	}{
+		`create event`: {
+			events.Event{Kind: "Pod", Name: "nginx", Namespace: "default", Cluster: "test", Type: config.CreateEvent},
+			"Pod *default/nginx* has been created in *test* cluster\n",
+		},
+		`cluster scoped resource`: {
+			events.Event{Kind: "Node", Name: "node-1", Cluster: "test", Type: config.DeleteEvent, ClusterScoped: true},
+			"Node *node-1* has been deleted in *test* cluster\n",
+		},
+		`update event with diff`: {
+			events.Event{Kind: "Pod", Name: "nginx", Namespace: "default", Cluster: "test", Type: config.UpdateEvent, Diff: "spec.containers[*].image:\n\t-: nginx:1.14\n\t+: nginx:1.15\n"},
+			"Pod *default/nginx* has been updated in *test* cluster\n```\nspec.containers[*].image:\n\t-: nginx:1.14\n\t+: nginx:1.15\n\n```",
+		},
+		`cluster scoped resource not known to botkube`: {
+			events.Event{Kind: "StorageClass", Name: "standard", Cluster: "test", Type: config.CreateEvent, ClusterScoped: true},
+			"StorageClass *standard* has been created in *test* cluster\n",
+		},
+		`error event with messages`: {
+			events.Event{Kind: "Pod", Name: "nginx", Namespace: "default", Cluster: "test", Type: config.ErrorEvent, Messages: []string{"Back-off restarting failed container"}},
+			"Error Occurred in Pod: *default/nginx* in *test* cluster\n```\nBack-off restarting failed container\n```",
+		},
+		`warning event with recommendations and warnings`: {
+			events.Event{Kind: "Pod", Name: "nginx", Namespace: "default", Cluster: "test", Type: config.WarningEvent, Recommendations: []string{"r1"}, Warnings: []string{"w1", "w2"}},
+			"Warning Pod: *default/nginx* in *test* cluster\n```\nRecommendations:\n- r1\nWarnings:\n- w1\n- w2\n```",
+		},
+		`normal event`: {
+			events.Event{Kind: "Namespace", Name: "dev", Cluster: "test", Type: config.NormalEvent, ClusterScoped: true},
+			"Namespace Info: *dev* in *test* cluster\n",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := FormatShortMessage(test.event); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFormatShortMessageCustomResource(t *testing.T) {
+	InitClusterScopedKinds([]string{"clusterissuer"})
+	defer InitClusterScopedKinds(nil)
+
+	tests := map[string]struct {
+		event    events.Event
+		expected string
+	}{
+		`namespaced custom resource`: {
+			events.Event{Kind: "Foo", Name: "example-foo", Namespace: "default", Cluster: "test", Type: config.CreateEvent},
+			"Foo *default/example-foo* has been created in *test* cluster\n",
+		},
+		`configured cluster scoped kind`: {
+			// Events from k8s events informer can carry a namespace for cluster scoped objects
+			events.Event{Kind: "ClusterIssuer", Name: "letsencrypt", Namespace: "default", Cluster: "test", Type: config.ErrorEvent},
+			"Error Occurred in ClusterIssuer: *letsencrypt* in *test* cluster\n",
+		},
+		`custom resource without namespace`: {
+			events.Event{Kind: "Tenant", Name: "acme", Cluster: "test", Type: config.DeleteEvent, ClusterScoped: true},
+			"Tenant *acme* has been deleted in *test* cluster\n",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := FormatShortMessage(test.event); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestInitTemplates(t *testing.T) {
+	defer InitTemplates(config.Templates{})
+	event := events.Event{Kind: "Pod", Name: "nginx", Namespace: "default", Cluster: "test", Type: config.CreateEvent}
+	tests := map[string]struct {
+		templates   config.Templates
+		expectedErr bool
+		expected    string
+	}{
+		`no template configured`: {config.Templates{}, false, "Pod *default/nginx* has been created in *test* cluster\n"},
+		`custom template`:        {config.Templates{Short: "{{ .Kind }} {{ .Name }} {{ .Type }}d"}, false, "Pod nginx created"},
+		`invalid syntax`:         {config.Templates{Short: "{{ .Kind "}, true, ""},
+		`unknown field`:          {config.Templates{Short: "{{ .Unknown }}"}, true, ""},
+		`render error falls back to default`: {
+			config.Templates{Short: "{{ if .Name }}{{ index .Messages 3 }}{{ end }}"}, false, "Pod *default/nginx* has been created in *test* cluster\n",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			InitTemplates(config.Templates{})
+			err := InitTemplates(test.templates)
+			if (err != nil) != test.expectedErr {
+				t.Fatalf("expected error: %+v != actual: %+v\n", test.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if actual := FormatShortMessage(event); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFormatShortMessageEventVerbs(t *testing.T) {
+	InitEventVerbs(map[string]string{"Create": "was deployed", "delete": ""})
+	defer InitEventVerbs(nil)
+
+	tests := map[string]struct {
+		event    events.Event
+		expected string
+	}{
+		`custom verb`: {
+			events.Event{Kind: "Pod", Name: "nginx", Namespace: "default", Cluster: "test", Type: config.CreateEvent},
+			"Pod *default/nginx* was deployed in *test* cluster\n",
+		},
+		`default verb`: {
+			events.Event{Kind: "Pod", Name: "nginx", Namespace: "default", Cluster: "test", Type: config.UpdateEvent},
+			"Pod *default/nginx* has been updated in *test* cluster\n",
+		},
+		`empty verb falls back to default`: {
+			events.Event{Kind: "Pod", Name: "nginx", Namespace: "default", Cluster: "test", Type: config.DeleteEvent},
+			"Pod *default/nginx* has been deleted in *test* cluster\n",
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := FormatShortMessage(test.event); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+// threadExpiry is the duration after which events of a resource start a new thread
+const threadExpiry = time.Hour
+
+// threadStore maps resources to the root message of their thread
+type threadStore struct {
+	mu      sync.Mutex
+	threads map[string]thread
+}
+
+type thread struct {
+	rootID    string
+	createdAt time.Time
+}
+
+// threadKey returns key to identify thread of the event resource in a channel
+func threadKey(channel string, event events.Event) string {
+	return fmt.Sprintf("%s/%s/%s/%s", channel, event.Kind, event.Namespace, event.Name)
+}
+
+// correlationThreadKey returns key to identify thread of the correlation ID in a channel
+func correlationThreadKey(channel, correlationID string) string {
+	return fmt.Sprintf("%s/%s", channel, correlationID)
+}
+
+// get returns root message ID of the thread, empty if thread doesn't exist or is expired
+func (s *threadStore) get(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.threads[key]
+	if !ok {
+		return ""
+	}
+	if time.Since(t.createdAt) > threadExpiry {
+		delete(s.threads, key)
+		return ""
+	}
+	return t.rootID
+}
+
+// set stores root message ID of the thread and removes expired threads
+func (s *threadStore) set(key, rootID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.threads == nil {
+		s.threads = map[string]thread{}
+	}
+	for k, t := range s.threads {
+		if time.Since(t.createdAt) > threadExpiry {
+			delete(s.threads, k)
+		}
+	}
+	s.threads[key] = thread{rootID: rootID, createdAt: time.Now()}
+}
+
+// delete removes the thread
+func (s *threadStore) delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.threads, key)
+}
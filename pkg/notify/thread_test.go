@@ -0,0 +1,67 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/events"
+)
+
+func TestThreadStore(t *testing.T) {
+	pod := events.Event{Kind: "Pod", Namespace: "default", Name: "nginx"}
+	tests := map[string]struct {
+		threads  map[string]thread
+		key      string
+		expected string
+	}{
+		`new resource`:            {map[string]thread{}, threadKey("channel", pod), ""},
+		`existing thread`:         {map[string]thread{threadKey("channel", pod): {"post-1", time.Now()}}, threadKey("channel", pod), "post-1"},
+		`thread in other channel`: {map[string]thread{threadKey("channel", pod): {"post-1", time.Now()}}, threadKey("other", pod), ""},
+		`expired thread`:          {map[string]thread{threadKey("channel", pod): {"post-1", time.Now().Add(-2 * threadExpiry)}}, threadKey("channel", pod), ""},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			s := threadStore{threads: test.threads}
+			if actual := s.get(test.key); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestThreadStoreSet(t *testing.T) {
+	s := threadStore{}
+	s.set("expired", "post-0")
+	s.threads["expired"] = thread{"post-0", time.Now().Add(-2 * threadExpiry)}
+	s.set("active", "post-1")
+	if _, ok := s.threads["expired"]; ok {
+		t.Errorf("expected expired thread to be removed")
+	}
+	if actual := s.get("active"); actual != "post-1" {
+		t.Errorf("expected: %+v != actual: %+v\n", "post-1", actual)
+	}
+	s.delete("active")
+	if actual := s.get("active"); actual != "" {
+		t.Errorf("expected: %+v != actual: %+v\n", "", actual)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/log"
+)
+
+const throttleDroppedMsg = "BotKube dropped %d messages to this channel over the limit of %d messages per minute."
+
+// sendLimiter is a token bucket per channel refilled with limit tokens per minute
+// In queue mode a worker per channel sends in FIFO order as tokens are available, up to limit sends wait in the queue
+// and the rest is dropped. In drop mode sends without a token are dropped
+type sendLimiter struct {
+	limit int
+	queue bool
+	// summary is called with the number of dropped sends once the channel has a token again
+	summary func(channel string, dropped int)
+	// sleep waits for the token of the queued send
+	sleep func(time.Duration)
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	queues  map[string]chan queuedSend
+}
+
+// tokenBucket holds the tokens of a channel at last, tokens are negative if the queue worker waits for one
+type tokenBucket struct {
+	tokens  float64
+	last    time.Time
+	dropped int
+}
+
+// queuedSend is a send waiting in the channel queue, its result is passed to done
+type queuedSend struct {
+	send func() error
+	done chan error
+}
+
+// newSendLimiter returns the limiter of communications.slack.maxSendsPerMinute, nil if the limit is disabled
+func newSendLimiter(limit int, mode config.SlackThrottleMode, summary func(channel string, dropped int)) *sendLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &sendLimiter{
+		limit:   limit,
+		queue:   mode != config.SlackThrottleDrop,
+		summary: summary,
+		sleep:   time.Sleep,
+		buckets: make(map[string]*tokenBucket),
+		queues:  make(map[string]chan queuedSend),
+	}
+}
+
+// send runs the send to the channel under the limit and returns its error, dropped sends return nil
+// The limit is disabled if the limiter is nil
+func (l *sendLimiter) send(channel string, send func() error) error {
+	if l == nil {
+		return send()
+	}
+	if !l.queue {
+		if !l.take(channel, time.Now()) {
+			return nil
+		}
+		return send()
+	}
+	done := make(chan error, 1)
+	if !l.enqueue(channel, queuedSend{send: send, done: done}) {
+		return nil
+	}
+	return <-done
+}
+
+// take uses a token of the channel at now, it returns false if there is none and the send is dropped
+func (l *sendLimiter) take(channel string, now time.Time) bool {
+	channel = strings.TrimPrefix(channel, "#")
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.bucket(channel, now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true
+	}
+	l.drop(channel, b, l.tokenWait(1-b.tokens))
+	return false
+}
+
+// enqueue adds the send to the channel queue and starts its worker if needed
+// It returns false if the queue is full and the send is dropped
+func (l *sendLimiter) enqueue(channel string, send queuedSend) bool {
+	channel = strings.TrimPrefix(channel, "#")
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	q, ok := l.queues[channel]
+	if !ok {
+		q = make(chan queuedSend, l.limit)
+		l.queues[channel] = q
+		go l.drain(channel, q)
+	}
+	select {
+	case q <- send:
+		return true
+	default:
+	}
+	// The summary is sent once the queued sends are drained
+	l.drop(channel, l.bucket(channel, time.Now()), l.tokenWait(float64(len(q))))
+	return false
+}
+
+// drain runs the queued sends of the channel in order, waiting for a token before each of them
+// The worker stops once the queue is empty, enqueue starts a new one
+func (l *sendLimiter) drain(channel string, q chan queuedSend) {
+	for {
+		l.mu.Lock()
+		if len(q) == 0 {
+			delete(l.queues, channel)
+			l.mu.Unlock()
+			return
+		}
+		send := <-q
+		b := l.bucket(channel, time.Now())
+		b.tokens--
+		wait := l.tokenWait(-b.tokens)
+		l.mu.Unlock()
+
+		if wait > 0 {
+			l.sleep(wait)
+		}
+		send.done <- send.send()
+	}
+}
+
+// bucket returns the bucket of the channel refilled up to now, l.mu must be held
+func (l *sendLimiter) bucket(channel string, now time.Time) *tokenBucket {
+	b, ok := l.buckets[channel]
+	if !ok {
+		b = &tokenBucket{tokens: float64(l.limit), last: now}
+		l.buckets[channel] = b
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Minutes() * float64(l.limit)
+		if b.tokens > float64(l.limit) {
+			b.tokens = float64(l.limit)
+		}
+		b.last = now
+	}
+	return b
+}
+
+// drop counts the dropped send, the summary of the dropped sends is scheduled after wait on the first one
+// l.mu must be held
+func (l *sendLimiter) drop(channel string, b *tokenBucket, wait time.Duration) {
+	log.Debugf("Dropping slack message to channel %s over maxSendsPerMinute", channel)
+	b.dropped++
+	if b.dropped == 1 && l.summary != nil {
+		time.AfterFunc(wait, func() { l.flush(channel) })
+	}
+}
+
+// tokenWait returns how long to wait for the number of tokens
+func (l *sendLimiter) tokenWait(tokens float64) time.Duration {
+	return time.Duration(tokens * float64(time.Minute) / float64(l.limit))
+}
+
+// flush reports the dropped sends of the channel and resets their count
+func (l *sendLimiter) flush(channel string) {
+	l.mu.Lock()
+	var dropped int
+	if b, ok := l.buckets[channel]; ok {
+		dropped = b.dropped
+		b.dropped = 0
+	}
+	l.mu.Unlock()
+	if dropped != 0 {
+		l.summary(channel, dropped)
+	}
+}
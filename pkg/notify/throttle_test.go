@@ -0,0 +1,236 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
+	"github.com/nlopes/slack"
+)
+
+func TestNewSendLimiter(t *testing.T) {
+	if l := newSendLimiter(0, config.SlackThrottleQueue, nil); l != nil {
+		t.Errorf("expected: nil limiter != actual: %+v\n", l)
+	}
+	if l := newSendLimiter(10, "", nil); l == nil || !l.queue {
+		t.Errorf("expected: queue limiter != actual: %+v\n", l)
+	}
+	if l := newSendLimiter(10, config.SlackThrottleDrop, nil); l == nil || l.queue {
+		t.Errorf("expected: drop limiter != actual: %+v\n", l)
+	}
+}
+
+func TestSendLimiterTake(t *testing.T) {
+	now := time.Now()
+	type take struct {
+		channel string
+		at      time.Time
+		ok      bool
+	}
+	tests := map[string]struct {
+		takes   []take
+		summary map[string]int
+	}{
+		`drop over the limit`: {
+			takes: []take{
+				{"general", now, true},
+				{"general", now, true},
+				{"#general", now, false},
+				{"team-a", now, true},
+				{"general", now.Add(10 * time.Second), false},
+				{"general", now.Add(30 * time.Second), true},
+				{"general", now.Add(30 * time.Second), false},
+			},
+			summary: map[string]int{"general": 3},
+		},
+		`refill up to the limit`: {
+			takes: []take{
+				{"general", now, true},
+				{"general", now.Add(time.Hour), true},
+				{"general", now.Add(time.Hour), true},
+				{"general", now.Add(time.Hour), false},
+			},
+			summary: map[string]int{"general": 1},
+		},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			summary := map[string]int{}
+			l := newSendLimiter(2, config.SlackThrottleDrop, func(channel string, dropped int) {
+				mu.Lock()
+				defer mu.Unlock()
+				summary[channel] += dropped
+			})
+			for i, take := range test.takes {
+				if ok := l.take(take.channel, take.at); ok != take.ok {
+					t.Errorf("%d: expected: %+v != actual: %+v\n", i, take.ok, ok)
+				}
+			}
+			l.flush("general")
+			l.flush("team-a")
+			mu.Lock()
+			defer mu.Unlock()
+			if !reflect.DeepEqual(summary, test.summary) {
+				t.Errorf("expected: %+v != actual: %+v\n", test.summary, summary)
+			}
+		})
+	}
+}
+
+func TestSendLimiterQueue(t *testing.T) {
+	var mu sync.Mutex
+	var sent []int
+	var sleeps []time.Duration
+	dropped := 0
+	l := newSendLimiter(2, config.SlackThrottleQueue, func(channel string, n int) {
+		mu.Lock()
+		defer mu.Unlock()
+		dropped += n
+	})
+	l.sleep = func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		sleeps = append(sleeps, d)
+	}
+	queued := func() int {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return len(l.queues["general"])
+	}
+
+	// The first send blocks the worker until released, the next ones wait in the queue
+	started, release := make(chan struct{}), make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = l.send("#general", func() error {
+				if i == 0 {
+					close(started)
+					<-release
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				sent = append(sent, i)
+				return nil
+			})
+		}()
+		if i == 0 {
+			<-started
+			continue
+		}
+		for queued() != i {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	// The queue holds limit sends, the rest is dropped
+	if err := l.send("general", func() error { t.Error("expected send to be dropped"); return nil }); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	close(release)
+	wg.Wait()
+	l.flush("general")
+
+	mu.Lock()
+	defer mu.Unlock()
+	if expected := []int{0, 1, 2}; !reflect.DeepEqual(sent, expected) {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, sent)
+	}
+	// The third send waits for a token, about 30s with limit 2
+	if len(sleeps) != 1 || sleeps[0] < 29*time.Second || sleeps[0] > 30*time.Second {
+		t.Errorf("expected: one sleep of about 30s != actual: %+v\n", sleeps)
+	}
+	if dropped != 1 {
+		t.Errorf("expected: 1 dropped != actual: %+v\n", dropped)
+	}
+	// The worker stops once the queue is drained
+	for i := 0; i < 100 && queuedWorker(l, "general"); i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if queuedWorker(l, "general") {
+		t.Errorf("expected queue worker to stop")
+	}
+}
+
+// queuedWorker returns true if the channel has a queue worker running
+func queuedWorker(l *sendLimiter, channel string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.queues[channel]
+	return ok
+}
+
+func TestSendLimiterSendError(t *testing.T) {
+	var l *sendLimiter
+	expected := errors.New("channel_not_found")
+	if err := l.send("general", func() error { return expected }); err != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, err)
+	}
+	l = newSendLimiter(2, config.SlackThrottleQueue, nil)
+	if err := l.send("general", func() error { return expected }); err != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, err)
+	}
+}
+
+func TestSlackSendEventThrottle(t *testing.T) {
+	var mu sync.Mutex
+	var texts []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		texts = append(texts, r.FormValue("channel")+" "+r.FormValue("text"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true,"channel":"` + r.FormValue("channel") + `","ts":"1.1"}`))
+	}))
+	defer ts.Close()
+
+	s := NewSlack(config.Slack{Channel: "general", MaxSendsPerMinute: 2, ThrottleMode: config.SlackThrottleDrop}).(*Slack)
+	s.Client = slack.New("token", slack.OptionAPIURL(ts.URL+"/"))
+	for i := 0; i < 5; i++ {
+		event := events.Event{Kind: "Pod", Name: "nginx", Namespace: "default", Type: config.ErrorEvent, Level: config.Error}
+		if err := s.SendEvent(event); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	s.limiter.flush("general")
+
+	expected := []string{
+		"general ",
+		"general ",
+		"general BotKube dropped 3 messages to this channel over the limit of 2 messages per minute.",
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(texts, expected) {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, texts)
+	}
+}
@@ -34,10 +34,40 @@ import (
 // Webhook contains URL
 type Webhook struct {
 	URL string
+
+	minSeverity config.Level
 }
 
+// WebhookAPIVersion is the version of the webhook payload schema
+// It changes only when fields are removed or changed in an incompatible way
+const WebhookAPIVersion = "botkube.io/v1"
+
+// WebhookPayloadType distinguishes the content of webhook payloads
+type WebhookPayloadType string
+
+const (
+	// WebhookEventType payload contains an event in the event field
+	WebhookEventType WebhookPayloadType = "event"
+	// WebhookCommandResultType payload contains a command and its output in the commandResult field
+	WebhookCommandResultType WebhookPayloadType = "commandResult"
+	// WebhookMessageType payload contains a plain message, e.g BotKube start or stop message, in the message field
+	WebhookMessageType WebhookPayloadType = "message"
+)
+
 // WebhookPayload contains json payload to be sent to webhook url
+// Only the field matching the payload type is set, e.g
+//
+//	{"apiVersion": "botkube.io/v1", "type": "event", "event": {"meta": {...}, "status": {...}, "summary": "...", ...}}
 type WebhookPayload struct {
+	APIVersion    string                `json:"apiVersion"`
+	Type          WebhookPayloadType    `json:"type"`
+	Event         *WebhookEvent         `json:"event,omitempty"`
+	CommandResult *WebhookCommandResult `json:"commandResult,omitempty"`
+	Message       string                `json:"message,omitempty"`
+}
+
+// WebhookEvent contains the event details in webhook payload
+type WebhookEvent struct {
 	EventMeta       EventMeta   `json:"meta"`
 	EventStatus     EventStatus `json:"status"`
 	EventSummary    string      `json:"summary"`
@@ -46,6 +76,16 @@ type WebhookPayload struct {
 	Warnings        []string    `json:"warnings,omitempty"`
 }
 
+// WebhookCommandResult contains the command executed and its output in webhook payload
+// It is also the reply to commands with --json-result flag, output is the stdout and error the stderr of kubectl commands
+type WebhookCommandResult struct {
+	Command  string `json:"command"`
+	Cluster  string `json:"cluster,omitempty"`
+	ExitCode int    `json:"exitCode"`
+	Output   string `json:"output"`
+	Error    string `json:"error,omitempty"`
+}
+
 // EventMeta contains the meta data about the event occurred
 type EventMeta struct {
 	Kind      string `json:"kind"`
@@ -67,30 +107,19 @@ type EventStatus struct {
 func NewWebhook(c config.CommunicationsConfig) Notifier {
 	return &Webhook{
 		URL: c.Webhook.URL,
+
+		minSeverity: c.Webhook.MinSeverity,
 	}
 }
 
+// MinSeverity returns minimum level of events posted to webhook
+func (w *Webhook) MinSeverity() config.Level {
+	return w.minSeverity
+}
+
 // SendEvent sends event notification to Webhook url
 func (w *Webhook) SendEvent(event events.Event) (err error) {
-	jsonPayload := &WebhookPayload{
-		EventMeta: EventMeta{
-			Kind:      event.Kind,
-			Name:      event.Name,
-			Namespace: event.Namespace,
-			Cluster:   event.Cluster,
-		},
-		EventStatus: EventStatus{
-			Type:     event.Type,
-			Level:    event.Level,
-			Reason:   event.Reason,
-			Error:    event.Error,
-			Messages: event.Messages,
-		},
-		EventSummary:    FormatShortMessage(event),
-		TimeStamp:       event.TimeStamp,
-		Recommendations: event.Recommendations,
-		Warnings:        event.Warnings,
-	}
+	jsonPayload := newWebhookEventPayload(event)
 
 	err = w.PostWebhook(jsonPayload)
 	if err != nil {
@@ -102,8 +131,55 @@ func (w *Webhook) SendEvent(event events.Event) (err error) {
 	return nil
 }
 
+// newWebhookEventPayload returns the payload of event type for the event
+func newWebhookEventPayload(event events.Event) *WebhookPayload {
+	// Diff is sent as a message, webhook payload had it in messages before it was a separate field
+	messages := event.Messages
+	if len(event.Diff) > 0 {
+		messages = append(append([]string{}, event.Messages...), event.Diff)
+	}
+	return &WebhookPayload{
+		APIVersion: WebhookAPIVersion,
+		Type:       WebhookEventType,
+		Event: &WebhookEvent{
+			EventMeta: EventMeta{
+				Kind:      event.Kind,
+				Name:      event.Name,
+				Namespace: event.Namespace,
+				Cluster:   event.Cluster,
+			},
+			EventStatus: EventStatus{
+				Type:     event.Type,
+				Level:    event.Level,
+				Reason:   event.Reason,
+				Error:    event.Error,
+				Messages: messages,
+			},
+			EventSummary:    FormatShortMessage(event),
+			TimeStamp:       event.TimeStamp,
+			Recommendations: event.Recommendations,
+			Warnings:        event.Warnings,
+		},
+	}
+}
+
 // SendMessage sends message to Webhook url
 func (w *Webhook) SendMessage(msg string) error {
+	err := w.PostWebhook(&WebhookPayload{
+		APIVersion: WebhookAPIVersion,
+		Type:       WebhookMessageType,
+		Message:    msg,
+	})
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	log.Debugf("Message successfully sent to Webhook %v", msg)
+	return nil
+}
+
+// Ready returns nil since webhook is posted over stateless HTTP requests
+func (w *Webhook) Ready() error {
 	return nil
 }
 
@@ -20,12 +20,18 @@
 package notify
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"github.com/infracloudio/botkube/pkg/events"
 )
 
 // Unit test PostWebhook
@@ -62,3 +68,55 @@ func TestPostWebhook(t *testing.T) {
 		})
 	}
 }
+
+func TestWebhookEventPayloadShape(t *testing.T) {
+	event := events.Event{
+		Kind:            "Pod",
+		Name:            "nginx",
+		Namespace:       "default",
+		Cluster:         "test",
+		Type:            config.UpdateEvent,
+		Level:           config.Info,
+		Messages:        []string{"Pod restarted"},
+		Diff:            "spec.replicas:\n\t-: 1\n\t+: 2\n",
+		TimeStamp:       time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC),
+		Recommendations: []string{"Set resource limits"},
+	}
+	payload, err := json.Marshal(newWebhookEventPayload(event))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := `{"apiVersion":"botkube.io/v1","type":"event","event":{` +
+		`"meta":{"kind":"Pod","name":"nginx","namespace":"default","cluster":"test"},` +
+		`"status":{"type":"update","level":"info","messages":["Pod restarted","spec.replicas:\n\t-: 1\n\t+: 2\n"]},` +
+		`"summary":` + string(mustMarshal(t, FormatShortMessage(event))) + `,` +
+		`"timestamp":"2021-03-01T10:00:00Z",` +
+		`"recommendations":["Set resource limits"]}}`
+	assert.JSONEq(t, expected, string(payload))
+}
+
+func TestWebhookMessagePayloadShape(t *testing.T) {
+	var received []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		if received, err = ioutil.ReadAll(r.Body); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	w := &Webhook{URL: ts.URL}
+	if err := w.SendMessage("...and now my watch begins for cluster 'test'!"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	assert.JSONEq(t, `{"apiVersion":"botkube.io/v1","type":"message","message":"...and now my watch begins for cluster 'test'!"}`, string(received))
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return b
+}
@@ -21,11 +21,24 @@ package utils
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/infracloudio/botkube/pkg/config"
 	"github.com/infracloudio/botkube/pkg/log"
 )
 
+const (
+	// maxDiffSize is the maximum length of the diff, longer diffs are cut at the last line which fits
+	maxDiffSize      = 2000
+	diffTruncatedMsg = "... diff truncated\n"
+	// RedactedValue replaces the values of secret fields
+	RedactedValue = "<redacted>"
+)
+
+// secretFieldRegex matches field paths with values that must not be sent in diffs
+var secretFieldRegex = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|apikey|api_key|private)`)
+
 type diffReporter struct {
 	field string
 }
@@ -46,6 +59,9 @@ func (d diffReporter) exec(x, y interface{}) (string, bool) {
 	if vx == vy || (vx == "<none>" && vy == "false") {
 		return "", false
 	}
+	if IsSecretField(d.field) {
+		return fmt.Sprintf("%s:\n\t-: %s\n\t+: %s\n", d.field, RedactedValue, RedactedValue), true
+	}
 	return fmt.Sprintf("%s:\n\t-: %+v\n\t+: %+v\n", d.field, vx, vy), true
 }
 
@@ -60,5 +76,22 @@ func Diff(x, y interface{}, updatesetting config.UpdateSetting) string {
 			msg = msg + diff
 		}
 	}
-	return msg
+	return TruncateDiff(msg)
+}
+
+// IsSecretField returns true if the field path looks like it holds a credential
+func IsSecretField(field string) bool {
+	return secretFieldRegex.MatchString(field)
+}
+
+// TruncateDiff cuts the diff longer than maxDiffSize at the last line which fits
+func TruncateDiff(diff string) string {
+	if len(diff) <= maxDiffSize {
+		return diff
+	}
+	cut := diff[:maxDiffSize-len(diffTruncatedMsg)]
+	if i := strings.LastIndex(cut, "\n"); i >= 0 {
+		cut = cut[:i+1]
+	}
+	return cut + diffTruncatedMsg
 }
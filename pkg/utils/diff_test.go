@@ -21,6 +21,7 @@ package utils
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/infracloudio/botkube/pkg/config"
@@ -60,6 +61,7 @@ type Status struct {
 // Data mocks ObjectData field in kubernetes object like configmap
 type Data struct {
 	Properties string `json:"properties"`
+	Password   string `json:"password"`
 }
 
 // Rules mocks ObjectRules field in kubernetes object
@@ -133,6 +135,16 @@ func TestDiff(t *testing.T) {
 				Y:    "color: red",
 			},
 		},
+		`Secret Data Diff`: {
+			old:    Object{Data: Data{Password: "cGFzc3dvcmQx"}},
+			new:    Object{Data: Data{Password: "cGFzc3dvcmQy"}},
+			update: config.UpdateSetting{Fields: []string{"data.password"}, IncludeDiff: true},
+			expected: ExpectedDiff{
+				Path: "data.password",
+				X:    "<redacted>",
+				Y:    "<redacted>",
+			},
+		},
 		`Non Data Diff`: {
 			old:      Object{Data: Data{Properties: "color: blue"}, Other: Other{Foo: "bar"}},
 			new:      Object{Data: Data{Properties: "color: blue"}, Other: Other{Foo: "boo"}},
@@ -166,6 +178,19 @@ func TestDiff(t *testing.T) {
 	}
 }
 
+func TestDiffTruncated(t *testing.T) {
+	old := Object{Spec: Spec{Containers: []Container{{Image: "nginx:1.14"}}}}
+	new := Object{Spec: Spec{Containers: []Container{{Image: strings.Repeat("x", maxDiffSize)}}}}
+	update := config.UpdateSetting{Fields: []string{"spec.port", "status.replicas", "spec.containers[*].image"}, IncludeDiff: true}
+	old.Spec.Port, new.Spec.Port = 80, 8080
+	old.Status.Replicas, new.Status.Replicas = 1, 2
+
+	expected := "spec.port:\n\t-: 80\n\t+: 8080\nstatus.replicas:\n\t-: 1\n\t+: 2\nspec.containers[*].image:\n\t-: nginx:1.14\n" + diffTruncatedMsg
+	if actual := Diff(old, new, update); actual != expected {
+		t.Errorf("expected: %+v != actual: %+v\n", expected, actual)
+	}
+}
+
 // MockDiff mocks utils.Diff
 func (e *ExpectedDiff) MockDiff() string {
 	if e.Path == "" {
@@ -0,0 +1,65 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package utils
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// KubectlMaps are the verbs and resources allowed with kubectl command and the names resolving to the resources
+// The maps must not be modified once they are set with SetKubectlMaps
+type KubectlMaps struct {
+	// Verbs is map of allowed verbs with kubectl command
+	Verbs map[string]bool
+	// Resources is map of allowed resources with kubectl command
+	Resources map[string]bool
+	// Kinds contains lowercase kind to resource name mapping
+	Kinds map[string]string
+	// Shortnames contains short name to resource name mapping
+	Shortnames map[string]string
+}
+
+// kubectlMaps holds the current *KubectlMaps, config reload replaces them while commands are executed
+var kubectlMaps atomic.Value
+
+// GetKubectlMaps returns the current kubectl maps, the maps are empty before InitResourceMap
+func GetKubectlMaps() *KubectlMaps {
+	if m, ok := kubectlMaps.Load().(*KubectlMaps); ok {
+		return m
+	}
+	return &KubectlMaps{}
+}
+
+// SetKubectlMaps replaces the kubectl maps in one step
+func SetKubectlMaps(m *KubectlMaps) {
+	kubectlMaps.Store(m)
+}
+
+// AllowedVerb checks if the verb is in settings.kubectl.commands.verbs
+func (m *KubectlMaps) AllowedVerb(verb string) bool {
+	return m.Verbs[verb]
+}
+
+// AllowedResource checks if the resource, its kind or short name is in settings.kubectl.commands.resources
+func (m *KubectlMaps) AllowedResource(name string) bool {
+	lower := strings.ToLower(name)
+	return m.Resources[name] || m.Resources[m.Kinds[lower]] || m.Resources[m.Shortnames[lower]]
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package utils
+
+import (
+	"sort"
+	"sync"
+)
+
+// MutedResources contains the resources muted at runtime with resources mute command
+// Events of muted resources are not sent to the notifiers
+var MutedResources = NewResourceSet()
+
+// ResourceSet is a set of resource names, e.g apps/v1/deployments, safe for concurrent use
+type ResourceSet struct {
+	mu        sync.RWMutex
+	resources map[string]bool
+}
+
+// NewResourceSet returns an empty ResourceSet
+func NewResourceSet() *ResourceSet {
+	return &ResourceSet{resources: make(map[string]bool)}
+}
+
+// Add adds the resource to the set, it returns false if the resource was present already
+func (s *ResourceSet) Add(resource string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resources[resource] {
+		return false
+	}
+	s.resources[resource] = true
+	return true
+}
+
+// Remove removes the resource from the set, it returns false if the resource was not present
+func (s *ResourceSet) Remove(resource string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.resources[resource] {
+		return false
+	}
+	delete(s.resources, resource)
+	return true
+}
+
+// Contains checks if the resource is present in the set
+func (s *ResourceSet) Contains(resource string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.resources[resource]
+}
+
+// List returns the resources in the set sorted by name
+func (s *ResourceSet) List() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]string, 0, len(s.resources))
+	for r := range s.resources {
+		list = append(list, r)
+	}
+	sort.Strings(list)
+	return list
+}
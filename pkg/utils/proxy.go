@@ -0,0 +1,99 @@
+// Copyright (c) 2019 InfraCloud Technologies
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+var (
+	// proxyFunc returns the proxy of settings.proxy, nil if the proxy is not set
+	proxyFunc   func(*url.URL) (*url.URL, error)
+	proxyMu     sync.RWMutex
+	defaultOnce sync.Once
+)
+
+// InitProxy sets the proxy for the connections to the communication platforms, e.g http://proxy:3128 or socks5://proxy:1080
+// HTTPS_PROXY and HTTP_PROXY env are used if proxy is empty, NO_PROXY env applies to both
+// http.DefaultTransport uses the proxy too since some clients of the platforms can't be configured, e.g Teams
+func InitProxy(proxy string) error {
+	defaultOnce.Do(func() {
+		if t, ok := http.DefaultTransport.(*http.Transport); ok {
+			t.Proxy = Proxy
+		}
+	})
+	var f func(*url.URL) (*url.URL, error)
+	err := validateProxy(proxy)
+	if err == nil && len(proxy) != 0 {
+		f = (&httpproxy.Config{HTTPProxy: proxy, HTTPSProxy: proxy, NoProxy: noProxyEnv()}).ProxyFunc()
+	}
+	proxyMu.Lock()
+	proxyFunc = f
+	proxyMu.Unlock()
+	return err
+}
+
+// validateProxy returns error if the proxy is not a http, https or socks5 URL
+func validateProxy(proxy string) error {
+	if len(proxy) == 0 {
+		return nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		// The error contains the URL, it can have credentials
+		return fmt.Errorf("Invalid proxy URL")
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return nil
+	}
+	return fmt.Errorf("Invalid proxy URL scheme '%s'. Use http, https or socks5", u.Scheme)
+}
+
+// Proxy returns the proxy for the request, set with InitProxy or from environment
+func Proxy(req *http.Request) (*url.URL, error) {
+	proxyMu.RLock()
+	f := proxyFunc
+	proxyMu.RUnlock()
+	if f == nil {
+		return http.ProxyFromEnvironment(req)
+	}
+	return f(req.URL)
+}
+
+// NewHTTPTransport returns a copy of http.DefaultTransport which connects through Proxy
+func NewHTTPTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = Proxy
+	return t
+}
+
+func noProxyEnv() string {
+	if v := os.Getenv("NO_PROXY"); len(v) != 0 {
+		return v
+	}
+	return os.Getenv("no_proxy")
+}
@@ -55,14 +55,6 @@ var (
 	AllowedEventKindsMap map[EventKind]bool
 	// AllowedUpdateEventsMap is a map of resource and namespace to updateconfig
 	AllowedUpdateEventsMap map[KindNS]config.UpdateSetting
-	// AllowedKubectlResourceMap is map of allowed resources with kubectl command
-	AllowedKubectlResourceMap map[string]bool
-	// AllowedKubectlVerbMap is map of allowed verb with kubectl command
-	AllowedKubectlVerbMap map[string]bool
-	// KindResourceMap contains resource name to kind mapping
-	KindResourceMap map[string]string
-	// ShortnameResourceMap contains resource name to short name mapping
-	ShortnameResourceMap map[string]string
 	// DynamicKubeClient is a global dynamic kubernetes client to communicate to apiserver
 	DynamicKubeClient dynamic.Interface
 	// DynamicKubeInformerFactory is a global DynamicSharedInformerFactory object to watch resources
@@ -77,37 +69,30 @@ var (
 const hyperlinkRegex = `(?m)<http:\/\/[a-z.0-9\/\-_=]*\|([a-z.0-9\/\-_=]*)>`
 
 // InitKubeClient creates K8s client from provided kubeconfig OR service account to interact with apiserver
-func InitKubeClient() {
+// The client is rate limited with settings.kubernetes qps and burst
+func InitKubeClient(c config.Kubernetes) {
 	kubeConfig, err := rest.InClusterConfig()
 	if err != nil {
 		kubeconfigPath := os.Getenv("KUBECONFIG")
 		if kubeconfigPath == "" {
 			kubeconfigPath = os.Getenv("HOME") + "/.kube/config"
 		}
-		botkubeConf, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
-		if err != nil {
-			log.Fatal(err)
-		}
-		// Initiate discovery client for REST resource mapping
-		DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(botkubeConf)
-		if err != nil {
-			log.Fatalf("Unable to create Discovery Client")
-		}
-		DynamicKubeClient, err = dynamic.NewForConfig(botkubeConf)
-		if err != nil {
-			log.Fatal(err)
-		}
-	} else {
-		// Initiate discovery client for REST resource mapping
-		DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(kubeConfig)
-		if err != nil {
-			log.Fatal(err)
-		}
-		DynamicKubeClient, err = dynamic.NewForConfig(kubeConfig)
+		kubeConfig, err = clientcmd.BuildConfigFromFlags("", kubeconfigPath)
 		if err != nil {
 			log.Fatal(err)
 		}
 	}
+	kubeConfig = withRateLimits(kubeConfig, c)
+
+	// Initiate discovery client for REST resource mapping
+	DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(kubeConfig)
+	if err != nil {
+		log.Fatalf("Unable to create Discovery Client")
+	}
+	DynamicKubeClient, err = dynamic.NewForConfig(kubeConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	discoCacheClient := cacheddiscovery.NewMemCacheClient(DiscoveryClient)
 	discoCacheClient.Invalidate()
@@ -115,6 +100,19 @@ func InitKubeClient() {
 
 }
 
+// withRateLimits returns the copy of the client config with QPS and burst from settings.kubernetes
+// client-go defaults are kept for the values which are not set
+func withRateLimits(kubeConfig *rest.Config, c config.Kubernetes) *rest.Config {
+	kubeConfig = rest.CopyConfig(kubeConfig)
+	if c.QPS > 0 {
+		kubeConfig.QPS = c.QPS
+	}
+	if c.Burst > 0 {
+		kubeConfig.Burst = c.Burst
+	}
+	return kubeConfig
+}
+
 // EventKind used in AllowedEventKindsMap to filter event kinds
 type EventKind struct {
 	Resource  string
@@ -202,6 +200,7 @@ func GetObjectMetaData(obj interface{}) metaV1.ObjectMeta {
 		Name:                       unstructuredObject.GetName(),
 		GenerateName:               unstructuredObject.GetGenerateName(),
 		Namespace:                  unstructuredObject.GetNamespace(),
+		UID:                        unstructuredObject.GetUID(),
 		ResourceVersion:            unstructuredObject.GetResourceVersion(),
 		Generation:                 unstructuredObject.GetGeneration(),
 		CreationTimestamp:          unstructuredObject.GetCreationTimestamp(),
@@ -282,21 +281,24 @@ func ExtractAnnotationsFromEvent(obj *coreV1.Event) map[string]string {
 }
 
 // InitResourceMap initializes helper maps to allow kubectl execution for required resources
+// The maps are built before they replace the current ones, commands use the old maps during discovery
 func InitResourceMap(conf *config.Config) {
 	if !conf.Settings.Kubectl.Enabled {
 		return
 	}
-	KindResourceMap = make(map[string]string)
-	ShortnameResourceMap = make(map[string]string)
-	AllowedKubectlResourceMap = make(map[string]bool)
-	AllowedKubectlVerbMap = make(map[string]bool)
-
+	m := &KubectlMaps{
+		Verbs:      make(map[string]bool),
+		Resources:  make(map[string]bool),
+		Kinds:      make(map[string]string),
+		Shortnames: make(map[string]string),
+	}
 	for _, r := range conf.Settings.Kubectl.Commands.Resources {
-		AllowedKubectlResourceMap[r] = true
+		m.Resources[r] = true
 	}
 	for _, r := range conf.Settings.Kubectl.Commands.Verbs {
-		AllowedKubectlVerbMap[r] = true
+		m.Verbs[r] = true
 	}
+	defer SetKubectlMaps(m)
 
 	resourceList, err := DiscoveryClient.ServerResources()
 	if err != nil {
@@ -309,16 +311,16 @@ func InitResourceMap(conf *config.Config) {
 			if strings.Contains(r.Name, "/") {
 				continue
 			}
-			KindResourceMap[strings.ToLower(r.Kind)] = r.Name
+			m.Kinds[strings.ToLower(r.Kind)] = r.Name
 			for _, sn := range r.ShortNames {
-				ShortnameResourceMap[sn] = r.Name
+				m.Shortnames[sn] = r.Name
 			}
 		}
 	}
-	log.Infof("AllowedKubectlResourceMap - %+v", AllowedKubectlResourceMap)
-	log.Infof("AllowedKubectlVerbMap - %+v", AllowedKubectlVerbMap)
-	log.Infof("KindResourceMap - %+v", KindResourceMap)
-	log.Infof("ShortnameResourceMap - %+v", ShortnameResourceMap)
+	log.Infof("AllowedKubectlResourceMap - %+v", m.Resources)
+	log.Infof("AllowedKubectlVerbMap - %+v", m.Verbs)
+	log.Infof("KindResourceMap - %+v", m.Kinds)
+	log.Infof("ShortnameResourceMap - %+v", m.Shortnames)
 }
 
 //GetClusterNameFromKubectlCmd this will return cluster name from kubectl command
@@ -415,3 +417,17 @@ func RemoveHyperlink(hyperlink string) string {
 	}
 	return command
 }
+
+// MatchNamespace matches namespace with the pattern. Pattern can contain a * that would expand
+// to zero or more arbitrary characters
+func MatchNamespace(pattern, namespace string) bool {
+	if len(pattern) == 0 {
+		return false
+	}
+	if !strings.Contains(pattern, "*") {
+		return pattern == namespace
+	}
+	expr := "^" + strings.Replace(regexp.QuoteMeta(pattern), `\*`, ".*", -1) + "$"
+	matched, err := regexp.MatchString(expr, namespace)
+	return err == nil && matched
+}
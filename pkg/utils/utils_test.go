@@ -22,8 +22,36 @@ package utils
 import (
 	"fmt"
 	"testing"
+
+	"github.com/infracloudio/botkube/pkg/config"
+	"k8s.io/client-go/rest"
 )
 
+func TestWithRateLimits(t *testing.T) {
+	tests := map[string]struct {
+		conf          config.Kubernetes
+		expectedQPS   float32
+		expectedBurst int
+	}{
+		`configured values`: {config.Kubernetes{QPS: 50, Burst: 100}, 50, 100},
+		`only qps`:          {config.Kubernetes{QPS: 20}, 20, 0},
+		`negative values`:   {config.Kubernetes{QPS: -1, Burst: -1}, 0, 0},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			base := &rest.Config{Host: "https://kubernetes.default"}
+			actual := withRateLimits(base, test.conf)
+			if actual.QPS != test.expectedQPS || actual.Burst != test.expectedBurst {
+				t.Errorf("expected: %+v/%+v != actual: %+v/%+v\n", test.expectedQPS, test.expectedBurst, actual.QPS, actual.Burst)
+			}
+			if actual.Host != base.Host || base.QPS != 0 || base.Burst != 0 {
+				t.Errorf("expected copy of the config, got: %+v, base: %+v", actual, base)
+			}
+		})
+	}
+}
+
 func TestGetClusterNameFromKubectlCmd(t *testing.T) {
 
 	type test struct {
@@ -111,3 +139,28 @@ func TestRemoveHypelink(t *testing.T) {
 		}
 	}
 }
+
+func TestMatchNamespace(t *testing.T) {
+	tests := map[string]struct {
+		pattern   string
+		namespace string
+		expected  bool
+	}{
+		`exact match`:          {"dev", "dev", true},
+		`exact mismatch`:       {"dev", "prod", false},
+		`wildcard suffix`:      {"dev-*", "dev-a", true},
+		`wildcard in between`:  {"ignored-*-ns", "ignored-42-ns", true},
+		`wildcard partial`:     {"dev-*", "prod-dev-a", false},
+		`regex chars escaped`:  {"dev.a", "devxa", false},
+		`empty pattern`:        {"", "dev", false},
+		`wildcard matches all`: {"*", "prod", true},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			if actual := MatchNamespace(test.pattern, test.namespace); actual != test.expected {
+				t.Errorf("expected: %+v != actual: %+v\n", test.expected, actual)
+			}
+		})
+	}
+}
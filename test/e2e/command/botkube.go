@@ -23,6 +23,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -34,9 +35,12 @@ import (
 
 	"github.com/infracloudio/botkube/pkg/config"
 	"github.com/infracloudio/botkube/pkg/execute"
+	"github.com/infracloudio/botkube/pkg/filterengine"
 	"github.com/infracloudio/botkube/test/e2e/utils"
 )
 
+var uptimeRegex = regexp.MustCompile(`Uptime: \S+`)
+
 type botkubeCommand struct {
 	command  string
 	expected string
@@ -45,11 +49,18 @@ type botkubeCommand struct {
 // Send botkube command via Slack message and check if BotKube returns correct response
 func (c *context) testBotkubeCommand(t *testing.T) {
 	botkubeVersion := os.Getenv("BOTKUBE_VERSION")
+	podName, _ := os.Hostname()
+	enabledFilters := 0
+	for _, enabled := range filterengine.DefaultFilterEngine.ShowFilters() {
+		if enabled {
+			enabledFilters++
+		}
+	}
 	// Test cases
 	tests := map[string]botkubeCommand{
 		"BotKube ping": {
 			command:  "ping",
-			expected: fmt.Sprintf("```\npong from cluster '%s'\n\nK8s Server Version: %s\nBotKube version: %s\n```", c.Config.Settings.ClusterName, execute.K8sVersion, botkubeVersion),
+			expected: fmt.Sprintf("```\npong from cluster '%s'\n\nK8s Server Version: %s\nBotKube version: %s\nCluster: %s\nPod: %s\nUptime: <uptime>\nRestarts: Unknown\nEnabled filters: %d\n```", c.Config.Settings.ClusterName, execute.K8sVersion, botkubeVersion, c.Config.Settings.ClusterName, podName, enabledFilters),
 		},
 		"BotKube filters list": {
 			command: "filters list",
@@ -59,7 +70,17 @@ func (c *context) testBotkubeCommand(t *testing.T) {
 				"ObjectAnnotationChecker true    Checks if annotations botkube.io/* present in object specs and filters them.\n" +
 				"PodLabelChecker         true    Checks and adds recommendations if labels are missing in the pod specs.\n" +
 				"ImageTagChecker         true    Checks and adds recommendation if 'latest' image tag is used for container image.\n" +
-				"IngressValidator        true    Checks if services and tls secrets used in ingress specs are available.",
+				"IngressValidator        true    Checks if services and tls secrets used in ingress specs are available.\n" +
+				"RequiredLabelChecker    true    Checks and adds recommendations if labels listed in settings.requiredLabels are missing in the Deployment, Pod or Service specs.\n" +
+				"LoadBalancerChecker     true    Checks and adds warning if Service of type LoadBalancer is created in namespaces listed in settings.nonProdNamespaces.\n" +
+				"RunbookChecker          true    Adds runbook link to the recommendations if event reason is listed in settings.runbooks.\n" +
+				"OwnerReferenceChecker   true    Adds the top-level controller owning the Pod, e.g Deployment, to the event messages.\n" +
+				"FailedSchedulingChecker true    Adds recommendations based on the scheduler message if Pod can't be scheduled.\n" +
+				"DisruptionBudgetChecker true    Warns when available replicas of Deployment or ReplicaSet reach the minimum of its PodDisruptionBudget.\n" +
+				"RestartSpikeChecker     true    Checks and adds warning if a container restarted settings.restartSpike.threshold times or more within the window.\n" +
+				"ImageRegistryChecker    true    Checks and adds warning if container images are pulled from registries not listed in settings.allowedRegistries.\n" +
+				"ServiceAccountChecker   false   Checks and adds warning if Pod uses the default ServiceAccount.\n" +
+				"SecretEnvChecker        true    Checks and adds warning if Pod containers source Secrets as environment variables.",
 		},
 		"BotKube commands list": {
 			command: "commands list",
@@ -126,6 +147,9 @@ func (c *context) testBotkubeCommand(t *testing.T) {
 				assert.NoError(t, err, "message should decode properly")
 				assert.Equal(t, c.Config.Communications.Slack.Channel, m.Channel)
 				switch test.command {
+				case "ping":
+					// Uptime changes with time
+					assert.Equal(t, test.expected, uptimeRegex.ReplaceAllString(m.Text, "Uptime: <uptime>"))
 				case "filters list":
 					fl := compareFilters(strings.Split(test.expected, "\n"), strings.Split(strings.TrimSpace(strings.Trim(m.Text, "```")), "\n"))
 					assert.Equal(t, fl, true)
@@ -46,7 +46,17 @@ func (c *context) testKubectlCommand(t *testing.T) {
 	tests := map[string]kubectlCommand{
 		"BotKube get pods from configured channel": {
 			command:  "get pods",
-			expected: fmt.Sprintf("```\nCluster: %s\n%s\n```", c.Config.Settings.ClusterName, execute.KubectlResponse["-n default get pods"]),
+			expected: fmt.Sprintf("```\nCluster: %s\n%s\n```", c.Config.Settings.ClusterName, execute.KubectlResponse["-n default get pods --chunk-size=500"]),
+			channel:  c.Config.Communications.Slack.Channel,
+		},
+		"BotKube logs with default tail and without follow": {
+			command:  "logs nginx-xxxxxxx-yyyyyyy --since=10m -f",
+			expected: fmt.Sprintf("```\nCluster: %s\n%s\n```", c.Config.Settings.ClusterName, execute.KubectlResponse["-n default logs nginx-xxxxxxx-yyyyyyy --since=10m --tail=100"]),
+			channel:  c.Config.Communications.Slack.Channel,
+		},
+		"BotKube logs with tail": {
+			command:  "logs nginx-xxxxxxx-yyyyyyy --tail=20",
+			expected: fmt.Sprintf("```\nCluster: %s\n%s\n```", c.Config.Settings.ClusterName, execute.KubectlResponse["-n default logs nginx-xxxxxxx-yyyyyyy --tail=20"]),
 			channel:  c.Config.Communications.Slack.Channel,
 		},
 		"BotKube get pods out of configured channel": {
@@ -20,6 +20,7 @@
 package e2e
 
 import (
+	"context"
 	"log"
 	"testing"
 	"time"
@@ -28,6 +29,8 @@ import (
 
 	"github.com/infracloudio/botkube/pkg/bot"
 	"github.com/infracloudio/botkube/pkg/controller"
+	"github.com/infracloudio/botkube/pkg/execute"
+	"github.com/infracloudio/botkube/pkg/filterengine"
 	"github.com/infracloudio/botkube/pkg/notify"
 	"github.com/infracloudio/botkube/pkg/utils"
 	"github.com/infracloudio/botkube/test/e2e/command"
@@ -70,9 +73,11 @@ func TestRun(t *testing.T) {
 	utils.Mapper = testEnv.Mapper
 	utils.InitInformerMap(testEnv.Config)
 	utils.InitResourceMap(testEnv.Config)
+	execute.InitConfig(testEnv.Config)
+	filterengine.DefaultFilterEngine.ResetFilters(testEnv.Config.Settings.Filters)
 
 	// Start controller with fake notifiers
-	go controller.RegisterInformers(testEnv.Config, notifiers)
+	go controller.RegisterInformers(context.Background(), testEnv.Config, notifiers)
 	t.Run("Welcome", welcome.E2ETests(testEnv))
 
 	if testEnv.Config.Communications.Slack.Enabled {
@@ -42,7 +42,7 @@ type SlackMessage struct {
 	Attachments []slack.Attachment
 }
 
-// WebhookPayload structure
+// WebhookPayload structure of the event field in webhook payload
 type WebhookPayload struct {
 	Summary     string             `json:"summary"`
 	EventMeta   notify.EventMeta   `json:"meta"`
@@ -25,6 +25,7 @@ import (
 	"net/http/httptest"
 
 	"github.com/infracloudio/botkube/pkg/log"
+	"github.com/infracloudio/botkube/pkg/notify"
 	"github.com/infracloudio/botkube/test/e2e/utils"
 )
 
@@ -34,17 +35,21 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	decoder := json.NewDecoder(r.Body)
 
-	var t utils.WebhookPayload
+	var t webhookEnvelope
 
 	err := decoder.Decode(&t)
 	if err != nil {
 		panic(err)
 	}
+	// Only events are collected, messages are posted on start and stop
+	if t.Type != string(notify.WebhookEventType) || t.Event == nil {
+		return
+	}
 
 	// update message in mutex
 	s.receivedPayloads.Lock()
 	log.Debugf("Incoming Webhook Messages :%#v", t)
-	s.receivedPayloads.messages = append(s.receivedPayloads.messages, t)
+	s.receivedPayloads.messages = append(s.receivedPayloads.messages, *t.Event)
 	s.receivedPayloads.Unlock()
 
 }
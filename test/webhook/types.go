@@ -38,3 +38,10 @@ type Server struct {
 	ServerAddr       string
 	receivedPayloads *payloadCollection
 }
+
+// webhookEnvelope is the versioned webhook payload, event details are decoded into utils.WebhookPayload
+type webhookEnvelope struct {
+	APIVersion string                `json:"apiVersion"`
+	Type       string                `json:"type"`
+	Event      *utils.WebhookPayload `json:"event"`
+}